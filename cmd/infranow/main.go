@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -18,7 +19,10 @@ var (
 func main() {
 	rootCmd := cli.NewRootCommand(version, commit, date)
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		util.Exit(util.ExitRuntimeError)
+		var status *util.ExitError
+		if !errors.As(err, &status) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		util.Exit(util.ExitCode(err))
 	}
 }
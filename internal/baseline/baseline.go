@@ -92,7 +92,9 @@ func Compare(current []*models.Problem, baseline *Baseline) *Comparison {
 	// Find resolved
 	for id, p := range baselineMap {
 		if _, exists := currentMap[id]; !exists {
-			comp.Resolved = append(comp.Resolved, p)
+			resolved := *p // Leave the loaded baseline untouched
+			resolved.State = models.StateResolved
+			comp.Resolved = append(comp.Resolved, &resolved)
 		}
 	}
 
@@ -104,3 +106,15 @@ func Compare(current []*models.Problem, baseline *Baseline) *Comparison {
 
 	return comp
 }
+
+// NewAtLeast returns the new problems at or above threshold. Problems that
+// were already in the baseline are ignored, whatever their severity.
+func (c *Comparison) NewAtLeast(threshold models.Severity) []*models.Problem {
+	var out []*models.Problem
+	for _, p := range c.New {
+		if p.Severity.AtLeast(threshold) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -94,6 +94,42 @@ func TestCompare(t *testing.T) {
 	}
 }
 
+func TestComparison_NewAtLeast(t *testing.T) {
+	known := &models.Problem{ID: "known", Severity: models.SeverityFatal}
+	b := &Baseline{Problems: []*models.Problem{known}}
+
+	tests := []struct {
+		name    string
+		current []*models.Problem
+		want    int
+	}{
+		{
+			name:    "pre-existing fatal does not fail",
+			current: []*models.Problem{known},
+			want:    0,
+		},
+		{
+			name:    "new critical fails",
+			current: []*models.Problem{known, {ID: "fresh", Severity: models.SeverityCritical}},
+			want:    1,
+		},
+		{
+			name:    "new warning below threshold",
+			current: []*models.Problem{known, {ID: "fresh", Severity: models.SeverityWarning}},
+			want:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(tt.current, b).NewAtLeast(models.SeverityCritical)
+			if len(got) != tt.want {
+				t.Errorf("NewAtLeast(CRITICAL) = %d problems, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
 func TestSaveAndLoadBaseline(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "baseline.json")
@@ -153,3 +189,22 @@ func TestLoadBaseline_InvalidJSON(t *testing.T) {
 		t.Fatal("expected error for invalid JSON")
 	}
 }
+
+func TestCompare_ResolvedState(t *testing.T) {
+	b := &Baseline{Problems: []*models.Problem{
+		{ID: "a", State: models.StateFiring},
+		{ID: "b", State: models.StateFiring},
+	}}
+	current := []*models.Problem{{ID: "a", State: models.StateFiring}}
+
+	comp := Compare(current, b)
+	if len(comp.Resolved) != 1 || comp.Resolved[0].State != models.StateResolved {
+		t.Fatalf("resolved = %+v, want b in state resolved", comp.Resolved)
+	}
+	if comp.Unchanged[0].State != models.StateFiring {
+		t.Errorf("unchanged state = %q, want firing", comp.Unchanged[0].State)
+	}
+	if b.Problems[1].State != models.StateFiring {
+		t.Error("Compare should not modify the loaded baseline")
+	}
+}
@@ -0,0 +1,100 @@
+package baseline
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// DefaultRollingKeep is the number of baselines --baseline-dir keeps
+const DefaultRollingKeep = 10
+
+// Rolling baseline file names embed a UTC timestamp that sorts lexically,
+// e.g. baseline-20260301T120000.000000000Z.json
+const (
+	rollingPrefix     = "baseline-"
+	rollingSuffix     = ".json"
+	rollingTimeLayout = "20060102T150405.000000000Z"
+)
+
+// Rolling is a directory of timestamped baselines. Each run compares against
+// the newest file, then saves a new one and prunes all but the newest keep.
+type Rolling struct {
+	dir  string
+	keep int
+}
+
+// NewRolling creates the baseline directory if needed
+func NewRolling(dir string, keep int) (*Rolling, error) {
+	if keep < 1 {
+		return nil, fmt.Errorf("keep must be at least 1, got %d", keep)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create baseline directory: %w", err)
+	}
+	return &Rolling{dir: dir, keep: keep}, nil
+}
+
+// Latest returns the path of the newest baseline, or "" if there is none yet
+func (r *Rolling) Latest() (string, error) {
+	files, err := r.files()
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", nil
+	}
+	return files[len(files)-1], nil
+}
+
+// Save writes problems as a new baseline stamped with now and prunes the
+// oldest files beyond keep. Returns the path written.
+func (r *Rolling) Save(problems []*models.Problem, metadata map[string]string, now time.Time) (string, error) {
+	path := filepath.Join(r.dir, rollingPrefix+now.UTC().Format(rollingTimeLayout)+rollingSuffix)
+	if err := SaveBaseline(problems, path, metadata); err != nil {
+		return "", err
+	}
+
+	files, err := r.files()
+	if err != nil {
+		return path, err
+	}
+	for len(files) > r.keep {
+		if err := os.Remove(files[0]); err != nil {
+			return path, fmt.Errorf("prune baseline: %w", err)
+		}
+		files = files[1:]
+	}
+	return path, nil
+}
+
+// files lists rolling baselines oldest first. Other files in the directory,
+// including baseline-*.json files without a timestamp, are ignored.
+func (r *Rolling) files() ([]string, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read baseline directory: %w", err)
+	}
+
+	var files []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, rollingPrefix) || !strings.HasSuffix(name, rollingSuffix) {
+			continue
+		}
+		// Only names Save could have written; a hand-made baseline-prod.json
+		// would otherwise sort last and be compared against or pruned
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, rollingPrefix), rollingSuffix)
+		if _, err := time.Parse(rollingTimeLayout, stamp); err != nil {
+			continue
+		}
+		files = append(files, filepath.Join(r.dir, name))
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,130 @@
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestRolling_KeepsNewestN(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewRolling(dir, 3)
+	if err != nil {
+		t.Fatalf("NewRolling failed: %v", err)
+	}
+	// Unrelated files are never pruned
+	other := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(other, []byte("keep me"), 0o600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var last string
+	for i := 0; i < 5; i++ {
+		problems := []*models.Problem{{ID: "run", Count: i}}
+		if last, err = r.Save(problems, nil, start.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatalf("Save %d failed: %v", i, err)
+		}
+	}
+
+	files, err := r.files()
+	if err != nil {
+		t.Fatalf("files failed: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("expected 3 baselines after rotation, got %d: %v", len(files), files)
+	}
+	if filepath.Base(files[0]) != "baseline-20260301T140000.000000000Z.json" {
+		t.Errorf("oldest kept baseline = %s, want the third run", filepath.Base(files[0]))
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("unrelated file was removed: %v", err)
+	}
+
+	latest, err := r.Latest()
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if latest != last {
+		t.Errorf("Latest() = %s, want %s", latest, last)
+	}
+	b, err := LoadBaseline(latest)
+	if err != nil {
+		t.Fatalf("LoadBaseline failed: %v", err)
+	}
+	if len(b.Problems) != 1 || b.Problems[0].Count != 4 {
+		t.Errorf("latest baseline is not the newest run: %+v", b.Problems)
+	}
+}
+
+func TestRolling_ComparesAgainstNewest(t *testing.T) {
+	r, err := NewRolling(filepath.Join(t.TempDir(), "baselines"), 2)
+	if err != nil {
+		t.Fatalf("NewRolling failed: %v", err)
+	}
+	if latest, err := r.Latest(); err != nil || latest != "" {
+		t.Fatalf("empty directory: Latest() = %q, %v", latest, err)
+	}
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if _, err := r.Save([]*models.Problem{{ID: "old"}}, nil, now); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := r.Save([]*models.Problem{{ID: "a"}, {ID: "b"}}, nil, now.Add(time.Minute)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	latest, err := r.Latest()
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	b, err := LoadBaseline(latest)
+	if err != nil {
+		t.Fatalf("LoadBaseline failed: %v", err)
+	}
+	comparison := Compare([]*models.Problem{{ID: "b"}, {ID: "c"}}, b)
+	if comparison.Summary.NewCount != 1 || comparison.Summary.ResolvedCount != 1 || comparison.Summary.UnchangedCount != 1 {
+		t.Errorf("comparison against newest baseline = %+v, want 1 new, 1 resolved, 1 unchanged", comparison.Summary)
+	}
+}
+
+func TestRolling_IgnoresUntimestampedBaselines(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewRolling(dir, 1)
+	if err != nil {
+		t.Fatalf("NewRolling failed: %v", err)
+	}
+	// Sorts after every timestamped name, so it would win Latest if counted
+	manual := filepath.Join(dir, "baseline-prod.json")
+	if err := SaveBaseline([]*models.Problem{{ID: "manual"}}, manual, nil); err != nil {
+		t.Fatalf("SaveBaseline failed: %v", err)
+	}
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var last string
+	for i := 0; i < 2; i++ {
+		if last, err = r.Save([]*models.Problem{{ID: "run"}}, nil, now.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("Save %d failed: %v", i, err)
+		}
+	}
+
+	latest, err := r.Latest()
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if latest != last {
+		t.Errorf("Latest() = %s, want %s", latest, last)
+	}
+	if _, err := os.Stat(manual); err != nil {
+		t.Errorf("hand-written baseline was pruned: %v", err)
+	}
+}
+
+func TestNewRolling_InvalidKeep(t *testing.T) {
+	if _, err := NewRolling(t.TempDir(), 0); err == nil {
+		t.Fatal("expected error for keep 0")
+	}
+}
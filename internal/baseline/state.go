@@ -0,0 +1,160 @@
+package baseline
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/util"
+)
+
+// State is the last seen problem-ID set, persisted between cron runs, with
+// the full problems it was taken from
+type State struct {
+	Timestamp  time.Time         `json:"timestamp"`
+	ProblemIDs []string          `json:"problem_ids"`
+	Problems   []*models.Problem `json:"problems,omitempty"`
+}
+
+// StateFormat is the encoding of a state file
+type StateFormat string
+
+const (
+	StateJSON   StateFormat = "json"   // Indented JSON, readable and diffable
+	StateBinary StateFormat = "binary" // gob, about twice as fast to load with many problems
+)
+
+// binaryStateMagic starts every binary state file, so LoadState can tell the
+// formats apart and a state file keeps loading after --state-format changes
+var binaryStateMagic = []byte("infranow-state/gob\n")
+
+// ParseStateFormat parses a --state-format value
+func ParseStateFormat(s string) (StateFormat, error) {
+	switch f := StateFormat(s); f {
+	case StateJSON, StateBinary:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown state format %q (use %s or %s)", s, StateJSON, StateBinary)
+}
+
+// StateDelta lists problem IDs that appeared or disappeared since the last run
+type StateDelta struct {
+	New      []string `json:"new"`
+	Resolved []string `json:"resolved"`
+}
+
+// Changed reports whether the problem set differs from the previous run
+func (d StateDelta) Changed() bool {
+	return len(d.New) > 0 || len(d.Resolved) > 0
+}
+
+// LoadState reads a state file in either format. A missing file yields an
+// empty state so the first run reports every current problem as new.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read state file: %w", err)
+	}
+
+	var s State
+	if rest, ok := bytes.CutPrefix(data, binaryStateMagic); ok {
+		err = gob.NewDecoder(bytes.NewReader(rest)).Decode(&s)
+	} else {
+		err = json.Unmarshal(data, &s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse state file: %w", err)
+	}
+	return &s, nil
+}
+
+// SaveState atomically writes the current problem-ID set and problems in
+// format
+func SaveState(path string, problems []*models.Problem, format StateFormat) error {
+	s := State{Timestamp: time.Now(), ProblemIDs: problemIDs(problems), Problems: problems}
+	data, err := encodeState(&s, format)
+	if err != nil {
+		return fmt.Errorf("marshal state: %w", err)
+	}
+
+	if err := util.WriteFileAtomic(path, data, 0o600); err != nil {
+		return fmt.Errorf("write state file: %w", err)
+	}
+	return nil
+}
+
+func encodeState(s *State, format StateFormat) ([]byte, error) {
+	if format != StateBinary {
+		return json.MarshalIndent(s, "", "  ")
+	}
+	var buf bytes.Buffer
+	buf.Write(binaryStateMagic)
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// FirstSeen returns, per problem ID, when the problems in the state were
+// first seen by any run, for WithOriginalFirstSeen. State files written
+// before problems were kept yield an empty map.
+func (s *State) FirstSeen() map[string]time.Time {
+	seen := make(map[string]time.Time, len(s.Problems))
+	for _, p := range s.Problems {
+		since := p.Since()
+		if since.IsZero() {
+			continue
+		}
+		if t, ok := seen[p.ID]; !ok || since.Before(t) {
+			seen[p.ID] = since
+		}
+	}
+	return seen
+}
+
+// DiffState compares the current problems against a previous state
+func DiffState(prev *State, current []*models.Problem) StateDelta {
+	prevSet := make(map[string]bool, len(prev.ProblemIDs))
+	for _, id := range prev.ProblemIDs {
+		prevSet[id] = true
+	}
+
+	delta := StateDelta{New: []string{}, Resolved: []string{}}
+	currentSet := make(map[string]bool, len(current))
+	for _, id := range problemIDs(current) {
+		currentSet[id] = true
+		if !prevSet[id] {
+			delta.New = append(delta.New, id)
+		}
+	}
+	for _, id := range prev.ProblemIDs {
+		if !currentSet[id] {
+			delta.Resolved = append(delta.Resolved, id)
+		}
+	}
+	sort.Strings(delta.Resolved)
+	return delta
+}
+
+// problemIDs returns the sorted, de-duplicated IDs of problems
+func problemIDs(problems []*models.Problem) []string {
+	seen := make(map[string]bool, len(problems))
+	ids := make([]string, 0, len(problems))
+	for _, p := range problems {
+		if !seen[p.ID] {
+			seen[p.ID] = true
+			ids = append(ids, p.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
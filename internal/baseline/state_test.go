@@ -0,0 +1,203 @@
+package baseline
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestState_RoundTripUnchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	problems := []*models.Problem{{ID: "b"}, {ID: "a"}, {ID: "a"}}
+
+	if err := SaveState(path, problems, StateJSON); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	prev, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if len(prev.ProblemIDs) != 2 || prev.ProblemIDs[0] != "a" {
+		t.Errorf("saved IDs = %v, want sorted and de-duplicated [a b]", prev.ProblemIDs)
+	}
+
+	delta := DiffState(prev, []*models.Problem{{ID: "a"}, {ID: "b"}})
+	if delta.Changed() {
+		t.Errorf("same set should be unchanged, got %+v", delta)
+	}
+}
+
+func TestDiffState_Changed(t *testing.T) {
+	prev := &State{ProblemIDs: []string{"a", "b"}}
+	delta := DiffState(prev, []*models.Problem{{ID: "b"}, {ID: "c"}})
+
+	if !delta.Changed() {
+		t.Fatal("expected change")
+	}
+	if len(delta.New) != 1 || delta.New[0] != "c" {
+		t.Errorf("new = %v, want [c]", delta.New)
+	}
+	if len(delta.Resolved) != 1 || delta.Resolved[0] != "a" {
+		t.Errorf("resolved = %v, want [a]", delta.Resolved)
+	}
+}
+
+func TestLoadState_Missing(t *testing.T) {
+	prev, err := LoadState(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("missing state should not error: %v", err)
+	}
+	delta := DiffState(prev, []*models.Problem{{ID: "a"}})
+	if len(delta.New) != 1 {
+		t.Errorf("first run should report all problems as new, got %+v", delta)
+	}
+}
+
+// fullProblem returns a problem with every field set, so a round trip that
+// drops one fails
+func fullProblem(i int) *models.Problem {
+	seen := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Second)
+	return &models.Problem{
+		ID:                fmt.Sprintf("prod/api-%d/crashloop", i),
+		Entity:            fmt.Sprintf("prod/api/api-%d", i),
+		EntityType:        "kubernetes_pod",
+		Type:              "crashloopbackoff",
+		Severity:          models.SeverityCritical,
+		State:             models.StateFiring,
+		Title:             "Pod in CrashLoopBackOff",
+		Message:           "Container app restarted 12 times",
+		FirstSeen:         seen,
+		LastSeen:          seen.Add(5 * time.Minute),
+		Count:             12,
+		OriginalFirstSeen: seen.Add(-72 * time.Hour),
+		BlastRadius:       3,
+		Persistence:       300,
+		Volatility:        0.4,
+		Labels:            map[string]string{"namespace": "prod", "pod": fmt.Sprintf("api-%d", i)},
+		Metrics:           map[string]float64{"restarts": 12},
+		Hint:              "Check the previous container logs",
+		RunbookURL:        models.RunbookBaseURL + "crashloopbackoff.md",
+		Query:             `kube_pod_container_status_waiting_reason{reason="CrashLoopBackOff"} == 1`,
+		QueryURL:          "http://prom:9090/graph?g0.expr=...",
+		MetricsFormatted:  map[string]string{"restarts": "12"},
+		IncidentID:        "inc-1",
+		IncidentType:      "node_failure",
+		RelatedIDs:        []string{"node/worker-3/not_ready"},
+		History: &models.HistoryAnnotation{
+			FirstSeenGlobal:  seen.Add(-24 * time.Hour),
+			TotalOccurrences: 4,
+			RecurringSince:   "1d",
+		},
+		Observations: []models.Observation{
+			{Timestamp: seen, Severity: models.SeverityWarning, Count: 1, Metrics: map[string]float64{"restarts": 3}},
+		},
+		EvaluatedAt: seen.Add(5 * time.Minute),
+		StaleSample: true,
+		Allowed:     true,
+		AllowReason: "known flaky canary",
+	}
+}
+
+func TestState_RoundTripPreservesProblems(t *testing.T) {
+	// Guard against fields added to Problem without a value here
+	v := reflect.ValueOf(*fullProblem(0))
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).IsZero() {
+			t.Fatalf("fullProblem leaves %s unset", v.Type().Field(i).Name)
+		}
+	}
+
+	for _, format := range []StateFormat{StateJSON, StateBinary} {
+		t.Run(string(format), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state")
+			problems := []*models.Problem{fullProblem(0), fullProblem(1)}
+			if err := SaveState(path, problems, format); err != nil {
+				t.Fatalf("SaveState() error = %v", err)
+			}
+			loaded, err := LoadState(path)
+			if err != nil {
+				t.Fatalf("LoadState() error = %v", err)
+			}
+			if !reflect.DeepEqual(loaded.Problems, problems) {
+				t.Errorf("problems changed in the round trip:\n got %+v\nwant %+v", loaded.Problems[0], problems[0])
+			}
+			if len(loaded.ProblemIDs) != 2 || loaded.ProblemIDs[0] != problems[0].ID {
+				t.Errorf("problem IDs = %v", loaded.ProblemIDs)
+			}
+		})
+	}
+}
+
+func TestLoadState_ReadsEitherFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	if err := SaveState(path, []*models.Problem{{ID: "a"}}, StateBinary); err != nil {
+		t.Fatal(err)
+	}
+	prev, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+
+	// Switching back to JSON compares against the binary state
+	if delta := DiffState(prev, []*models.Problem{{ID: "a"}}); delta.Changed() {
+		t.Errorf("same set should be unchanged, got %+v", delta)
+	}
+	if err := SaveState(path, []*models.Problem{{ID: "a"}}, StateJSON); err != nil {
+		t.Fatal(err)
+	}
+	if prev, err = LoadState(path); err != nil || len(prev.ProblemIDs) != 1 {
+		t.Errorf("LoadState() = %+v, %v", prev, err)
+	}
+}
+
+func TestParseStateFormat(t *testing.T) {
+	for _, s := range []string{"json", "binary"} {
+		if _, err := ParseStateFormat(s); err != nil {
+			t.Errorf("ParseStateFormat(%q) error = %v", s, err)
+		}
+	}
+	if _, err := ParseStateFormat("protobuf"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func BenchmarkLoadState(b *testing.B) {
+	problems := make([]*models.Problem, 20000)
+	for i := range problems {
+		problems[i] = fullProblem(i)
+	}
+	for _, format := range []StateFormat{StateJSON, StateBinary} {
+		path := filepath.Join(b.TempDir(), "state")
+		if err := SaveState(path, problems, format); err != nil {
+			b.Fatal(err)
+		}
+		b.Run(string(format), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := LoadState(path); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestState_FirstSeen(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := &State{Problems: []*models.Problem{
+		{ID: "a", FirstSeen: t0.Add(time.Hour), OriginalFirstSeen: t0},
+		{ID: "b", FirstSeen: t0.Add(2 * time.Hour)},
+		{ID: "c"}, // No timestamps: not carried over
+	}}
+
+	got := s.FirstSeen()
+	if len(got) != 2 || !got["a"].Equal(t0) || !got["b"].Equal(t0.Add(2*time.Hour)) {
+		t.Errorf("FirstSeen() = %v", got)
+	}
+	if len((&State{ProblemIDs: []string{"a"}}).FirstSeen()) != 0 {
+		t.Error("an ID-only state should carry nothing over")
+	}
+}
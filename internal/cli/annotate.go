@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"context"
+	"time"
+)
+
+// refreshAnnotationCaches refreshes what the TUI annotates problems from once
+// per refresh interval until ctx is done. The TUI filter runs on every tick,
+// update and key press; querying there would freeze the screen for up to
+// --prometheus-timeout each time.
+func refreshAnnotationCaches(ctx context.Context, interval time.Duration) {
+	if len(deploySources) == 0 && serviceProvider == nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		refreshDeployCache()
+		refreshServiceCache()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/deploy"
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/service"
+)
+
+// countingDeploySource returns a fixed deploy and counts how often it is read
+type countingDeploySource struct {
+	deploy deploy.Deploy
+	calls  atomic.Int32
+}
+
+func (s *countingDeploySource) Deploys(context.Context) ([]deploy.Deploy, error) {
+	s.calls.Add(1)
+	return []deploy.Deploy{s.deploy}, nil
+}
+
+func TestTUIProblems_DeploysFromCache(t *testing.T) {
+	oldSources, oldWindow := deploySources, deployWindow
+	t.Cleanup(func() {
+		deploySources, deployWindow = oldSources, oldWindow
+		deployCache.deploys = nil
+	})
+	deployWindow = deploy.DefaultWindow
+	now := time.Now()
+	src := &countingDeploySource{deploy: deploy.Deploy{Timestamp: now.Add(-5 * time.Minute), Service: "api", Version: "v1.4.2"}}
+	deploySources = []deploy.Source{src}
+
+	problems := func() []*models.Problem {
+		return []*models.Problem{{
+			ID: "crashloopbackoff/prod/api", Entity: "prod/api", Type: "crashloopbackoff", Severity: models.SeverityCritical,
+			FirstSeen: now, Labels: map[string]string{"namespace": "prod", "deployment": "api"},
+		}}
+	}
+
+	// Refreshing the TUI must not read the sources
+	for range 3 {
+		if got := tuiProblems(problems()); got[0].Labels[deploy.LikelyCauseLabel] != "" {
+			t.Errorf("annotated before the cache was filled: %v", got[0].Labels)
+		}
+	}
+	if n := src.calls.Load(); n != 0 {
+		t.Fatalf("TUI filter read the deploy sources %d times", n)
+	}
+
+	// One background refresh, then the filter annotates from the cache
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	refreshAnnotationCaches(ctx, time.Minute)
+	if n := src.calls.Load(); n != 1 {
+		t.Errorf("refresh read the sources %d times, want 1", n)
+	}
+	got := tuiProblems(problems())
+	if cause := got[0].Labels[deploy.LikelyCauseLabel]; cause != "deploy v1.4.2" {
+		t.Errorf("likely_cause = %q, want deploy v1.4.2", cause)
+	}
+	if n := src.calls.Load(); n != 1 {
+		t.Errorf("TUI filter read the deploy sources after the refresh: %d calls", n)
+	}
+}
+
+func TestTUIProblems_ServicesFromCache(t *testing.T) {
+	oldProvider := serviceProvider
+	t.Cleanup(func() {
+		serviceProvider = oldProvider
+		serviceCache.pods = nil
+	})
+	var queries atomic.Int32
+	serviceProvider = &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			queries.Add(1)
+			return model.Vector{{Metric: model.Metric{"namespace": "prod", "pod": "api-7d9f8b-x2k4p", "label_app": "checkout"}, Value: 1}}, nil
+		},
+	}
+
+	problems := func() []*models.Problem {
+		return []*models.Problem{{
+			ID: "crashloopbackoff/prod/api-7d9f8b-x2k4p", Entity: "prod/api-7d9f8b-x2k4p", Type: "crashloopbackoff", Severity: models.SeverityCritical,
+			Labels: map[string]string{"namespace": "prod", "pod": "api-7d9f8b-x2k4p"},
+		}}
+	}
+
+	for range 3 {
+		tuiProblems(problems())
+	}
+	if n := queries.Load(); n != 0 {
+		t.Fatalf("TUI filter queried pod labels %d times", n)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	refreshAnnotationCaches(ctx, time.Minute)
+	got := tuiProblems(problems())
+	if svc := got[0].Labels[service.Label]; svc != "checkout" {
+		t.Errorf("service = %q, want checkout from the cached pod labels", svc)
+	}
+	if n := queries.Load(); n != 1 {
+		t.Errorf("pod labels queried %d times, want once by the refresh", n)
+	}
+}
@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ppiankov/infranow/internal/config"
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/monitor"
+)
+
+var (
+	// activeConfig is the last successfully loaded config file. It is replaced
+	// atomically on hot-reload; readers must go through currentConfig.
+	activeConfigMu sync.RWMutex
+	activeConfig   = &config.Config{}
+)
+
+// currentConfig returns the active config file settings
+func currentConfig() *config.Config {
+	activeConfigMu.RLock()
+	defer activeConfigMu.RUnlock()
+	return activeConfig
+}
+
+func setActiveConfig(cfg *config.Config) {
+	activeConfigMu.Lock()
+	defer activeConfigMu.Unlock()
+	activeConfig = cfg
+}
+
+// loadConfigFile loads the config file selected by --config (or the default
+// path if it exists). Returns the resolved path, empty if no config is in use.
+func loadConfigFile() (string, error) {
+	path := config.ResolvePath(configFile)
+	if path == "" {
+		return "", nil
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := buildRegistry(cfg); err != nil {
+		return "", fmt.Errorf("config %s: %w", path, err)
+	}
+
+	setActiveConfig(cfg)
+	return path, nil
+}
+
+// buildRegistry creates a registry with all default detectors plus the
+// optional ones enabled in the config file or named by --detectors, then
+// applies --sustained-window and the config file: disabled detectors are
+// removed and thresholds overridden. Window overrides and detector labels are
+// only checked here, the watcher applies them. With --detectors only the
+// listed ones are kept.
+func buildRegistry(cfg *config.Config) (*detector.Registry, error) {
+	registry := detector.DefaultRegistry()
+
+	enabled := make(map[string]bool, len(cfg.EnabledDetectors)+len(onlyDetectors))
+	for _, name := range cfg.EnabledDetectors {
+		if !detector.IsOptional(name) {
+			return nil, fmt.Errorf("enabled_detectors: %q is not an optional detector", name)
+		}
+		enabled[name] = true
+	}
+	for _, name := range onlyDetectors {
+		enabled[name] = true
+	}
+	for _, d := range detector.Optional() {
+		if enabled[d.Name()] {
+			registry.Register(d)
+		}
+	}
+
+	if sustainedWindow > 0 {
+		for _, d := range registry.All() {
+			if s, ok := d.(detector.Sustainable); ok {
+				s.SetSustainedWindow(sustainedWindow)
+			}
+		}
+	}
+
+	for _, name := range cfg.DisabledDetectors {
+		if _, ok := registry.Get(name); !ok {
+			return nil, fmt.Errorf("disabled_detectors: unknown detector %q", name)
+		}
+		registry.Unregister(name)
+	}
+
+	names := make([]string, 0, len(cfg.Thresholds))
+	for name := range cfg.Thresholds {
+		names = append(names, name)
+	}
+	sort.Strings(names) // deterministic error reporting
+
+	for _, name := range names {
+		d, ok := registry.Get(name)
+		if !ok {
+			if cfg.IsDisabled(name) || detector.IsOptional(name) {
+				continue
+			}
+			return nil, fmt.Errorf("thresholds: unknown detector %q", name)
+		}
+		tunable, ok := d.(detector.Tunable)
+		if !ok {
+			return nil, fmt.Errorf("thresholds: detector %q has no tunable threshold", name)
+		}
+		tunable.SetThreshold(cfg.Thresholds[name])
+	}
+
+	names = names[:0]
+	for name := range cfg.Windows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := registry.Get(name); !ok && !cfg.IsDisabled(name) && !detector.IsOptional(name) {
+			return nil, fmt.Errorf("windows: unknown detector %q", name)
+		}
+	}
+
+	names = names[:0]
+	for name := range cfg.DetectorLabels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := registry.Get(name); !ok && !cfg.IsDisabled(name) && !detector.IsOptional(name) {
+			return nil, fmt.Errorf("detector_labels: unknown detector %q", name)
+		}
+	}
+
+	if len(onlyDetectors) > 0 {
+		keep := make(map[string]bool, len(onlyDetectors))
+		for _, name := range onlyDetectors {
+			if _, ok := registry.Get(name); !ok {
+				return nil, fmt.Errorf("--detectors: unknown or disabled detector %q", name)
+			}
+			keep[name] = true
+		}
+		for _, d := range registry.All() {
+			if !keep[d.Name()] {
+				registry.Unregister(d.Name())
+			}
+		}
+	}
+
+	if d, ok := registry.Get("ingress_error_rate"); ok && cfg.IngressMetrics != nil {
+		d.(*detector.IngressErrorRateDetector).SetMetrics(detector.IngressMetrics{
+			Requests:       cfg.IngressMetrics.Requests,
+			StatusLabel:    cfg.IngressMetrics.StatusLabel,
+			HostLabel:      cfg.IngressMetrics.HostLabel,
+			IngressLabel:   cfg.IngressMetrics.IngressLabel,
+			NamespaceLabel: cfg.IngressMetrics.NamespaceLabel,
+		})
+	}
+
+	if d, ok := registry.Get("trustwatch_cert_expiry"); ok {
+		certs := d.(*detector.TrustwatchCertExpiryDetector)
+		for source, radius := range cfg.TrustwatchBlastRadius {
+			certs.SetSourceBlastRadius(source, radius)
+		}
+		for _, source := range cfg.TrustwatchSkipWarning {
+			certs.SkipSourceWarnings(source)
+		}
+	}
+
+	return registry, nil
+}
+
+// applyProfile sets every flag the profile configures, except flags given
+// explicitly on the command line
+func applyProfile(flags *pflag.FlagSet, p *config.Profile) error {
+	values := p.Flags()
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if flags.Changed(name) {
+			continue
+		}
+		if err := flags.Set(name, values[name]); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// severityOverrides converts the config file's severity_overrides for the
+// watcher. Values were validated when the config was loaded.
+func severityOverrides(cfg *config.Config) []monitor.SeverityOverride {
+	overrides := make([]monitor.SeverityOverride, 0, len(cfg.SeverityOverrides))
+	for _, o := range cfg.SeverityOverrides {
+		severity, _ := models.ParseSeverity(o.Severity)
+		override := monitor.SeverityOverride{Types: o.Types, Severity: severity}
+		if sched := o.Schedule; sched != nil {
+			loc, _ := sched.Location()
+			override.Active = func(now time.Time) bool {
+				return sched.Contains(now.In(loc))
+			}
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides
+}
+
+// namespaceCriticality converts the config file's namespace_criticality for
+// the watcher
+func namespaceCriticality(cfg *config.Config) []monitor.NamespaceCriticality {
+	rules := make([]monitor.NamespaceCriticality, 0, len(cfg.NamespaceCriticality))
+	for _, rule := range cfg.NamespaceCriticality {
+		rules = append(rules, monitor.NamespaceCriticality{Patterns: rule.Patterns(), Levels: rule.Adjust})
+	}
+	return rules
+}
+
+// watchConfigFile hot-reloads the config file into the running watcher.
+// Invalid configs are logged to log and the previous good config stays
+// active.
+func watchConfigFile(ctx context.Context, path string, watcher *monitor.Watcher, log io.Writer) error {
+	onReload := func(cfg *config.Config) {
+		registry, err := buildRegistry(cfg)
+		if err != nil {
+			fmt.Fprintf(log, "[infranow] config reload rejected, keeping previous config: %v\n", err)
+			return
+		}
+		setActiveConfig(cfg)
+		watcher.SetProblemTTLs(cfg.ProblemTTLs())
+		watcher.SetDetectorWindows(cfg.DetectorWindows())
+		watcher.SetSeverityOverrides(severityOverrides(cfg))
+		watcher.SetNamespaceCriticality(namespaceCriticality(cfg))
+		watcher.SetLabelNormalization(cfg.LabelNormalization)
+		templates, _ := cfg.ProblemTemplates() // Validated on load
+		watcher.SetProblemTemplates(templates)
+		watcher.SetDetectorLabels(cfg.DetectorLabels)
+		watcher.Reload(registry)
+		fmt.Fprintf(log, "[infranow] config reloaded from %s (%d detectors)\n", path, registry.Count())
+	}
+	onError := func(err error) {
+		fmt.Fprintf(log, "[infranow] config reload failed, keeping previous config: %v\n", err)
+	}
+	return config.Watch(ctx, path, onReload, onError)
+}
@@ -0,0 +1,443 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/config"
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/filter"
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/monitor"
+)
+
+func TestBuildRegistry_AppliesConfig(t *testing.T) {
+	cfg := &config.Config{
+		DisabledDetectors: []string{"kubernetes_pending"},
+		Thresholds:        map[string]float64{"pg_replication_lag": 90},
+	}
+
+	registry, err := buildRegistry(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := registry.Get("kubernetes_pending"); ok {
+		t.Error("disabled detector should not be registered")
+	}
+
+	d, ok := registry.Get("pg_replication_lag")
+	if !ok {
+		t.Fatal("pg_replication_lag should be registered")
+	}
+	if got := d.(detector.Tunable).Threshold(); got != 90 {
+		t.Errorf("threshold = %g, want 90", got)
+	}
+}
+
+func TestBuildRegistry_OptionalDetectors(t *testing.T) {
+	saved := onlyDetectors
+	t.Cleanup(func() { onlyDetectors = saved })
+
+	registry, err := buildRegistry(&config.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := registry.Get("kubernetes_missing_limits"); ok {
+		t.Error("optional detector should be off by default")
+	}
+
+	registry, err = buildRegistry(&config.Config{EnabledDetectors: []string{"kubernetes_missing_limits"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := registry.Get("kubernetes_missing_limits"); !ok {
+		t.Error("enabled_detectors should register the optional detector")
+	}
+	if _, ok := registry.Get("kubernetes_oom_kills"); !ok {
+		t.Error("enabling an optional detector must keep the defaults")
+	}
+
+	onlyDetectors = []string{"kubernetes_missing_limits"}
+	registry, err = buildRegistry(&config.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := detectorNames(registry); len(got) != 1 || got[0] != "kubernetes_missing_limits" {
+		t.Errorf("--detectors should select an optional detector, got %v", got)
+	}
+}
+
+func TestBuildRegistry_TrustwatchBlastRadius(t *testing.T) {
+	registry, err := buildRegistry(&config.Config{
+		TrustwatchBlastRadius: map[string]int{"webhook": 40},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d, ok := registry.Get("trustwatch_cert_expiry")
+	if !ok {
+		t.Fatal("trustwatch_cert_expiry should be registered")
+	}
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{&model.Sample{
+				Metric: model.Metric{"source": "webhook", "namespace": "ns", "name": "hook"},
+				Value:  3600,
+			}}, nil
+		},
+	}
+	problems, err := d.Detect(context.Background(), provider, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 1 || problems[0].BlastRadius != 40 {
+		t.Errorf("problems = %+v, want one with blast radius 40", problems)
+	}
+}
+
+func TestBuildRegistry_IngressMetrics(t *testing.T) {
+	registry, err := buildRegistry(&config.Config{
+		IngressMetrics: &config.IngressMetrics{Requests: "edge_requests_total"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d, ok := registry.Get("ingress_error_rate")
+	if !ok {
+		t.Fatal("ingress_error_rate should be registered")
+	}
+	var query string
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, q string, ts time.Time) (model.Vector, error) {
+			query = q
+			return model.Vector{}, nil
+		},
+	}
+	if _, err := d.Detect(context.Background(), provider, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(query, `edge_requests_total{status=~"5.."}`) {
+		t.Errorf("query %s does not use the configured metric with the default status label", query)
+	}
+}
+
+func TestBuildRegistry_SustainedWindow(t *testing.T) {
+	sustainedWindow = 5 * time.Minute
+	defer func() { sustainedWindow = 0 }()
+
+	registry, err := buildRegistry(&config.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d, ok := registry.Get("generic_memory_pressure")
+	if !ok {
+		t.Fatal("generic_memory_pressure should be registered")
+	}
+	var gotRange time.Duration
+	provider := &metrics.MockProvider{
+		QueryRangeFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (model.Matrix, error) {
+			gotRange = end.Sub(start)
+			return nil, nil
+		},
+	}
+	if _, err := d.Detect(context.Background(), provider, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if gotRange != 5*time.Minute {
+		t.Errorf("range query over %v, want the 5m sustained window", gotRange)
+	}
+}
+
+func TestEffectiveConfig_ReflectsOverrides(t *testing.T) {
+	cfg := &config.Config{
+		DisabledDetectors: []string{"kubernetes_pending"},
+		Thresholds:        map[string]float64{"pg_replication_lag": 90},
+		Windows:           map[string]config.Duration{"kubernetes_oom_kills": config.Duration(20 * time.Minute)},
+		IncludeNamespaces: "prod-*",
+	}
+	registry, err := buildRegistry(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	eff := effectiveConfig("/tmp/infranow.yaml", cfg, registry)
+
+	byName := map[string]EffectiveDetector{}
+	for _, d := range eff.Detectors {
+		byName[d.Name] = d
+	}
+	if _, ok := byName["kubernetes_pending"]; ok {
+		t.Error("disabled detector should not be listed as active")
+	}
+	if len(eff.Disabled) != 1 || eff.Disabled[0] != "kubernetes_pending" {
+		t.Errorf("disabled_detectors = %v, want [kubernetes_pending]", eff.Disabled)
+	}
+	lag, ok := byName["pg_replication_lag"]
+	if !ok || lag.Threshold == nil || *lag.Threshold != 90 {
+		t.Errorf("pg_replication_lag = %+v, want threshold 90", lag)
+	}
+	if lag.Interval == "" {
+		t.Error("detector interval should be reported")
+	}
+	if got := byName["kubernetes_oom_kills"].Window; got != "20m0s" {
+		t.Errorf("kubernetes_oom_kills window = %q, want the configured 20m0s", got)
+	}
+	if got := byName["kubernetes_restart_rate"].Window; got != "15m0s" {
+		t.Errorf("kubernetes_restart_rate window = %q, want its 15m0s default", got)
+	}
+	if eff.Filters.IncludeNamespaces != "prod-*" {
+		t.Errorf("include_namespaces = %q, want config file value", eff.Filters.IncludeNamespaces)
+	}
+	if eff.ConfigFile != "/tmp/infranow.yaml" {
+		t.Errorf("config_file = %q", eff.ConfigFile)
+	}
+}
+
+func TestSeverityOverrides_ScheduleTimezone(t *testing.T) {
+	cfg, err := config.Parse([]byte(`
+severity_overrides:
+  - types: [imagepullbackoff]
+    severity: FATAL
+    schedule: {days: [mon], start: "09:00", end: "18:00", timezone: America/New_York}
+  - types: [oom_kill]
+    severity: CRITICAL
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	overrides := severityOverrides(cfg)
+	if len(overrides) != 2 || overrides[0].Severity != models.SeverityFatal || overrides[1].Active != nil {
+		t.Fatalf("overrides = %+v", overrides)
+	}
+
+	// 14:00 UTC Monday is 09:00 in New York (EST): inside; 13:00 UTC is not
+	if !overrides[0].Active(time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC)) {
+		t.Error("schedule should be evaluated in its time zone")
+	}
+	if overrides[0].Active(time.Date(2026, 3, 2, 13, 0, 0, 0, time.UTC)) {
+		t.Error("08:00 New York time is outside business hours")
+	}
+}
+
+func TestBuildRegistry_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.Config
+	}{
+		{"unknown disabled detector", &config.Config{DisabledDetectors: []string{"nope"}}},
+		{"unknown threshold detector", &config.Config{Thresholds: map[string]float64{"nope": 1}}},
+		{"detector without threshold", &config.Config{Thresholds: map[string]float64{"kubernetes_oom_kills": 1}}},
+		{"enabled default detector", &config.Config{EnabledDetectors: []string{"kubernetes_oom_kills"}}},
+		{"unknown labeled detector", &config.Config{DetectorLabels: map[string]map[string]string{"nope": {"category": "capacity"}}}},
+		{"unknown window detector", &config.Config{Windows: map[string]config.Duration{"nope": config.Duration(time.Minute)}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := buildRegistry(tt.cfg); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestWatchConfigFile_RebuildsRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "infranow.yaml")
+	if err := os.WriteFile(path, []byte("{}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		setActiveConfig(&config.Config{})
+	})
+
+	registry, err := buildRegistry(&config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := registry.Count()
+
+	watcher := monitor.NewWatcher(&metrics.MockProvider{}, registry, 0, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = watcher.Start(ctx)
+	}()
+
+	if err := watchConfigFile(ctx, path, watcher, io.Discard); err != nil {
+		t.Fatalf("watchConfigFile: %v", err)
+	}
+
+	// Invalid config is rejected and the previous registry stays active
+	if err := os.WriteFile(path, []byte("disabled_detectors: [nope]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	if got := len(watcher.DetectorNames()); got != total {
+		t.Fatalf("detectors after invalid config = %d, want %d", got, total)
+	}
+
+	if err := os.WriteFile(path, []byte("disabled_detectors: [kubernetes_pending]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(watcher.DetectorNames()) == total-1 {
+			if !currentConfig().IsDisabled("kubernetes_pending") {
+				t.Error("active config should be updated after reload")
+			}
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("registry not rebuilt: %d detectors, want %d", len(watcher.DetectorNames()), total-1)
+}
+
+func TestApplyProfile_BundlesSettings(t *testing.T) {
+	// Registering the flags again resets every flag variable to its default
+	t.Cleanup(func() { NewRootCommand("test", "none", "unknown") })
+
+	cmd := NewMonitorCommand()
+	if err := cmd.Flags().Parse([]string{"--include-namespaces", "payments-eu"}); err != nil {
+		t.Fatal(err)
+	}
+	profile := &config.Profile{
+		IncludeNamespaces: "payments-*",
+		ExcludeEntity:     "-canary-",
+		MinSeverity:       "CRITICAL",
+		MinBlastRadius:    3,
+		Detectors:         []string{"kubernetes_oom_kills", "kubernetes_crashloop"},
+	}
+	if err := applyProfile(cmd.Flags(), profile); err != nil {
+		t.Fatalf("applyProfile() error = %v", err)
+	}
+
+	if includeNamespaces != "payments-eu" {
+		t.Errorf("explicit --include-namespaces overridden: %q", includeNamespaces)
+	}
+	if excludeEntity != "-canary-" || minSeverity != "CRITICAL" || minBlastRadius != 3 {
+		t.Errorf("profile not applied: exclude-entity %q, min-severity %q, min-blast-radius %d",
+			excludeEntity, minSeverity, minBlastRadius)
+	}
+
+	registry, err := buildRegistry(&config.Config{})
+	if err != nil {
+		t.Fatalf("buildRegistry() error = %v", err)
+	}
+	if got := detectorNames(registry); len(got) != 2 || got[0] != "kubernetes_crashloop" || got[1] != "kubernetes_oom_kills" {
+		t.Errorf("registered detectors = %v, want only the profile's", got)
+	}
+}
+
+func TestBuildRegistry_UnknownSelectedDetector(t *testing.T) {
+	saved := onlyDetectors
+	t.Cleanup(func() { onlyDetectors = saved })
+
+	onlyDetectors = []string{"kubernetes_oom_kill"}
+	if _, err := buildRegistry(&config.Config{}); err == nil {
+		t.Error("expected error for an unknown --detectors name")
+	}
+}
+
+func TestApplyFilters_MinSeverity(t *testing.T) {
+	saved := minSeverityLevel
+	t.Cleanup(func() { minSeverityLevel = saved })
+
+	minSeverityLevel = models.SeverityCritical
+	problems := applyFilters([]*models.Problem{
+		{ID: "warn", Entity: "prod/a", Severity: models.SeverityWarning},
+		{ID: "crit", Entity: "prod/b", Severity: models.SeverityCritical},
+	})
+	if len(problems) != 1 || problems[0].ID != "crit" {
+		t.Errorf("expected only the CRITICAL problem, got %d", len(problems))
+	}
+}
+
+func TestApplyFilters_Mutes(t *testing.T) {
+	t.Cleanup(func() { mutes = nil })
+
+	mutes = filter.NewMutes(nil)
+	mutes.Mute("crash", time.Hour)
+	problems := applyFilters([]*models.Problem{
+		{ID: "crash", Entity: "prod/a", Severity: models.SeverityCritical},
+		{ID: "disk", Entity: "prod/b", Severity: models.SeverityWarning},
+	})
+	if len(problems) != 1 || problems[0].ID != "disk" {
+		t.Errorf("expected the muted problem kept out of counts and notifications, got %d", len(problems))
+	}
+}
+
+func TestFilterProblems_CountsAllowListAndMutes(t *testing.T) {
+	savedAllow := allowList
+	t.Cleanup(func() { allowList, mutes = savedAllow, nil })
+
+	allowList = &filter.AllowList{Rules: []filter.AllowRule{{Entity: "sandbox/*", Reason: "demo"}}}
+	mutes = filter.NewMutes(nil)
+	mutes.Mute("crash", time.Hour)
+	problems, stats := filterProblems([]*models.Problem{
+		{ID: "crash", Entity: "prod/a", Severity: models.SeverityCritical},
+		{ID: "demo", Entity: "sandbox/b", Severity: models.SeverityCritical},
+		{ID: "disk", Entity: "prod/c", Severity: models.SeverityWarning},
+	})
+	if len(problems) != 1 || stats.AllowList != 1 || stats.Muted != 1 || stats.Total() != 2 {
+		t.Errorf("kept %d, stats %+v; want 1 kept, 1 allow-listed, 1 muted", len(problems), stats)
+	}
+}
+
+func TestApplyFilters_IncludeUnnamespaced(t *testing.T) {
+	savedInclude, savedUnnamespaced := includeNamespaces, includeUnnamespaced
+	t.Cleanup(func() { includeNamespaces, includeUnnamespaced = savedInclude, savedUnnamespaced })
+
+	problems := []*models.Problem{
+		{ID: "team", Entity: "team-x/api-1", Severity: models.SeverityWarning, Labels: map[string]string{"namespace": "team-x"}},
+		{ID: "other", Entity: "team-y/api-1", Severity: models.SeverityWarning, Labels: map[string]string{"namespace": "team-y"}},
+		{ID: "node", Entity: "node-1", Severity: models.SeverityCritical, Labels: map[string]string{"node": "node-1"}},
+	}
+
+	includeNamespaces = "team-x"
+	includeUnnamespaced = true
+	if got := problemIDs(applyFilters(problems)); got != "team,node" {
+		t.Errorf("with --include-unnamespaced: %s, want team,node", got)
+	}
+
+	includeUnnamespaced = false
+	if got := problemIDs(applyFilters(problems)); got != "team" {
+		t.Errorf("with --include-unnamespaced=false: %s, want team", got)
+	}
+}
+
+// problemIDs joins the IDs of problems in order
+func problemIDs(problems []*models.Problem) string {
+	ids := make([]string, 0, len(problems))
+	for _, p := range problems {
+		ids = append(ids, p.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestApplyFilters_KeepsUnavailableProblemInEveryScope(t *testing.T) {
+	savedInclude, savedReport := includeNamespaces, reportUnavailable
+	t.Cleanup(func() { includeNamespaces, reportUnavailable = savedInclude, savedReport })
+
+	includeNamespaces = "payments-*"
+	reportUnavailable = true
+	problems := applyFilters([]*models.Problem{
+		{ID: "other", Entity: "prod/a", Severity: models.SeverityCritical, Labels: map[string]string{"namespace": "prod"}},
+		{ID: "down", Entity: "monitoring", Type: monitor.UnavailableProblemType, Severity: models.SeverityFatal, BlastRadius: 100},
+	})
+	if len(problems) != 1 || problems[0].ID != "down" {
+		t.Errorf("expected only the monitoring outage past the namespace filter, got %d problems", len(problems))
+	}
+}
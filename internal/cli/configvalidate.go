@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ppiankov/infranow/internal/config"
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/util"
+)
+
+// NewConfigCommand creates the config subcommand
+func NewConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Work with the infranow config file",
+	}
+
+	cmd.AddCommand(newConfigValidateCommand())
+	return cmd
+}
+
+func newConfigValidateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Check a config file without connecting to Prometheus",
+		Long: `Validate parses the config file selected by --config (default
+$HOME/.infranow.yaml) and builds the detector registry from it the way
+monitor does: unknown keys, invalid values, unknown or non-tunable detectors,
+bad templates and profiles naming unknown detectors are reported with the
+line and field they concern. Prometheus is not contacted, so it can run in CI.
+Exits 0 if the config is valid, 3 otherwise.`,
+		Args: cobra.NoArgs,
+		RunE: runConfigValidate,
+	}
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	path := config.ResolvePath(configFile)
+	if path == "" {
+		return util.InvalidInputf("no config file: pass --config or create $HOME/%s", config.DefaultFileName)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return util.InvalidInputf("read config: %w", err)
+	}
+
+	registry, err := validateConfig(data)
+	if err != nil {
+		return util.InvalidInputf("config %s: %w", path, config.Locate(data, err))
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "config %s is valid (%d detectors)\n", path, registry.Count())
+	return nil
+}
+
+// validateConfig parses config data and builds its detector registry as
+// monitor would, then checks the detectors profiles select, which monitor
+// only checks once a profile is used
+func validateConfig(data []byte) (*detector.Registry, error) {
+	cfg, err := config.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	registry, err := buildRegistry(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names) // deterministic error reporting
+	for _, name := range names {
+		for _, d := range cfg.Profiles[name].Detectors {
+			if cfg.IsDisabled(d) {
+				return nil, fmt.Errorf("profiles.%s.detectors: detector %q is disabled", name, d)
+			}
+			if _, ok := registry.Get(d); !ok && !detector.IsOptional(d) {
+				return nil, fmt.Errorf("profiles.%s.detectors: unknown detector %q", name, d)
+			}
+		}
+	}
+	return registry, nil
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ppiankov/infranow/internal/util"
+)
+
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "infranow.yaml")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigValidate_Valid(t *testing.T) {
+	path := writeConfig(t, `
+disabled_detectors: [kubernetes_pending]
+enabled_detectors: [gpu_health]
+thresholds:
+  pg_replication_lag: 90
+  gpu_health: 0.95
+windows:
+  kubernetes_oom_kills: 15m
+profiles:
+  ci:
+    fail_on: CRITICAL
+    detectors: [kubernetes_oom_kills, kubernetes_missing_limits]
+`)
+	if err := executeRoot(t, "config", "validate", "--config", path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// The config file example in the README stays valid as detectors change
+func TestConfigValidate_READMEExample(t *testing.T) {
+	readme, err := os.ReadFile(filepath.Join("..", "..", "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const marker = "# ~/.infranow.yaml (or --config path)\n"
+	_, example, ok := strings.Cut(string(readme), marker)
+	if !ok {
+		t.Fatal("config file example not found in README.md")
+	}
+	example, _, _ = strings.Cut(example, "```")
+
+	if err := executeRoot(t, "config", "validate", "--config", writeConfig(t, example)); err != nil {
+		t.Errorf("README config example is invalid: %v", err)
+	}
+}
+
+func TestConfigValidate_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			"yaml syntax",
+			"thresholds:\n  pg_replication_lag: 90\n bad: [\n",
+			"yaml: line",
+		},
+		{
+			"unknown key",
+			"thresholds:\n  pg_replication_lag: 90\ndisabled_detector: [kubernetes_pending]\n",
+			`line 3: parse config: error unmarshaling JSON: while decoding JSON: json: unknown field "disabled_detector"`,
+		},
+		{
+			"negative threshold",
+			"thresholds:\n  pg_replication_lag: -5\n",
+			"line 2: thresholds.pg_replication_lag: must be positive, got -5",
+		},
+		{
+			"unknown disabled detector",
+			"disabled_detectors:\n  - kubernetes_pending\n  - kubernetes_pendng\n",
+			`line 3: disabled_detectors: unknown detector "kubernetes_pendng"`,
+		},
+		{
+			"detector without threshold",
+			"thresholds:\n  kubernetes_oom_kills: 3\n",
+			`line 2: thresholds: detector "kubernetes_oom_kills" has no tunable threshold`,
+		},
+		{
+			"bad severity override",
+			"severity_overrides:\n  - types: [oom_kill]\n    severity: FATAL\n  - types: [imagepullbackoff]\n    severity: SEVERE\n",
+			"line 4: severity_overrides[1]:",
+		},
+		{
+			"bad template",
+			"templates:\n  oom_kill:\n    message: \"{{.Labels.pod\"\n",
+			"line 3: templates.oom_kill.message:",
+		},
+		{
+			"profile with unknown detector",
+			"profiles:\n  ci:\n    detectors:\n      - kubernetes_oom_kills\n      - nope\n",
+			`line 5: profiles.ci.detectors: unknown detector "nope"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executeRoot(t, "config", "validate", "--config", writeConfig(t, tt.data))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if code := util.ExitCode(err); code != util.ExitInvalidInput {
+				t.Errorf("exit code = %d, want %d", code, util.ExitInvalidInput)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigValidate_MissingFile(t *testing.T) {
+	err := executeRoot(t, "config", "validate", "--config", filepath.Join(t.TempDir(), "missing.yaml"))
+	if code := util.ExitCode(err); code != util.ExitInvalidInput {
+		t.Errorf("exit code = %d, want %d (err %v)", code, util.ExitInvalidInput, err)
+	}
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ppiankov/infranow/internal/deploy"
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// deploySources are consulted by annotateDeploys (empty = disabled)
+var deploySources []deploy.Source
+
+// deployCache holds the deploys last fetched by refreshDeployCache, so the
+// TUI annotates without querying on its event loop
+var deployCache struct {
+	sync.RWMutex
+	deploys []deploy.Deploy
+}
+
+// setupDeploySources configures deploy correlation from --deploys-file and --deploy-metric
+func setupDeploySources(provider metrics.MetricsProvider) {
+	deploySources = nil
+	if deploysFile != "" {
+		deploySources = append(deploySources, deploy.FileSource{Path: deploysFile})
+	}
+	if deployMetric != "" {
+		deploySources = append(deploySources, deploy.MetricSource{Provider: provider, Query: deployMetric})
+	}
+}
+
+// annotateDeploys tags problems that started shortly after a deploy.
+// Source failures are logged and skipped; enrichment never blocks output.
+func annotateDeploys(problems []*models.Problem) []*models.Problem {
+	if len(deploySources) == 0 {
+		return problems
+	}
+	deploy.Annotate(problems, fetchDeploys(), deployWindow)
+	return problems
+}
+
+// annotateCachedDeploys is annotateDeploys from the deploys last fetched by
+// refreshDeployCache, for the TUI filter that runs on every key press
+func annotateCachedDeploys(problems []*models.Problem) []*models.Problem {
+	if len(deploySources) == 0 {
+		return problems
+	}
+	deployCache.RLock()
+	defer deployCache.RUnlock()
+	deploy.Annotate(problems, deployCache.deploys, deployWindow)
+	return problems
+}
+
+// refreshDeployCache fetches the deploys for annotateCachedDeploys
+func refreshDeployCache() {
+	if len(deploySources) == 0 {
+		return
+	}
+	deploys := fetchDeploys()
+	deployCache.Lock()
+	defer deployCache.Unlock()
+	deployCache.deploys = deploys
+}
+
+// fetchDeploys reads every deploy source, logging and skipping failures
+func fetchDeploys() []deploy.Deploy {
+	ctx, cancel := context.WithTimeout(context.Background(), prometheusTimeout)
+	defer cancel()
+
+	var deploys []deploy.Deploy
+	for _, src := range deploySources {
+		d, err := src.Deploys(ctx)
+		if err != nil {
+			fmt.Fprintf(backgroundLog, "[infranow] deploy correlation skipped: %v\n", err)
+			continue
+		}
+		deploys = append(deploys, d...)
+	}
+	return deploys
+}
@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"sort"
+
+	"github.com/ppiankov/infranow/internal/config"
+	"github.com/ppiankov/infranow/internal/detector"
+)
+
+// EffectiveConfig is the fully resolved monitor configuration after merging
+// flags, environment and the config file, printed by --print-config
+type EffectiveConfig struct {
+	ConfigFile string              `json:"config_file,omitempty"`
+	Profile    string              `json:"profile,omitempty"`
+	Backend    EffectiveBackend    `json:"backend"`
+	Output     string              `json:"output"`
+	Refresh    string              `json:"refresh_interval"`
+	PersistCap float64             `json:"persistence_cap"` // 0 = uncapped
+	Recency    float64             `json:"recency_weight"`  // 0 = off
+	Detectors  []EffectiveDetector `json:"detectors"`
+	Disabled   []string            `json:"disabled_detectors,omitempty"`
+	Filters    EffectiveFilters    `json:"filters"`
+	FailOn     EffectiveFailOn     `json:"fail_on"`
+	TTLs       map[string]string   `json:"ttls,omitempty"`
+	Notify     *EffectiveNotify    `json:"notify,omitempty"`
+}
+
+// EffectiveBackend describes where metrics are queried
+type EffectiveBackend struct {
+	PrometheusURL string   `json:"prometheus_url,omitempty"` // Credentials redacted
+	QueryPath     string   `json:"query_path,omitempty"`     // Empty = auto-discover
+	K8sService    string   `json:"k8s_service,omitempty"`
+	K8sPod        string   `json:"k8s_pod,omitempty"`
+	K8sSelector   string   `json:"k8s_selector,omitempty"`
+	K8sNamespace  string   `json:"k8s_namespace,omitempty"`
+	Tenants       []string `json:"tenants,omitempty"`
+	OpenShift     bool     `json:"openshift,omitempty"`
+	Timeout       string   `json:"timeout"`
+}
+
+// EffectiveDetector is one registered detector and its tuning
+type EffectiveDetector struct {
+	Name            string   `json:"name"`
+	Interval        string   `json:"interval"`
+	Window          string   `json:"window"` // Range queried over
+	Threshold       *float64 `json:"threshold,omitempty"`
+	SustainedWindow string   `json:"sustained_window,omitempty"`
+}
+
+// EffectiveFilters are the post-detection filters
+type EffectiveFilters struct {
+	IncludeNamespaces string `json:"include_namespaces,omitempty"`
+	ExcludeNamespaces string `json:"exclude_namespaces,omitempty"`
+
+	// Problems without a namespace pass the namespace filters
+	IncludeUnnamespaced bool `json:"include_unnamespaced"`
+
+	IncludeEntity  string `json:"include_entity,omitempty"`
+	ExcludeEntity  string `json:"exclude_entity,omitempty"`
+	EntityType     string `json:"entity_type,omitempty"`
+	MinSeverity    string `json:"min_severity"`
+	MinBlastRadius int    `json:"min_blast_radius,omitempty"`
+
+	// Escalated per-type aggregates (see --aggregate-threshold)
+	AggregateThreshold int  `json:"aggregate_threshold,omitempty"`
+	AggregateCollapse  bool `json:"aggregate_collapse,omitempty"`
+
+	// Per-container problems merged per pod (see --coalesce-containers)
+	CoalesceContainers bool `json:"coalesce_containers,omitempty"`
+}
+
+// EffectiveNotify reports which notification channels are on. Webhook URLs
+// can carry tokens, so they are not printed.
+type EffectiveNotify struct {
+	Webhook        bool   `json:"webhook"`
+	Slack          bool   `json:"slack"`
+	Teams          bool   `json:"teams"`
+	WebhookRate    int    `json:"webhook_rate_per_minute,omitempty"`
+	SlackRate      int    `json:"slack_rate_per_minute,omitempty"`
+	TeamsRate      int    `json:"teams_rate_per_minute,omitempty"`
+	DigestInterval string `json:"digest_interval,omitempty"`
+	Events         bool   `json:"events"`
+	DedupKey       string `json:"dedup_key,omitempty"`
+	StateFile      string `json:"state_file,omitempty"`
+}
+
+// EffectiveFailOn are the exit-code gates
+type EffectiveFailOn struct {
+	Severity        string `json:"severity,omitempty"`
+	Drift           bool   `json:"drift"`
+	CompareBaseline string `json:"compare_baseline,omitempty"`
+	StateFile       string `json:"state_file,omitempty"`
+}
+
+// effectiveConfig resolves the configuration monitor would run with
+func effectiveConfig(cfgPath string, cfg *config.Config, registry *detector.Registry) *EffectiveConfig {
+	include, exclude := effectiveNamespaces(cfg)
+	eff := &EffectiveConfig{
+		ConfigFile: cfgPath,
+		Profile:    profileName,
+		Backend: EffectiveBackend{
+			QueryPath: queryPath,
+			Tenants:   tenants,
+			Timeout:   prometheusTimeout.String(),
+		},
+		Output:     outputFormat,
+		Refresh:    refreshInterval.String(),
+		PersistCap: persistenceCap,
+		Recency:    recencyWeight,
+		Disabled:   cfg.DisabledDetectors,
+		Filters: EffectiveFilters{
+			IncludeNamespaces:   include,
+			ExcludeNamespaces:   exclude,
+			IncludeUnnamespaced: includeUnnamespaced,
+			IncludeEntity:       includeEntity,
+			ExcludeEntity:       excludeEntity,
+			EntityType:          entityTypeFilter,
+			MinSeverity:         minSeverity,
+			MinBlastRadius:      minBlastRadius,
+			AggregateThreshold:  aggregateThreshold,
+			AggregateCollapse:   aggregateCollapse,
+			CoalesceContainers:  coalesceContainers,
+		},
+		FailOn: EffectiveFailOn{
+			Severity:        failOnSeverity,
+			Drift:           failOnDrift,
+			CompareBaseline: compareBaseline,
+			StateFile:       stateFile,
+		},
+	}
+	eff.Backend.OpenShift = openshift
+	if prometheusURL != "" {
+		eff.Backend.PrometheusURL = sanitizeURL(prometheusURL)
+	}
+	if k8sService != "" || k8sPod != "" || k8sSelector != "" {
+		eff.Backend.K8sService = k8sService
+		eff.Backend.K8sPod = k8sPod
+		eff.Backend.K8sSelector = k8sSelector
+		eff.Backend.K8sNamespace = k8sNamespace
+	}
+
+	for _, d := range registry.All() {
+		ed := EffectiveDetector{
+			Name:     d.Name(),
+			Interval: d.Interval().String(),
+			Window:   detector.EffectiveWindow(d, detectionWindow, cfg.DetectorWindows()).String(),
+		}
+		if t, ok := d.(detector.Tunable); ok {
+			v := t.Threshold()
+			ed.Threshold = &v
+		}
+		if _, ok := d.(detector.Sustainable); ok && sustainedWindow > 0 {
+			ed.SustainedWindow = sustainedWindow.String()
+		}
+		eff.Detectors = append(eff.Detectors, ed)
+	}
+	sort.Slice(eff.Detectors, func(i, j int) bool {
+		return eff.Detectors[i].Name < eff.Detectors[j].Name
+	})
+
+	if ttls := cfg.ProblemTTLs(); len(ttls) > 0 {
+		eff.TTLs = make(map[string]string, len(ttls))
+		for problemType, ttl := range ttls {
+			eff.TTLs[problemType] = ttl.String()
+		}
+	}
+
+	if notifyWebhook != "" || notifySlack != "" || notifyTeams != "" {
+		eff.Notify = &EffectiveNotify{
+			Webhook:     notifyWebhook != "",
+			Slack:       notifySlack != "",
+			Teams:       notifyTeams != "",
+			WebhookRate: webhookRate,
+			SlackRate:   slackRate,
+			TeamsRate:   teamsRate,
+			Events:      notifyEvents,
+			DedupKey:    notifyDedupKey,
+			StateFile:   notifyStateFile,
+		}
+		if digestInterval > 0 {
+			eff.Notify.DigestInterval = digestInterval.String()
+		}
+	}
+	return eff
+}
+
+// effectiveNamespaces merges the namespace filter flags with the config file;
+// flags win
+func effectiveNamespaces(cfg *config.Config) (include, exclude string) {
+	include, exclude = includeNamespaces, excludeNamespaces
+	if include == "" {
+		include = cfg.IncludeNamespaces
+	}
+	if exclude == "" {
+		exclude = cfg.ExcludeNamespaces
+	}
+	return include, exclude
+}
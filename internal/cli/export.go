@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/monitor"
+)
+
+// exportFormats are the formats --export-format accepts
+var exportFormats = []string{"json", "sarif", "text", "table-compact"}
+
+// validateExportFormat checks --export-format against --export-file and
+// --output. prometheus-textfile owns the export file, so it takes no format.
+func validateExportFormat(format, file, output string) error {
+	if format == "" {
+		return nil
+	}
+	if !slices.Contains(exportFormats, format) {
+		return fmt.Errorf("--export-format must be one of %s, got %q", strings.Join(exportFormats, ", "), format)
+	}
+	if file == "" {
+		return fmt.Errorf("--export-format requires --export-file")
+	}
+	if output == "prometheus-textfile" {
+		return fmt.Errorf("--export-format does not apply to --output prometheus-textfile")
+	}
+	return nil
+}
+
+// resolveExportFormat returns the format the export file is written in:
+// --export-format, else the console format
+func resolveExportFormat(format, output string) string {
+	if format != "" {
+		return format
+	}
+	if output == "table" {
+		return "text" // One-shot table output is the plain text table
+	}
+	return output
+}
+
+// writeExport writes problems to --export-file, if set, in the resolved
+// export format
+func writeExport(watcher *monitor.Watcher, problems []*models.Problem) error {
+	if exportFile == "" {
+		return nil
+	}
+	data, err := renderExport(resolveExportFormat(exportFormat, outputFormat), watcher, problems)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(exportFile, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Exported to: %s\n", exportFile)
+	}
+	return nil
+}
+
+// renderExport renders problems as an export file in format
+func renderExport(format string, watcher *monitor.Watcher, problems []*models.Problem) ([]byte, error) {
+	problems = redactProblems(problems)
+	switch format {
+	case "json":
+		models.InDisplayLocation(problems)
+		data, err := json.MarshalIndent(jsonReport(watcher, problems), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode export file: %w", err)
+		}
+		return append(data, '\n'), nil
+	case "sarif":
+		data, err := monitor.SARIF(problems, version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render SARIF export: %w", err)
+		}
+		return append(data, '\n'), nil
+	case "table-compact":
+		return []byte(monitor.CompactText(problems, time.Now())), nil
+	default:
+		return []byte(monitor.PlainText(problems, time.Now())), nil
+	}
+}
@@ -0,0 +1,294 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/filter"
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/monitor"
+	"github.com/ppiankov/infranow/internal/redact"
+)
+
+func TestWriteExport_IndependentOfConsoleFormat(t *testing.T) {
+	oldOutput, oldFormat, oldFile := outputFormat, exportFormat, exportFile
+	t.Cleanup(func() { outputFormat, exportFormat, exportFile = oldOutput, oldFormat, oldFile })
+
+	outputFormat = "table"
+	exportFormat = "json"
+	exportFile = filepath.Join(t.TempDir(), "problems.json")
+
+	now := time.Now()
+	problems := []*models.Problem{{
+		ID: "p1", Entity: "prod/api", Type: "oom_kill", Title: "OOM Kill",
+		Severity: models.SeverityCritical, FirstSeen: now, LastSeen: now, Count: 1,
+	}}
+	watcher := monitor.NewWatcher(&metrics.MockProvider{}, detector.NewRegistry(), 0, time.Second)
+
+	// One run: the console gets the table, the file gets JSON
+	console := monitor.PlainText(problems, now)
+	if err := writeExport(watcher, problems); err != nil {
+		t.Fatalf("writeExport: %v", err)
+	}
+
+	if !strings.HasPrefix(console, "SEV") || json.Valid([]byte(console)) {
+		t.Errorf("expected a text table on the console, got:\n%s", console)
+	}
+	data, err := os.ReadFile(exportFile)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	var report struct {
+		Problems []models.Problem `json:"problems"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("export file is not JSON: %v\n%s", err, data)
+	}
+	if len(report.Problems) != 1 || report.Problems[0].Entity != "prod/api" {
+		t.Errorf("unexpected exported problems: %+v", report.Problems)
+	}
+}
+
+func TestWriteExport_DefaultsToConsoleFormat(t *testing.T) {
+	oldOutput, oldFormat, oldFile := outputFormat, exportFormat, exportFile
+	t.Cleanup(func() { outputFormat, exportFormat, exportFile = oldOutput, oldFormat, oldFile })
+
+	outputFormat = "sarif"
+	exportFormat = ""
+	exportFile = filepath.Join(t.TempDir(), "results.sarif")
+
+	watcher := monitor.NewWatcher(&metrics.MockProvider{}, detector.NewRegistry(), 0, time.Second)
+	if err := writeExport(watcher, nil); err != nil {
+		t.Fatalf("writeExport: %v", err)
+	}
+	data, err := os.ReadFile(exportFile)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if !strings.Contains(string(data), `"$schema"`) {
+		t.Errorf("expected SARIF export, got:\n%s", data)
+	}
+}
+
+func TestValidateExportFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		file    string
+		output  string
+		wantErr bool
+	}{
+		{"unset", "", "", "table", false},
+		{"json file with table console", "json", "out.json", "table", false},
+		{"sarif file with json console", "sarif", "out.sarif", "json", false},
+		{"unknown format", "yaml", "out.yaml", "table", true},
+		{"missing file", "json", "", "table", true},
+		{"textfile output", "json", "out.prom", "prometheus-textfile", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateExportFormat(tt.format, tt.file, tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateExportFormat(%q, %q, %q) error = %v, wantErr %v", tt.format, tt.file, tt.output, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJSONReport_ClusterMetadata(t *testing.T) {
+	old := clusterName
+	t.Cleanup(func() { clusterName = old })
+
+	watcher := monitor.NewWatcher(&metrics.MockProvider{}, detector.NewRegistry(), 0, time.Second)
+
+	clusterName = ""
+	metadata := jsonReport(watcher, nil)["metadata"].(map[string]interface{})
+	if _, ok := metadata["cluster"]; ok {
+		t.Errorf("expected no cluster without --cluster-name, got %v", metadata["cluster"])
+	}
+
+	clusterName = "prod-eu"
+	metadata = jsonReport(watcher, nil)["metadata"].(map[string]interface{})
+	if metadata["cluster"] != "prod-eu" {
+		t.Errorf("expected cluster prod-eu in metadata, got %v", metadata["cluster"])
+	}
+}
+
+func TestJSONReport_DetectorMetadata(t *testing.T) {
+	registry := detector.NewRegistry()
+	registry.Register(detector.NewOOMKillDetector())
+	registry.Register(detector.NewRestartRateDetector())
+	registry.Register(detector.NewDiskSpaceDetector())
+	watcher := monitor.NewWatcher(&metrics.MockProvider{}, registry, 0, time.Second,
+		monitor.WithDetectorWindows(map[string]time.Duration{"kubernetes_oom_kills": 20 * time.Minute}))
+
+	data, err := json.Marshal(jsonReport(watcher, nil))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var report struct {
+		Metadata struct {
+			Detectors []struct {
+				Name     string `json:"name"`
+				Interval string `json:"interval"`
+				Window   string `json:"window"`
+				Status   string `json:"status"`
+				LastRun  string `json:"last_run"`
+			} `json:"detectors"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	got := report.Metadata.Detectors
+	if len(got) != 3 {
+		t.Fatalf("got %d detectors in metadata, want every active detector: %s", len(got), data)
+	}
+	want := []struct{ name, interval, window string }{
+		{"generic_disk_space", "1m0s", "5m0s"},
+		{"kubernetes_oom_kills", "30s", "20m0s"},
+		{"kubernetes_restart_rate", "30s", "15m0s"},
+	}
+	for i, w := range want {
+		d := got[i]
+		if d.Name != w.name || d.Interval != w.interval || d.Window != w.window {
+			t.Errorf("detector %d = %+v, want %s every %s over %s", i, d, w.name, w.interval, w.window)
+		}
+		if d.Status != "pending" || d.LastRun != "" {
+			t.Errorf("%s: status %q, last_run %q, want pending before the first run", d.Name, d.Status, d.LastRun)
+		}
+	}
+}
+
+func TestRenderExport_RedactsEntities(t *testing.T) {
+	oldRedactor, oldCluster := redactor, clusterName
+	t.Cleanup(func() { redactor, clusterName = oldRedactor, oldCluster })
+	redactor = redact.NewWithKey([]byte("test"))
+	clusterName = "prod-eu"
+
+	watcher := monitor.NewWatcher(&metrics.MockProvider{}, detector.NewRegistry(), 0, time.Second)
+	problems := []*models.Problem{{
+		ID: "oom_kill/abc", Entity: "payments/api-0", Type: "oom_kill", Severity: models.SeverityCritical,
+		Message: "Pod api-0 in payments was OOM killed", Labels: map[string]string{"namespace": "payments", "pod": "api-0"}, Count: 2,
+	}}
+
+	for _, format := range []string{"json", "text", "sarif"} {
+		data, err := renderExport(format, watcher, problems)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		out := string(data)
+		for _, secret := range []string{"payments", "api-0", "prod-eu"} {
+			if strings.Contains(out, secret) {
+				t.Errorf("%s export leaks %q:\n%s", format, secret, out)
+			}
+		}
+		if !strings.Contains(out, redactor.Token("payments")) {
+			t.Errorf("%s export should use the namespace token:\n%s", format, out)
+		}
+	}
+	if problems[0].Entity != "payments/api-0" {
+		t.Error("redacting output must not change the watcher's problems")
+	}
+}
+
+func TestJSONReport_FormatsMetrics(t *testing.T) {
+	watcher := monitor.NewWatcher(&metrics.MockProvider{}, detector.NewRegistry(), 0, time.Second)
+	problems := []*models.Problem{
+		{ID: "cert/1", Metrics: map[string]float64{"remaining_seconds": 432000}},
+		{ID: "pod/2"},
+	}
+
+	data, err := json.Marshal(jsonReport(watcher, problems))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"metrics_formatted":{"remaining_seconds":"5d"}`) {
+		t.Errorf("expected formatted metrics in %s", data)
+	}
+	if problems[0].Metrics["remaining_seconds"] != 432000 {
+		t.Error("raw metric value must be kept")
+	}
+	if problems[1].MetricsFormatted != nil {
+		t.Errorf("problem without metrics got %v", problems[1].MetricsFormatted)
+	}
+}
+
+func TestAllowList_ExcludedFromFailOnButShownWhenRequested(t *testing.T) {
+	oldList, oldShow := allowList, showAllowed
+	t.Cleanup(func() { allowList, showAllowed = oldList, oldShow })
+
+	registry := detector.NewRegistry()
+	registry.Register(staticDetector{problems: []*models.Problem{
+		{Entity: "sandbox/demo", EntityType: "kubernetes_pod", Type: "crashloopbackoff", Severity: models.SeverityFatal, Labels: map[string]string{"pod": "demo"}},
+		{Entity: "prod/api", EntityType: "kubernetes_pod", Type: "oom_kill", Severity: models.SeverityWarning, Labels: map[string]string{"pod": "api"}},
+	}})
+	watcher := monitor.NewWatcher(&metrics.MockProvider{}, registry, 0, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = watcher.Start(ctx)
+	}()
+	select {
+	case <-watcher.UpdateChan():
+	case <-time.After(5 * time.Second):
+		t.Fatal("no detection cycle")
+	}
+	allowList = &filter.AllowList{Rules: []filter.AllowRule{{Entity: "sandbox/*", Reason: "demo pod"}}}
+
+	// --fail-on CRITICAL only sees the WARNING
+	problems := applyFilters(watcher.ProblemsBy(monitor.SortBySeverity))
+	if len(problems) != 1 || problems[0].Entity != "prod/api" {
+		t.Fatalf("applyFilters kept %d problems, want only prod", len(problems))
+	}
+	for _, p := range problems {
+		if p.Severity.AtLeast(models.SeverityCritical) {
+			t.Errorf("allow-listed %s would trip --fail-on CRITICAL", p.Entity)
+		}
+	}
+
+	report := jsonReport(watcher, problems)
+	summary := report["summary"].(map[string]interface{})
+	if summary["fatal"] != 0 || summary["total_problems"] != 1 {
+		t.Errorf("allow-listed problem counted in summary: %v", summary)
+	}
+	if _, ok := report["allowed"]; ok {
+		t.Error("allowed problems reported without --show-allowed")
+	}
+
+	showAllowed = true
+	report = jsonReport(watcher, problems)
+	allowed, _ := report["allowed"].([]*models.Problem)
+	if len(allowed) != 1 || allowed[0].Entity != "sandbox/demo" || !allowed[0].Allowed || allowed[0].AllowReason != "demo pod" {
+		t.Errorf("expected the sandbox problem under allowed, got %+v", report["allowed"])
+	}
+	if summary := report["summary"].(map[string]interface{}); summary["fatal"] != 0 {
+		t.Errorf("--show-allowed must not change the summary: %v", summary)
+	}
+}
+
+// staticDetector reports copies of a fixed problem list every cycle
+type staticDetector struct {
+	problems []*models.Problem
+}
+
+func (staticDetector) Name() string            { return "static" }
+func (staticDetector) EntityTypes() []string   { return []string{"kubernetes_pod"} }
+func (staticDetector) Interval() time.Duration { return time.Hour }
+func (d staticDetector) Detect(context.Context, metrics.MetricsProvider, time.Duration) ([]*models.Problem, error) {
+	out := make([]*models.Problem, len(d.problems))
+	for i, p := range d.problems {
+		c := *p
+		out[i] = &c
+	}
+	return out, nil
+}
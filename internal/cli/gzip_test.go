@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGzipHandler_CompressesWhenRequested(t *testing.T) {
+	body := strings.Repeat(`{"entity":"prod/api","severity":"CRITICAL"},`, 200)
+	handler := gzipHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", "999999") // Must not leak through
+		_, _ = io.WriteString(w, body)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/problems.json", nil)
+	req.Header.Set("Accept-Encoding", "br, gzip;q=0.8")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if rec.Header().Get("Content-Length") != "" {
+		t.Error("Content-Length of the uncompressed body should be dropped")
+	}
+	if rec.Body.Len() >= len(body) {
+		t.Errorf("compressed body is %d bytes, plain is %d", rec.Body.Len(), len(body))
+	}
+
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(decoded) != body {
+		t.Error("decoded body does not match the original")
+	}
+}
+
+func TestGzipHandler_PlainWithoutAcceptEncoding(t *testing.T) {
+	handler := gzipHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "<html>ok</html>")
+	}))
+
+	for _, accept := range []string{"", "identity", "gzip;q=0", "gzip; q=0.0"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", accept)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != "<html>ok</html>" {
+			t.Errorf("Accept-Encoding %q: got encoding %q body %q", accept, rec.Header().Get("Content-Encoding"), rec.Body.String())
+		}
+	}
+}
+
+func TestGzipHandler_SniffsPlainContentType(t *testing.T) {
+	handler := gzipHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "<html><body>snapshot</body></html>")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/html") {
+		t.Errorf("Content-Type = %q, want text/html sniffed from the uncompressed body", got)
+	}
+}
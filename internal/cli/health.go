@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/monitor"
+)
+
+// backgroundShutdownTimeout bounds draining in-flight requests on exit
+const backgroundShutdownTimeout = 5 * time.Second
+
+// startHealthServer serves watcher's /livez and /readyz on addr until ctx is
+// done. The address is bound before returning so a port clash fails startup.
+func startHealthServer(ctx context.Context, addr string, watcher *monitor.Watcher) error {
+	return serveInBackground(ctx, "health", addr, gzipHandler(monitor.HealthHandler(watcher)))
+}
+
+// serveInBackground binds addr, then serves handler until ctx is done.
+// Serve errors are logged to backgroundLog under name.
+func serveInBackground(ctx context.Context, name, addr string, handler http.Handler) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("%s server: %w", name, err)
+	}
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: snapshotReadHeaderTimeout,
+	}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(backgroundLog, "[infranow] %s server failed: %v\n", name, err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), backgroundShutdownTimeout)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx) // Best-effort
+	}()
+	return nil
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/monitor"
+)
+
+func TestStartHealthServer_AddressInUse(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	watcher := monitor.NewWatcher(&metrics.MockProvider{}, detector.NewRegistry(), 1, time.Second)
+	if err := startHealthServer(context.Background(), ln.Addr().String(), watcher); err == nil {
+		t.Fatal("expected error binding an address already in use")
+	}
+}
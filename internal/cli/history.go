@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ppiankov/infranow/internal/history"
+	"github.com/ppiankov/infranow/internal/models"
 	"github.com/ppiankov/infranow/internal/util"
 )
 
@@ -83,8 +84,7 @@ func openHistoryStore() (*history.SQLiteStore, error) {
 func runHistoryList(cmd *cobra.Command, args []string) error {
 	store, err := openHistoryStore()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		util.Exit(util.ExitRuntimeError)
+		return util.Runtime(err)
 	}
 	defer func() {
 		if closeErr := store.Close(); closeErr != nil {
@@ -109,6 +109,10 @@ func runHistoryList(cmd *cobra.Command, args []string) error {
 	}
 
 	if historyListOutput == "json" {
+		for i := range records {
+			records[i].FirstSeen = models.DisplayTime(records[i].FirstSeen)
+			records[i].LastSeen = models.DisplayTime(records[i].LastSeen)
+		}
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		return encoder.Encode(records)
@@ -134,7 +138,7 @@ func runHistoryList(cmd *cobra.Command, args []string) error {
 			truncateStr(r.Entity, 30),
 			r.OccurrenceCount,
 			fp,
-			r.LastSeen.Format("2006-01-02 15:04"),
+			models.DisplayTime(r.LastSeen).Format("2006-01-02 15:04"),
 		)
 	}
 	fmt.Printf("\n%d records\n", len(records))
@@ -145,8 +149,7 @@ func runHistoryList(cmd *cobra.Command, args []string) error {
 func runHistoryPrune(cmd *cobra.Command, args []string) error {
 	store, err := openHistoryStore()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		util.Exit(util.ExitRuntimeError)
+		return util.Runtime(err)
 	}
 	defer func() {
 		if closeErr := store.Close(); closeErr != nil {
@@ -3,12 +3,16 @@ package cli
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
+	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
@@ -17,54 +21,167 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/klog/v2"
 
 	"github.com/ppiankov/infranow/internal/baseline"
 	"github.com/ppiankov/infranow/internal/correlator"
+	"github.com/ppiankov/infranow/internal/deploy"
 	"github.com/ppiankov/infranow/internal/detector"
 	"github.com/ppiankov/infranow/internal/filter"
 	"github.com/ppiankov/infranow/internal/history"
 	"github.com/ppiankov/infranow/internal/metrics"
 	"github.com/ppiankov/infranow/internal/models"
 	"github.com/ppiankov/infranow/internal/monitor"
+	"github.com/ppiankov/infranow/internal/notify"
+	"github.com/ppiankov/infranow/internal/redact"
+	"github.com/ppiankov/infranow/internal/service"
 	"github.com/ppiankov/infranow/internal/util"
 )
 
 // firstDetectionTimeout is how long to wait for the initial detection cycle
 const firstDetectionTimeout = 30 * time.Second
 
+// defaultIntervalFloor is the default --min-refresh-interval
+const defaultIntervalFloor = 5 * time.Second
+
 var (
-	prometheusURL     string
-	prometheusTimeout time.Duration
-	namespaceFilter   string
-	entityTypeFilter  string
-	minSeverity       string
-	refreshInterval   time.Duration
-	outputFormat      string
-	exportFile        string
+	prometheusURL      string
+	prometheusTimeout  time.Duration
+	queryHeaders       []string
+	openshift          bool // preset for OpenShift cluster monitoring (thanos-querier)
+	queryPath          string
+	tenants            []string
+	namespaceFilter    string
+	entityTypeFilter   string
+	minSeverity        string
+	sortOrder          string
+	minBlastRadius     int
+	aggregateThreshold int  // synthesize an escalated problem per type with this many problems
+	aggregateCollapse  bool // show only the aggregate, not its problems
+	coalesceContainers bool // merge per-container problems of one pod and type
+	refreshInterval    time.Duration
+	intervalFloor      time.Duration // refresh intervals below this are raised to it
+	allowAggressive    bool          // keep refresh intervals below intervalFloor
+	outputFormat       string
+	exportFile         string
+	exportFormat       string        // --export-file format, empty = same as --output
+	otlpLogsEndpoint   string        // OTLP/HTTP collector receiving problems as log records
+	resolvedRetention  time.Duration // keep resolved problems in textfile output this long
 
 	// Kubernetes port-forward options
 	k8sService    string
+	k8sPod        string
+	k8sSelector   string
 	k8sNamespace  string
 	k8sLocalPort  string
 	k8sRemotePort string
 
 	// v0.1.2 features
-	failOnSeverity    string // Feature 2: --fail-on
-	includeNamespaces string // Feature 3: namespace filters
-	excludeNamespaces string // Feature 3: namespace filters
-	saveBaseline      string // Feature 1: baseline mode
-	compareBaseline   string // Feature 1: baseline mode
-	failOnDrift       bool   // Feature 1: baseline mode
-	maxConcurrency    int    // Feature 4: concurrency controls
-	detectorTimeout   time.Duration
+	failOnSeverity      string        // Feature 2: --fail-on
+	failOnStableFor     time.Duration // continuous modes: --fail-on once a problem persists this long
+	includeNamespaces   string        // Feature 3: namespace filters
+	excludeNamespaces   string        // Feature 3: namespace filters
+	includeUnnamespaced bool          // problems without a namespace label pass namespace filters
+	includeEntity       string        // regex matched against Problem.Entity
+	excludeEntity       string        // regex matched against Problem.Entity
+	allowListFile       string        // known-good problems, excluded from counts and --fail-on
+	showAllowed         bool          // still show allow-listed problems (TUI, JSON "allowed")
+	redactEntities      bool          // pseudonymize entity names in output for sharing
+	saveBaseline        string        // Feature 1: baseline mode
+	compareBaseline     string        // Feature 1: baseline mode
+	failOnDrift         bool          // Feature 1: baseline mode
+	baselineDir         string        // rolling baselines: compare to newest, save new
+	baselineKeep        int           // rolling baselines kept in --baseline-dir
+	stateFile           string        // Last problem-ID set for cron change detection
+	stateFormat         string        // --state-file encoding: json or binary
+	summaryLine         bool          // end text output with a SUMMARY line for log scrapers
+	maxConcurrency      int           // Feature 4: concurrency controls
+	detectorTimeout     time.Duration
+	batchQueries        bool // combine concurrent instant queries into fewer requests
 
 	// v0.2.0 features
-	runOnce bool // --once: single detection cycle then exit
+	runOnce           bool          // --once: single detection cycle then exit
+	maxRuntime        time.Duration // --max-runtime: bound on a one-shot run
+	runDeadline       time.Time     // when maxRuntime runs out (zero = no bound)
+	watchTable        bool          // --watch: reprint the table every refresh instead of the TUI
+	logQueries        bool          // log detector PromQL with result counts
+	logQueriesEvery   int           // log every Nth cycle per detector (0 = each query once)
+	maxDataStaleness  time.Duration // exit/alarm when no fresh data for this long
+	healthListenAddr  string        // serve /livez and /readyz here, empty = disabled
+	pprofAddr         string        // serve net/http/pprof here (loopback only), empty = disabled
+	memoryLimit       string        // --memory-limit: GOMEMLIMIT and the watchdog's heap bound
+	maxGoroutines     int           // watchdog goroutine bound, 0 = unbounded
+	prometheusUIURL   string        // base URL or {expr} template for per-problem query links
+	clusterName       string        // stamped into JSON metadata, notifications and the TUI header
+	clusterLabel      bool          // also label every problem with clusterName
+	downAfter         int           // consecutive failures before Prometheus is shown down
+	reportUnavailable bool          // report a FATAL problem while monitoring is down
+	printConfig       bool          // print the effective configuration as JSON and exit
+	listEntityTypes   bool          // print the entity types --entity-type accepts and exit
+	sustainedWindow   time.Duration // memory/error-rate must hold this long before reporting
+	detectionWindow   time.Duration // range selector detector queries look back over
+	persistenceCap    float64       // max persistence multiplier in problem scores
+	recencyWeight     float64       // score boost for newly first-seen problems
+	profileName       string        // config file profile bundling filter, severity and detector flags
+	onlyDetectors     []string      // run only these detectors (empty = all enabled)
+
+	// TUI terminal handling
+	noAltScreen bool // render inline instead of on the alternate screen
+	tuiWidth    int  // fixed layout width when no WindowSizeMsg arrives
 
 	// History (WO-08)
 	historyEnabled bool
 	historyDBPath  string
+	includeHistory bool // recent detections per problem in JSON exports
+
+	// Notifications
+	notifyWebhook   string
+	notifySlack     string
+	notifyTeams     string
+	webhookRate     int // max webhook notifications per minute (0 = unlimited)
+	slackRate       int // max Slack notifications per minute (0 = unlimited)
+	teamsRate       int // max Teams notifications per minute (0 = unlimited)
+	digestInterval  time.Duration
+	notifyEvents    bool
+	notifyDedupKey  string
+	fireAfter       time.Duration // --notify-events: presence required before firing
+	resolveAfter    time.Duration // --notify-events: absence required before resolving
+	notifyStateFile string        // --notify-events: firing keys persisted across restarts
+
+	// Deploy correlation
+	deploysFile  string
+	deployMetric string
+	deployWindow time.Duration
+
+	// Service derivation
+	deriveService bool
+	groupBy       string // Problem label text output is grouped by
+
+	// entityFilter is built from --include-entity / --exclude-entity
+	entityFilter *filter.EntityFilter
+
+	// allowList is loaded from --allow-list (nil = none)
+	allowList *filter.AllowList
+
+	// mutes holds problems muted from the TUI (nil = not running the TUI)
+	mutes *filter.Mutes
+
+	// redactor pseudonymizes output with --redact-entities (nil = off)
+	redactor *redact.Redactor
+
+	// minSeverityLevel is parsed from --min-severity
+	minSeverityLevel models.Severity
+
+	// sortMode is parsed from --sort
+	sortMode monitor.SortMode
+
+	// rollingBaseline is set by --baseline-dir
+	rollingBaseline *baseline.Rolling
+
+	// backgroundLog receives messages from background tasks (config hot-reload,
+	// notifications). Discarded in TUI mode so the alt-screen is not corrupted.
+	backgroundLog io.Writer = os.Stderr
 )
 
 // NewMonitorCommand creates the monitor subcommand
@@ -80,67 +197,383 @@ surfaces problems ranked by importance.`,
 
 	// Flags
 	cmd.Flags().StringVar(&prometheusURL, "prometheus-url", "", "Prometheus endpoint URL (required unless using --k8s-service)")
+	cmd.Flags().StringVar(&prometheusUIURL, "prometheus-ui-url", "", "Link each problem to its PromQL: a Prometheus UI root, or a template with {expr}, e.g. a Grafana Explore URL (empty = no links)")
+	cmd.Flags().StringVar(&clusterName, "cluster-name", "", "Name of the monitored cluster, shown in JSON metadata, notifications and the TUI header")
+	cmd.Flags().BoolVar(&clusterLabel, "cluster-label", false, "With --cluster-name: add a cluster label to every problem (changes problem IDs)")
 	cmd.Flags().DurationVar(&prometheusTimeout, "prometheus-timeout", 30*time.Second, "Prometheus query timeout")
+	cmd.Flags().BoolVar(&openshift, "openshift", false, "OpenShift cluster monitoring preset: in-cluster thanos-querier, service account bearer token and service CA")
+	cmd.Flags().StringArrayVar(&queryHeaders, "query-header", nil, "HTTP header sent with every query as key=value (repeatable, e.g. X-Scope-OrgID=tenant)")
+	cmd.Flags().StringVar(&queryPath, "query-path", "", "Query API base path under --prometheus-url, e.g. /prometheus (default: probe /, /prometheus, /api)")
+	cmd.Flags().StringArrayVar(&tenants, "tenant", nil, "Mimir/Cortex tenant (X-Scope-OrgID) to monitor (repeatable, problems are labeled by tenant)")
 	cmd.Flags().StringVar(&namespaceFilter, "namespace", "", "Filter by namespace pattern (regex)")
 	cmd.Flags().StringVar(&entityTypeFilter, "entity-type", "", "Filter by entity type")
+	cmd.Flags().BoolVar(&listEntityTypes, "list-entity-types", false, "List the entity types declared by active detectors and exit")
 	cmd.Flags().StringVar(&minSeverity, "min-severity", "WARNING", "Minimum severity (FATAL, CRITICAL, WARNING)")
+	cmd.Flags().IntVar(&aggregateThreshold, "aggregate-threshold", 0, "Add one escalated problem per type with at least this many problems, e.g. 20 throttling WARNINGs become a CRITICAL (0 = disabled)")
+	cmd.Flags().BoolVar(&coalesceContainers, "coalesce-containers", false, "Merge problems of one type that differ only by container into one pod-level problem")
+	cmd.Flags().BoolVar(&aggregateCollapse, "aggregate-collapse", false, "With --aggregate-threshold: show only the aggregate instead of the individual problems")
+	cmd.Flags().StringVar(&profileName, "profile", "", "Apply a named profile from the config file (filters, severity, detectors); explicit flags win")
+	cmd.Flags().StringSliceVar(&onlyDetectors, "detectors", nil, "Comma-separated detector names to run; all others are skipped (default: all enabled)")
+	cmd.Flags().IntVar(&minBlastRadius, "min-blast-radius", 0, "Hide problems affecting fewer entities than this (0 = show all)")
+	cmd.Flags().StringVar(&sortOrder, "sort", "severity", "Problem order in one-shot output and initial TUI order (severity, recency, count, blast-radius, oldest)")
 	cmd.Flags().DurationVar(&refreshInterval, "refresh-interval", 10*time.Second, "Detection refresh rate")
-	cmd.Flags().StringVar(&outputFormat, "output", "table", "Output format (table, text, json, sarif). Auto-detects piped stdout")
-	cmd.Flags().StringVar(&exportFile, "export-file", "", "Export problems to file")
+	cmd.Flags().DurationVar(&intervalFloor, "min-refresh-interval", defaultIntervalFloor, "Raise --refresh-interval to at least this, with a warning, to protect Prometheus")
+	cmd.Flags().BoolVar(&allowAggressive, "allow-aggressive-intervals", false, "Allow --refresh-interval below --min-refresh-interval")
+	cmd.Flags().StringVar(&outputFormat, "output", "table", "Output format (table, table-compact, text, json, sarif, prometheus-textfile). Auto-detects piped stdout")
+	cmd.Flags().StringVar(&exportFile, "export-file", "", "Export problems to file (.prom target for prometheus-textfile)")
+	cmd.Flags().StringVar(&exportFormat, "export-format", "", "Format of --export-file (json, sarif, text, table-compact), independent of --output (default: same as --output)")
+	cmd.Flags().StringVar(&otlpLogsEndpoint, "otlp-logs-endpoint", "", "Export problems as OpenTelemetry log records to this OTLP/HTTP endpoint (e.g., http://otel-collector:4318)")
+	cmd.Flags().DurationVar(&resolvedRetention, "resolved-retention", 0, "Keep resolved problems in prometheus-textfile output for this long so scrapes catch short-lived ones (0 = disabled)")
 
 	// Kubernetes port-forward flags
 	cmd.Flags().StringVar(&k8sService, "k8s-service", "", "Kubernetes service name for port-forward (e.g., 'prometheus-operated')")
-	cmd.Flags().StringVar(&k8sNamespace, "k8s-namespace", "monitoring", "Kubernetes namespace for service")
+	cmd.Flags().StringVar(&k8sPod, "k8s-pod", "", "Kubernetes pod name for port-forward, instead of --k8s-service (e.g., 'prometheus-k8s-1')")
+	cmd.Flags().StringVar(&k8sSelector, "k8s-selector", "", "Label selector for port-forward, resolved to a ready pod on every (re)start (e.g., 'app=prometheus,component=server')")
+	cmd.Flags().StringVar(&k8sNamespace, "k8s-namespace", "monitoring", "Kubernetes namespace for service or pod")
 	cmd.Flags().StringVar(&k8sLocalPort, "k8s-local-port", "9090", "Local port for port-forward")
 	cmd.Flags().StringVar(&k8sRemotePort, "k8s-remote-port", "9090", "Remote port for port-forward")
 
 	// v0.1.2 feature flags
-	cmd.Flags().StringVar(&failOnSeverity, "fail-on", "", "Exit 1 if problems at/above this severity (WARNING, CRITICAL, FATAL)")
+	cmd.Flags().StringVar(&failOnSeverity, "fail-on", "", "Exit 1 if problems at/above this severity (WARNING, CRITICAL, FATAL); with --compare-baseline only new problems count")
+	cmd.Flags().DurationVar(&failOnStableFor, "fail-on-stable-for", 0, "With --fail-on in --watch or continuous prometheus-textfile mode: exit 1 once a qualifying problem has persisted this long (one-shot runs ignore it)")
 	cmd.Flags().StringVar(&includeNamespaces, "include-namespaces", "", "Comma-separated namespace patterns (wildcards supported)")
 	cmd.Flags().StringVar(&excludeNamespaces, "exclude-namespaces", "", "Comma-separated namespace patterns to exclude")
+	cmd.Flags().BoolVar(&includeUnnamespaced, "include-unnamespaced", true, "Keep problems without a namespace (nodes, services, certificates) when filtering by namespace")
+	cmd.Flags().StringVar(&includeEntity, "include-entity", "", "Only show problems whose entity matches this regex (e.g. '-canary-')")
+	cmd.Flags().StringVar(&excludeEntity, "exclude-entity", "", "Hide problems whose entity matches this regex (wins over --include-entity)")
+	cmd.Flags().StringVar(&allowListFile, "allow-list", "", "File of permanently accepted problems (by id, entity, type, labels), excluded from counts, --fail-on and notifications")
+	cmd.Flags().BoolVar(&showAllowed, "show-allowed", false, "With --allow-list: still show allow-listed problems, marked ~ in the TUI and under \"allowed\" in JSON")
+	cmd.Flags().BoolVar(&redactEntities, "redact-entities", false, "Replace namespace, pod and other entity names in output with per-session tokens (same name, same token) for sharing")
 	cmd.Flags().StringVar(&saveBaseline, "save-baseline", "", "Save problems snapshot to file")
 	cmd.Flags().StringVar(&compareBaseline, "compare-baseline", "", "Compare current problems to baseline file")
 	cmd.Flags().BoolVar(&failOnDrift, "fail-on-drift", false, "Exit 1 if new problems detected vs baseline")
+	cmd.Flags().StringVar(&baselineDir, "baseline-dir", "", "Compare to the newest baseline in this directory, then save a new one (rolling history)")
+	cmd.Flags().IntVar(&baselineKeep, "baseline-keep", baseline.DefaultRollingKeep, "Baselines kept in --baseline-dir; older ones are deleted")
+	cmd.Flags().BoolVar(&summaryLine, "summary-line", false, "With text or table output: end with one machine-parseable line, e.g. SUMMARY fatal=1 critical=3 warning=5 total=9 exit=2")
+	cmd.Flags().StringVar(&stateFile, "state-file", "", "Persist the problem-ID set; exit 5 and print the delta if it changed since the last run")
+	cmd.Flags().StringVar(&stateFormat, "state-format", string(baseline.StateJSON), "Encoding --state-file writes: json (readable) or binary (faster with tens of thousands of problems); either is read")
 	cmd.Flags().IntVar(&maxConcurrency, "max-concurrency", 0, "Max concurrent detector executions (0 = unlimited)")
 	cmd.Flags().DurationVar(&detectorTimeout, "detector-timeout", 30*time.Second, "Detector execution timeout")
+	cmd.Flags().BoolVar(&batchQueries, "batch-queries", false, "Combine instant queries detectors issue at the same time into one Prometheus request (up to 16 each), retrying individually if the backend rejects it")
+	cmd.Flags().BoolVar(&logQueries, "log-queries", false, "Log the PromQL each detector runs with its result count to stderr (not shown in the TUI or --watch)")
+	cmd.Flags().IntVar(&logQueriesEvery, "log-queries-every", 0, "With --log-queries: log all queries every Nth cycle per detector (0 = each distinct query once)")
 	cmd.Flags().BoolVar(&runOnce, "once", false, "Run one detection cycle and exit")
+	cmd.Flags().DurationVar(&maxRuntime, "max-runtime", 0, "Bound a one-shot run: when reached, print what was collected, marked incomplete, and exit 6 (0 = no bound)")
+	cmd.Flags().BoolVar(&watchTable, "watch", false, "With --output table or table-compact: reprint the table in place every refresh instead of the TUI")
+	cmd.Flags().DurationVar(&maxDataStaleness, "max-data-staleness", 0, "Exit non-zero (TUI: show alarm) when no fresh Prometheus data for this long (0 = disabled)")
+	cmd.Flags().BoolVar(&reportUnavailable, "report-unavailable", false, "Report one FATAL monitoring_unavailable problem while Prometheus is down and every detector fails, instead of zero problems")
+	cmd.Flags().IntVar(&downAfter, "down-after-failures", 1, "Show Prometheus DOWN only after a detector or health check fails this many times in a row")
+	cmd.Flags().StringVar(&memoryLimit, "memory-limit", "", "Soft memory limit (GOMEMLIMIT), e.g. 512MiB; exit 4 when the live heap outgrows it, except in the interactive TUI (empty = unbounded)")
+	cmd.Flags().IntVar(&maxGoroutines, "max-goroutines", 0, "Exit 4 when infranow runs more goroutines than this, a sign of a leak; not in the interactive TUI (0 = unbounded)")
+	cmd.Flags().StringVar(&pprofAddr, "pprof-addr", "", "Serve Go pprof profiles on this loopback address, e.g. 127.0.0.1:6060 (empty = disabled)")
+	cmd.Flags().StringVar(&healthListenAddr, "health-listen-addr", "", "Serve /livez and /readyz probes on this address, e.g. :8081 (empty = disabled)")
+	cmd.Flags().BoolVar(&printConfig, "print-config", false, "Print the effective configuration (flags + config file) as JSON and exit")
+	cmd.Flags().Float64Var(&persistenceCap, "persistence-cap", models.DefaultPersistenceCap, "Max score multiplier for long-lived problems within a severity (0 = uncapped)")
+	cmd.Flags().Float64Var(&recencyWeight, "recency-weight", 0, "Score boost for new problems within a severity, halving every 10m they persist (0 = off; 5 puts new problems above capped old ones)")
+	cmd.Flags().DurationVar(&sustainedWindow, "sustained-window", 0, "Only report high memory/error rates (service and ingress) after it held for this long, e.g. 5m (0 = report instantly)")
+	cmd.Flags().DurationVar(&detectionWindow, "detection-window", detector.DefaultWindow, "Range detectors' rate()/increase() queries look back over, e.g. 15m on clusters with a long scrape interval")
+	cmd.Flags().BoolVar(&noAltScreen, "no-altscreen", false, "Render the TUI inline instead of on the alternate screen (tmux capture, CI logs)")
+	cmd.Flags().IntVar(&tuiWidth, "width", 0, "Fixed TUI width in columns, for terminals that do not report their size (0 = auto)")
 
 	// History flags (WO-08)
 	cmd.Flags().BoolVar(&historyEnabled, "history", false, "Enable problem history tracking (local SQLite)")
 	cmd.Flags().StringVar(&historyDBPath, "history-db", "", "History database path (env: INFRANOW_HISTORY_DB)")
+	cmd.Flags().BoolVar(&includeHistory, "include-history", false, "Include each problem's recent detections (timestamp, severity, metrics) in JSON output")
+
+	// Deploy correlation flags
+	cmd.Flags().StringVar(&deploysFile, "deploys-file", "", "YAML/JSON list of deploys (timestamp, service, version) to correlate problems with")
+	cmd.Flags().StringVar(&deployMetric, "deploy-metric", "", "PromQL returning deploy Unix timestamps with service/version labels")
+	cmd.Flags().DurationVar(&deployWindow, "deploy-window", deploy.DefaultWindow, "Attribute problems first seen within this window after a deploy")
+
+	// Service derivation flags
+	cmd.Flags().BoolVar(&deriveService, "derive-service", false, "Label problems with their service: the pod's app.kubernetes.io/name, app or k8s-app label (kube_pod_labels), else its workload name")
+	cmd.Flags().StringVar(&groupBy, "group-by", "", "Group text output by problem label: service, namespace or node (service implies --derive-service)")
+
+	// Notification flags
+	cmd.Flags().StringVar(&notifyWebhook, "notify-webhook", "", "Generic webhook URL for notifications (JSON POST)")
+	cmd.Flags().StringVar(&notifySlack, "notify-slack", "", "Slack incoming webhook URL for notifications")
+	cmd.Flags().StringVar(&notifyTeams, "notify-teams", "", "Microsoft Teams incoming webhook URL for notifications (Adaptive Card)")
+	cmd.Flags().IntVar(&webhookRate, "notify-webhook-rate", 0, "Max webhook notifications per minute; the overflow is sent as one summary (0 = unlimited)")
+	cmd.Flags().IntVar(&slackRate, "notify-slack-rate", 0, "Max Slack notifications per minute; the overflow is sent as one summary (0 = unlimited)")
+	cmd.Flags().IntVar(&teamsRate, "notify-teams-rate", 0, "Max Teams notifications per minute; the overflow is sent as one summary (0 = unlimited)")
+	cmd.Flags().DurationVar(&digestInterval, "digest-interval", 0, "Send a summary digest of current problems every interval (0 = disabled)")
+	cmd.Flags().BoolVar(&notifyEvents, "notify-events", false, "Notify when a problem starts and when it resolves")
+	cmd.Flags().DurationVar(&fireAfter, "notify-fire-after", 0, "With --notify-events: notify firing only after a problem has been present this long (0 = immediately)")
+	cmd.Flags().DurationVar(&resolveAfter, "notify-resolve-after", 0, "With --notify-events: notify resolved only after a problem has been gone this long (0 = immediately)")
+	cmd.Flags().StringVar(&notifyStateFile, "notify-state-file", "", "With --notify-events: persist notified problems to this file so a restart does not re-notify them")
+	cmd.Flags().StringVar(&notifyDedupKey, "notify-dedup-key", notify.DefaultDedupKey, "Go template over the problem that --notify-events deduplicates on, e.g. '{{.Labels.namespace}}/{{workload .Labels.pod}}/{{.Type}}'")
 	return cmd
 }
 
 func runMonitor(cmd *cobra.Command, args []string) error {
+	// Load config file (optional, hot-reloaded while monitoring)
+	cfgPath, err := loadConfigFile()
+	if err != nil {
+		return util.InvalidInput(err)
+	}
+	if profileName != "" {
+		p, err := currentConfig().Profile(profileName)
+		if err != nil {
+			return util.InvalidInputf("--profile: %w", err)
+		}
+		if err := applyProfile(cmd.Flags(), p); err != nil {
+			return util.InvalidInputf("--profile %s: %w", profileName, err)
+		}
+	}
+	if minSeverityLevel, err = models.ParseSeverity(minSeverity); err != nil {
+		return util.InvalidInputf("--min-severity: %w", err)
+	}
+
+	if intervalFloor <= 0 {
+		return util.InvalidInputf("--min-refresh-interval must be positive")
+	}
+	if refreshInterval <= 0 {
+		return util.InvalidInputf("--refresh-interval must be positive")
+	}
+	refreshInterval = clampInterval(os.Stderr, refreshInterval, intervalFloor, allowAggressive)
+
+	headers, err := parseQueryHeaders(queryHeaders)
+	if err != nil {
+		return util.InvalidInput(err)
+	}
+
+	senders, err := buildNotifySenders()
+	if err != nil {
+		return util.InvalidInput(err)
+	}
+	logExporter, err = buildLogExporter()
+	if err != nil {
+		return util.InvalidInput(err)
+	}
+	if digestInterval < 0 {
+		return util.InvalidInputf("--digest-interval must not be negative")
+	}
+	if maxDataStaleness < 0 {
+		return util.InvalidInputf("--max-data-staleness must not be negative")
+	}
+	var watchdogLimits util.WatchdogLimits
+	if memoryLimit != "" {
+		if watchdogLimits.MaxHeapBytes, err = parseByteSize(memoryLimit); err != nil {
+			return util.InvalidInputf("--memory-limit: %w", err)
+		}
+	}
+	if maxGoroutines < 0 {
+		return util.InvalidInputf("--max-goroutines must not be negative")
+	}
+	watchdogLimits.MaxGoroutines = maxGoroutines
+	if pprofAddr != "" {
+		if err := validateLoopbackAddr(pprofAddr); err != nil {
+			return util.InvalidInputf("--pprof-addr: %w", err)
+		}
+	}
+	if clusterLabel && clusterName == "" {
+		return util.InvalidInputf("--cluster-label requires --cluster-name")
+	}
+	if prometheusUIURL != "" {
+		if err := validateUIURL(prometheusUIURL); err != nil {
+			return util.InvalidInput(err)
+		}
+	}
+	if downAfter < 1 {
+		return util.InvalidInputf("--down-after-failures must be at least 1")
+	}
+	if resolvedRetention < 0 {
+		return util.InvalidInputf("--resolved-retention must not be negative")
+	}
+	entityFilter, err = filter.NewEntityFilter(includeEntity, excludeEntity)
+	if err != nil {
+		return util.InvalidInput(err)
+	}
+	if showAllowed && allowListFile == "" {
+		return util.InvalidInputf("--show-allowed requires --allow-list")
+	}
+	if allowListFile != "" {
+		if allowList, err = filter.LoadAllowList(allowListFile); err != nil {
+			return util.InvalidInputf("--allow-list: %w", err)
+		}
+	}
+	redactor = nil
+	if redactEntities {
+		if redactor, err = redact.New(); err != nil {
+			return util.Runtime(err)
+		}
+	}
+	if aggregateThreshold < 0 {
+		return util.InvalidInputf("--aggregate-threshold must not be negative")
+	}
+	if aggregateCollapse && aggregateThreshold == 0 {
+		return util.InvalidInputf("--aggregate-collapse requires --aggregate-threshold")
+	}
+	if minBlastRadius < 0 {
+		return util.InvalidInputf("--min-blast-radius must not be negative")
+	}
+	if persistenceCap < 0 {
+		return util.InvalidInputf("--persistence-cap must not be negative")
+	}
+	models.SetPersistenceCap(persistenceCap)
+	if recencyWeight < 0 {
+		return util.InvalidInputf("--recency-weight must not be negative")
+	}
+	models.SetRecencyWeight(recencyWeight)
+	if sustainedWindow < 0 {
+		return util.InvalidInputf("--sustained-window must not be negative")
+	}
+	if detectionWindow <= 0 {
+		return util.InvalidInputf("--detection-window must be positive")
+	}
+	if logQueriesEvery < 0 {
+		return util.InvalidInputf("--log-queries-every must not be negative")
+	}
+	if logQueriesEvery > 0 && !logQueries {
+		return util.InvalidInputf("--log-queries-every requires --log-queries")
+	}
+	if watchTable && runOnce {
+		return util.InvalidInputf("--watch and --once are mutually exclusive")
+	}
+	if maxRuntime < 0 {
+		return util.InvalidInputf("--max-runtime must not be negative")
+	}
+	if maxRuntime > 0 && (watchTable || (outputFormat == "prometheus-textfile" && !runOnce)) {
+		return util.InvalidInputf("--max-runtime requires a one-shot run (not --watch; --once with prometheus-textfile)")
+	}
+	if watchTable && outputFormat != "table" && outputFormat != "table-compact" {
+		return util.InvalidInputf("--watch requires --output table or table-compact")
+	}
+	if summaryLine && (watchTable || (outputFormat != "text" && outputFormat != "table" && outputFormat != "table-compact")) {
+		return util.InvalidInputf("--summary-line requires --output text, table or table-compact without --watch")
+	}
+	if _, err := baseline.ParseStateFormat(stateFormat); err != nil {
+		return util.InvalidInputf("--state-format: %w", err)
+	}
+	if tuiWidth < 0 {
+		return util.InvalidInputf("--width must not be negative")
+	}
+	if deployWindow <= 0 {
+		return util.InvalidInputf("--deploy-window must be positive")
+	}
+	if digestInterval > 0 && len(senders) == 0 {
+		return util.InvalidInputf("--digest-interval requires --notify-webhook, --notify-slack or --notify-teams")
+	}
+	if notifyEvents && len(senders) == 0 {
+		return util.InvalidInputf("--notify-events requires --notify-webhook, --notify-slack or --notify-teams")
+	}
+	if fireAfter < 0 || resolveAfter < 0 {
+		return util.InvalidInputf("--notify-fire-after and --notify-resolve-after must not be negative")
+	}
+	if (fireAfter > 0 || resolveAfter > 0) && !notifyEvents {
+		return util.InvalidInputf("--notify-fire-after and --notify-resolve-after require --notify-events")
+	}
+	if notifyStateFile != "" && !notifyEvents {
+		return util.InvalidInputf("--notify-state-file requires --notify-events")
+	}
+	dedupKey, err := notify.ParseDedupKey(notifyDedupKey)
+	if err != nil {
+		return util.InvalidInputf("--notify-dedup-key: %w", err)
+	}
+	sortMode, err = monitor.ParseSortMode(sortOrder)
+	if err != nil {
+		return util.InvalidInputf("--sort: %w", err)
+	}
+	if failOnStableFor < 0 {
+		return util.InvalidInputf("--fail-on-stable-for must not be negative")
+	}
+	if failOnStableFor > 0 {
+		if failOnSeverity == "" {
+			return util.InvalidInputf("--fail-on-stable-for requires --fail-on")
+		}
+		if _, err := models.ParseSeverity(failOnSeverity); err != nil {
+			return util.InvalidInputf("--fail-on: %w", err)
+		}
+	}
+	switch groupBy {
+	case "", service.Label, "namespace", "node":
+	default:
+		return util.InvalidInputf("--group-by must be service, namespace or node")
+	}
+	if groupBy != "" && (outputFormat == "json" || outputFormat == "sarif" || outputFormat == "prometheus-textfile") {
+		return util.InvalidInputf("--group-by requires --output table, text or table-compact")
+	}
+	if baselineDir != "" {
+		if saveBaseline != "" || compareBaseline != "" {
+			return util.InvalidInputf("--baseline-dir cannot be combined with --save-baseline or --compare-baseline")
+		}
+		if watchTable || outputFormat == "sarif" || outputFormat == "prometheus-textfile" {
+			return util.InvalidInputf("--baseline-dir requires one-shot --output json, text or table-compact")
+		}
+		rollingBaseline, err = baseline.NewRolling(baselineDir, baselineKeep)
+		if err != nil {
+			return util.InvalidInputf("--baseline-dir: %w", err)
+		}
+		// The first run has nothing to compare against and only saves
+		compareBaseline, err = rollingBaseline.Latest()
+		if err != nil {
+			return util.InvalidInputf("--baseline-dir: %w", err)
+		}
+	}
+
+	if printConfig {
+		registry, err := buildRegistry(currentConfig())
+		if err != nil {
+			return util.InvalidInput(err)
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(effectiveConfig(cfgPath, currentConfig(), registry))
+	}
+
+	if listEntityTypes {
+		registry, err := buildRegistry(currentConfig())
+		if err != nil {
+			return util.InvalidInput(err)
+		}
+		fmt.Println(strings.Join(registry.EntityTypes(), "\n"))
+		return nil
+	}
+
+	// --max-runtime bounds everything from here: port-forward, health check
+	// and detection
+	if maxRuntime > 0 {
+		runDeadline = time.Now().Add(maxRuntime)
+	}
+
+	// prometheus-textfile writes to a file, never stdout
+	if outputFormat == "prometheus-textfile" && exportFile == "" {
+		return util.InvalidInputf("--output prometheus-textfile requires --export-file")
+	}
+	if err := validateExportFormat(exportFormat, exportFile, outputFormat); err != nil {
+		return util.InvalidInput(err)
+	}
+
+	pfTarget, usePortForward, err := portForwardTarget(k8sService, k8sPod, k8sSelector)
+	if err != nil {
+		return util.InvalidInput(err)
+	}
+
 	// Validate port numbers before use
-	if k8sService != "" {
+	if usePortForward {
 		if err := validatePort(k8sLocalPort, "k8s-local-port"); err != nil {
-			return err
+			return util.InvalidInput(err)
 		}
 		if err := validatePort(k8sRemotePort, "k8s-remote-port"); err != nil {
-			return err
+			return util.InvalidInput(err)
 		}
 	}
 
-	// Setup kubectl port-forward if k8s-service is specified
+	// Setup kubectl port-forward if --k8s-service or --k8s-pod is specified
 	var portForward *util.PortForward
-	if k8sService != "" {
+	if usePortForward {
 		if verbose {
-			fmt.Printf("Setting up native port-forward to %s/%s...\n", k8sNamespace, k8sService)
+			fmt.Printf("Setting up native port-forward to %s/%s...\n", k8sNamespace, pfTarget)
 		}
 
 		var err error
-		portForward, err = util.NewPortForward(k8sService, k8sNamespace, k8sLocalPort, k8sRemotePort)
+		portForward, err = util.NewPortForward(pfTarget, k8sNamespace, k8sLocalPort, k8sRemotePort)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to create port-forward: %v\n", err)
-			fmt.Fprintf(os.Stderr, "Hint: Make sure you have access to the Kubernetes cluster (check ~/.kube/config)\n")
-			util.Exit(util.ExitRuntimeError)
+			return util.Runtimef("failed to create port-forward: %w\nHint: Make sure you have access to the Kubernetes cluster (check ~/.kube/config)", err)
 		}
 
 		if err := portForward.Start(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to start port-forward: %v\n", err)
-			fmt.Fprintf(os.Stderr, "Hint: Check that service '%s' exists in namespace '%s'\n", k8sService, k8sNamespace)
-			util.Exit(util.ExitRuntimeError)
+			return util.Runtimef("failed to start port-forward: %w\nHint: Check that %s exists in namespace '%s'", err, pfTarget, k8sNamespace)
 		}
 
 		// Set prometheus URL to local port-forward
@@ -161,48 +594,152 @@ func runMonitor(cmd *cobra.Command, args []string) error {
 		}()
 	}
 
+	// OpenShift cluster monitoring: thanos-querier, service account token and
+	// service CA
+	var transportOpts []metrics.ClientOption // Headers are added per client
+	if openshift {
+		if usePortForward {
+			return util.InvalidInputf("--openshift cannot be combined with --k8s-service, --k8s-pod or --k8s-selector")
+		}
+		oc, err := openshiftPreset(prometheusURL, headers, serviceAccountDir)
+		if err != nil {
+			return util.InvalidInput(err)
+		}
+		prometheusURL, headers = oc.URL, oc.Headers
+		if oc.CAFile != "" {
+			transportOpts = append(transportOpts, metrics.WithCAFile(oc.CAFile))
+		}
+	}
+
 	// Validate Prometheus URL
 	if prometheusURL == "" {
-		fmt.Fprintf(os.Stderr, "Error: --prometheus-url or --k8s-service is required\n")
-		util.Exit(util.ExitInvalidInput)
+		return util.InvalidInputf("--prometheus-url, --k8s-service, --k8s-pod or --k8s-selector is required")
 	}
 	if err := validatePrometheusURL(prometheusURL); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		util.Exit(util.ExitInvalidInput)
+		return util.InvalidInput(err)
+	}
+
+	// Locate the query API: an explicit --query-path wins, otherwise probe
+	// the common prefixes and keep the URL as given if none answers
+	if queryPath != "" {
+		prometheusURL = metrics.JoinQueryPath(prometheusURL, queryPath)
+	} else {
+		prometheusURL = discoverQueryURL(prometheusURL, headers, transportOpts...)
 	}
 
 	// Create Prometheus client
-	provider, err := metrics.NewPrometheusClient(prometheusURL, prometheusTimeout)
+	client, err := metrics.NewPrometheusClient(prometheusURL, prometheusTimeout,
+		append(transportOpts, metrics.WithHeaders(headers))...)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to create Prometheus client: %v\n", err)
-		util.Exit(util.ExitRuntimeError)
+		return util.Runtimef("failed to create Prometheus client: %w", err)
+	}
+	var provider metrics.MetricsProvider = client
+	if batchQueries {
+		provider = metrics.NewBatchingProvider(client)
+	}
+
+	// Multi-tenant mode: one client per X-Scope-OrgID
+	var tenantProviders map[string]metrics.MetricsProvider
+	if len(tenants) > 0 {
+		tenantProviders, err = newTenantProviders(prometheusURL, headers, tenants, transportOpts...)
+		if err != nil {
+			return util.InvalidInput(err)
+		}
 	}
 
 	// Health check
 	ctx, cancel := context.WithTimeout(context.Background(), prometheusTimeout)
 	defer cancel()
+	if !runDeadline.IsZero() {
+		ctx, cancel = context.WithDeadline(ctx, runDeadline)
+		defer cancel()
+	}
 
-	if err := provider.Health(ctx); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Prometheus health check failed: %v\n", err)
-		if portForward != nil {
-			fmt.Fprintf(os.Stderr, "Hint: Port-forward may still be initializing, try waiting a moment\n")
+	if tenantProviders != nil {
+		if err := checkTenantHealth(ctx, tenantProviders); err != nil {
+			if runTimedOut() {
+				return errMaxRuntime("the health check")
+			}
+			return util.Runtime(err)
+		}
+	} else if err := provider.Health(ctx); err != nil {
+		if runTimedOut() {
+			return errMaxRuntime("the health check")
 		}
-		util.Exit(util.ExitRuntimeError)
+		if hint := healthCheckHint(err, portForward != nil); hint != "" {
+			return util.Runtimef("Prometheus health check failed: %w\nHint: %s", err, hint)
+		}
+		return util.Runtimef("Prometheus health check failed: %w", err)
 	}
 
-	// Create detector registry and register all detectors
-	registry := detector.NewRegistry()
-	registerDetectors(registry)
+	// Create detector registry with config file overrides applied
+	registry, err := buildRegistry(currentConfig())
+	if err != nil {
+		return util.InvalidInput(err)
+	}
 
 	if verbose {
 		fmt.Printf("Connected to Prometheus: %s\n", sanitizeURL(prometheusURL))
 		fmt.Printf("Registered %d detectors\n", registry.Count())
 		fmt.Printf("Refresh interval: %s\n", refreshInterval)
 		fmt.Printf("Output format: %s\n", outputFormat)
+		if cfgPath != "" {
+			fmt.Printf("Config file: %s\n", cfgPath)
+		}
 	}
 
+	setupDeploySources(provider)
+	setupServiceDerivation(provider)
+
+	templates, _ := currentConfig().ProblemTemplates() // Validated on load
+
 	// Setup history store if enabled (WO-08)
-	var watcherOpts []monitor.WatcherOption
+	watcherOpts := []monitor.WatcherOption{
+		monitor.WithProblemTTLs(currentConfig().ProblemTTLs()),
+		monitor.WithDetectionWindow(detectionWindow),
+		monitor.WithDetectorWindows(currentConfig().DetectorWindows()),
+		monitor.WithSeverityOverrides(severityOverrides(currentConfig())),
+		monitor.WithNamespaceCriticality(namespaceCriticality(currentConfig())),
+		monitor.WithLabelNormalization(currentConfig().LabelNormalization),
+		monitor.WithProblemTemplates(templates),
+		monitor.WithDetectorLabels(currentConfig().DetectorLabels),
+		monitor.WithDownAfterFailures(downAfter),
+	}
+	if reportUnavailable {
+		watcherOpts = append(watcherOpts, monitor.WithUnavailableProblem())
+	}
+	// Problems the last run already saw keep their original age
+	if stateFile != "" {
+		prev, err := baseline.LoadState(stateFile)
+		if err != nil {
+			return util.Runtime(err)
+		}
+		watcherOpts = append(watcherOpts, monitor.WithOriginalFirstSeen(prev.FirstSeen()))
+	}
+	// The TUI detail view shows the trajectory over recent cycles
+	if includeHistory || (outputFormat == "table" && !runOnce && !watchTable) {
+		watcherOpts = append(watcherOpts, monitor.WithObservationHistory(monitor.DefaultObservationHistory))
+	}
+	if verbose {
+		watcherOpts = append(watcherOpts, monitor.WithEvalTime(monitor.DefaultEvalStaleAfter))
+	}
+	if prometheusUIURL != "" {
+		watcherOpts = append(watcherOpts, monitor.WithQueryLinks(prometheusUIURL))
+	}
+	if clusterName != "" {
+		watcherOpts = append(watcherOpts, monitor.WithClusterName(clusterName, clusterLabel))
+	}
+	if logQueries {
+		watcherOpts = append(watcherOpts, monitor.WithQueryLogger(monitor.NewQueryLogger(func(format string, args ...any) {
+			fmt.Fprintf(backgroundLog, format, args...)
+		}, logQueriesEvery)))
+	}
+	if tenantProviders != nil {
+		watcherOpts = append(watcherOpts, monitor.WithTenants(tenantProviders))
+		if verbose {
+			fmt.Printf("Tenants: %s\n", strings.Join(tenants, ", "))
+		}
+	}
 	if historyEnabled {
 		dbPath := historyDBPath
 		if dbPath == "" {
@@ -241,151 +778,225 @@ func runMonitor(cmd *cobra.Command, args []string) error {
 	// Create watcher with concurrency controls
 	watcher := monitor.NewWatcher(provider, registry, maxConcurrency, detectorTimeout, watcherOpts...)
 
-	// Setup signal handling
-	monitorCtx, monitorCancel := context.WithCancel(context.Background())
-	defer monitorCancel()
+	// Setup signal handling. A cancel cause other than context.Canceled
+	// (e.g. stale data) is returned once the output mode stops.
+	monitorCtx, monitorCancel := context.WithCancelCause(context.Background())
+	defer monitorCancel(nil)
 
-	// Start watcher in background
+	// Auto-detect: fall back to text when stdout is piped
+	stdoutIsTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+	if outputFormat == "table" && !watchTable && !stdoutIsTerminal {
+		outputFormat = "text"
+	}
+
+	if (outputFormat == "table" && !runOnce) || (watchTable && stdoutIsTerminal) {
+		backgroundLog = io.Discard
+	}
+	if maxRuntime > 0 && outputFormat == "table" && !runOnce {
+		return util.InvalidInputf("--max-runtime requires a one-shot run: add --once or use --output json, text or sarif")
+	}
+	if groupBy != "" && outputFormat == "table" && !runOnce && !watchTable {
+		return util.InvalidInputf("--group-by does not apply to the interactive TUI: add --once or --watch")
+	}
+	if failOnStableFor > 0 && outputFormat == "table" && !runOnce && !watchTable {
+		return util.InvalidInputf("--fail-on-stable-for does not apply to the interactive TUI: add --watch or use --output prometheus-textfile")
+	}
+
+	// Only once every flag is valid, so a rejected run leaves the process
+	// untouched. The GC works harder near the limit; the watchdog catches a
+	// live heap it cannot get back under.
+	if watchdogLimits.MaxHeapBytes > 0 {
+		debug.SetMemoryLimit(int64(watchdogLimits.MaxHeapBytes))
+	}
+
+	// Start watcher in background, after backgroundLog is settled since query
+	// logging writes to it from the first cycle
 	go func() {
 		if err := watcher.Start(monitorCtx); err != nil {
 			fmt.Fprintf(os.Stderr, "Watcher error: %v\n", err)
 		}
 	}()
 
-	// Auto-detect: fall back to text when stdout is piped
-	if outputFormat == "table" && !term.IsTerminal(int(os.Stdout.Fd())) {
-		outputFormat = "text"
+	// Liveness/readiness probes for running under an orchestrator
+	if healthListenAddr != "" {
+		if err := startHealthServer(monitorCtx, healthListenAddr, watcher); err != nil {
+			return util.Runtime(err)
+		}
+	}
+
+	// Profiling infranow itself
+	if pprofAddr != "" {
+		if err := startPprofServer(monitorCtx, pprofAddr); err != nil {
+			return util.Runtime(err)
+		}
+	}
+
+	// Hot-reload the config file while monitoring
+	if cfgPath != "" {
+		if err := watchConfigFile(monitorCtx, cfgPath, watcher, backgroundLog); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: config hot-reload disabled: %v\n", err)
+		}
+	}
+
+	// Stale data guard: the TUI shows an alarm instead of exiting
+	if maxDataStaleness > 0 && (outputFormat != "table" || runOnce || watchTable) {
+		go watcher.WatchDataStaleness(monitorCtx, maxDataStaleness, func(age time.Duration) {
+			monitorCancel(util.Runtimef("no fresh Prometheus data for %s (--max-data-staleness %s)",
+				age.Round(time.Second), maxDataStaleness))
+		})
+	}
+
+	// Self-protection: a leaking infranow exits instead of becoming the
+	// outage. The TUI is interactive and keeps running.
+	if watchdogLimits != (util.WatchdogLimits{}) && (outputFormat != "table" || runOnce || watchTable) {
+		go util.NewWatchdog(watchdogLimits, util.DefaultWatchdogInterval).Run(monitorCtx, func(err error) {
+			monitorCancel(util.Runtimef("watchdog: %w", err))
+		})
+	}
+
+	// --fail-on in continuous modes, once startup noise has settled: only a
+	// problem persisting for --fail-on-stable-for ends the run
+	if failOnStableFor > 0 && !runOnce && (watchTable || outputFormat == "prometheus-textfile") {
+		threshold, _ := models.ParseSeverity(failOnSeverity) // Validated above
+		go monitor.WatchStableProblems(monitorCtx, func() []*models.Problem {
+			return applyFilters(watcher.GetProblems())
+		}, threshold, failOnStableFor, func(stable []*models.Problem) {
+			fmt.Fprintf(os.Stderr, "[infranow] --fail-on %s: %s %s persisted for %s\n",
+				threshold, stable[0].Severity, stable[0].Entity, failOnStableFor)
+			monitorCancel(util.ExitStatus(util.ExitProblemsWarning))
+		})
+	}
+
+	// Overflow summaries of rate-limited channels
+	for _, s := range senders {
+		if limited, ok := s.(*notify.RateLimitedSender); ok {
+			go limited.Run(monitorCtx, func(err error) {
+				fmt.Fprintf(backgroundLog, "[infranow] throttled notification summary failed: %v\n", err)
+			})
+		}
+	}
+
+	// Periodic digest notifications
+	if digestInterval > 0 {
+		digester := notify.NewDigester(digestInterval, func() []*models.Problem {
+			return notifyProblems(watcher)
+		}, func(err error) {
+			fmt.Fprintf(backgroundLog, "[infranow] digest notification failed: %v\n", err)
+		}, senders...)
+		go digester.Run(monitorCtx)
+	}
+
+	// Firing/resolved notifications, deduplicated on --notify-dedup-key
+	if notifyEvents {
+		notifier := notify.NewEventNotifier(refreshInterval, func() []*models.Problem {
+			return notifyProblems(watcher)
+		}, dedupKey, func(err error) {
+			fmt.Fprintf(backgroundLog, "[infranow] event notification failed: %v\n", err)
+		}, senders...)
+		notifier.SetHysteresis(fireAfter, resolveAfter)
+		if notifyStateFile != "" {
+			if err := notifier.SetStateFile(notifyStateFile); err != nil {
+				return util.Runtimef("--notify-state-file: %w", err)
+			}
+		}
+		go notifier.Run(monitorCtx)
+	}
+
+	// OTLP log export: one-shot modes send their result once, long-running
+	// modes every refresh interval
+	if logExporter != nil && ((outputFormat == "table" && !runOnce) || watchTable || (outputFormat == "prometheus-textfile" && !runOnce)) {
+		go runLogExport(monitorCtx, watcher)
+	}
+
+	err = runOutput(monitorCtx, watcher, stdoutIsTerminal, portForward)
+	if cause := context.Cause(monitorCtx); cause != nil && !errors.Is(cause, context.Canceled) {
+		return cause
+	}
+	return err
+}
+
+// runOutput runs the selected output mode until it finishes or ctx ends
+func runOutput(ctx context.Context, watcher *monitor.Watcher, stdoutIsTerminal bool, portForward *util.PortForward) error {
+	if watchTable {
+		render := textRenderer(monitor.PlainText)
+		if outputFormat == "table-compact" {
+			render = textRenderer(monitor.CompactText)
+		}
+		return runWatchMode(ctx, watcher, monitor.NewLiveTable(os.Stdout, render, stdoutIsTerminal))
 	}
 
 	switch outputFormat {
 	case "json":
-		return runJSONMode(monitorCtx, watcher)
+		return runJSONMode(ctx, watcher)
 	case "text":
-		return runTextMode(monitorCtx, watcher)
+		return runTextMode(ctx, watcher, textRenderer(monitor.PlainText))
+	case "table-compact":
+		return runTextMode(ctx, watcher, textRenderer(monitor.CompactText))
 	case "sarif":
-		return runSARIFMode(monitorCtx, watcher)
+		return runSARIFMode(ctx, watcher)
+	case "prometheus-textfile":
+		return runTextfileMode(ctx, watcher)
 	default:
 		if runOnce {
-			return runTextMode(monitorCtx, watcher)
-		}
-		return runTUIMode(monitorCtx, watcher, prometheusURL, refreshInterval, portForward)
-	}
-}
-
-func registerDetectors(registry *detector.Registry) {
-	// Kubernetes detectors
-	registry.Register(detector.NewOOMKillDetector())
-	registry.Register(detector.NewCrashLoopBackOffDetector())
-	registry.Register(detector.NewImagePullBackOffDetector())
-	registry.Register(detector.NewPodPendingDetector())
-
-	// Generic detectors
-	registry.Register(detector.NewHighErrorRateDetector())
-	registry.Register(detector.NewDiskSpaceDetector())
-	registry.Register(detector.NewHighMemoryPressureDetector())
-
-	// Service mesh control plane detectors
-	registry.Register(detector.NewLinkerdControlPlaneDetector())
-	registry.Register(detector.NewLinkerdProxyInjectionDetector())
-	registry.Register(detector.NewIstioControlPlaneDetector())
-	registry.Register(detector.NewIstioSidecarInjectionDetector())
-
-	// Service mesh certificate expiry detectors
-	registry.Register(detector.NewLinkerdCertExpiryDetector())
-	registry.Register(detector.NewIstioCertExpiryDetector())
-
-	// Trustwatch certificate detectors
-	registry.Register(detector.NewTrustwatchCertExpiryDetector())
-	registry.Register(detector.NewTrustwatchProbeFailureDetector())
-
-	// Tote image salvage detectors
-	registry.Register(detector.NewToteSalvageFailureDetector())
-	registry.Register(detector.NewTotePushFailureDetector())
-	registry.Register(detector.NewToteHighFailureRateDetector())
-
-	// pgpulse (PostgreSQL) detectors
-	registry.Register(detector.NewPgConnectionExhaustionDetector())
-	registry.Register(detector.NewPgReplicationLagDetector())
-	registry.Register(detector.NewPgDeadTupleRatioDetector())
-	registry.Register(detector.NewPgLockChainDepthDetector())
-	registry.Register(detector.NewPgSlowQueriesDetector())
-
-	// clickpulse (ClickHouse) detectors
-	registry.Register(detector.NewChMergePressureDetector())
-	registry.Register(detector.NewChStuckMutationsDetector())
-	registry.Register(detector.NewChReplicaLagDetector())
-	registry.Register(detector.NewChPartCountExplosionDetector())
-	registry.Register(detector.NewChDDLQueueStuckDetector())
-	registry.Register(detector.NewChKeeperHighLatencyDetector())
-	registry.Register(detector.NewChKeeperOutstandingRequestsDetector())
-
-	// mongopulse (MongoDB) detectors
-	registry.Register(detector.NewMongoConnectionExhaustionDetector())
-	registry.Register(detector.NewMongoReplicationLagDetector())
-	registry.Register(detector.NewMongoOplogWindowDetector())
-	registry.Register(detector.NewMongoLockPercentageDetector())
-	registry.Register(detector.NewMongoCursorTimeoutDetector())
-
-	// mysqlpulse (MySQL/MariaDB) detectors
-	registry.Register(detector.NewMySQLConnectionExhaustionDetector())
-	registry.Register(detector.NewMySQLReplicationLagDetector())
-	registry.Register(detector.NewMySQLDeadlocksDetector())
-	registry.Register(detector.NewMySQLSlowQueriesDetector())
-	registry.Register(detector.NewMySQLInnoDBBufferPoolPressureDetector())
-
-	// airflowpulse (Apache Airflow) detectors
-	registry.Register(detector.NewAirflowDAGFailureRateDetector())
-	registry.Register(detector.NewAirflowSchedulerHeartbeatDetector())
-	registry.Register(detector.NewAirflowTaskQueueBacklogDetector())
-	registry.Register(detector.NewAirflowPoolExhaustionDetector())
-	registry.Register(detector.NewAirflowZombieTasksDetector())
+			return runTextMode(ctx, watcher, textRenderer(monitor.PlainText))
+		}
+		return runTUIMode(ctx, watcher, prometheusURL, refreshInterval, portForward)
+	}
+}
+
+// textRenderer applies --group-by to a text renderer
+func textRenderer(render func([]*models.Problem, time.Time) string) func([]*models.Problem, time.Time) string {
+	if groupBy == "" {
+		return render
+	}
+	return monitor.Grouped(render, groupBy)
 }
 
 func runJSONMode(ctx context.Context, watcher *monitor.Watcher) error {
 	// Wait for first detection cycle to complete
-	select {
-	case <-watcher.UpdateChan():
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.After(firstDetectionTimeout):
+	incomplete, err := waitFirstCycle(ctx, watcher)
+	if err != nil {
+		return err
 	}
 
-	problems := watcher.GetProblems()
+	problems := watcher.ProblemsBy(sortMode)
+	unfiltered := len(problems)
 
 	// Apply namespace filter (v0.1.2 Feature 3)
-	problems = applyFilters(problems)
+	problems, stats := filterProblems(problems)
 	problems = correlator.Correlate(problems)
+	problems = annotateDeploys(annotateServices(problems))
 	watcher.AnnotateHistory(problems)
+	watcher.AnnotateObservations(problems)
 
-	// Save baseline if requested (v0.1.2 Feature 1)
-	if saveBaseline != "" {
-		metadata := map[string]string{
-			"prometheus_url": prometheusURL,
-			"version":        version,
-		}
-		if err := baseline.SaveBaseline(problems, saveBaseline, metadata); err != nil {
-			return fmt.Errorf("failed to save baseline: %w", err)
-		}
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Baseline saved to: %s\n", saveBaseline)
-		}
+	// Baselines: compare to and save as requested
+	b, err := comparedBaseline(problems, incomplete)
+	if err != nil {
+		return err
 	}
 
 	// Compare to baseline if requested (v0.1.2 Feature 1)
-	if compareBaseline != "" {
-		b, err := baseline.LoadBaseline(compareBaseline)
-		if err != nil {
-			return fmt.Errorf("failed to load baseline: %w", err)
-		}
+	if b != nil {
 		comparison := baseline.Compare(problems, b)
+		comparison.New = redactProblems(comparison.New)
+		comparison.Resolved = redactProblems(comparison.Resolved)
+		comparison.Unchanged = redactProblems(comparison.Unchanged)
+		models.InDisplayLocation(comparison.New)
+		models.InDisplayLocation(comparison.Resolved)
+		models.InDisplayLocation(comparison.Unchanged)
 
 		// Output comparison instead of raw problems
+		metadata := map[string]interface{}{
+			"prometheus_url": prometheusURL,
+			"timestamp":      models.FormatTime(time.Now()),
+			"baseline_time":  models.FormatTime(b.Timestamp),
+		}
+		if clusterName != "" {
+			metadata["cluster"] = clusterName
+		}
+		redactMetadata(metadata)
 		output := map[string]interface{}{
-			"metadata": map[string]interface{}{
-				"prometheus_url": prometheusURL,
-				"timestamp":      time.Now().Format(time.RFC3339),
-				"baseline_time":  b.Timestamp.Format(time.RFC3339),
-			},
+			"metadata":   metadata,
 			"comparison": comparison,
 		}
 
@@ -395,30 +1006,26 @@ func runJSONMode(ctx context.Context, watcher *monitor.Watcher) error {
 			return fmt.Errorf("failed to encode JSON: %w", err)
 		}
 
+		// Change detection tracks the full problem set and overrides the
+		// drift exit codes, as without a baseline
+		if stateFile != "" {
+			return exitOnStateChange(problems)
+		}
+
 		// Fail if new problems detected (v0.1.2 Feature 1)
 		if failOnDrift && len(comparison.New) > 0 {
-			util.Exit(util.ExitProblemsWarning)
+			return util.ExitStatus(util.ExitProblemsWarning)
 		}
 
-		return nil
+		return exitOnNewAtLeast(comparison, util.ExitProblemsWarning)
 	}
 
 	// Normal JSON output
-	summary := watcher.GetSummary()
-	output := map[string]interface{}{
-		"metadata": map[string]interface{}{
-			"prometheus_url":   prometheusURL,
-			"timestamp":        time.Now().Format(time.RFC3339),
-			"refresh_interval": refreshInterval.String(),
-		},
-		"summary": map[string]interface{}{
-			"total_problems": len(problems),
-			"fatal":          summary[models.SeverityFatal],
-			"critical":       summary[models.SeverityCritical],
-			"warning":        summary[models.SeverityWarning],
-			"incidents":      countIncidents(problems),
-		},
-		"problems": problems,
+	models.InDisplayLocation(problems)
+	output := jsonReport(watcher, redactProblems(problems))
+	addFilterSummary(output, unfiltered, stats)
+	if incomplete {
+		output["metadata"].(map[string]interface{})["incomplete"] = true
 	}
 
 	encoder := json.NewEncoder(os.Stdout)
@@ -427,27 +1034,17 @@ func runJSONMode(ctx context.Context, watcher *monitor.Watcher) error {
 		return fmt.Errorf("failed to encode JSON: %w", err)
 	}
 
-	// Export to file if specified
-	if exportFile != "" {
-		file, err := os.OpenFile(exportFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
-		if err != nil {
-			return fmt.Errorf("failed to create export file: %w", err)
-		}
-		defer func() {
-			if err := file.Close(); err != nil {
-				fmt.Fprintf(os.Stderr, "[infranow] warning: failed to close export file: %v\n", err)
-			}
-		}()
-
-		encoder := json.NewEncoder(file)
-		encoder.SetIndent("", "  ")
-		if err := encoder.Encode(output); err != nil {
-			return fmt.Errorf("failed to write export file: %w", err)
-		}
+	if err := writeExport(watcher, problems); err != nil {
+		return err
+	}
+	exportLogs(ctx, problems)
+	if incomplete {
+		return errMaxRuntime("detection")
+	}
 
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Exported to: %s\n", exportFile)
-		}
+	// Change detection overrides severity exit codes
+	if stateFile != "" {
+		return exitOnStateChange(problems)
 	}
 
 	// Check fail-on severity threshold (v0.1.2 Feature 2)
@@ -459,7 +1056,7 @@ func runJSONMode(ctx context.Context, watcher *monitor.Watcher) error {
 
 		for _, p := range problems {
 			if p.Severity.AtLeast(threshold) {
-				util.Exit(util.ExitProblemsWarning) // Fail CI/CD
+				return util.ExitStatus(util.ExitProblemsWarning) // Fail CI/CD
 			}
 		}
 	}
@@ -467,51 +1064,149 @@ func runJSONMode(ctx context.Context, watcher *monitor.Watcher) error {
 	return nil
 }
 
-func runTextMode(ctx context.Context, watcher *monitor.Watcher) error {
+// jsonReport builds the JSON mode document for problems
+func jsonReport(watcher *monitor.Watcher, problems []*models.Problem) map[string]interface{} {
+	// Count what is reported, so filtered and allow-listed problems never
+	// show up in the totals
+	summary := make(map[models.Severity]int)
+	for _, p := range problems {
+		summary[p.Severity]++
+	}
+	metadata := map[string]interface{}{
+		"prometheus_url":   prometheusURL,
+		"timestamp":        models.FormatTime(time.Now()),
+		"refresh_interval": refreshInterval.String(),
+	}
+	if health := watcher.GetTenantHealth(); health != nil {
+		metadata["tenants"] = health
+	}
+	if clusterName != "" {
+		metadata["cluster"] = clusterName
+	}
+	metadata["detectors"] = detectorMetadata(watcher.DetectorStats())
+	redactMetadata(metadata)
+	for _, p := range problems {
+		p.MetricsFormatted = p.FormattedMetrics()
+	}
+	affected, topNamespaces := monitor.AffectedNamespaces(problems, monitor.TopNamespaces)
+	report := map[string]interface{}{
+		"metadata": metadata,
+		"summary": map[string]interface{}{
+			"total_problems":      len(problems),
+			"fatal":               summary[models.SeverityFatal],
+			"critical":            summary[models.SeverityCritical],
+			"warning":             summary[models.SeverityWarning],
+			"incidents":           countIncidents(problems),
+			"affected_namespaces": affected,
+			"top_namespaces":      topNamespaces,
+		},
+		"problems": problems,
+	}
+
+	// Shown for reference only, outside the summary and --fail-on
+	if allowed := allowedProblems(watcher.ProblemsBy(sortMode)); allowed != nil {
+		allowed = redactProblems(allowed)
+		models.InDisplayLocation(allowed)
+		report["allowed"] = allowed
+	}
+	return report
+}
+
+// addFilterSummary records in a JSON report how many problems were found
+// before filtering and how many each filter removed
+func addFilterSummary(report map[string]interface{}, unfiltered int, stats filterStats) {
+	summary := report["summary"].(map[string]interface{})
+	summary["unfiltered_problems"] = unfiltered
+	summary["filtered_problems"] = stats.Total()
+	summary["filtered"] = stats
+}
+
+// detectorMetadata records how each active detector ran, so an exported
+// snapshot says which detectors it covers, over what window, and which of
+// them failed or had not run yet
+func detectorMetadata(stats []monitor.DetectorStat) []map[string]interface{} {
+	detectors := make([]map[string]interface{}, 0, len(stats))
+	for _, s := range stats {
+		d := map[string]interface{}{
+			"name":     s.Detector,
+			"interval": s.Interval.String(),
+			"window":   s.Window.String(),
+			"status":   "ok",
+		}
+		if s.Tenant != "" {
+			d["tenant"] = s.Tenant
+		}
+		if !s.LastRun.IsZero() {
+			d["last_run"] = models.FormatTime(s.LastRun)
+		}
+		switch {
+		case s.LastRun.IsZero():
+			d["status"] = "pending"
+		case s.Err != "":
+			d["status"] = "error"
+			d["error"] = s.Err
+		}
+		detectors = append(detectors, d)
+	}
+	return detectors
+}
+
+// runTextMode renders one snapshot with render (PlainText or CompactText)
+func runTextMode(ctx context.Context, watcher *monitor.Watcher, render func([]*models.Problem, time.Time) string) (err error) {
 	// Wait for first detection cycle
-	select {
-	case <-watcher.UpdateChan():
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.After(firstDetectionTimeout):
+	incomplete, err := waitFirstCycle(ctx, watcher)
+	if err != nil {
+		return err
 	}
 
-	problems := watcher.GetProblems()
+	problems := watcher.ProblemsBy(sortMode)
 	problems = applyFilters(problems)
 	problems = correlator.Correlate(problems)
+	problems = annotateDeploys(annotateServices(problems))
 	watcher.AnnotateHistory(problems)
 
-	// Save baseline if requested
-	if saveBaseline != "" {
-		metadata := map[string]string{
-			"prometheus_url": prometheusURL,
-			"version":        version,
-		}
-		if err := baseline.SaveBaseline(problems, saveBaseline, metadata); err != nil {
-			return fmt.Errorf("failed to save baseline: %w", err)
-		}
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Baseline saved to: %s\n", saveBaseline)
-		}
+	// The summary counts what was printed, with the exit code the run ends
+	// with, after every other output line
+	shown := problems
+	if summaryLine {
+		defer func() { fmt.Println(monitor.SummaryLine(shown, util.ExitCode(err))) }()
+	}
+
+	// Baselines: compare to and save as requested
+	b, err := comparedBaseline(problems, incomplete)
+	if err != nil {
+		return err
 	}
 
 	// Compare to baseline if requested
-	if compareBaseline != "" {
-		b, err := baseline.LoadBaseline(compareBaseline)
-		if err != nil {
-			return fmt.Errorf("failed to load baseline: %w", err)
-		}
+	if b != nil {
 		comparison := baseline.Compare(problems, b)
-		fmt.Print(monitor.PlainText(comparison.New, time.Now()))
+		shown = comparison.New
+		fmt.Print(render(redactProblems(comparison.New), time.Now()))
+		if stateFile != "" {
+			return exitOnStateChange(problems)
+		}
 		if failOnDrift && len(comparison.New) > 0 {
-			util.Exit(util.ExitProblemsWarning)
+			return util.ExitStatus(util.ExitProblemsWarning)
 		}
-		return nil
+		return exitOnNewAtLeast(comparison, util.ExitProblemsCritical)
 	}
 
 	// Render plain text table
-	fmt.Print(monitor.PlainText(problems, time.Now()))
+	fmt.Print(render(redactProblems(problems), time.Now()))
 	fmt.Fprintln(os.Stderr, monitor.PlainTextSummary(problems))
+	if err := writeExport(watcher, problems); err != nil {
+		return err
+	}
+	exportLogs(ctx, problems)
+	if incomplete {
+		return errMaxRuntime("detection")
+	}
+
+	// Change detection overrides severity exit codes
+	if stateFile != "" {
+		return exitOnStateChange(problems)
+	}
 
 	// Check --fail-on threshold (explicit override)
 	if failOnSeverity != "" {
@@ -521,54 +1216,68 @@ func runTextMode(ctx context.Context, watcher *monitor.Watcher) error {
 		}
 		for _, p := range problems {
 			if p.Severity.AtLeast(threshold) {
-				util.Exit(util.ExitProblemsCritical)
+				return util.ExitStatus(util.ExitProblemsCritical)
 			}
 		}
 		return nil
 	}
 
 	// Tiered exit code based on highest severity
-	if len(problems) > 0 {
-		switch monitor.HighestSeverity(problems) {
-		case models.SeverityCritical, models.SeverityFatal:
-			util.Exit(util.ExitProblemsCritical)
-		default:
-			util.Exit(util.ExitProblemsWarning)
+	return util.ExitStatus(severityExitCode(problems))
+}
+
+// notifyProblems returns the current problems as sent in notifications
+func notifyProblems(watcher *monitor.Watcher) []*models.Problem {
+	problems := annotateDeploys(annotateServices(correlator.Correlate(applyFilters(watcher.GetProblems()))))
+	models.InDisplayLocation(problems)
+	return problems
+}
+
+// runWatchMode redraws the problem table after every detection cycle until
+// ctx is cancelled
+func runWatchMode(ctx context.Context, watcher *monitor.Watcher, live *monitor.LiveTable) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-watcher.UpdateChan():
+			if !ok {
+				return nil
+			}
+			problems := annotateDeploys(annotateServices(correlator.Correlate(applyFilters(watcher.ProblemsBy(sortMode)))))
+			if err := live.Update(redactProblems(problems), time.Now()); err != nil {
+				return fmt.Errorf("failed to write table: %w", err)
+			}
 		}
 	}
-
-	return nil
 }
 
 func runSARIFMode(ctx context.Context, watcher *monitor.Watcher) error {
 	// Wait for first detection cycle
-	select {
-	case <-watcher.UpdateChan():
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.After(firstDetectionTimeout):
+	incomplete, err := waitFirstCycle(ctx, watcher)
+	if err != nil {
+		return err
 	}
 
-	problems := watcher.GetProblems()
+	problems := watcher.ProblemsBy(sortMode)
 	problems = applyFilters(problems)
 	problems = correlator.Correlate(problems)
+	problems = annotateDeploys(annotateServices(problems))
 	watcher.AnnotateHistory(problems)
 
 	// Compare to baseline if requested — SARIF output for new problems only
-	if compareBaseline != "" {
+	if compareBaseline != "" && !incomplete {
 		b, err := baseline.LoadBaseline(compareBaseline)
 		if err != nil {
 			return fmt.Errorf("failed to load baseline: %w", err)
 		}
+		// Only new problems remain, so the tiered exit code below also
+		// covers --fail-on-drift
 		comparison := baseline.Compare(problems, b)
 		problems = comparison.New
-
-		if failOnDrift && len(problems) > 0 {
-			defer util.Exit(util.ExitProblemsWarning)
-		}
 	}
 
-	data, err := monitor.SARIF(problems, version)
+	data, err := monitor.SARIF(redactProblems(problems), version)
 	if err != nil {
 		return fmt.Errorf("failed to render SARIF: %w", err)
 	}
@@ -576,33 +1285,131 @@ func runSARIFMode(ctx context.Context, watcher *monitor.Watcher) error {
 		return fmt.Errorf("failed to write SARIF output: %w", err)
 	}
 	fmt.Fprintln(os.Stderr, monitor.FormatSARIFSummary(problems))
+	if err := writeExport(watcher, problems); err != nil {
+		return err
+	}
+	exportLogs(ctx, problems)
+	if incomplete {
+		return errMaxRuntime("detection")
+	}
+
+	// Change detection overrides severity exit codes
+	if stateFile != "" {
+		return exitOnStateChange(problems)
+	}
 
 	// Tiered exit code based on highest severity
-	if len(problems) > 0 {
-		switch monitor.HighestSeverity(problems) {
-		case models.SeverityCritical, models.SeverityFatal:
-			util.Exit(util.ExitProblemsCritical)
-		default:
-			util.Exit(util.ExitProblemsWarning)
+	return util.ExitStatus(severityExitCode(problems))
+}
+
+// runTextfileMode writes problem gauges for node_exporter's textfile collector.
+// With --once it writes a single snapshot (cron usage); otherwise the file is
+// rewritten every refresh interval until interrupted, keeping problems that
+// resolved within --resolved-retention.
+func runTextfileMode(ctx context.Context, watcher *monitor.Watcher) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Wait for first detection cycle
+	incomplete := false
+	select {
+	case <-watcher.UpdateChan():
+	case <-ctx.Done():
+		return nil
+	case <-runDeadlineC():
+		incomplete = true
+	case <-time.After(firstDetectionTimeout):
+	}
+	// A one-shot run must not publish an empty file when nothing could be
+	// queried: node_exporter would report the cluster healthy
+	if runOnce && !incomplete {
+		if err := errNoData(watcher); err != nil {
+			return err
 		}
 	}
 
-	return nil
+	retention := monitor.NewResolvedRetention(resolvedRetention)
+	write := func() error {
+		problems := watcher.GetProblems()
+		problems = applyFilters(problems)
+		now := time.Now()
+		resolved := retention.Observe(problems, now)
+		if err := monitor.WriteTextfile(exportFile, monitor.PrometheusTextfile(problems, resolved, now)); err != nil {
+			return fmt.Errorf("failed to write textfile: %w", err)
+		}
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Wrote %d problems to: %s\n", len(problems), exportFile)
+		}
+		return nil
+	}
+
+	if err := write(); err != nil {
+		return err
+	}
+	if incomplete {
+		return errMaxRuntime("detection")
+	}
+	if runOnce {
+		exportLogs(ctx, applyFilters(watcher.GetProblems()))
+		return nil
+	}
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := write(); err != nil {
+				// Keep the previous file in place and retry next tick
+				fmt.Fprintf(os.Stderr, "[infranow] warning: %v\n", err)
+			}
+		}
+	}
 }
 
 func runTUIMode(ctx context.Context, watcher *monitor.Watcher, prometheusURL string, refreshInterval time.Duration, portForward *util.PortForward) error {
 	// Silence klog so client-go port-forward errors don't corrupt the TUI
 	klog.SetOutput(io.Discard)
 
-	// Create TUI model
-	model := monitor.NewModel(watcher, prometheusURL, refreshInterval, portForward)
+	go refreshAnnotationCaches(ctx, refreshInterval)
+
+	// Create TUI model. Mutes are shared with applyFilters so muted problems
+	// also stay out of notifications.
+	mutes = filter.NewMutes(nil)
+	modelOpts := []monitor.ModelOption{
+		monitor.WithProblemFilter(tuiProblems),
+		monitor.WithMutes(mutes),
+		monitor.WithMuteKey(unredactedID),
+		monitor.WithMaxDataStaleness(maxDataStaleness),
+		monitor.WithSortMode(sortMode),
+	}
+	if tuiWidth > 0 {
+		modelOpts = append(modelOpts, monitor.WithFixedWidth(tuiWidth))
+	}
+	b, err := loadComparedBaseline()
+	if err != nil {
+		return err
+	}
+	if b != nil {
+		modelOpts = append(modelOpts, monitor.WithBaseline(b))
+	}
+	if redactor != nil {
+		modelOpts = append(modelOpts, monitor.WithHiddenSource())
+	}
+	model := monitor.NewModel(watcher, prometheusURL, refreshInterval, portForward, modelOpts...)
 
 	// Setup signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
 	// Run TUI
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	var programOpts []tea.ProgramOption
+	if !noAltScreen {
+		programOpts = append(programOpts, tea.WithAltScreen())
+	}
+	p := tea.NewProgram(model, programOpts...)
 
 	go func() {
 		<-sigChan
@@ -615,15 +1422,287 @@ func runTUIMode(ctx context.Context, watcher *monitor.Watcher, prometheusURL str
 	return nil
 }
 
-// applyFilters applies namespace filtering to problems (v0.1.2 Feature 3)
+// redactProblems returns problems pseudonymized for output with
+// --redact-entities, and problems themselves otherwise
+func redactProblems(problems []*models.Problem) []*models.Problem {
+	if redactor == nil {
+		return problems
+	}
+	return redactor.Problems(problems)
+}
+
+// tuiProblems is the TUI's problem filter: applyFilters with annotations,
+// then allow-listed problems listed after the rest (marked ~), redacted last.
+// Deploys and services come from the caches refreshAnnotationCaches keeps.
+func tuiProblems(problems []*models.Problem) []*models.Problem {
+	return redactProblems(append(annotateCachedDeploys(annotateCachedServices(applyFilters(problems))), allowedProblems(problems)...))
+}
+
+// unredactedID returns the ID the watcher knows a listed problem by, so a
+// mute from a redacted TUI applies to the real problem and its notifications
+func unredactedID(p *models.Problem) string {
+	if redactor != nil {
+		if id, ok := redactor.RevealID(p.ID); ok {
+			return id
+		}
+	}
+	return p.ID
+}
+
+// redactMetadata drops the backend URL from JSON metadata and pseudonymizes
+// the cluster name with --redact-entities
+func redactMetadata(metadata map[string]interface{}) {
+	if redactor == nil {
+		return
+	}
+	delete(metadata, "prometheus_url")
+	if cluster, ok := metadata["cluster"].(string); ok {
+		metadata["cluster"] = redactor.Token(cluster)
+	}
+}
+
+// filterStats counts the problems each filter removed, so JSON consumers can
+// tell "5 of 42 shown" from "5 found". Merging (certificate dedup, container
+// coalescing, aggregation) is not filtering and is not counted.
+type filterStats struct {
+	Namespace   int `json:"namespace"`
+	Entity      int `json:"entity"`
+	AllowList   int `json:"allow_list"`
+	Muted       int `json:"muted"`
+	BlastRadius int `json:"min_blast_radius"`
+	Severity    int `json:"min_severity"`
+}
+
+// Total is the number of problems removed by all filters
+func (s filterStats) Total() int {
+	return s.Namespace + s.Entity + s.AllowList + s.Muted + s.BlastRadius + s.Severity
+}
+
+// applyFilters applies namespace filtering to problems (v0.1.2 Feature 3).
+// Flags take precedence over the config file.
 func applyFilters(problems []*models.Problem) []*models.Problem {
+	problems, _ = filterProblems(problems)
+	return problems
+}
+
+// filterProblems is applyFilters, also counting what each filter removed
+func filterProblems(problems []*models.Problem) ([]*models.Problem, filterStats) {
+	var stats filterStats
+	problems = scopeFilters(problems, &stats)
+
+	// Allow-listed problems never reach counts, --fail-on or notifications
+	if allowList != nil {
+		n := len(problems)
+		problems, _ = allowList.Split(problems)
+		stats.AllowList = n - len(problems)
+	}
+
+	// So are problems muted from the TUI, until their mute expires
+	if mutes != nil {
+		n := len(problems)
+		problems, _ = mutes.Split(problems)
+		stats.Muted = n - len(problems)
+	}
+
+	// One certificate watched by several sources is one problem
+	problems = correlator.DedupCertificates(problems)
+
+	if coalesceContainers {
+		problems = correlator.CoalesceContainers(problems)
+	}
+
+	// Aggregate before the blast radius filter: many single-pod problems
+	// together are exactly what it should let through
+	problems = correlator.Aggregate(problems, aggregateThreshold, aggregateCollapse)
+	n := len(problems)
+	problems = filter.MinBlastRadius(problems, minBlastRadius)
+	stats.BlastRadius = n - len(problems)
+	n = len(problems)
+	problems = filter.MinSeverity(problems, minSeverityLevel)
+	stats.Severity = n - len(problems)
+
+	return problems, stats
+}
+
+// scopeFilters applies the namespace and entity filters, counting what they
+// removed into stats (nil = not counted). A monitoring outage
+// (--report-unavailable) concerns every scope and is always kept.
+func scopeFilters(problems []*models.Problem, stats *filterStats) []*models.Problem {
+	if stats == nil {
+		stats = &filterStats{}
+	}
+	var unavailable []*models.Problem
+	if reportUnavailable {
+		kept := make([]*models.Problem, 0, len(problems))
+		for _, p := range problems {
+			if p.Type == monitor.UnavailableProblemType {
+				unavailable = append(unavailable, p)
+			} else {
+				kept = append(kept, p)
+			}
+		}
+		problems = kept
+	}
+	include, exclude := effectiveNamespaces(currentConfig())
+
 	// Apply namespace filter if specified
-	if includeNamespaces != "" || excludeNamespaces != "" {
-		nsFilter := filter.NewNamespaceFilter(includeNamespaces, excludeNamespaces)
+	if include != "" || exclude != "" {
+		nsFilter := filter.NewNamespaceFilter(include, exclude)
+		nsFilter.IncludeUnnamespaced = includeUnnamespaced
+		n := len(problems)
 		problems = nsFilter.Apply(problems)
+		stats.Namespace = n - len(problems)
 	}
 
-	return problems
+	if entityFilter != nil {
+		n := len(problems)
+		problems = entityFilter.Apply(problems)
+		stats.Entity = n - len(problems)
+	}
+	return append(problems, unavailable...)
+}
+
+// allowedProblems returns the allow-listed problems within the namespace and
+// entity filters when --show-allowed is set, nil otherwise
+func allowedProblems(problems []*models.Problem) []*models.Problem {
+	if allowList == nil || !showAllowed {
+		return nil
+	}
+	_, allowed := allowList.Split(scopeFilters(problems, nil))
+	return allowed
+}
+
+// comparedBaseline loads the compared baseline, then saves problems as a new
+// one if requested (in this order: saving to --baseline-dir may rotate the
+// compared one out). An incomplete run is neither compared nor saved:
+// detectors that never ran would count as resolved.
+func comparedBaseline(problems []*models.Problem, incomplete bool) (*baseline.Baseline, error) {
+	if incomplete {
+		return nil, nil
+	}
+	b, err := loadComparedBaseline()
+	if err != nil {
+		return nil, err
+	}
+	if err := writeBaseline(problems); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// loadComparedBaseline loads --compare-baseline (or the newest file in
+// --baseline-dir). Returns nil when there is nothing to compare against.
+func loadComparedBaseline() (*baseline.Baseline, error) {
+	if compareBaseline == "" {
+		return nil, nil
+	}
+	b, err := baseline.LoadBaseline(compareBaseline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load baseline: %w", err)
+	}
+	return b, nil
+}
+
+// writeBaseline saves problems to --save-baseline or as a new file in
+// --baseline-dir. Without either flag it does nothing.
+func writeBaseline(problems []*models.Problem) error {
+	if saveBaseline == "" && rollingBaseline == nil {
+		return nil
+	}
+	metadata := map[string]string{
+		"prometheus_url": prometheusURL,
+		"version":        version,
+	}
+
+	path := saveBaseline
+	var err error
+	if rollingBaseline != nil {
+		path, err = rollingBaseline.Save(problems, metadata, time.Now())
+	} else {
+		err = baseline.SaveBaseline(problems, path, metadata)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save baseline: %w", err)
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Baseline saved to: %s\n", path)
+	}
+	return nil
+}
+
+// severityExitCode returns the tiered exit code for the highest severity in
+// problems, ExitSuccess if there are none
+func severityExitCode(problems []*models.Problem) int {
+	if len(problems) == 0 {
+		return util.ExitSuccess
+	}
+	switch monitor.HighestSeverity(problems) {
+	case models.SeverityCritical, models.SeverityFatal:
+		return util.ExitProblemsCritical
+	default:
+		return util.ExitProblemsWarning
+	}
+}
+
+// waitFirstCycle waits for the first detection results of a one-shot run.
+// incomplete reports that --max-runtime ran out first: the caller renders
+// what was collected and returns errMaxRuntime.
+func waitFirstCycle(ctx context.Context, watcher *monitor.Watcher) (incomplete bool, err error) {
+	select {
+	case <-watcher.UpdateChan():
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-runDeadlineC():
+		return true, nil
+	case <-time.After(firstDetectionTimeout):
+	}
+	return false, errNoData(watcher)
+}
+
+// runDeadlineC fires when --max-runtime runs out, or never without it
+func runDeadlineC() <-chan time.Time {
+	if runDeadline.IsZero() {
+		return nil
+	}
+	return time.After(time.Until(runDeadline))
+}
+
+// runTimedOut reports whether --max-runtime has run out
+func runTimedOut() bool {
+	return !runDeadline.IsZero() && !time.Now().Before(runDeadline)
+}
+
+// errMaxRuntime reports that --max-runtime ran out during stage and returns
+// the exit status for an incomplete run
+func errMaxRuntime(stage string) error {
+	fmt.Fprintf(os.Stderr, "[infranow] INCOMPLETE: --max-runtime %s reached during %s\n", maxRuntime, stage)
+	return util.ExitStatus(util.ExitTimeout)
+}
+
+// errNoData fails one-shot output when no detector query succeeded, so an
+// unreachable or erroring backend never reads as "0 problems"
+func errNoData(watcher *monitor.Watcher) error {
+	if watcher.HasData() {
+		return nil
+	}
+	stats := watcher.GetPrometheusStats()
+	return util.Runtimef("no detector query succeeded (%d failed); Prometheus may be unhealthy", stats.ErrorCount)
+}
+
+// exitOnNewAtLeast applies --fail-on to a baseline comparison: only problems
+// that are new since the baseline count, so known problems never fail CI
+func exitOnNewAtLeast(comparison *baseline.Comparison, code int) error {
+	if failOnSeverity == "" {
+		return nil
+	}
+	threshold, err := models.ParseSeverity(failOnSeverity)
+	if err != nil {
+		return err
+	}
+	if len(comparison.NewAtLeast(threshold)) > 0 {
+		return util.ExitStatus(code)
+	}
+	return nil
 }
 
 // countIncidents returns the number of unique incidents in the problem set
@@ -661,6 +1740,175 @@ func validatePort(portStr, name string) error {
 	return nil
 }
 
+// portForwardTarget picks the port-forward target from --k8s-service,
+// --k8s-pod and --k8s-selector. ok is false when none is set.
+func portForwardTarget(service, pod, selector string) (target util.Target, ok bool, err error) {
+	set := 0
+	for _, v := range []string{service, pod, selector} {
+		if v != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return util.Target{}, false, fmt.Errorf("--k8s-service, --k8s-pod and --k8s-selector are mutually exclusive")
+	}
+
+	switch {
+	case service != "":
+		return util.ServiceTarget(service), true, nil
+	case pod != "":
+		return util.PodTarget(pod), true, nil
+	case selector != "":
+		if _, err := labels.Parse(selector); err != nil {
+			return util.Target{}, false, fmt.Errorf("--k8s-selector: %w", err)
+		}
+		return util.SelectorTarget(selector), true, nil
+	}
+	return util.Target{}, false, nil
+}
+
+// parseQueryHeaders parses repeatable --query-header key=value flags
+func parseQueryHeaders(raw []string) (http.Header, error) {
+	headers := make(http.Header)
+	for _, kv := range raw {
+		key, value, ok := strings.Cut(kv, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("--query-header must be key=value: %q", kv)
+		}
+		if !validHeaderName(key) {
+			return nil, fmt.Errorf("--query-header has invalid header name: %q", key)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("--query-header value for %s must not contain newlines", key)
+		}
+		headers.Add(key, strings.TrimSpace(value))
+	}
+	return headers, nil
+}
+
+// clampInterval raises requested to floor, warning on w, unless allow is set.
+// A typo like --refresh-interval 1s should not hammer Prometheus.
+func clampInterval(w io.Writer, requested, floor time.Duration, allow bool) time.Duration {
+	if allow || requested >= floor {
+		return requested
+	}
+	fmt.Fprintf(w, "Warning: --refresh-interval %s is below %s, using %s (--allow-aggressive-intervals to override)\n",
+		requested, floor, floor)
+	return floor
+}
+
+// tenantHeader is the Mimir/Cortex tenant isolation header
+const tenantHeader = "X-Scope-OrgID"
+
+// newTenantProviders creates one Prometheus client per tenant, each sending
+// its own X-Scope-OrgID on top of any --query-header values.
+func newTenantProviders(promURL string, headers http.Header, tenantIDs []string, opts ...metrics.ClientOption) (map[string]metrics.MetricsProvider, error) {
+	providers := make(map[string]metrics.MetricsProvider, len(tenantIDs))
+	for _, id := range tenantIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return nil, fmt.Errorf("--tenant must not be empty")
+		}
+		if strings.ContainsAny(id, "\r\n:") {
+			return nil, fmt.Errorf("--tenant %q contains invalid characters", id)
+		}
+		if _, dup := providers[id]; dup {
+			return nil, fmt.Errorf("--tenant %q given more than once", id)
+		}
+
+		tenantHeaders := headers.Clone()
+		tenantHeaders.Set(tenantHeader, id)
+		tenantOpts := append(append([]metrics.ClientOption{}, opts...), metrics.WithHeaders(tenantHeaders))
+		client, err := metrics.NewPrometheusClient(promURL, prometheusTimeout, tenantOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Prometheus client for tenant %s: %w", id, err)
+		}
+		if batchQueries {
+			providers[id] = metrics.NewBatchingProvider(client)
+			continue
+		}
+		providers[id] = client
+	}
+	return providers, nil
+}
+
+// discoverQueryURL probes metrics.DefaultQueryPaths under promURL. The first
+// --tenant is sent along, since multi-tenant backends reject anonymous queries.
+func discoverQueryURL(promURL string, headers http.Header, opts ...metrics.ClientOption) string {
+	probeHeaders := headers.Clone()
+	if probeHeaders == nil {
+		probeHeaders = make(http.Header)
+	}
+	if len(tenants) > 0 {
+		probeHeaders.Set(tenantHeader, strings.TrimSpace(tenants[0]))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), prometheusTimeout)
+	defer cancel()
+	probeOpts := append(append([]metrics.ClientOption{}, opts...), metrics.WithHeaders(probeHeaders))
+	found, err := metrics.DiscoverQueryURL(ctx, promURL, metrics.DefaultQueryPaths, prometheusTimeout, probeOpts...)
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Warning: query API discovery failed, using %s as given: %v\n", sanitizeURL(promURL), err)
+		}
+		return promURL
+	}
+	if verbose && found != strings.TrimRight(promURL, "/") {
+		fmt.Printf("Discovered query API at %s\n", sanitizeURL(found))
+	}
+	return found
+}
+
+// healthCheckHint suggests a fix for a failed health check, or "" when there
+// is nothing useful to add
+func healthCheckHint(err error, viaPortForward bool) string {
+	if errors.Is(err, metrics.ErrUnauthorized) {
+		hint := `Prometheus requires authentication. Pass credentials with --query-header, e.g. --query-header "Authorization=Bearer $TOKEN"`
+		if viaPortForward {
+			hint += ", or set --k8s-remote-port to a port that is not behind the auth proxy"
+		}
+		return hint
+	}
+	if viaPortForward {
+		return "Port-forward may still be initializing, try waiting a moment"
+	}
+	return ""
+}
+
+// checkTenantHealth health-checks every tenant. Unhealthy tenants are reported
+// as warnings; it only fails when no tenant is reachable.
+func checkTenantHealth(ctx context.Context, providers map[string]metrics.MetricsProvider) error {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	healthy := 0
+	for _, name := range names {
+		if err := providers[name].Health(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: tenant %s health check failed: %v\n", name, err)
+			continue
+		}
+		healthy++
+	}
+	if healthy == 0 {
+		return fmt.Errorf("prometheus health check failed for all %d tenants", len(providers))
+	}
+	return nil
+}
+
+// validHeaderName reports whether s is a valid HTTP header field name (RFC 7230 token)
+func validHeaderName(s string) bool {
+	for _, c := range s {
+		if c > 0x7e || c <= 0x20 || strings.ContainsRune("\"(),/:;<=>?@[\\]{}", c) {
+			return false
+		}
+	}
+	return true
+}
+
 // validatePrometheusURL checks that the URL has a valid http or https scheme
 // and does not point to link-local addresses (SSRF prevention).
 func validatePrometheusURL(rawURL string) error {
@@ -694,3 +1942,20 @@ func validatePrometheusURL(rawURL string) error {
 
 	return nil
 }
+
+// validateUIURL checks --prometheus-ui-url is an absolute http(s) URL once
+// the {expr} placeholder is filled in
+func validateUIURL(rawURL string) error {
+	u, err := url.Parse(strings.ReplaceAll(rawURL, monitor.QueryLinkPlaceholder, "up"))
+	if err != nil {
+		return fmt.Errorf("invalid --prometheus-ui-url: %w", err)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return fmt.Errorf("--prometheus-ui-url must use http:// or https:// scheme, got %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("--prometheus-ui-url must include a host")
+	}
+	return nil
+}
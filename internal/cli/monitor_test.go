@@ -1,6 +1,26 @@
 package cli
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/monitor"
+	"github.com/ppiankov/infranow/internal/util"
+)
 
 func TestSanitizeURL(t *testing.T) {
 	tests := []struct {
@@ -75,3 +95,404 @@ func TestValidatePrometheusURL(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateUIURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{"prometheus root", "http://prom.example.com:9090", false},
+		{"grafana template", "https://grafana.example.com/explore?left={expr}", false},
+		{"missing scheme", "prom.example.com", true},
+		{"missing host", "https://", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUIURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateUIURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseQueryHeaders(t *testing.T) {
+	headers, err := parseQueryHeaders([]string{"X-Scope-OrgID=tenant-a", "X-Extra = a=b "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := headers.Get("X-Scope-OrgID"); got != "tenant-a" {
+		t.Errorf("X-Scope-OrgID = %q, want tenant-a", got)
+	}
+	if got := headers.Get("X-Extra"); got != "a=b" {
+		t.Errorf("X-Extra = %q, want a=b", got)
+	}
+
+	invalid := []string{"no-equals", "=value", "Bad Header=x", "X-Ok=line\nbreak"}
+	for _, raw := range invalid {
+		if _, err := parseQueryHeaders([]string{raw}); err == nil {
+			t.Errorf("parseQueryHeaders(%q) expected error", raw)
+		}
+	}
+}
+
+func TestNewTenantProviders(t *testing.T) {
+	providers, err := newTenantProviders("http://localhost:9090", http.Header{}, []string{"tenant-a", "tenant-b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(providers) != 2 {
+		t.Errorf("expected 2 providers, got %d", len(providers))
+	}
+
+	invalid := [][]string{{""}, {"a", "a"}, {"bad:tenant"}}
+	for _, ids := range invalid {
+		if _, err := newTenantProviders("http://localhost:9090", http.Header{}, ids); err == nil {
+			t.Errorf("newTenantProviders(%q) expected error", ids)
+		}
+	}
+}
+
+func TestNewTenantProviders_Batched(t *testing.T) {
+	batchQueries = true
+	defer func() { batchQueries = false }()
+
+	providers, err := newTenantProviders("http://localhost:9090", http.Header{}, []string{"tenant-a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := providers["tenant-a"].(*metrics.BatchingProvider); !ok {
+		t.Errorf("provider = %T, want each tenant's queries batched", providers["tenant-a"])
+	}
+}
+
+func TestHealthCheckHint_Unauthorized(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	provider, err := metrics.NewPrometheusClient(srv.URL, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewPrometheusClient() error = %v", err)
+	}
+	err = provider.Health(context.Background())
+	if err == nil {
+		t.Fatal("expected health check to fail")
+	}
+
+	hint := healthCheckHint(err, true)
+	if !strings.Contains(hint, "--query-header") || !strings.Contains(hint, "Authorization=") {
+		t.Errorf("hint should point at --query-header credentials, got %q", hint)
+	}
+	if !strings.Contains(hint, "--k8s-remote-port") {
+		t.Errorf("port-forward hint should mention --k8s-remote-port, got %q", hint)
+	}
+	if strings.Contains(healthCheckHint(err, false), "--k8s-remote-port") {
+		t.Error("hint without port-forward should not mention --k8s-remote-port")
+	}
+}
+
+func TestHealthCheckHint_Other(t *testing.T) {
+	err := context.DeadlineExceeded
+	if hint := healthCheckHint(err, true); !strings.Contains(hint, "initializing") {
+		t.Errorf("port-forward hint = %q, want initializing hint", hint)
+	}
+	if hint := healthCheckHint(err, false); hint != "" {
+		t.Errorf("hint without port-forward = %q, want empty", hint)
+	}
+}
+
+func TestPortForwardTarget(t *testing.T) {
+	tests := []struct {
+		name, service, pod, selector string
+		want                         string
+		wantOK, wantErr              bool
+	}{
+		{name: "none"},
+		{name: "service", service: "prometheus-operated", want: "service/prometheus-operated", wantOK: true},
+		{name: "pod", pod: "prometheus-k8s-1", want: "pod/prometheus-k8s-1", wantOK: true},
+		{name: "selector", selector: "app=prometheus,component=server", want: "pods matching app=prometheus,component=server", wantOK: true},
+		{name: "service and pod", service: "prometheus-operated", pod: "prometheus-k8s-1", wantErr: true},
+		{name: "pod and selector", pod: "prometheus-k8s-1", selector: "app=prometheus", wantErr: true},
+		{name: "invalid selector", selector: "app==prometheus=", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, ok, err := portForwardTarget(tt.service, tt.pod, tt.selector)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("portForwardTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ok != tt.wantOK {
+				t.Errorf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && target.String() != tt.want {
+				t.Errorf("target = %s, want %s", target, tt.want)
+			}
+		})
+	}
+}
+
+func TestClampInterval(t *testing.T) {
+	var warn strings.Builder
+	if got := clampInterval(&warn, time.Second, 5*time.Second, false); got != 5*time.Second {
+		t.Errorf("sub-floor interval = %s, want clamped to 5s", got)
+	}
+	if !strings.Contains(warn.String(), "below 5s") {
+		t.Errorf("expected a warning, got %q", warn.String())
+	}
+
+	warn.Reset()
+	if got := clampInterval(&warn, time.Second, 5*time.Second, true); got != time.Second {
+		t.Errorf("overridden interval = %s, want 1s", got)
+	}
+	if got := clampInterval(&warn, 10*time.Second, 5*time.Second, false); got != 10*time.Second {
+		t.Errorf("interval above the floor = %s, want 10s", got)
+	}
+	if warn.Len() != 0 {
+		t.Errorf("unexpected warning %q", warn.String())
+	}
+}
+
+func TestSeverityExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		problems []*models.Problem
+		want     int
+	}{
+		{"none", nil, util.ExitSuccess},
+		{"warning", []*models.Problem{{Severity: models.SeverityWarning}}, util.ExitProblemsWarning},
+		{"critical", []*models.Problem{{Severity: models.SeverityWarning}, {Severity: models.SeverityCritical}}, util.ExitProblemsCritical},
+		{"fatal", []*models.Problem{{Severity: models.SeverityFatal}}, util.ExitProblemsCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := severityExitCode(tt.problems); got != tt.want {
+				t.Errorf("severityExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunJSONMode_ReturnsExitStatus(t *testing.T) {
+	savedFailOn := failOnSeverity
+	t.Cleanup(func() { failOnSeverity = savedFailOn })
+
+	// One fresh watcher per run: runJSONMode waits for a detection cycle
+	run := func(failOn string) error {
+		registry := detector.NewRegistry()
+		registry.Register(staticDetector{problems: []*models.Problem{
+			{Entity: "prod/api", EntityType: "kubernetes_pod", Type: "oom_kill", Severity: models.SeverityWarning, Labels: map[string]string{"pod": "api"}},
+		}})
+		watcher := monitor.NewWatcher(&metrics.MockProvider{}, registry, 0, time.Second)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			_ = watcher.Start(ctx)
+		}()
+		failOnSeverity = failOn
+		return runJSONMode(ctx, watcher)
+	}
+
+	// Returned instead of exiting, so deferred cleanup in runMonitor runs
+	err := run("WARNING")
+	if got := util.ExitCode(err); got != util.ExitProblemsWarning {
+		t.Fatalf("ExitCode(%v) = %d, want %d", err, got, util.ExitProblemsWarning)
+	}
+	if err := run("CRITICAL"); err != nil {
+		t.Errorf("expected no error below --fail-on, got %v", err)
+	}
+}
+
+func TestRunJSONMode_FilteredCounts(t *testing.T) {
+	savedInclude, savedUnnamespaced, savedMin, savedFailOn := includeNamespaces, includeUnnamespaced, minSeverityLevel, failOnSeverity
+	t.Cleanup(func() {
+		includeNamespaces, includeUnnamespaced, minSeverityLevel, failOnSeverity = savedInclude, savedUnnamespaced, savedMin, savedFailOn
+	})
+	includeNamespaces, includeUnnamespaced, minSeverityLevel, failOnSeverity = "prod", true, models.SeverityCritical, ""
+
+	registry := detector.NewRegistry()
+	registry.Register(staticDetector{problems: []*models.Problem{
+		{Entity: "prod/api", EntityType: "kubernetes_pod", Type: "oom_kill", Severity: models.SeverityWarning, Labels: map[string]string{"namespace": "prod", "pod": "api"}},
+		{Entity: "prod/web", EntityType: "kubernetes_pod", Type: "crashloopbackoff", Severity: models.SeverityCritical, Labels: map[string]string{"namespace": "prod", "pod": "web"}},
+		{Entity: "staging/db", EntityType: "kubernetes_pod", Type: "crashloopbackoff", Severity: models.SeverityCritical, Labels: map[string]string{"namespace": "staging", "pod": "db"}},
+		{Entity: "node-1", EntityType: "node", Type: "high_memory", Severity: models.SeverityCritical, Labels: map[string]string{"node": "node-1"}},
+	}})
+	watcher := monitor.NewWatcher(&metrics.MockProvider{}, registry, 0, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = watcher.Start(ctx)
+	}()
+
+	out := captureStdout(t, func() {
+		if err := runJSONMode(ctx, watcher); err != nil {
+			t.Errorf("runJSONMode: %v", err)
+		}
+	})
+
+	var report struct {
+		Summary struct {
+			Total      int            `json:"total_problems"`
+			Unfiltered int            `json:"unfiltered_problems"`
+			Filtered   int            `json:"filtered_problems"`
+			ByFilter   map[string]int `json:"filtered"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	s := report.Summary
+	if s.Total != 2 || s.Unfiltered != 4 || s.Filtered != 2 {
+		t.Errorf("total %d unfiltered %d filtered %d, want 2 of 4 with 2 filtered", s.Total, s.Unfiltered, s.Filtered)
+	}
+	if s.ByFilter["namespace"] != 1 || s.ByFilter["min_severity"] != 1 || s.ByFilter["entity"] != 0 || s.ByFilter["allow_list"] != 0 {
+		t.Errorf("filtered = %v, want namespace 1 and min_severity 1", s.ByFilter)
+	}
+}
+
+func TestErrNoData(t *testing.T) {
+	watcher := monitor.NewWatcher(&metrics.MockProvider{}, detector.NewRegistry(), 0, time.Second)
+	if got := util.ExitCode(errNoData(watcher)); got != util.ExitRuntimeError {
+		t.Errorf("ExitCode() = %d, want %d", got, util.ExitRuntimeError)
+	}
+}
+
+// failingDetector fails every run, as when Prometheus rejects its queries
+type failingDetector struct{}
+
+func (failingDetector) Name() string            { return "failing" }
+func (failingDetector) EntityTypes() []string   { return []string{"kubernetes_pod"} }
+func (failingDetector) Interval() time.Duration { return time.Hour }
+func (failingDetector) Detect(context.Context, metrics.MetricsProvider, time.Duration) ([]*models.Problem, error) {
+	return nil, errors.New("query failed")
+}
+
+func TestRunTextfileMode_OnceWithoutDataWritesNothing(t *testing.T) {
+	savedFile, savedOnce := exportFile, runOnce
+	t.Cleanup(func() { exportFile, runOnce = savedFile, savedOnce })
+	exportFile, runOnce = filepath.Join(t.TempDir(), "infranow.prom"), true
+
+	registry := detector.NewRegistry()
+	registry.Register(failingDetector{})
+	// Reporting unavailability signals the failed cycle instead of waiting
+	// for firstDetectionTimeout
+	watcher := monitor.NewWatcher(&metrics.MockProvider{}, registry, 0, time.Second, monitor.WithUnavailableProblem())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = watcher.Start(ctx)
+	}()
+
+	err := runTextfileMode(ctx, watcher)
+	if got := util.ExitCode(err); got != util.ExitRuntimeError {
+		t.Errorf("ExitCode() = %d, want %d (err %v)", got, util.ExitRuntimeError, err)
+	}
+	if _, statErr := os.Stat(exportFile); !os.IsNotExist(statErr) {
+		t.Errorf("textfile written without data: %v", statErr)
+	}
+}
+
+func TestRunTextMode_SummaryLine(t *testing.T) {
+	savedSummary, savedFailOn := summaryLine, failOnSeverity
+	t.Cleanup(func() { summaryLine, failOnSeverity = savedSummary, savedFailOn })
+	summaryLine, failOnSeverity = true, ""
+
+	registry := detector.NewRegistry()
+	registry.Register(staticDetector{problems: []*models.Problem{
+		{Entity: "prod/api", EntityType: "kubernetes_pod", Type: "oom_kill", Severity: models.SeverityWarning, Labels: map[string]string{"pod": "api"}},
+		{Entity: "prod/web", EntityType: "kubernetes_pod", Type: "crashloopbackoff", Severity: models.SeverityCritical, Labels: map[string]string{"pod": "web"}},
+		{Entity: "prod/db", EntityType: "kubernetes_pod", Type: "oom_kill", Severity: models.SeverityWarning, Labels: map[string]string{"pod": "db"}},
+	}})
+	watcher := monitor.NewWatcher(&metrics.MockProvider{}, registry, 0, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = watcher.Start(ctx)
+	}()
+
+	var runErr error
+	out := captureStdout(t, func() {
+		runErr = runTextMode(ctx, watcher, monitor.PlainText)
+	})
+
+	if got := util.ExitCode(runErr); got != util.ExitProblemsCritical {
+		t.Fatalf("ExitCode(%v) = %d, want %d", runErr, got, util.ExitProblemsCritical)
+	}
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	want := "SUMMARY fatal=0 critical=1 warning=2 total=3 exit=2"
+	if last := lines[len(lines)-1]; last != want {
+		t.Errorf("last line = %q, want %q", last, want)
+	}
+}
+
+func TestRunJSONMode_MaxRuntime(t *testing.T) {
+	savedDeadline, savedRuntime := runDeadline, maxRuntime
+	t.Cleanup(func() { runDeadline, maxRuntime = savedDeadline, savedRuntime })
+
+	// A hung Prometheus: queries only return when the detector gives up
+	registry := detector.NewRegistry()
+	registry.Register(detector.NewHighLoadAverageDetector())
+	slow := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	watcher := monitor.NewWatcher(slow, registry, 0, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = watcher.Start(ctx)
+	}()
+
+	maxRuntime = 200 * time.Millisecond
+	runDeadline = time.Now().Add(maxRuntime)
+	start := time.Now()
+	var runErr error
+	out := captureStdout(t, func() {
+		runErr = runJSONMode(ctx, watcher)
+	})
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("run took %s, want it bounded by --max-runtime", elapsed)
+	}
+	if got := util.ExitCode(runErr); got != util.ExitTimeout {
+		t.Fatalf("ExitCode(%v) = %d, want %d", runErr, got, util.ExitTimeout)
+	}
+	var report struct {
+		Metadata struct {
+			Incomplete bool `json:"incomplete"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if !report.Metadata.Incomplete {
+		t.Error("metadata.incomplete not set on a timed-out run")
+	}
+}
+
+// captureStdout returns what fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan []byte)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- out
+	}()
+	fn()
+	_ = w.Close()
+	return string(<-done)
+}
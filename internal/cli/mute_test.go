@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/filter"
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/monitor"
+	"github.com/ppiankov/infranow/internal/redact"
+)
+
+func pressKeys(m tea.Model, keys ...string) tea.Model {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		if k == "enter" {
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		} else {
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+func TestTUIMute_WithRedaction(t *testing.T) {
+	oldRedactor, oldMutes := redactor, mutes
+	t.Cleanup(func() { redactor, mutes = oldRedactor, oldMutes })
+	redactor = redact.NewWithKey([]byte("test"))
+	mutes = filter.NewMutes(nil)
+
+	registry := detector.NewRegistry()
+	registry.Register(staticDetector{problems: []*models.Problem{
+		{Entity: "payments/api-0", EntityType: "kubernetes_pod", Type: "crashloopbackoff", Severity: models.SeverityCritical, Labels: map[string]string{"namespace": "payments", "pod": "api-0"}},
+	}})
+	watcher := monitor.NewWatcher(&metrics.MockProvider{}, registry, 0, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = watcher.Start(ctx)
+	}()
+	<-watcher.UpdateChan()
+
+	m := tea.Model(monitor.NewModel(watcher, "http://localhost:9090", 2*time.Second, nil,
+		monitor.WithProblemFilter(tuiProblems), monitor.WithMutes(mutes), monitor.WithMuteKey(unredactedID),
+		monitor.WithFixedWidth(160)))
+	m = pressKeys(m, "/", "enter") // Load the problems
+	if view := m.View(); !strings.Contains(view, redactor.Token("payments")) {
+		t.Fatalf("expected the redacted problem listed:\n%s", view)
+	}
+
+	m = pressKeys(m, "M", "1")
+	if view := m.View(); !strings.Contains(view, "Problems: 0 +1 muted") {
+		t.Errorf("muted problem still listed:\n%s", view)
+	}
+	// Notifications and counts filter the real problems
+	if kept := applyFilters(watcher.GetProblems()); len(kept) != 0 {
+		t.Errorf("mute did not reach the unredacted problem: %d kept", len(kept))
+	}
+}
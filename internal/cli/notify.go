@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/ppiankov/infranow/internal/notify"
+)
+
+// buildNotifySenders creates a sender for every configured notification
+// target, rate-limited when its --notify-*-rate flag is set
+func buildNotifySenders() ([]notify.Sender, error) {
+	if webhookRate < 0 {
+		return nil, fmt.Errorf("--notify-webhook-rate must not be negative")
+	}
+	if slackRate < 0 {
+		return nil, fmt.Errorf("--notify-slack-rate must not be negative")
+	}
+	if teamsRate < 0 {
+		return nil, fmt.Errorf("--notify-teams-rate must not be negative")
+	}
+
+	var senders []notify.Sender
+	if notifyWebhook != "" {
+		s, err := notify.NewWebhookSender(notifyWebhook)
+		if err != nil {
+			return nil, fmt.Errorf("--notify-webhook: %w", err)
+		}
+		senders = append(senders, rateLimited(withCluster(s), webhookRate))
+	}
+	if notifySlack != "" {
+		s, err := notify.NewSlackSender(notifySlack)
+		if err != nil {
+			return nil, fmt.Errorf("--notify-slack: %w", err)
+		}
+		senders = append(senders, rateLimited(withCluster(s), slackRate))
+	}
+	if notifyTeams != "" {
+		s, err := notify.NewTeamsSender(notifyTeams)
+		if err != nil {
+			return nil, fmt.Errorf("--notify-teams: %w", err)
+		}
+		senders = append(senders, rateLimited(withCluster(s), teamsRate))
+	}
+	return senders, nil
+}
+
+// withCluster stamps --cluster-name into s's messages, including rate-limit
+// summaries, which is why it wraps inside rateLimited
+func withCluster(s notify.Sender) notify.Sender {
+	if clusterName == "" {
+		return s
+	}
+	return notify.NewClusterSender(s, clusterName)
+}
+
+// rateLimited wraps s with a per-minute limit (0 = unlimited)
+func rateLimited(s notify.Sender, perMinute int) notify.Sender {
+	if perMinute == 0 {
+		return s
+	}
+	return notify.NewRateLimitedSender(s, perMinute, notify.DefaultRateWindow)
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// openshiftThanosQuerierURL is the in-cluster thanos-querier service of
+	// OpenShift cluster monitoring. Port 9091 serves cluster-wide queries to
+	// service accounts with the cluster-monitoring-view role.
+	openshiftThanosQuerierURL = "https://thanos-querier.openshift-monitoring.svc:9091"
+
+	// serviceAccountDir holds the mounted pod service account credentials
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+)
+
+// openshiftClient is the Prometheus client setup the --openshift preset
+// resolves to
+type openshiftClient struct {
+	URL     string
+	Headers http.Header
+	CAFile  string // Empty when the system roots suffice
+}
+
+// openshiftPreset configures access to OpenShift cluster monitoring. Without
+// --prometheus-url it targets the in-cluster thanos-querier and trusts the
+// service CA; a route URL is used as given. The service account token in
+// saDir is sent as bearer token unless --query-header already sets
+// Authorization (e.g. "Authorization=Bearer $(oc whoami -t)" outside the
+// cluster).
+func openshiftPreset(promURL string, headers http.Header, saDir string) (openshiftClient, error) {
+	client := openshiftClient{URL: promURL, Headers: headers.Clone()}
+	if client.Headers == nil {
+		client.Headers = make(http.Header)
+	}
+
+	if client.URL == "" {
+		client.URL = openshiftThanosQuerierURL
+		caFile := filepath.Join(saDir, "service-ca.crt")
+		if _, err := os.Stat(caFile); err == nil {
+			client.CAFile = caFile
+		}
+	}
+
+	if client.Headers.Get("Authorization") == "" {
+		token, err := os.ReadFile(filepath.Join(saDir, "token"))
+		if err != nil {
+			return openshiftClient{}, fmt.Errorf("--openshift: no service account token (%w); outside the cluster pass --prometheus-url with the thanos-querier route and --query-header \"Authorization=Bearer $(oc whoami -t)\"", err)
+		}
+		client.Headers.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	return client, nil
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeServiceAccount(t *testing.T, withCA bool) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte("sa-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if withCA {
+		if err := os.WriteFile(filepath.Join(dir, "service-ca.crt"), []byte("ca"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestOpenShiftPreset_InCluster(t *testing.T) {
+	dir := writeServiceAccount(t, true)
+	headers := http.Header{"X-Custom": {"value"}}
+
+	client, err := openshiftPreset("", headers, dir)
+	if err != nil {
+		t.Fatalf("openshiftPreset() error = %v", err)
+	}
+	if client.URL != openshiftThanosQuerierURL {
+		t.Errorf("URL = %s, want %s", client.URL, openshiftThanosQuerierURL)
+	}
+	if got := client.Headers.Get("Authorization"); got != "Bearer sa-token" {
+		t.Errorf("Authorization = %q, want service account bearer token", got)
+	}
+	if client.Headers.Get("X-Custom") != "value" {
+		t.Error("--query-header values must be kept")
+	}
+	if client.CAFile != filepath.Join(dir, "service-ca.crt") {
+		t.Errorf("CAFile = %q, want the service CA", client.CAFile)
+	}
+	if headers.Get("Authorization") != "" {
+		t.Error("caller's headers must not be modified")
+	}
+}
+
+func TestOpenShiftPreset_Route(t *testing.T) {
+	route := "https://thanos-querier-openshift-monitoring.apps.example.com"
+	headers := http.Header{"Authorization": {"Bearer user-token"}}
+
+	// Outside the cluster: no service account, explicit route and token
+	client, err := openshiftPreset(route, headers, filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatalf("openshiftPreset() error = %v", err)
+	}
+	if client.URL != route {
+		t.Errorf("URL = %s, want the route as given", client.URL)
+	}
+	if got := client.Headers.Get("Authorization"); got != "Bearer user-token" {
+		t.Errorf("Authorization = %q, explicit header must win", got)
+	}
+	if client.CAFile != "" {
+		t.Errorf("CAFile = %q, routes use the system roots", client.CAFile)
+	}
+}
+
+func TestOpenShiftPreset_NoToken(t *testing.T) {
+	if _, err := openshiftPreset("", nil, t.TempDir()); err == nil {
+		t.Fatal("expected error without a service account token or Authorization header")
+	}
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/monitor"
+	"github.com/ppiankov/infranow/internal/otlp"
+)
+
+// logExporter sends problems to --otlp-logs-endpoint; nil when unset
+var logExporter otlp.LogExporter
+
+// buildLogExporter creates the OTLP logs exporter, or nil without
+// --otlp-logs-endpoint
+func buildLogExporter() (otlp.LogExporter, error) {
+	if otlpLogsEndpoint == "" {
+		return nil, nil
+	}
+	resource := map[string]string{"service.version": version}
+	if clusterName != "" {
+		resource["k8s.cluster.name"] = clusterName
+	}
+	exporter, err := otlp.NewHTTPExporter(otlpLogsEndpoint, version, resource)
+	if err != nil {
+		return nil, fmt.Errorf("--otlp-logs-endpoint: %w", err)
+	}
+	return exporter, nil
+}
+
+// exportLogs sends a one-shot run's problems as OTLP log records. A
+// collector outage is reported but does not change the run's result.
+func exportLogs(ctx context.Context, problems []*models.Problem) {
+	if logExporter == nil {
+		return
+	}
+	if err := logExporter.Export(ctx, otlp.Records(redactProblems(problems), time.Now())); err != nil {
+		fmt.Fprintf(os.Stderr, "[infranow] warning: %v\n", err)
+	}
+}
+
+// runLogExport sends the current problems as OTLP log records every
+// refresh interval, for the TUI, --watch and continuous textfile modes
+func runLogExport(ctx context.Context, watcher *monitor.Watcher) {
+	otlp.Run(ctx, logExporter, refreshInterval, func() []*models.Problem {
+		return redactProblems(notifyProblems(watcher))
+	}, func(err error) {
+		fmt.Fprintf(backgroundLog, "[infranow] OTLP log export failed: %v\n", err)
+	})
+}
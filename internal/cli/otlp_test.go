@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/otlp"
+)
+
+// recordingExporter keeps the records of every export
+type recordingExporter struct {
+	records []otlp.LogRecord
+}
+
+func (r *recordingExporter) Export(ctx context.Context, records []otlp.LogRecord) error {
+	r.records = append(r.records, records...)
+	return nil
+}
+
+func TestExportLogs_OneRecordPerProblem(t *testing.T) {
+	exporter := &recordingExporter{}
+	old := logExporter
+	logExporter = exporter
+	t.Cleanup(func() { logExporter = old })
+
+	exportLogs(context.Background(), []*models.Problem{
+		{ID: "p1", Entity: "prod/api", Type: "oom_kill", Severity: models.SeverityCritical, Labels: map[string]string{"namespace": "prod"}},
+		{ID: "p2", Entity: "node-1", Type: "high_load", Severity: models.SeverityWarning},
+	})
+
+	if len(exporter.records) != 2 {
+		t.Fatalf("%d records, want 2", len(exporter.records))
+	}
+	if exporter.records[0].SeverityNumber != otlp.SeverityNumberError || exporter.records[0].Attributes["namespace"] != "prod" {
+		t.Errorf("unexpected first record %+v", exporter.records[0])
+	}
+	if exporter.records[1].SeverityNumber != otlp.SeverityNumberWarn {
+		t.Errorf("severity number = %d, want %d", exporter.records[1].SeverityNumber, otlp.SeverityNumberWarn)
+	}
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+)
+
+// startPprofServer serves net/http/pprof on addr until ctx is done. Profiles
+// expose internals, so addr must be a loopback address.
+func startPprofServer(ctx context.Context, addr string) error {
+	if err := validateLoopbackAddr(addr); err != nil {
+		return fmt.Errorf("--pprof-addr: %w", err)
+	}
+	return serveInBackground(ctx, "pprof", addr, pprofHandler())
+}
+
+// pprofHandler serves the pprof endpoints from their own mux. Importing
+// net/http/pprof also registers them on http.DefaultServeMux, so no server
+// in infranow may use it (a nil Handler): it would expose profiles on that
+// server's address, loopback or not.
+func pprofHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// validateLoopbackAddr rejects addresses that listen beyond this host,
+// including an empty host (":6060" binds every interface)
+func validateLoopbackAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("%q is not a loopback address (use 127.0.0.1:<port> or localhost:<port>)", addr)
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPprofHandler_Responds(t *testing.T) {
+	srv := httptest.NewServer(pprofHandler())
+	defer srv.Close()
+
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/goroutine?debug=1", "/debug/pprof/heap"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s = %d, want 200", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestValidateLoopbackAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		wantErr bool
+	}{
+		{"127.0.0.1:6060", false},
+		{"localhost:6060", false},
+		{"[::1]:6060", false},
+		{":6060", true},
+		{"0.0.0.0:6060", true},
+		{"10.0.0.5:6060", true},
+		{"6060", true},
+	}
+	for _, tt := range tests {
+		if err := validateLoopbackAddr(tt.addr); (err != nil) != tt.wantErr {
+			t.Errorf("validateLoopbackAddr(%q) error = %v, wantErr %v", tt.addr, err, tt.wantErr)
+		}
+	}
+}
+
+func TestStartPprofServer_RejectsPublicAddr(t *testing.T) {
+	if err := startPprofServer(context.Background(), ":6060"); err == nil {
+		t.Fatal("expected error for an address listening on every interface")
+	}
+}
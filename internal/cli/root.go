@@ -2,13 +2,18 @@ package cli
 
 import (
 	"runtime"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/util"
 )
 
 var (
 	configFile string
 	verbose    bool
+	timezone   string // zone for displayed/exported timestamps
 	version    string // stored for use in subcommands (baseline metadata)
 )
 
@@ -38,16 +43,35 @@ deterministically identifies the most important infrastructure problems right no
 
 It prioritizes silence when systems are healthy and surfaces only ranked,
 actionable problems when intervention is required.`,
+		// main prints the error once and maps it to an exit code
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			loc, err := time.LoadLocation(timezone)
+			if err != nil {
+				return util.InvalidInputf("--timezone: %w", err)
+			}
+			models.SetDisplayLocation(loc)
+			return nil
+		},
 	}
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return util.InvalidInput(err)
+	})
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file (default: $HOME/.infranow.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose logging")
+	rootCmd.PersistentFlags().StringVar(&timezone, "timezone", "UTC", "time zone for displayed and exported timestamps (IANA name, UTC or Local)")
 
 	// Add subcommands
 	rootCmd.AddCommand(NewMonitorCommand())
 	rootCmd.AddCommand(NewHistoryCommand())
 	rootCmd.AddCommand(NewSweepCommand())
+	rootCmd.AddCommand(NewSnapshotCommand())
+	rootCmd.AddCommand(NewRunDetectorCommand())
+	rootCmd.AddCommand(NewTopEntitiesCommand())
+	rootCmd.AddCommand(NewConfigCommand())
 	rootCmd.AddCommand(newVersionCommand(info))
 
 	return rootCmd
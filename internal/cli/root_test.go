@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"io"
+	"testing"
+
+	"github.com/ppiankov/infranow/internal/util"
+)
+
+// executeRoot runs the root command with args and returns its error
+func executeRoot(t *testing.T, args ...string) error {
+	t.Helper()
+	// Registering the flags again resets every flag variable to its default
+	t.Cleanup(func() { NewRootCommand("test", "none", "unknown") })
+
+	root := NewRootCommand("test", "none", "unknown")
+	root.SetArgs(args)
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	return root.Execute()
+}
+
+func TestRootCommand_ExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"unknown flag", []string{"top-entities", "--no-such-flag"}, util.ExitInvalidInput},
+		{"bad timezone", []string{"--timezone", "Mars/Olympus", "history", "list"}, util.ExitInvalidInput},
+		{"bad flag value", []string{"top-entities", "--prometheus-url", "http://127.0.0.1:9", "--by", "planet"}, util.ExitInvalidInput},
+		{"bad url", []string{"top-entities", "--prometheus-url", "ftp://prom"}, util.ExitInvalidInput},
+		{"monitor unknown profile", []string{"monitor", "--profile", "payments"}, util.ExitInvalidInput},
+		{"monitor bad min severity", []string{"monitor", "--min-severity", "SEVERE"}, util.ExitInvalidInput},
+		{"monitor flag check", []string{"monitor", "--refresh-interval", "0"}, util.ExitInvalidInput},
+		{"monitor zero detection window", []string{"monitor", "--detection-window", "0"}, util.ExitInvalidInput},
+		{"monitor negative recency weight", []string{"monitor", "--recency-weight", "-1"}, util.ExitInvalidInput},
+		{"monitor bad state format", []string{"monitor", "--state-file", "state", "--state-format", "protobuf"}, util.ExitInvalidInput},
+		{"run-detector zero detection window", []string{"run-detector", "--detection-window", "0"}, util.ExitInvalidInput},
+		{"monitor state file without events", []string{"monitor", "--notify-slack", "http://127.0.0.1:9/hook", "--notify-state-file", "state.json"}, util.ExitInvalidInput},
+		{"monitor summary line with json", []string{"monitor", "--output", "json", "--summary-line"}, util.ExitInvalidInput},
+		{"monitor negative max runtime", []string{"monitor", "--max-runtime", "-1s"}, util.ExitInvalidInput},
+		{"monitor max runtime with watch", []string{"monitor", "--watch", "--max-runtime", "1m"}, util.ExitInvalidInput},
+		{"monitor otlp endpoint without scheme", []string{"monitor", "--otlp-logs-endpoint", "otel-collector:4318"}, util.ExitInvalidInput},
+		{"monitor bad memory limit", []string{"monitor", "--memory-limit", "lots"}, util.ExitInvalidInput},
+		{"monitor negative max goroutines", []string{"monitor", "--max-goroutines", "-1"}, util.ExitInvalidInput},
+		{"monitor teams url without scheme", []string{"monitor", "--notify-teams", "example.webhook.office.com/x"}, util.ExitInvalidInput},
+		{"monitor stable-for without fail-on", []string{"monitor", "--fail-on-stable-for", "5m"}, util.ExitInvalidInput},
+		{"monitor negative stable-for", []string{"monitor", "--fail-on", "CRITICAL", "--fail-on-stable-for", "-1m"}, util.ExitInvalidInput},
+		{"monitor unknown group-by", []string{"monitor", "--group-by", "pod"}, util.ExitInvalidInput},
+		{"monitor group-by with json", []string{"monitor", "--group-by", "service", "--output", "json"}, util.ExitInvalidInput},
+		{"monitor without prometheus", []string{"monitor", "--output", "json"}, util.ExitInvalidInput},
+		{"monitor unreachable prometheus", []string{"monitor", "--output", "json", "--prometheus-url", "http://127.0.0.1:1", "--prometheus-timeout", "2s"}, util.ExitRuntimeError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executeRoot(t, tt.args...)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := util.ExitCode(err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", err, got, tt.want)
+			}
+		})
+	}
+}
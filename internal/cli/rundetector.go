@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/spf13/cobra"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/util"
+)
+
+var runDetectorURL string
+
+// NewRunDetectorCommand creates the run-detector subcommand
+func NewRunDetectorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run-detector <name>",
+		Short: "Run a single detector once and show its queries and results",
+		Long: `Run-detector runs one detector a single time for debugging. Every PromQL
+query it issues is printed with the raw series Prometheus returned, followed
+by the problems the detector produced. Thresholds, windows,
+--detection-window and --sustained-window apply as in monitor. Without a name, the available detectors
+are listed and Prometheus is not contacted.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runRunDetector,
+	}
+
+	cmd.Flags().StringVar(&runDetectorURL, "prometheus-url", "", "Prometheus endpoint URL (required with a detector name)")
+	cmd.Flags().DurationVar(&sustainedWindow, "sustained-window", 0, "Only report high memory/error rate after it held for this long (0 = instantly)")
+	cmd.Flags().DurationVar(&detectionWindow, "detection-window", detector.DefaultWindow, "Range the detector's rate()/increase() queries look back over")
+
+	return cmd
+}
+
+func runRunDetector(cmd *cobra.Command, args []string) error {
+	if detectionWindow <= 0 {
+		return util.InvalidInputf("--detection-window must be positive")
+	}
+	if _, err := loadConfigFile(); err != nil {
+		return util.InvalidInput(err)
+	}
+	registry, err := buildRegistry(currentConfig())
+	if err != nil {
+		return util.InvalidInput(err)
+	}
+
+	if len(args) == 0 {
+		fmt.Println(strings.Join(detectorNames(registry), "\n"))
+		return nil
+	}
+	d, ok := registry.Get(args[0])
+	if !ok && !currentConfig().IsDisabled(args[0]) {
+		// Optional detectors can be run without enabling them first
+		d, ok = detector.LookupOptional(args[0])
+	}
+	if !ok {
+		return util.InvalidInputf("unknown or disabled detector %q (available: %s)",
+			args[0], strings.Join(detectorNames(registry), ", "))
+	}
+
+	if err := validatePrometheusURL(runDetectorURL); err != nil {
+		return util.InvalidInput(err)
+	}
+	provider, err := metrics.NewPrometheusClient(runDetectorURL, prometheusTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to create Prometheus client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), detectorTimeout)
+	defer cancel()
+	if err := runSingleDetector(ctx, os.Stdout, provider, d); err != nil {
+		return util.Runtime(err)
+	}
+	return nil
+}
+
+// runSingleDetector runs d once against provider, writing each query with its
+// raw result and then the resulting problems to out
+func runSingleDetector(ctx context.Context, out io.Writer, provider metrics.MetricsProvider, d detector.Detector) error {
+	traced := &tracingProvider{MetricsProvider: provider, out: out}
+	start := time.Now()
+	window := detector.EffectiveWindow(d, detectionWindow, currentConfig().DetectorWindows())
+	problems, err := d.Detect(ctx, traced, window)
+	if err != nil {
+		return fmt.Errorf("detector %s: %w", d.Name(), err)
+	}
+
+	fmt.Fprintf(out, "\n%s: %d queries, %d problems in %s\n",
+		d.Name(), traced.queries, len(problems), time.Since(start).Round(time.Millisecond))
+	templates, _ := currentConfig().ProblemTemplates() // Validated on load
+	for _, p := range problems {
+		p.NormalizeLabels(currentConfig().LabelNormalization)
+		p.ID = models.StableID(p.EntityType, p.Type, p.Labels)
+		p.AddStaticLabels(currentConfig().DetectorLabels[d.Name()])
+		templates.Apply(p)
+		fmt.Fprintf(out, "\n[%s] %s\n", p.Severity, p.Entity)
+		fmt.Fprintf(out, "  id:      %s\n", p.ID)
+		fmt.Fprintf(out, "  title:   %s\n", p.Title)
+		fmt.Fprintf(out, "  message: %s\n", p.Message)
+		if p.Hint != "" {
+			fmt.Fprintf(out, "  hint:    %s\n", p.Hint)
+		}
+		if len(p.Labels) > 0 {
+			fmt.Fprintf(out, "  labels:  %s\n", toLabelSet(p.Labels))
+		}
+		for _, name := range sortedMetricNames(p.Metrics) {
+			fmt.Fprintf(out, "  metric:  %s = %g\n", name, p.Metrics[name])
+		}
+	}
+	return nil
+}
+
+// tracingProvider prints every query and the series it returned
+type tracingProvider struct {
+	metrics.MetricsProvider
+	out     io.Writer
+	queries int
+}
+
+func (t *tracingProvider) QueryInstant(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+	t.queries++
+	fmt.Fprintf(t.out, "query: %s\n", query)
+	result, err := t.MetricsProvider.QueryInstant(ctx, query, ts)
+	if err != nil {
+		fmt.Fprintf(t.out, "  error: %v\n", err)
+		return nil, err
+	}
+	fmt.Fprintf(t.out, "  %d series\n", len(result))
+	for _, sample := range result {
+		fmt.Fprintf(t.out, "  %s => %s\n", sample.Metric, sample.Value)
+	}
+	return result, nil
+}
+
+func (t *tracingProvider) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (model.Matrix, error) {
+	t.queries++
+	fmt.Fprintf(t.out, "query: %s [%s step %s]\n", query, end.Sub(start), step)
+	result, err := t.MetricsProvider.QueryRange(ctx, query, start, end, step)
+	if err != nil {
+		fmt.Fprintf(t.out, "  error: %v\n", err)
+		return nil, err
+	}
+	fmt.Fprintf(t.out, "  %d series\n", len(result))
+	for _, stream := range result {
+		fmt.Fprintf(t.out, "  %s => %d points\n", stream.Metric, len(stream.Values))
+	}
+	return result, nil
+}
+
+func detectorNames(registry *detector.Registry) []string {
+	var names []string
+	for _, d := range registry.All() {
+		names = append(names, d.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func toLabelSet(labels map[string]string) model.LabelSet {
+	set := make(model.LabelSet, len(labels))
+	for k, v := range labels {
+		set[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return set
+}
+
+func sortedMetricNames(m map[string]float64) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
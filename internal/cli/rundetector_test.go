@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/config"
+	"github.com/ppiankov/infranow/internal/metrics"
+)
+
+func TestRunSingleDetector_PrintsSeriesAndProblems(t *testing.T) {
+	registry, err := buildRegistry(&config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, ok := registry.Get("kubernetes_oom_kills")
+	if !ok {
+		t.Fatal("kubernetes_oom_kills not registered")
+	}
+
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{
+				&model.Sample{
+					Metric: model.Metric{"namespace": "prod", "pod": "api-0", "container": "app"},
+					Value:  2,
+				},
+			}, nil
+		},
+	}
+
+	var out bytes.Buffer
+	if err := runSingleDetector(context.Background(), &out, provider, d); err != nil {
+		t.Fatalf("runSingleDetector() error = %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		`query: increase(kube_pod_container_status_restarts_total{reason="OOMKilled"}[5m]) > 0`,
+		`{container="app", namespace="prod", pod="api-0"} => 2`,
+		"kubernetes_oom_kills: 1 queries, 1 problems",
+		"[CRITICAL] prod/api-0/app",
+		"id:      oom_kill/",
+		"metric:  restart_count = 2",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunSingleDetector_QueryError(t *testing.T) {
+	registry, err := buildRegistry(&config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, _ := registry.Get("kubernetes_oom_kills")
+
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, context.DeadlineExceeded
+		},
+	}
+
+	var out bytes.Buffer
+	if err := runSingleDetector(context.Background(), &out, provider, d); err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(out.String(), "error: context deadline exceeded") {
+		t.Errorf("query error not printed:\n%s", out.String())
+	}
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/service"
+)
+
+// serviceProvider is queried by annotateServices (nil = disabled)
+var serviceProvider metrics.MetricsProvider
+
+// serviceCache holds the pod services last resolved by refreshServiceCache,
+// so the TUI annotates without querying on its event loop
+var serviceCache struct {
+	sync.RWMutex
+	pods map[string]string
+}
+
+// setupServiceDerivation enables service labels with --derive-service or
+// --group-by service
+func setupServiceDerivation(provider metrics.MetricsProvider) {
+	serviceProvider = nil
+	if deriveService || groupBy == service.Label {
+		serviceProvider = provider
+	}
+}
+
+// annotateServices labels problems with their service. A failed pod labels
+// query is logged and the service falls back to the workload name;
+// enrichment never blocks output.
+func annotateServices(problems []*models.Problem) []*models.Problem {
+	if serviceProvider == nil {
+		return problems
+	}
+	service.Annotate(problems, resolveServices())
+	return problems
+}
+
+// annotateCachedServices is annotateServices from the pod services last
+// resolved by refreshServiceCache, for the TUI filter that runs on every key
+// press
+func annotateCachedServices(problems []*models.Problem) []*models.Problem {
+	if serviceProvider == nil {
+		return problems
+	}
+	serviceCache.RLock()
+	defer serviceCache.RUnlock()
+	service.Annotate(problems, serviceCache.pods)
+	return problems
+}
+
+// refreshServiceCache resolves the pod services for annotateCachedServices
+func refreshServiceCache() {
+	if serviceProvider == nil {
+		return
+	}
+	pods := resolveServices()
+	serviceCache.Lock()
+	defer serviceCache.Unlock()
+	serviceCache.pods = pods
+}
+
+// resolveServices maps pods to services, logging a failed query (nil map)
+func resolveServices() map[string]string {
+	ctx, cancel := context.WithTimeout(context.Background(), prometheusTimeout)
+	defer cancel()
+
+	pods, err := service.Resolve(ctx, serviceProvider)
+	if err != nil {
+		fmt.Fprintf(backgroundLog, "[infranow] service labels from pod labels skipped: %v\n", err)
+	}
+	return pods
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ppiankov/infranow/internal/correlator"
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/monitor"
+	"github.com/ppiankov/infranow/internal/util"
+)
+
+// snapshotReadHeaderTimeout bounds slow clients on the snapshot server
+const snapshotReadHeaderTimeout = 10 * time.Second
+
+var (
+	snapshotURL      string
+	snapshotListen   string
+	snapshotHTMLFile string
+)
+
+// NewSnapshotCommand creates the snapshot subcommand
+func NewSnapshotCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Capture current problems and share them as a frozen page",
+		Long: `Snapshot runs one detection cycle and freezes the result. The captured
+problems are served read-only over HTTP (HTML at /, JSON at /problems.json),
+or written to a self-contained HTML file with --html-file. Prometheus is not
+queried again after capture.`,
+		RunE: runSnapshot,
+	}
+
+	cmd.Flags().StringVar(&snapshotURL, "prometheus-url", "", "Prometheus endpoint URL (required)")
+	cmd.Flags().StringVar(&snapshotListen, "listen", "127.0.0.1:8080", "Address to serve the snapshot on")
+	cmd.Flags().StringVar(&snapshotHTMLFile, "html-file", "", "Write the snapshot to this HTML file and exit instead of serving it")
+
+	if err := cmd.MarkFlagRequired("prometheus-url"); err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func runSnapshot(cmd *cobra.Command, args []string) error {
+	if err := validatePrometheusURL(snapshotURL); err != nil {
+		return util.InvalidInput(err)
+	}
+
+	problems, err := captureProblems(snapshotURL)
+	if err != nil {
+		return util.Runtime(err)
+	}
+
+	models.InDisplayLocation(problems)
+	snap := &monitor.Snapshot{
+		CapturedAt: models.DisplayTime(time.Now()),
+		Source:     sanitizeURL(snapshotURL),
+		Problems:   problems,
+	}
+	fmt.Fprintln(os.Stderr, monitor.PlainTextSummary(problems))
+
+	if snapshotHTMLFile != "" {
+		page, err := monitor.SnapshotHTML(snap)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(snapshotHTMLFile, page, 0o600); err != nil {
+			return fmt.Errorf("failed to write snapshot: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Snapshot written to: %s\n", snapshotHTMLFile)
+		return nil
+	}
+
+	handler, err := monitor.SnapshotHandler(snap)
+	if err != nil {
+		return err
+	}
+	return serveSnapshot(cmd.Context(), snapshotListen, handler)
+}
+
+// captureProblems runs a single detection cycle against promURL
+func captureProblems(promURL string) ([]*models.Problem, error) {
+	provider, err := metrics.NewPrometheusClient(promURL, prometheusTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("prometheus client: %w", err)
+	}
+
+	healthCtx, healthCancel := context.WithTimeout(context.Background(), prometheusTimeout)
+	defer healthCancel()
+	if err := provider.Health(healthCtx); err != nil {
+		return nil, fmt.Errorf("prometheus health: %w", err)
+	}
+
+	registry := detector.DefaultRegistry()
+	watcher := monitor.NewWatcher(provider, registry, 0, detectorTimeout)
+
+	watchCtx, watchCancel := context.WithCancel(context.Background())
+	defer watchCancel()
+	go func() {
+		_ = watcher.Start(watchCtx) // Best-effort
+	}()
+
+	select {
+	case <-watcher.UpdateChan():
+	case <-time.After(firstDetectionTimeout):
+	}
+	watchCancel()
+
+	return correlator.Correlate(watcher.GetProblems()), nil
+}
+
+// serveSnapshot serves handler on addr until interrupted
+func serveSnapshot(ctx context.Context, addr string, handler http.Handler) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           gzipHandler(handler),
+		ReadHeaderTimeout: snapshotReadHeaderTimeout,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+	fmt.Fprintf(os.Stderr, "Serving snapshot on http://%s (Ctrl+C to stop)\n", addr)
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("snapshot server: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), snapshotReadHeaderTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("snapshot server shutdown: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ppiankov/infranow/internal/baseline"
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/util"
+)
+
+// exitOnStateChange runs --state-file change detection and returns an
+// ExitProblemsChanged status if the problem set changed, nil otherwise.
+func exitOnStateChange(problems []*models.Problem) error {
+	format, _ := baseline.ParseStateFormat(stateFormat) // Validated on startup
+	code, err := checkStateFile(stateFile, format, problems, os.Stderr)
+	if err != nil {
+		return err
+	}
+	return util.ExitStatus(code)
+}
+
+// checkStateFile compares problems against the state file, prints the delta
+// to w, saves the new state in format and returns the exit code to use.
+func checkStateFile(path string, format baseline.StateFormat, problems []*models.Problem, w io.Writer) (int, error) {
+	prev, err := baseline.LoadState(path)
+	if err != nil {
+		return util.ExitRuntimeError, err
+	}
+
+	delta := baseline.DiffState(prev, problems)
+	if err := baseline.SaveState(path, problems, format); err != nil {
+		return util.ExitRuntimeError, err
+	}
+
+	if !delta.Changed() {
+		fmt.Fprintln(w, "Problem set unchanged since last run")
+		return util.ExitSuccess, nil
+	}
+
+	fmt.Fprintf(w, "Problem set changed since last run: %d new, %d resolved\n", len(delta.New), len(delta.Resolved))
+	for _, id := range delta.New {
+		fmt.Fprintf(w, "+ %s\n", id)
+	}
+	for _, id := range delta.Resolved {
+		fmt.Fprintf(w, "- %s\n", id)
+	}
+	return util.ExitProblemsChanged, nil
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/baseline"
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/monitor"
+	"github.com/ppiankov/infranow/internal/util"
+)
+
+func TestCheckStateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	problems := []*models.Problem{{ID: "prod/api/crashloop"}, {ID: "prod/db/disk"}}
+
+	// First run: everything is new
+	var out bytes.Buffer
+	code, err := checkStateFile(path, baseline.StateJSON, problems, &out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if code != util.ExitProblemsChanged {
+		t.Errorf("first run exit = %d, want %d", code, util.ExitProblemsChanged)
+	}
+
+	// Same set: exit 0
+	out.Reset()
+	code, err = checkStateFile(path, baseline.StateJSON, problems, &out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if code != util.ExitSuccess {
+		t.Errorf("unchanged exit = %d, want %d", code, util.ExitSuccess)
+	}
+
+	// One resolved, one new: distinct code and delta printed
+	out.Reset()
+	changed := []*models.Problem{{ID: "prod/api/crashloop"}, {ID: "prod/web/oom"}}
+	code, err = checkStateFile(path, baseline.StateJSON, changed, &out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if code != util.ExitProblemsChanged {
+		t.Errorf("changed exit = %d, want %d", code, util.ExitProblemsChanged)
+	}
+	if !strings.Contains(out.String(), "+ prod/web/oom") || !strings.Contains(out.String(), "- prod/db/disk") {
+		t.Errorf("delta output = %q", out.String())
+	}
+}
+
+func TestStateFile_WithCompareBaseline(t *testing.T) {
+	savedBaseline, savedState, savedFormat := compareBaseline, stateFile, stateFormat
+	savedDrift, savedFailOn := failOnDrift, failOnSeverity
+	t.Cleanup(func() {
+		compareBaseline, stateFile, stateFormat = savedBaseline, savedState, savedFormat
+		failOnDrift, failOnSeverity = savedDrift, savedFailOn
+	})
+
+	dir := t.TempDir()
+	compareBaseline = filepath.Join(dir, "baseline.json")
+	if err := baseline.SaveBaseline(nil, compareBaseline, nil); err != nil {
+		t.Fatalf("SaveBaseline failed: %v", err)
+	}
+	stateFormat, failOnDrift, failOnSeverity = string(baseline.StateJSON), true, ""
+
+	modes := map[string]func(context.Context, *monitor.Watcher) error{
+		"json": runJSONMode,
+		"text": func(ctx context.Context, w *monitor.Watcher) error { return runTextMode(ctx, w, monitor.PlainText) },
+	}
+	for name, run := range modes {
+		t.Run(name, func(t *testing.T) {
+			stateFile = filepath.Join(t.TempDir(), "state.json")
+			// The problem is new against the baseline every run; only the
+			// state file decides the exit code
+			for i, want := range []int{util.ExitProblemsChanged, util.ExitSuccess} {
+				var runErr error
+				captureStdout(t, func() {
+					runErr = run(context.Background(), startStaticWatcher(t))
+				})
+				if got := util.ExitCode(runErr); got != want {
+					t.Errorf("run %d: ExitCode(%v) = %d, want %d", i+1, runErr, got, want)
+				}
+			}
+		})
+	}
+}
+
+// startStaticWatcher runs a watcher over one fixed problem until the test ends
+func startStaticWatcher(t *testing.T) *monitor.Watcher {
+	t.Helper()
+	registry := detector.NewRegistry()
+	registry.Register(staticDetector{problems: []*models.Problem{
+		{Entity: "prod/api", EntityType: "kubernetes_pod", Type: "oom_kill", Severity: models.SeverityWarning, Labels: map[string]string{"pod": "api"}},
+	}})
+	watcher := monitor.NewWatcher(&metrics.MockProvider{}, registry, 0, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		_ = watcher.Start(ctx)
+	}()
+	return watcher
+}
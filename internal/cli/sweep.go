@@ -70,13 +70,12 @@ Results are unified into a single output with cluster annotations.`,
 
 func runSweep(cmd *cobra.Command, args []string) error {
 	if err := validatePort(sweepK8sRemotePort, "k8s-remote-port"); err != nil {
-		return err
+		return util.InvalidInput(err)
 	}
 
 	contexts, err := util.ListContexts("")
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		util.Exit(util.ExitRuntimeError)
+		return util.Runtime(err)
 	}
 
 	if sweepContexts != "" {
@@ -84,8 +83,7 @@ func runSweep(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(contexts) == 0 {
-		fmt.Fprintf(os.Stderr, "Error: no kubeconfig contexts match filter %q\n", sweepContexts)
-		util.Exit(util.ExitInvalidInput)
+		return util.InvalidInputf("no kubeconfig contexts match filter %q", sweepContexts)
 	}
 
 	if verbose {
@@ -150,7 +148,7 @@ func scanCluster(contextName string) ClusterResult {
 		return result
 	}
 
-	pf := util.NewPortForwardForContext(kctx, sweepK8sService, sweepK8sNamespace, "0", sweepK8sRemotePort)
+	pf := util.NewPortForwardForContext(kctx, util.ServiceTarget(sweepK8sService), sweepK8sNamespace, "0", sweepK8sRemotePort)
 	if startErr := pf.Start(); startErr != nil {
 		result.Error = fmt.Errorf("port-forward: %w", startErr)
 		return result
@@ -175,8 +173,7 @@ func scanCluster(contextName string) ClusterResult {
 		return result
 	}
 
-	registry := detector.NewRegistry()
-	registerDetectors(registry)
+	registry := detector.DefaultRegistry()
 
 	watcher := monitor.NewWatcher(provider, registry, 0, detectorTimeout)
 
@@ -261,9 +258,10 @@ func sweepOutputJSON(problems []*models.Problem, contexts []string, failures []C
 		})
 	}
 
+	models.InDisplayLocation(problems)
 	output := map[string]interface{}{
 		"metadata": map[string]interface{}{
-			"timestamp":        time.Now().Format(time.RFC3339),
+			"timestamp":        models.FormatTime(time.Now()),
 			"contexts_scanned": len(contexts) - len(failures),
 			"contexts_failed":  len(failures),
 			"mode":             "sweep",
@@ -310,21 +308,12 @@ func sweepExitCode(problems []*models.Problem) error {
 		}
 		for _, p := range problems {
 			if p.Severity.AtLeast(threshold) {
-				util.Exit(util.ExitProblemsCritical)
+				return util.ExitStatus(util.ExitProblemsCritical)
 			}
 		}
 		return nil
 	}
-
-	if len(problems) > 0 {
-		switch monitor.HighestSeverity(problems) {
-		case models.SeverityCritical, models.SeverityFatal:
-			util.Exit(util.ExitProblemsCritical)
-		default:
-			util.Exit(util.ExitProblemsWarning)
-		}
-	}
-	return nil
+	return util.ExitStatus(severityExitCode(problems))
 }
 
 func countBySeverity(problems []*models.Problem, sev models.Severity) int {
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ppiankov/infranow/internal/monitor"
+	"github.com/ppiankov/infranow/internal/util"
+)
+
+// defaultTopEntities is how many entities top-entities lists by default
+const defaultTopEntities = 10
+
+var (
+	topEntitiesURL    string
+	topEntitiesBy     string
+	topEntitiesLimit  int
+	topEntitiesOutput string
+)
+
+// NewTopEntitiesCommand creates the top-entities subcommand
+func NewTopEntitiesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "top-entities",
+		Short: "Rank entities, nodes or namespaces by their current problems",
+		Long: `Top-entities runs one detection cycle and groups the problems across types
+by entity, node or namespace. Groups are ranked by the sum of their problem
+scores, so the worst offenders come first.`,
+		RunE: runTopEntities,
+	}
+
+	cmd.Flags().StringVar(&topEntitiesURL, "prometheus-url", "", "Prometheus endpoint URL (required)")
+	cmd.Flags().StringVar(&topEntitiesBy, "by", monitor.GroupByEntity, "Group by: entity, node, namespace")
+	cmd.Flags().IntVar(&topEntitiesLimit, "limit", defaultTopEntities, "Number of groups to list (0 = all)")
+	cmd.Flags().StringVar(&topEntitiesOutput, "output", "text", "Output format (text, json)")
+
+	if err := cmd.MarkFlagRequired("prometheus-url"); err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func runTopEntities(cmd *cobra.Command, args []string) error {
+	if err := validatePrometheusURL(topEntitiesURL); err != nil {
+		return util.InvalidInput(err)
+	}
+	if err := monitor.ValidGrouping(topEntitiesBy); err != nil {
+		return util.InvalidInputf("--by: %w", err)
+	}
+	if topEntitiesLimit < 0 {
+		return util.InvalidInputf("--limit must not be negative")
+	}
+	if topEntitiesOutput != "text" && topEntitiesOutput != "json" {
+		return util.InvalidInputf("--output must be text or json")
+	}
+
+	problems, err := captureProblems(topEntitiesURL)
+	if err != nil {
+		return util.Runtime(err)
+	}
+	ranked := monitor.TopEntities(problems, topEntitiesBy, topEntitiesLimit)
+
+	if topEntitiesOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(map[string]interface{}{
+			"by":       topEntitiesBy,
+			"entities": ranked,
+		})
+	}
+
+	printTopEntities(ranked)
+	return nil
+}
+
+func printTopEntities(ranked []monitor.EntityRank) {
+	if len(ranked) == 0 {
+		fmt.Println("No problems found.")
+		return
+	}
+
+	fmt.Printf("%-40s %8s %8s  %-8s  %s\n", strings.ToUpper(topEntitiesBy), "PROBLEMS", "SCORE", "WORST", "TYPES")
+	for _, r := range ranked {
+		fmt.Printf("%-40s %8d %8.1f  %-8s  %s\n",
+			truncateStr(r.Name, 40),
+			r.Problems,
+			r.Score,
+			r.Worst,
+			strings.Join(r.Types, ","),
+		)
+	}
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// byteUnits are the suffixes --memory-limit accepts, as in GOMEMLIMIT
+var byteUnits = []struct {
+	suffix string
+	size   uint64
+}{
+	{"TiB", 1 << 40},
+	{"GiB", 1 << 30},
+	{"MiB", 1 << 20},
+	{"KiB", 1 << 10},
+	{"B", 1},
+}
+
+// parseByteSize parses a size like 512MiB or 1GiB; a bare number is bytes
+func parseByteSize(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	multiplier := uint64(1)
+	for _, u := range byteUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			s, multiplier = strings.TrimSpace(strings.TrimSuffix(s, u.suffix)), u.size
+			break
+		}
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 512MiB or 2GiB)", s)
+	}
+	return n * multiplier, nil
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/ppiankov/infranow/internal/util"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint64
+		wantErr bool
+	}{
+		{"512MiB", 512 << 20, false},
+		{"2GiB", 2 << 30, false},
+		{"64 KiB", 64 << 10, false},
+		{"1048576", 1 << 20, false},
+		{"100B", 100, false},
+		{"1.5GiB", 0, true},
+		{"512MB", 0, true},
+		{"0MiB", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseByteSize(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseByteSize(%q) = %d, %v; want %d (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestMemoryLimit_NotAppliedOnInvalidInput(t *testing.T) {
+	before := debug.SetMemoryLimit(-1)
+	t.Cleanup(func() { debug.SetMemoryLimit(before) })
+
+	err := executeRoot(t, "monitor", "--memory-limit", "1MiB", "--max-goroutines", "-1")
+	if got := util.ExitCode(err); got != util.ExitInvalidInput {
+		t.Fatalf("ExitCode(%v) = %d, want %d", err, got, util.ExitInvalidInput)
+	}
+	if got := debug.SetMemoryLimit(-1); got != before {
+		t.Errorf("memory limit changed to %d by a rejected run, want %d", got, before)
+	}
+}
@@ -0,0 +1,255 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// DefaultFileName is the config file looked up in $HOME when --config is not set
+const DefaultFileName = ".infranow.yaml"
+
+// Config holds settings loaded from the infranow config file.
+// Command-line flags take precedence over values set here.
+type Config struct {
+	// Namespace filters (same syntax as --include-namespaces / --exclude-namespaces)
+	IncludeNamespaces string `json:"include_namespaces,omitempty"`
+	ExcludeNamespaces string `json:"exclude_namespaces,omitempty"`
+
+	// Detectors removed from the registry by name
+	DisabledDetectors []string `json:"disabled_detectors,omitempty"`
+
+	// Optional detectors added to the registry by name (off by default)
+	EnabledDetectors []string `json:"enabled_detectors,omitempty"`
+
+	// Threshold overrides keyed by detector name
+	Thresholds map[string]float64 `json:"thresholds,omitempty"`
+
+	// Problem expiry keyed by problem type. A problem is dropped once it is
+	// older than its TTL, even if the detector still reports it.
+	TTLs map[string]Duration `json:"ttls,omitempty"`
+
+	// Query range window overrides keyed by detector name (e.g. widen
+	// rate() windows on clusters with a long scrape interval)
+	Windows map[string]Duration `json:"windows,omitempty"`
+
+	// Blast radius overrides for trustwatch certificates keyed by source
+	// (mesh-issuer, apiservice, webhook, ...)
+	TrustwatchBlastRadius map[string]int `json:"trustwatch_blast_radius,omitempty"`
+
+	// Trustwatch sources with short-lived certificates: their expiry is only
+	// reported from CRITICAL up, never as WARNING
+	TrustwatchSkipWarning []string `json:"trustwatch_skip_warning,omitempty"`
+
+	// Severity raised per problem type, optionally only during a schedule
+	// (e.g. image pull failures are FATAL during business hours)
+	SeverityOverrides []SeverityOverride `json:"severity_overrides,omitempty"`
+
+	// Severity moved up or down by namespace, e.g. prod one level up and
+	// dev one level down; the first matching rule applies
+	NamespaceCriticality []NamespaceCriticality `json:"namespace_criticality,omitempty"`
+
+	// Rules rewriting problem label values before IDs are derived, keyed by
+	// label name (e.g. node: [strip_port, lowercase])
+	LabelNormalization map[string][]string `json:"label_normalization,omitempty"`
+
+	// Static labels added to every problem of a detector, keyed by detector
+	// name (e.g. category: capacity), for routing and filtering downstream
+	DetectorLabels map[string]map[string]string `json:"detector_labels,omitempty"`
+
+	// Title and message templates keyed by problem type, replacing the
+	// detector's wording (e.g. to localize it)
+	Templates map[string]models.ProblemTemplate `json:"templates,omitempty"`
+
+	// Named filter bundles selected with --profile
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+
+	// Metric and label names for the ingress error rate detector
+	// (default: ingress-nginx)
+	IngressMetrics *IngressMetrics `json:"ingress_metrics,omitempty"`
+}
+
+// DefaultPath returns $HOME/.infranow.yaml
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(home, DefaultFileName), nil
+}
+
+// ResolvePath returns the config file to use. An explicit path is returned as-is;
+// otherwise the default path is returned only if the file exists. An empty result
+// means no config file is in use.
+func ResolvePath(explicit string) string {
+	if explicit != "" {
+		return explicit
+	}
+	path, err := DefaultPath()
+	if err != nil {
+		return ""
+	}
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// Load reads and validates a config file. Unknown keys are rejected so typos
+// surface as errors instead of being silently ignored.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates YAML (or JSON) config data.
+func Parse(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks config values that can be verified without a detector registry.
+func (c *Config) Validate() error {
+	for name, v := range c.Thresholds {
+		if v <= 0 {
+			return fmt.Errorf("thresholds.%s: must be positive, got %g", name, v)
+		}
+	}
+	for problemType, ttl := range c.TTLs {
+		if ttl <= 0 {
+			return fmt.Errorf("ttls.%s: must be positive, got %s", problemType, time.Duration(ttl))
+		}
+	}
+	for name, window := range c.Windows {
+		if window <= 0 {
+			return fmt.Errorf("windows.%s: must be positive, got %s", name, time.Duration(window))
+		}
+	}
+	for source, radius := range c.TrustwatchBlastRadius {
+		if radius <= 0 {
+			return fmt.Errorf("trustwatch_blast_radius.%s: must be positive, got %d", source, radius)
+		}
+	}
+	for i, source := range c.TrustwatchSkipWarning {
+		if source == "" {
+			return fmt.Errorf("trustwatch_skip_warning[%d]: empty source", i)
+		}
+	}
+	for i, o := range c.SeverityOverrides {
+		if len(o.Types) == 0 {
+			return fmt.Errorf("severity_overrides[%d]: types must not be empty", i)
+		}
+		if _, err := models.ParseSeverity(o.Severity); err != nil {
+			return fmt.Errorf("severity_overrides[%d]: %w", i, err)
+		}
+		if o.Schedule != nil {
+			if err := o.Schedule.validate(); err != nil {
+				return fmt.Errorf("severity_overrides[%d].schedule: %w", i, err)
+			}
+		}
+	}
+	for i, rule := range c.NamespaceCriticality {
+		if rule.Namespaces == "" {
+			return fmt.Errorf("namespace_criticality[%d]: namespaces must not be empty", i)
+		}
+		for _, pattern := range rule.Patterns() {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return fmt.Errorf("namespace_criticality[%d]: bad pattern %q", i, pattern)
+			}
+		}
+		if rule.Adjust == 0 || rule.Adjust < -2 || rule.Adjust > 2 {
+			return fmt.Errorf("namespace_criticality[%d]: adjust must be -2, -1, 1 or 2, got %d", i, rule.Adjust)
+		}
+	}
+	for label, rules := range c.LabelNormalization {
+		if len(rules) == 0 {
+			return fmt.Errorf("label_normalization.%s: rules must not be empty", label)
+		}
+		for _, rule := range rules {
+			if !models.ValidNormalization(rule) {
+				return fmt.Errorf("label_normalization.%s: unknown rule %q (use %s or %s)", label, rule, models.NormalizeStripPort, models.NormalizeLowercase)
+			}
+		}
+	}
+	for name, labels := range c.DetectorLabels {
+		if len(labels) == 0 {
+			return fmt.Errorf("detector_labels.%s: labels must not be empty", name)
+		}
+		for label := range labels {
+			if !labelNameRe.MatchString(label) {
+				return fmt.Errorf("detector_labels.%s: invalid label name %q", name, label)
+			}
+		}
+	}
+	if _, err := models.CompileTemplates(c.Templates); err != nil {
+		return fmt.Errorf("templates.%w", err)
+	}
+	for i, name := range c.DisabledDetectors {
+		if name == "" {
+			return fmt.Errorf("disabled_detectors[%d]: empty detector name", i)
+		}
+	}
+	for i, name := range c.EnabledDetectors {
+		if name == "" {
+			return fmt.Errorf("enabled_detectors[%d]: empty detector name", i)
+		}
+	}
+	for name, p := range c.Profiles {
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("profiles.%s.%w", name, err)
+		}
+	}
+	if c.IngressMetrics != nil {
+		if err := c.IngressMetrics.validate(); err != nil {
+			return fmt.Errorf("ingress_metrics.%w", err)
+		}
+	}
+	return nil
+}
+
+// IsDisabled reports whether the named detector is disabled.
+func (c *Config) IsDisabled(name string) bool {
+	for _, d := range c.DisabledDetectors {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ProblemTTLs returns the configured problem expiry keyed by problem type.
+func (c *Config) ProblemTTLs() map[string]time.Duration {
+	ttls := make(map[string]time.Duration, len(c.TTLs))
+	for problemType, ttl := range c.TTLs {
+		ttls[problemType] = time.Duration(ttl)
+	}
+	return ttls
+}
+
+// ProblemTemplates returns the compiled title and message templates
+func (c *Config) ProblemTemplates() (*models.ProblemTemplates, error) {
+	return models.CompileTemplates(c.Templates)
+}
+
+// DetectorWindows returns the configured query windows keyed by detector name.
+func (c *Config) DetectorWindows() map[string]time.Duration {
+	windows := make(map[string]time.Duration, len(c.Windows))
+	for name, window := range c.Windows {
+		windows[name] = time.Duration(window)
+	}
+	return windows
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParse_Valid(t *testing.T) {
+	data := []byte(`
+include_namespaces: "prod-*"
+disabled_detectors:
+  - kubernetes_pending
+thresholds:
+  pg_replication_lag: 60
+ttls:
+  tote_salvage_failure: 10m
+windows:
+  kubernetes_oom_kills: 15m
+trustwatch_blast_radius:
+  webhook: 25
+trustwatch_skip_warning:
+  - spiffe
+severity_overrides:
+  - types: [imagepullbackoff]
+    severity: FATAL
+    schedule:
+      days: [mon, tue, wed, thu, fri]
+      start: "09:00"
+      end: "18:00"
+      timezone: Europe/Berlin
+namespace_criticality:
+  - namespaces: "prod, prod-*"
+    adjust: 1
+label_normalization:
+  node: [strip_port, lowercase]
+detector_labels:
+  generic_disk_space:
+    category: capacity
+templates:
+  oom_kill:
+    message: "{{.Labels.pod}} exceeded its memory limit"
+ingress_metrics:
+  host_label: server_name
+`)
+	cfg, err := Parse(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.IncludeNamespaces != "prod-*" {
+		t.Errorf("include_namespaces = %q, want %q", cfg.IncludeNamespaces, "prod-*")
+	}
+	if !cfg.IsDisabled("kubernetes_pending") {
+		t.Error("kubernetes_pending should be disabled")
+	}
+	if cfg.IsDisabled("kubernetes_oom_kills") {
+		t.Error("kubernetes_oom_kills should not be disabled")
+	}
+	if cfg.Thresholds["pg_replication_lag"] != 60 {
+		t.Errorf("threshold = %g, want 60", cfg.Thresholds["pg_replication_lag"])
+	}
+	if got := cfg.ProblemTTLs()["tote_salvage_failure"]; got != 10*time.Minute {
+		t.Errorf("ttl = %s, want 10m", got)
+	}
+	if got := cfg.DetectorWindows()["kubernetes_oom_kills"]; got != 15*time.Minute {
+		t.Errorf("window = %s, want 15m", got)
+	}
+	if got := cfg.TrustwatchBlastRadius["webhook"]; got != 25 {
+		t.Errorf("trustwatch_blast_radius.webhook = %d, want 25", got)
+	}
+	if len(cfg.TrustwatchSkipWarning) != 1 || cfg.TrustwatchSkipWarning[0] != "spiffe" {
+		t.Errorf("trustwatch_skip_warning = %v, want [spiffe]", cfg.TrustwatchSkipWarning)
+	}
+	if len(cfg.SeverityOverrides) != 1 || cfg.SeverityOverrides[0].Schedule.Timezone != "Europe/Berlin" {
+		t.Errorf("severity_overrides = %+v", cfg.SeverityOverrides)
+	}
+	if n := cfg.NamespaceCriticality; len(n) != 1 || n[0].Adjust != 1 || len(n[0].Patterns()) != 2 || n[0].Patterns()[1] != "prod-*" {
+		t.Errorf("namespace_criticality = %+v", n)
+	}
+	if rules := cfg.LabelNormalization["node"]; len(rules) != 2 || rules[0] != "strip_port" {
+		t.Errorf("label_normalization.node = %v, want [strip_port lowercase]", rules)
+	}
+	if cfg.DetectorLabels["generic_disk_space"]["category"] != "capacity" {
+		t.Errorf("detector_labels.generic_disk_space = %v", cfg.DetectorLabels["generic_disk_space"])
+	}
+	if cfg.Templates["oom_kill"].Message != "{{.Labels.pod}} exceeded its memory limit" {
+		t.Errorf("templates.oom_kill = %+v", cfg.Templates["oom_kill"])
+	}
+	if cfg.IngressMetrics == nil || cfg.IngressMetrics.HostLabel != "server_name" {
+		t.Errorf("ingress_metrics = %+v, want host_label server_name", cfg.IngressMetrics)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"unknown key", "include_namespace: prod\n"},
+		{"malformed yaml", "thresholds: [\n"},
+		{"negative threshold", "thresholds:\n  pg_replication_lag: -1\n"},
+		{"zero threshold", "thresholds:\n  pg_replication_lag: 0\n"},
+		{"malformed ttl", "ttls:\n  tote_salvage_failure: soon\n"},
+		{"numeric ttl", "ttls:\n  tote_salvage_failure: 600\n"},
+		{"zero ttl", "ttls:\n  tote_salvage_failure: 0s\n"},
+		{"negative window", "windows:\n  kubernetes_oom_kills: -5m\n"},
+		{"empty detector name", "disabled_detectors: [\"\"]\n"},
+		{"empty enabled detector name", "enabled_detectors: [\"\"]\n"},
+		{"zero blast radius", "trustwatch_blast_radius:\n  webhook: 0\n"},
+		{"empty skip warning source", "trustwatch_skip_warning: [\"\"]\n"},
+		{"override without types", "severity_overrides:\n  - severity: FATAL\n"},
+		{"override bad severity", "severity_overrides:\n  - types: [oom_kill]\n    severity: SEVERE\n"},
+		{"schedule bad day", "severity_overrides:\n  - types: [oom_kill]\n    severity: FATAL\n    schedule: {days: [funday], start: \"09:00\", end: \"18:00\"}\n"},
+		{"schedule bad clock", "severity_overrides:\n  - types: [oom_kill]\n    severity: FATAL\n    schedule: {start: \"9am\", end: \"18:00\"}\n"},
+		{"schedule end before start", "severity_overrides:\n  - types: [oom_kill]\n    severity: FATAL\n    schedule: {start: \"18:00\", end: \"09:00\"}\n"},
+		{"normalization unknown rule", "label_normalization:\n  node: [uppercase]\n"},
+		{"normalization no rules", "label_normalization:\n  node: []\n"},
+		{"detector labels empty", "detector_labels:\n  disk_space: {}\n"},
+		{"detector labels bad name", "detector_labels:\n  disk_space:\n    team-name: storage\n"},
+		{"template parse error", "templates:\n  oom_kill:\n    message: \"{{.Labels.pod\"\n"},
+		{"template empty", "templates:\n  oom_kill: {}\n"},
+		{"profile bad severity", "profiles:\n  payments:\n    min_severity: SEVERE\n"},
+		{"profile unknown key", "profiles:\n  payments:\n    namespaces: payments-*\n"},
+		{"profile negative blast radius", "profiles:\n  payments:\n    min_blast_radius: -1\n"},
+		{"ingress bad metric name", "ingress_metrics:\n  requests: edge-requests\n"},
+		{"ingress bad label name", "ingress_metrics:\n  host_label: server.name\n"},
+		{"criticality without namespaces", "namespace_criticality:\n  - adjust: 1\n"},
+		{"criticality zero adjust", "namespace_criticality:\n  - namespaces: prod\n    adjust: 0\n"},
+		{"criticality adjust out of range", "namespace_criticality:\n  - namespaces: prod\n    adjust: 3\n"},
+		{"criticality bad pattern", "namespace_criticality:\n  - namespaces: \"prod-[\"\n    adjust: 1\n"},
+		{"schedule bad timezone", "severity_overrides:\n  - types: [oom_kill]\n    severity: FATAL\n    schedule: {start: \"09:00\", end: \"18:00\", timezone: Mars/Olympus}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.data)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestSchedule_Contains(t *testing.T) {
+	businessHours := &Schedule{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "09:00", End: "18:00"}
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"weekday morning", time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), true}, // Monday, start inclusive
+		{"weekday afternoon", time.Date(2026, 3, 6, 17, 59, 0, 0, time.UTC), true},
+		{"weekday evening", time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC), false}, // end exclusive
+		{"weekday night", time.Date(2026, 3, 3, 3, 0, 0, 0, time.UTC), false},
+		{"saturday", time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		if got := businessHours.Contains(tt.at); got != tt.want {
+			t.Errorf("%s: Contains(%s) = %v, want %v", tt.name, tt.at, got, tt.want)
+		}
+	}
+
+	everyDay := &Schedule{Start: "00:00", End: "06:00"}
+	if !everyDay.Contains(time.Date(2026, 3, 7, 5, 0, 0, 0, time.UTC)) {
+		t.Error("schedule without days applies every day")
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if got := ResolvePath("/explicit.yaml"); got != "/explicit.yaml" {
+		t.Errorf("explicit path = %q, want /explicit.yaml", got)
+	}
+	if got := ResolvePath(""); got != "" {
+		t.Errorf("missing default should resolve to empty, got %q", got)
+	}
+
+	defaultPath := filepath.Join(home, DefaultFileName)
+	if err := os.WriteFile(defaultPath, []byte("{}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := ResolvePath(""); got != defaultPath {
+		t.Errorf("existing default = %q, want %q", got, defaultPath)
+	}
+}
@@ -0,0 +1,19 @@
+package config
+
+import "strings"
+
+// NamespaceCriticality moves problems in the listed namespaces Adjust
+// severity levels: 1 raises a WARNING to CRITICAL, -1 lowers it back
+type NamespaceCriticality struct {
+	Namespaces string `json:"namespaces"` // same syntax as include_namespaces
+	Adjust     int    `json:"adjust"`     // -2..2, not 0
+}
+
+// Patterns returns the namespace globs of the rule
+func (n NamespaceCriticality) Patterns() []string {
+	patterns := strings.Split(n.Namespaces, ",")
+	for i, p := range patterns {
+		patterns[i] = strings.TrimSpace(p)
+	}
+	return patterns
+}
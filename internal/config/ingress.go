@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	metricNameRe = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRe  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// IngressMetrics overrides the metric and label names the ingress error rate
+// detector queries, for controllers or relabeling that differ from
+// ingress-nginx. Unset fields keep the ingress-nginx names.
+type IngressMetrics struct {
+	Requests       string `json:"requests,omitempty"`
+	StatusLabel    string `json:"status_label,omitempty"`
+	HostLabel      string `json:"host_label,omitempty"`
+	IngressLabel   string `json:"ingress_label,omitempty"`
+	NamespaceLabel string `json:"namespace_label,omitempty"`
+}
+
+func (m *IngressMetrics) validate() error {
+	if m.Requests != "" && !metricNameRe.MatchString(m.Requests) {
+		return fmt.Errorf("requests: invalid metric name %q", m.Requests)
+	}
+	labels := []struct{ key, value string }{
+		{"status_label", m.StatusLabel},
+		{"host_label", m.HostLabel},
+		{"ingress_label", m.IngressLabel},
+		{"namespace_label", m.NamespaceLabel},
+	}
+	for _, l := range labels {
+		if l.value != "" && !labelNameRe.MatchString(l.value) {
+			return fmt.Errorf("%s: invalid label name %q", l.key, l.value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	// fieldPathRe matches the field path config errors start with, e.g.
+	// "severity_overrides[0].schedule" in "severity_overrides[0].schedule: ..."
+	fieldPathRe = regexp.MustCompile(`^([a-z_]+(?:\[\d+\]|\.[A-Za-z0-9_-]+)*): `)
+
+	// unknownFieldRe matches the error strict decoding reports for a key the
+	// config does not have
+	unknownFieldRe = regexp.MustCompile(`unknown field "([^"]+)"`)
+
+	quotedRe = regexp.MustCompile(`"([^"]+)"`)
+)
+
+// Locate returns err prefixed with the line of data it concerns, e.g.
+// "line 12: thresholds.pg_replication_lag: must be positive, got -1". Errors
+// starting with a field path are located by that path, narrowed to the
+// quoted key or list entry they name; unknown keys are located by name. YAML
+// syntax errors already carry their line. Errors that cannot be located are
+// returned unchanged.
+func Locate(data []byte, err error) error {
+	msg := strings.TrimPrefix(err.Error(), "parse config: ")
+	if strings.Contains(msg, "yaml: line ") {
+		return err
+	}
+
+	var root yaml.Node
+	if yaml.Unmarshal(data, &root) != nil || len(root.Content) == 0 {
+		return err
+	}
+	doc := root.Content[0]
+
+	var node *yaml.Node
+	if m := unknownFieldRe.FindStringSubmatch(msg); m != nil {
+		node = findKey(doc, m[1])
+	} else if m := fieldPathRe.FindStringSubmatch(msg); m != nil {
+		var value *yaml.Node
+		node, value = lookupPath(doc, m[1])
+		if q := quotedRe.FindStringSubmatch(strings.TrimPrefix(msg, m[0])); q != nil && value != nil {
+			if entry := findEntry(value, q[1]); entry != nil {
+				node = entry
+			}
+		}
+	}
+	if node == nil {
+		return err
+	}
+	return fmt.Errorf("line %d: %w", node.Line, err)
+}
+
+// lookupPath returns the node a field path like "profiles.ci.detectors[1]"
+// names (the key for mapping entries, the entry for list items) and its
+// value. It stops at the deepest part of the path that exists; the value is
+// nil if the path does not exist in full.
+func lookupPath(doc *yaml.Node, path string) (*yaml.Node, *yaml.Node) {
+	var found *yaml.Node
+	value := doc
+	for _, part := range strings.Split(path, ".") {
+		name, rest, _ := strings.Cut(part, "[")
+		key, next := mappingEntry(value, name)
+		if key == nil {
+			return found, nil
+		}
+		found, value = key, next
+
+		for rest != "" {
+			index, tail, _ := strings.Cut(rest, "]")
+			rest = strings.TrimPrefix(tail, "[")
+			i, err := strconv.Atoi(index)
+			if err != nil || value.Kind != yaml.SequenceNode || i >= len(value.Content) {
+				return found, nil
+			}
+			value = value.Content[i]
+			found = value
+		}
+	}
+	return found, value
+}
+
+// mappingEntry returns the key and value nodes of name in a mapping node
+func mappingEntry(node *yaml.Node, name string) (*yaml.Node, *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// findEntry returns the key named name in a mapping node, or the item equal
+// to name in a list node
+func findEntry(value *yaml.Node, name string) *yaml.Node {
+	switch value.Kind {
+	case yaml.MappingNode:
+		key, _ := mappingEntry(value, name)
+		return key
+	case yaml.SequenceNode:
+		for _, item := range value.Content {
+			if item.Kind == yaml.ScalarNode && item.Value == name {
+				return item
+			}
+		}
+	}
+	return nil
+}
+
+// findKey returns the first mapping key named name anywhere in the document
+func findKey(node *yaml.Node, name string) *yaml.Node {
+	if key, _ := mappingEntry(node, name); key != nil {
+		return key
+	}
+	for _, child := range node.Content {
+		if found := findKey(child, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
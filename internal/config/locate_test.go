@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLocate(t *testing.T) {
+	data := []byte(`include_namespaces: "prod-*"
+thresholds:
+  pg_replication_lag: 60
+  redis_memory: -1
+disabled_detectors:
+  - kubernetes_pending
+  - nope
+severity_overrides:
+  - types: [imagepullbackoff]
+    severity: FATAL
+  - types: [oom_kill]
+    severity: SEVERE
+profiles:
+  ci:
+    min_severity: CRITICAL
+    colour: red
+`)
+	tests := []struct {
+		err  string
+		want string
+	}{
+		{
+			"thresholds.redis_memory: must be positive, got -1",
+			"line 4: thresholds.redis_memory: must be positive, got -1",
+		},
+		{
+			`disabled_detectors: unknown detector "nope"`,
+			`line 7: disabled_detectors: unknown detector "nope"`,
+		},
+		{
+			`thresholds: unknown detector "pg_replication_lag"`,
+			`line 3: thresholds: unknown detector "pg_replication_lag"`,
+		},
+		{
+			`severity_overrides[1]: unknown severity "SEVERE"`,
+			`line 11: severity_overrides[1]: unknown severity "SEVERE"`,
+		},
+		{
+			"profiles.ci.min_severity: invalid",
+			"line 15: profiles.ci.min_severity: invalid",
+		},
+		{
+			`parse config: error unmarshaling JSON: while decoding JSON: json: unknown field "colour"`,
+			`line 16: parse config: error unmarshaling JSON: while decoding JSON: json: unknown field "colour"`,
+		},
+		{
+			// Deepest part of the path that exists
+			"profiles.staging.min_severity: invalid",
+			"line 13: profiles.staging.min_severity: invalid",
+		},
+		{
+			"parse config: error converting YAML to JSON: yaml: line 2: mapping values are not allowed in this context",
+			"parse config: error converting YAML to JSON: yaml: line 2: mapping values are not allowed in this context",
+		},
+		{"windows.redis_memory: must be positive", "windows.redis_memory: must be positive"},
+		{"no field here", "no field here"},
+	}
+	for _, tt := range tests {
+		if got := Locate(data, errors.New(tt.err)).Error(); got != tt.want {
+			t.Errorf("Locate(%q)\n got %q\nwant %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestLocate_KeepsWrappedError(t *testing.T) {
+	cause := errors.New("thresholds.redis_memory: must be positive, got -1")
+	err := Locate([]byte("thresholds:\n  redis_memory: -1\n"), cause)
+	if !errors.Is(err, cause) {
+		t.Errorf("Locate(...) = %v, does not wrap the original error", err)
+	}
+}
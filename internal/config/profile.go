@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// Profile is a named bundle of filter, severity and detector settings,
+// selected with --profile. Each field set here acts as the monitor flag of
+// the same name unless that flag is given on the command line.
+type Profile struct {
+	IncludeNamespaces string   `json:"include_namespaces,omitempty"`
+	ExcludeNamespaces string   `json:"exclude_namespaces,omitempty"`
+	IncludeEntity     string   `json:"include_entity,omitempty"`
+	ExcludeEntity     string   `json:"exclude_entity,omitempty"`
+	MinSeverity       string   `json:"min_severity,omitempty"`
+	MinBlastRadius    int      `json:"min_blast_radius,omitempty"`
+	FailOn            string   `json:"fail_on,omitempty"`
+	Detectors         []string `json:"detectors,omitempty"` // Only run these
+}
+
+// Flags returns the profile's settings as monitor flag values keyed by flag
+// name. Unset fields are left out.
+func (p *Profile) Flags() map[string]string {
+	flags := make(map[string]string)
+	set := func(name, value string) {
+		if value != "" {
+			flags[name] = value
+		}
+	}
+	set("include-namespaces", p.IncludeNamespaces)
+	set("exclude-namespaces", p.ExcludeNamespaces)
+	set("include-entity", p.IncludeEntity)
+	set("exclude-entity", p.ExcludeEntity)
+	set("min-severity", p.MinSeverity)
+	set("fail-on", p.FailOn)
+	set("detectors", strings.Join(p.Detectors, ","))
+	if p.MinBlastRadius > 0 {
+		flags["min-blast-radius"] = strconv.Itoa(p.MinBlastRadius)
+	}
+	return flags
+}
+
+func (p *Profile) validate() error {
+	if p.MinSeverity != "" {
+		if _, err := models.ParseSeverity(p.MinSeverity); err != nil {
+			return fmt.Errorf("min_severity: %w", err)
+		}
+	}
+	if p.FailOn != "" {
+		if _, err := models.ParseSeverity(p.FailOn); err != nil {
+			return fmt.Errorf("fail_on: %w", err)
+		}
+	}
+	if p.MinBlastRadius < 0 {
+		return fmt.Errorf("min_blast_radius: must not be negative, got %d", p.MinBlastRadius)
+	}
+	for i, name := range p.Detectors {
+		if name == "" {
+			return fmt.Errorf("detectors[%d]: empty detector name", i)
+		}
+	}
+	return nil
+}
+
+// Profile returns the named profile
+func (c *Config) Profile(name string) (*Profile, error) {
+	p, ok := c.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(c.Profiles))
+		for n := range c.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("unknown profile %q: the config file defines no profiles", name)
+		}
+		return nil, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	return &p, nil
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProfile_Flags(t *testing.T) {
+	cfg, err := Parse([]byte(`
+profiles:
+  payments:
+    include_namespaces: "payments-*"
+    min_severity: CRITICAL
+    min_blast_radius: 3
+    detectors: [kubernetes_oom_kills, kubernetes_crashloop]
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p, err := cfg.Profile("payments")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	flags := p.Flags()
+	want := map[string]string{
+		"include-namespaces": "payments-*",
+		"min-severity":       "CRITICAL",
+		"min-blast-radius":   "3",
+		"detectors":          "kubernetes_oom_kills,kubernetes_crashloop",
+	}
+	if len(flags) != len(want) {
+		t.Errorf("flags = %v, want %v", flags, want)
+	}
+	for name, value := range want {
+		if flags[name] != value {
+			t.Errorf("flags[%s] = %q, want %q", name, flags[name], value)
+		}
+	}
+}
+
+func TestConfig_UnknownProfile(t *testing.T) {
+	cfg := &Config{Profiles: map[string]Profile{"payments": {}, "edge": {}}}
+	_, err := cfg.Profile("billing")
+	if err == nil || !strings.Contains(err.Error(), "available: edge, payments") {
+		t.Errorf("expected error listing available profiles, got %v", err)
+	}
+
+	if _, err := (&Config{}).Profile("billing"); err == nil {
+		t.Error("expected error without profiles")
+	}
+}
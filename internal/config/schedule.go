@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// clockLayout is the time-of-day format of schedule start/end ("09:00")
+const clockLayout = "15:04"
+
+// weekdays maps schedule day names to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// SeverityOverride raises problems of the listed types to Severity, always or
+// only while Schedule is active. Overrides only ever raise severity.
+type SeverityOverride struct {
+	Types    []string  `json:"types"`
+	Severity string    `json:"severity"`
+	Schedule *Schedule `json:"schedule,omitempty"` // nil = always
+}
+
+// Schedule is a recurring daily time window, e.g. business hours
+type Schedule struct {
+	Days     []string `json:"days,omitempty"`     // mon..sun, empty = every day
+	Start    string   `json:"start"`              // "09:00", inclusive
+	End      string   `json:"end"`                // "18:00", exclusive
+	Timezone string   `json:"timezone,omitempty"` // IANA name, empty = local time
+}
+
+// Location returns the schedule's time zone
+func (s *Schedule) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("timezone: %w", err)
+	}
+	return loc, nil
+}
+
+// Contains reports whether t, in the location it carries, falls inside the
+// schedule. Callers convert t with In(Location()) first.
+func (s *Schedule) Contains(t time.Time) bool {
+	if len(s.Days) > 0 {
+		onDay := false
+		for _, day := range s.Days {
+			if weekdays[strings.ToLower(day)] == t.Weekday() {
+				onDay = true
+				break
+			}
+		}
+		if !onDay {
+			return false
+		}
+	}
+
+	start, _ := time.Parse(clockLayout, s.Start) // Checked by validate
+	end, _ := time.Parse(clockLayout, s.End)
+	clock := t.Hour()*60 + t.Minute()
+	return clock >= start.Hour()*60+start.Minute() && clock < end.Hour()*60+end.Minute()
+}
+
+func (s *Schedule) validate() error {
+	for _, day := range s.Days {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("days: unknown day %q (use mon, tue, wed, thu, fri, sat, sun)", day)
+		}
+	}
+	start, err := time.Parse(clockLayout, s.Start)
+	if err != nil {
+		return fmt.Errorf("start: want HH:MM, got %q", s.Start)
+	}
+	end, err := time.Parse(clockLayout, s.End)
+	if err != nil {
+		return fmt.Errorf("end: want HH:MM, got %q", s.End)
+	}
+	if !start.Before(end) {
+		return fmt.Errorf("start %s must be before end %s", s.Start, s.End)
+	}
+	if _, err := s.Location(); err != nil {
+		return err
+	}
+	return nil
+}
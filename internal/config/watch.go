@@ -0,0 +1,75 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce coalesces the burst of events editors emit on a single save
+const reloadDebounce = 200 * time.Millisecond
+
+// Watch reloads the config file whenever it changes until ctx is cancelled.
+// onReload receives each successfully parsed config; onError receives load
+// failures, in which case the caller should keep its previous config.
+//
+// The parent directory is watched rather than the file itself so that editors
+// which save via rename (vim, most IDEs) keep triggering reloads.
+func Watch(ctx context.Context, path string, onReload func(*Config), onError func(error)) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create config watcher: %w", err)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		_ = fsw.Close() // Best-effort
+		return fmt.Errorf("resolve config path: %w", err)
+	}
+	if err := fsw.Add(filepath.Dir(absPath)); err != nil {
+		_ = fsw.Close() // Best-effort
+		return fmt.Errorf("watch config directory: %w", err)
+	}
+
+	go func() {
+		defer func() {
+			_ = fsw.Close() // Best-effort
+		}()
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-fsw.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != absPath {
+					continue
+				}
+				if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename) {
+					debounce = time.After(reloadDebounce)
+				}
+			case watchErr, ok := <-fsw.Errors:
+				if !ok {
+					return
+				}
+				onError(fmt.Errorf("config watcher: %w", watchErr))
+			case <-debounce:
+				debounce = nil
+				cfg, loadErr := Load(absPath)
+				if loadErr != nil {
+					onError(loadErr)
+					continue
+				}
+				onReload(cfg)
+			}
+		}
+	}()
+
+	return nil
+}
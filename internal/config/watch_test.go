@@ -0,0 +1,77 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const watchTestTimeout = 5 * time.Second
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatch_ReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "infranow.yaml")
+	writeFile(t, path, "disabled_detectors: []\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloads := make(chan *Config, 1)
+	errs := make(chan error, 1)
+	err := Watch(ctx, path,
+		func(cfg *Config) { reloads <- cfg },
+		func(err error) { errs <- err },
+	)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	writeFile(t, path, "disabled_detectors: [kubernetes_pending]\n")
+
+	select {
+	case cfg := <-reloads:
+		if !cfg.IsDisabled("kubernetes_pending") {
+			t.Error("reloaded config should disable kubernetes_pending")
+		}
+	case err := <-errs:
+		t.Fatalf("unexpected error: %v", err)
+	case <-time.After(watchTestTimeout):
+		t.Fatal("timed out waiting for reload")
+	}
+}
+
+func TestWatch_InvalidConfigReportsError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "infranow.yaml")
+	writeFile(t, path, "{}\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloads := make(chan *Config, 1)
+	errs := make(chan error, 1)
+	if err := Watch(ctx, path,
+		func(cfg *Config) { reloads <- cfg },
+		func(err error) { errs <- err },
+	); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	writeFile(t, path, "not_a_field: true\n")
+
+	select {
+	case <-reloads:
+		t.Fatal("invalid config should not be reloaded")
+	case <-errs:
+		// expected
+	case <-time.After(watchTestTimeout):
+		t.Fatal("timed out waiting for error")
+	}
+}
@@ -0,0 +1,113 @@
+package correlator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// AggregateType is the Type of problems synthesized by Aggregate
+const AggregateType = "widespread"
+
+// Aggregate synthesizes one problem per Type with at least threshold
+// problems, one severity above the most severe of them: fifty WARNING
+// throttling problems are a cluster-wide CRITICAL. Aggregates come first,
+// ordered by type. With collapse the individual problems are dropped and
+// only the aggregate remains. threshold <= 0 disables aggregation.
+func Aggregate(problems []*models.Problem, threshold int, collapse bool) []*models.Problem {
+	if threshold <= 0 || len(problems) == 0 {
+		return problems
+	}
+
+	byType := make(map[string][]*models.Problem)
+	for _, p := range problems {
+		if p.Type == AggregateType {
+			continue
+		}
+		byType[p.Type] = append(byType[p.Type], p)
+	}
+
+	var types []string
+	for problemType, members := range byType {
+		if len(members) >= threshold {
+			types = append(types, problemType)
+		}
+	}
+	if len(types) == 0 {
+		return problems
+	}
+	sort.Strings(types)
+
+	result := make([]*models.Problem, 0, len(problems)+len(types))
+	aggregated := make(map[string]bool, len(types))
+	for _, problemType := range types {
+		result = append(result, aggregateOf(problemType, byType[problemType]))
+		aggregated[problemType] = true
+	}
+	for _, p := range problems {
+		if collapse && aggregated[p.Type] {
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// aggregateOf summarizes members, all of one problem type
+func aggregateOf(problemType string, members []*models.Problem) *models.Problem {
+	labels := map[string]string{"problem_type": problemType}
+	worst := members[0].Severity
+	firstSeen, lastSeen := members[0].FirstSeen, members[0].LastSeen
+	blastRadius := 0
+	namespaces := make(map[string]bool)
+	for _, p := range members {
+		if p.Severity.AtLeast(worst) {
+			worst = p.Severity
+		}
+		if p.FirstSeen.Before(firstSeen) {
+			firstSeen = p.FirstSeen
+		}
+		if p.LastSeen.After(lastSeen) {
+			lastSeen = p.LastSeen
+		}
+		blastRadius += p.BlastRadius
+		if ns := p.Labels["namespace"]; ns != "" {
+			namespaces[ns] = true
+		}
+	}
+
+	message := fmt.Sprintf("%d %s problems at once", len(members), problemType)
+	if len(namespaces) > 1 {
+		message = fmt.Sprintf("%d %s problems across %d namespaces", len(members), problemType, len(namespaces))
+	}
+
+	aggregate := &models.Problem{
+		ID:          models.StableID("problem_type", AggregateType, labels),
+		Entity:      problemType,
+		EntityType:  "problem_type",
+		Type:        AggregateType,
+		Severity:    escalate(worst),
+		Title:       fmt.Sprintf("Widespread %s", problemType),
+		Message:     message,
+		FirstSeen:   firstSeen,
+		LastSeen:    lastSeen,
+		Count:       1,
+		BlastRadius: blastRadius,
+		Labels:      labels,
+		Metrics:     map[string]float64{"problems": float64(len(members))},
+		Hint:        "Many entities share this problem: look for a common cause (node, network, quota, recent rollout) before fixing them one by one",
+	}
+	aggregate.UpdatePersistence()
+	return aggregate
+}
+
+// escalate returns the next severity up; FATAL stays FATAL
+func escalate(s models.Severity) models.Severity {
+	switch s {
+	case models.SeverityWarning:
+		return models.SeverityCritical
+	default:
+		return models.SeverityFatal
+	}
+}
@@ -0,0 +1,86 @@
+package correlator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func throttled(n int) []*models.Problem {
+	problems := make([]*models.Problem, 0, n)
+	for i := 0; i < n; i++ {
+		problems = append(problems, &models.Problem{
+			ID:          fmt.Sprintf("throttle-%d", i),
+			Type:        "cpu_throttling",
+			Severity:    models.SeverityWarning,
+			BlastRadius: 1,
+			Labels:      map[string]string{"namespace": fmt.Sprintf("ns-%d", i%3)},
+		})
+	}
+	return problems
+}
+
+func TestAggregate_EscalatesWarningsToCritical(t *testing.T) {
+	problems := append(throttled(5), &models.Problem{ID: "oom", Type: "oom_kill", Severity: models.SeverityCritical})
+
+	result := Aggregate(problems, 5, false)
+	if len(result) != 7 {
+		t.Fatalf("expected 6 problems plus 1 aggregate, got %d", len(result))
+	}
+
+	agg := result[0]
+	if agg.Type != AggregateType || agg.Entity != "cpu_throttling" {
+		t.Fatalf("first problem should be the aggregate, got %s %s", agg.Type, agg.Entity)
+	}
+	if agg.Severity != models.SeverityCritical {
+		t.Errorf("aggregate severity = %s, want CRITICAL", agg.Severity)
+	}
+	if agg.Metrics["problems"] != 5 || agg.BlastRadius != 5 {
+		t.Errorf("aggregate problems = %g, blast radius = %d, want 5 and 5", agg.Metrics["problems"], agg.BlastRadius)
+	}
+	if agg.Message != "5 cpu_throttling problems across 3 namespaces" {
+		t.Errorf("unexpected message %q", agg.Message)
+	}
+	for _, p := range result[1:] {
+		if p.Type == AggregateType {
+			t.Errorf("only one aggregate expected, found %s", p.Entity)
+		}
+	}
+}
+
+func TestAggregate_BelowThreshold(t *testing.T) {
+	problems := throttled(4)
+	if result := Aggregate(problems, 5, true); len(result) != 4 {
+		t.Errorf("below threshold: got %d problems, want 4 unchanged", len(result))
+	}
+	if result := Aggregate(problems, 0, true); len(result) != 4 {
+		t.Errorf("threshold 0 disables aggregation: got %d problems, want 4", len(result))
+	}
+}
+
+func TestAggregate_Collapse(t *testing.T) {
+	problems := append(throttled(6), &models.Problem{ID: "oom", Type: "oom_kill", Severity: models.SeverityCritical})
+
+	result := Aggregate(problems, 5, true)
+	if len(result) != 2 {
+		t.Fatalf("expected aggregate plus the unrelated problem, got %d", len(result))
+	}
+	if result[0].Type != AggregateType || result[1].ID != "oom" {
+		t.Errorf("unexpected result %s, %s", result[0].ID, result[1].ID)
+	}
+}
+
+func TestAggregate_StableIDAndEscalation(t *testing.T) {
+	a := Aggregate(throttled(5), 5, true)[0]
+	b := Aggregate(throttled(8), 5, true)[0]
+	if a.ID != b.ID {
+		t.Errorf("aggregate ID should depend only on the problem type: %s vs %s", a.ID, b.ID)
+	}
+
+	critical := throttled(5)
+	critical[2].Severity = models.SeverityCritical
+	if got := Aggregate(critical, 5, true)[0].Severity; got != models.SeverityFatal {
+		t.Errorf("aggregate of CRITICAL problems = %s, want FATAL", got)
+	}
+}
@@ -0,0 +1,132 @@
+package correlator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// certSources maps certificate expiry problem types to the source named in
+// the merged problem's "sources" label
+var certSources = map[string]string{
+	"linkerd_cert_expiry":    "linkerd",
+	"istio_cert_expiry":      "istio",
+	"trustwatch_cert_expiry": "trustwatch",
+	"x509_cert_expiry":       "x509",
+}
+
+// meshCertSecrets are the secrets the mesh detectors' certificates live in,
+// so the same certificate seen by the x509 exporter lines up with them
+var meshCertSecrets = map[string]string{
+	"linkerd_cert_expiry": "linkerd-identity-issuer",
+	"istio_cert_expiry":   "istio-ca-secret",
+}
+
+// DedupCertificates merges certificate expiry problems from different
+// sources that describe the same certificate, so one cert watched by
+// trustwatch and the x509 exporter counts once. Certificates are matched by
+// subject CN and serial when known, else by namespace and name. The merged
+// problem is the member expiring soonest, at the highest member severity,
+// with every source in the "sources" label; it takes the first member's place.
+func DedupCertificates(problems []*models.Problem) []*models.Problem {
+	groups := make(map[string][]*models.Problem)
+	for _, p := range problems {
+		if key, ok := certIdentity(p); ok {
+			groups[key] = append(groups[key], p)
+		}
+	}
+
+	result := make([]*models.Problem, 0, len(problems))
+	emitted := make(map[string]bool)
+	for _, p := range problems {
+		key, ok := certIdentity(p)
+		if !ok || !multiSource(groups[key]) {
+			result = append(result, p)
+			continue
+		}
+		if emitted[key] {
+			continue
+		}
+		emitted[key] = true
+		result = append(result, mergeCertificates(groups[key]))
+	}
+	return result
+}
+
+// multiSource reports whether members were reported by more than one
+// source; one source reporting two problems describes two certificates
+func multiSource(members []*models.Problem) bool {
+	for _, p := range members[1:] {
+		if certSources[p.Type] != certSources[members[0].Type] {
+			return true
+		}
+	}
+	return false
+}
+
+// certIdentity returns a source-independent key for the certificate a
+// problem reports on
+func certIdentity(p *models.Problem) (string, bool) {
+	if _, ok := certSources[p.Type]; !ok {
+		return "", false
+	}
+	tenant := p.Labels["tenant"]
+	cn, serial := p.Labels["subject_cn"], p.Labels["serial"]
+	if cn != "" && serial != "" {
+		return strings.Join([]string{tenant, "serial", cn, serial}, "\x00"), true
+	}
+
+	namespace, name := p.Labels["namespace"], p.Labels["name"]
+	switch {
+	case p.Labels["secret"] != "":
+		name = p.Labels["secret"]
+	case meshCertSecrets[p.Type] != "":
+		name = meshCertSecrets[p.Type]
+	}
+	if namespace == "" || name == "" {
+		return "", false // e.g. a certificate file on a node
+	}
+	return strings.Join([]string{tenant, "ref", namespace, name}, "\x00"), true
+}
+
+// mergeCertificates keeps the member expiring soonest and records where the
+// certificate was seen
+func mergeCertificates(members []*models.Problem) *models.Problem {
+	soonest := members[0]
+	for _, p := range members[1:] {
+		if p.Metrics["remaining_seconds"] < soonest.Metrics["remaining_seconds"] {
+			soonest = p
+		}
+	}
+	merged := *soonest
+
+	seen := make(map[string]bool)
+	var sources []string
+	for _, p := range members {
+		if p.Severity.AtLeast(merged.Severity) {
+			merged.Severity = p.Severity
+		}
+		merged.BlastRadius = max(merged.BlastRadius, p.BlastRadius)
+		if p.FirstSeen.Before(merged.FirstSeen) {
+			merged.FirstSeen = p.FirstSeen
+		}
+		if p.Count > merged.Count {
+			merged.Count = p.Count
+		}
+		if source := certSources[p.Type]; !seen[source] {
+			seen[source] = true
+			sources = append(sources, source)
+		}
+	}
+	sort.Strings(sources)
+	merged.UpdatePersistence()
+
+	merged.Labels = make(map[string]string, len(soonest.Labels)+1)
+	for k, v := range soonest.Labels {
+		merged.Labels[k] = v
+	}
+	merged.Labels["sources"] = strings.Join(sources, ",")
+	merged.Message = soonest.Message + " (seen by " + strings.Join(sources, ", ") + ")"
+	return &merged
+}
@@ -0,0 +1,112 @@
+package correlator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func certProblem(id, problemType string, severity models.Severity, remaining float64, labels map[string]string) *models.Problem {
+	now := time.Now()
+	return &models.Problem{
+		ID:          id,
+		Entity:      id,
+		Type:        problemType,
+		Severity:    severity,
+		Message:     id + " expires soon",
+		FirstSeen:   now,
+		LastSeen:    now,
+		Count:       1,
+		BlastRadius: 3,
+		Labels:      labels,
+		Metrics:     map[string]float64{"remaining_seconds": remaining},
+	}
+}
+
+func TestDedupCertificates_MergesSameCertAcrossSources(t *testing.T) {
+	trustwatch := certProblem("tw", "trustwatch_cert_expiry", models.SeverityWarning, 5*86400,
+		map[string]string{"source": "webhook", "namespace": "prod", "name": "api-tls"})
+	trustwatch.BlastRadius = 10
+	x509 := certProblem("x509", "x509_cert_expiry", models.SeverityCritical, 36*3600,
+		map[string]string{"namespace": "prod", "secret": "api-tls", "subject_cn": "api.example.com"})
+	other := certProblem("other", "x509_cert_expiry", models.SeverityWarning, 6*86400,
+		map[string]string{"namespace": "prod", "secret": "web-tls"})
+	oom := &models.Problem{ID: "oom", Type: "oom_kill", Labels: map[string]string{"namespace": "prod"}}
+
+	got := DedupCertificates([]*models.Problem{trustwatch, oom, x509, other})
+	if len(got) != 3 {
+		t.Fatalf("expected 3 problems, got %d", len(got))
+	}
+
+	merged := got[0]
+	if merged.ID != "x509" {
+		t.Errorf("merged ID = %s, want the soonest-expiring member x509", merged.ID)
+	}
+	if merged.Metrics["remaining_seconds"] != 36*3600 || merged.Severity != models.SeverityCritical {
+		t.Errorf("merged = %v remaining, %v; want the soonest expiry", merged.Metrics["remaining_seconds"], merged.Severity)
+	}
+	if merged.Labels["sources"] != "trustwatch,x509" {
+		t.Errorf("sources label = %q, want trustwatch,x509", merged.Labels["sources"])
+	}
+	if merged.BlastRadius != 10 {
+		t.Errorf("blast radius = %d, want the largest member's", merged.BlastRadius)
+	}
+	if _, ok := x509.Labels["sources"]; ok {
+		t.Error("merging must not modify the member's labels")
+	}
+	if got[1].ID != "oom" || got[2].ID != "other" {
+		t.Errorf("unrelated problems should keep their order, got %s, %s", got[1].ID, got[2].ID)
+	}
+}
+
+func TestDedupCertificates_BySerial(t *testing.T) {
+	a := certProblem("a", "x509_cert_expiry", models.SeverityWarning, 4*86400,
+		map[string]string{"subject_cn": "etcd", "serial": "1f", "filepath": "/etc/etcd/server.crt"})
+	b := certProblem("b", "trustwatch_cert_expiry", models.SeverityWarning, 4*86400+60,
+		map[string]string{"subject_cn": "etcd", "serial": "1f", "namespace": "kube-system", "name": "etcd"})
+
+	got := DedupCertificates([]*models.Problem{a, b})
+	if len(got) != 1 || got[0].Labels["sources"] != "trustwatch,x509" {
+		t.Fatalf("expected one merged problem, got %d", len(got))
+	}
+}
+
+func TestDedupCertificates_SeverityIsHighestMember(t *testing.T) {
+	// The mesh detector escalates nothing, trustwatch reports the issuer
+	// as CRITICAL although it expires later
+	istio := certProblem("istio", "istio_cert_expiry", models.SeverityWarning, 5*86400,
+		map[string]string{"mesh": "istio", "namespace": "istio-system"})
+	issuer := certProblem("issuer", "trustwatch_cert_expiry", models.SeverityCritical, 5*86400+3600,
+		map[string]string{"source": "mesh-issuer", "namespace": "istio-system", "name": "istio-ca-secret"})
+
+	got := DedupCertificates([]*models.Problem{istio, issuer})
+	if len(got) != 1 {
+		t.Fatalf("expected the mesh root cert and its secret merged, got %d", len(got))
+	}
+	if got[0].ID != "istio" || got[0].Severity != models.SeverityCritical {
+		t.Errorf("merged %s at %v, want istio (soonest) at CRITICAL", got[0].ID, got[0].Severity)
+	}
+}
+
+func TestDedupCertificates_SameSourceNotMerged(t *testing.T) {
+	webhook := certProblem("webhook", "trustwatch_cert_expiry", models.SeverityWarning, 86400*3,
+		map[string]string{"source": "webhook", "namespace": "prod", "name": "api"})
+	apiservice := certProblem("apiservice", "trustwatch_cert_expiry", models.SeverityWarning, 86400*4,
+		map[string]string{"source": "apiservice", "namespace": "prod", "name": "api"})
+
+	if got := DedupCertificates([]*models.Problem{webhook, apiservice}); len(got) != 2 {
+		t.Errorf("one source reporting two certificates should not merge, got %d", len(got))
+	}
+}
+
+func TestDedupCertificates_TenantsKeptApart(t *testing.T) {
+	a := certProblem("a", "trustwatch_cert_expiry", models.SeverityWarning, 86400,
+		map[string]string{"namespace": "prod", "name": "api-tls", "tenant": "team-a"})
+	b := certProblem("b", "x509_cert_expiry", models.SeverityWarning, 86400,
+		map[string]string{"namespace": "prod", "secret": "api-tls", "tenant": "team-b"})
+
+	if got := DedupCertificates([]*models.Problem{a, b}); len(got) != 2 {
+		t.Errorf("certificates of different tenants should not merge, got %d", len(got))
+	}
+}
@@ -0,0 +1,102 @@
+package correlator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// CoalesceContainers merges problems of one type that differ only by their
+// container label into a single pod-level problem, so a pod whose three
+// containers are OOM killed is one problem instead of three. The merged
+// problem takes the place of the first member, lists the containers in the
+// "containers" label and counts them in the "containers" metric.
+func CoalesceContainers(problems []*models.Problem) []*models.Problem {
+	groups := make(map[string][]*models.Problem)
+	for _, p := range problems {
+		if key, ok := containerGroupKey(p); ok {
+			groups[key] = append(groups[key], p)
+		}
+	}
+
+	result := make([]*models.Problem, 0, len(problems))
+	emitted := make(map[string]bool)
+	for _, p := range problems {
+		key, ok := containerGroupKey(p)
+		if !ok || len(groups[key]) < 2 {
+			result = append(result, p)
+			continue
+		}
+		if emitted[key] {
+			continue
+		}
+		emitted[key] = true
+		result = append(result, coalesce(groups[key]))
+	}
+	return result
+}
+
+// containerGroupKey groups container-level problems by type and pod
+func containerGroupKey(p *models.Problem) (string, bool) {
+	if p.Labels["container"] == "" || p.Labels["pod"] == "" {
+		return "", false
+	}
+	return strings.Join([]string{p.EntityType, p.Type, p.Labels["namespace"], p.Labels["pod"]}, "\x00"), true
+}
+
+// coalesce merges members, all of one type and pod, into the most severe
+// member's problem with the container dropped from its identity
+func coalesce(members []*models.Problem) *models.Problem {
+	worst := members[0]
+	containers := make([]string, 0, len(members))
+	for _, p := range members {
+		if p.Score() > worst.Score() {
+			worst = p
+		}
+		containers = append(containers, p.Labels["container"])
+	}
+	merged := *worst
+	sort.Strings(containers)
+
+	merged.Labels = make(map[string]string, len(worst.Labels))
+	for k, v := range worst.Labels {
+		if k != "container" {
+			merged.Labels[k] = v
+		}
+	}
+	// The ID stays stable as containers come and go
+	merged.ID = models.StableID(merged.EntityType, merged.Type, merged.Labels)
+	merged.Labels["containers"] = strings.Join(containers, ",")
+
+	merged.Metrics = make(map[string]float64, len(worst.Metrics)+1)
+	for k, v := range worst.Metrics {
+		merged.Metrics[k] = v
+	}
+	merged.Metrics["containers"] = float64(len(members))
+
+	for _, p := range members {
+		if p.FirstSeen.Before(merged.FirstSeen) {
+			merged.FirstSeen = p.FirstSeen
+		}
+		if p.LastSeen.After(merged.LastSeen) {
+			merged.LastSeen = p.LastSeen
+		}
+		if p.Count > merged.Count {
+			merged.Count = p.Count
+		}
+	}
+	merged.UpdatePersistence()
+
+	namespace, pod := worst.Labels["namespace"], worst.Labels["pod"]
+	merged.Entity = fmt.Sprintf("%s/%s", namespace, pod)
+	if tenant := merged.Labels["tenant"]; tenant != "" {
+		// Scoped like the watcher scopes tenant problems
+		merged.ID = tenant + ":" + merged.ID
+		merged.Entity = tenant + ":" + merged.Entity
+	}
+	merged.Message = fmt.Sprintf("%s in %d containers of pod %s/%s: %s",
+		worst.Title, len(members), namespace, pod, strings.Join(containers, ", "))
+	return &merged
+}
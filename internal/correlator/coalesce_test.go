@@ -0,0 +1,80 @@
+package correlator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func containerOOM(pod, container string, seen time.Time) *models.Problem {
+	labels := map[string]string{"namespace": "prod", "pod": pod, "container": container}
+	return &models.Problem{
+		ID:          models.StableID("kubernetes_pod", "oom_kill", labels),
+		Entity:      "prod/" + pod + "/" + container,
+		EntityType:  "kubernetes_pod",
+		Type:        "oom_kill",
+		Severity:    models.SeverityCritical,
+		Title:       "Container OOM Killed",
+		FirstSeen:   seen,
+		LastSeen:    seen,
+		Count:       1,
+		BlastRadius: 1,
+		Labels:      labels,
+		Metrics:     map[string]float64{"restart_count": 1},
+	}
+}
+
+func TestCoalesceContainers_MergesOnePod(t *testing.T) {
+	now := time.Now()
+	problems := []*models.Problem{
+		containerOOM("api-0", "app", now),
+		containerOOM("api-0", "sidecar", now.Add(-time.Minute)),
+		containerOOM("api-0", "init", now),
+		containerOOM("web-0", "app", now),
+	}
+
+	got := CoalesceContainers(problems)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 problems, got %d", len(got))
+	}
+
+	merged := got[0]
+	if merged.Entity != "prod/api-0" {
+		t.Errorf("entity = %q, want prod/api-0", merged.Entity)
+	}
+	if merged.Labels["containers"] != "app,init,sidecar" {
+		t.Errorf("containers label = %q", merged.Labels["containers"])
+	}
+	if _, ok := merged.Labels["container"]; ok {
+		t.Error("merged problem should drop the container label")
+	}
+	if merged.Metrics["containers"] != 3 {
+		t.Errorf("containers metric = %v, want 3", merged.Metrics["containers"])
+	}
+	if !merged.FirstSeen.Equal(now.Add(-time.Minute)) {
+		t.Errorf("FirstSeen should be the earliest member's, got %v", merged.FirstSeen)
+	}
+	wantID := models.StableID("kubernetes_pod", "oom_kill", map[string]string{"namespace": "prod", "pod": "api-0"})
+	if merged.ID != wantID {
+		t.Errorf("ID = %s, want the pod-level ID %s", merged.ID, wantID)
+	}
+	if problems[0].Labels["container"] != "app" {
+		t.Error("CoalesceContainers should not modify its input")
+	}
+
+	if got[1].Entity != "prod/web-0/app" {
+		t.Errorf("single-container pod should be unchanged, got %q", got[1].Entity)
+	}
+}
+
+func TestCoalesceContainers_KeepsTypesApart(t *testing.T) {
+	now := time.Now()
+	crash := containerOOM("api-0", "sidecar", now)
+	crash.Type = "crashloop"
+
+	got := CoalesceContainers([]*models.Problem{containerOOM("api-0", "app", now), crash})
+	if len(got) != 2 {
+		t.Errorf("different problem types on one pod should not merge, got %d", len(got))
+	}
+}
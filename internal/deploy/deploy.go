@@ -0,0 +1,137 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// DefaultWindow is how long after a deploy a new problem is attributed to it
+const DefaultWindow = 15 * time.Minute
+
+// LikelyCauseLabel is the problem label set by Annotate
+const LikelyCauseLabel = "likely_cause"
+
+// serviceLabels are the problem labels compared against Deploy.Service
+var serviceLabels = []string{"service", "deployment", "workload", "app", "namespace", "dag_id"}
+
+// Deploy is a single deployment event
+type Deploy struct {
+	Timestamp time.Time `json:"timestamp"`
+	Service   string    `json:"service"` // Empty matches every problem
+	Version   string    `json:"version"`
+}
+
+// Source provides deployment events
+type Source interface {
+	Deploys(ctx context.Context) ([]Deploy, error)
+}
+
+// FileSource reads deploys from a YAML or JSON list. The file is re-read on
+// every call so a CD pipeline can append to it while infranow runs.
+type FileSource struct {
+	Path string
+}
+
+// Deploys reads and parses the deploys file
+func (s FileSource) Deploys(_ context.Context) ([]Deploy, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("read deploys file: %w", err)
+	}
+	var deploys []Deploy
+	if err := yaml.UnmarshalStrict(data, &deploys); err != nil {
+		return nil, fmt.Errorf("parse deploys file: %w", err)
+	}
+	for i, d := range deploys {
+		if d.Timestamp.IsZero() {
+			return nil, fmt.Errorf("deploys file entry %d: missing timestamp", i)
+		}
+	}
+	return deploys, nil
+}
+
+// MetricSource reads deploys from a PromQL query whose samples carry
+// service/version labels and whose value is the deploy Unix timestamp,
+// e.g. `deploy_timestamp_seconds`.
+type MetricSource struct {
+	Provider metrics.MetricsProvider
+	Query    string
+}
+
+// Deploys runs the deploy query
+func (s MetricSource) Deploys(ctx context.Context) ([]Deploy, error) {
+	result, err := s.Provider.QueryInstant(ctx, s.Query, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("deploy metric query failed: %w", err)
+	}
+	deploys := make([]Deploy, 0, len(result))
+	for _, sample := range result {
+		deploys = append(deploys, Deploy{
+			Timestamp: time.Unix(int64(sample.Value), 0),
+			Service:   string(sample.Metric["service"]),
+			Version:   string(sample.Metric["version"]),
+		})
+	}
+	return deploys, nil
+}
+
+// Annotate tags every problem whose FirstSeen falls within window after a
+// matching deploy with likely_cause="deploy <version>". The most recent
+// matching deploy wins. Labels are copied before modification because problem
+// copies from the watcher share their label maps.
+func Annotate(problems []*models.Problem, deploys []Deploy, window time.Duration) {
+	if len(deploys) == 0 {
+		return
+	}
+
+	sorted := make([]Deploy, len(deploys))
+	copy(sorted, deploys)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.After(sorted[j].Timestamp)
+	})
+
+	for _, p := range problems {
+		for _, d := range sorted {
+			if p.FirstSeen.Before(d.Timestamp) || p.FirstSeen.Sub(d.Timestamp) > window {
+				continue
+			}
+			if !matchesService(p, d.Service) {
+				continue
+			}
+			labels := make(map[string]string, len(p.Labels)+1)
+			for k, v := range p.Labels {
+				labels[k] = v
+			}
+			labels[LikelyCauseLabel] = strings.TrimSpace("deploy " + d.Version)
+			p.Labels = labels
+			break
+		}
+	}
+}
+
+// matchesService reports whether a deploy of service plausibly affects p
+func matchesService(p *models.Problem, service string) bool {
+	if service == "" {
+		return true
+	}
+	for _, key := range serviceLabels {
+		if p.Labels[key] == service {
+			return true
+		}
+	}
+	for _, segment := range strings.Split(p.Entity, "/") {
+		if segment == service || strings.HasPrefix(segment, service+"-") {
+			return true // pod names are <deployment>-<hash>
+		}
+	}
+	return false
+}
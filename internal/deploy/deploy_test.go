@@ -0,0 +1,119 @@
+package deploy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestAnnotate_MatchesRecentDeploy(t *testing.T) {
+	deployTime := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	deploys := []Deploy{
+		{Timestamp: deployTime.Add(-2 * time.Hour), Service: "api", Version: "v1.3.0"},
+		{Timestamp: deployTime, Service: "api", Version: "v1.4.0"},
+		{Timestamp: deployTime, Service: "billing", Version: "v9.0.0"},
+	}
+
+	shared := map[string]string{"namespace": "prod", "pod": "api-7d9f8-xk2"}
+	problems := []*models.Problem{
+		// 5m after the api deploy: attributed to the latest api version
+		{ID: "a", Entity: "prod/api-7d9f8-xk2/app", FirstSeen: deployTime.Add(5 * time.Minute), Labels: shared},
+		// Same service but long after the window
+		{ID: "b", Entity: "prod/api-7d9f8-zz1/app", FirstSeen: deployTime.Add(2 * time.Hour)},
+		// Before the deploy
+		{ID: "c", Entity: "prod/api-7d9f8-yy3/app", FirstSeen: deployTime.Add(-time.Minute)},
+		// Different service
+		{ID: "d", Entity: "prod/web-5c4b-aa1/app", FirstSeen: deployTime.Add(time.Minute)},
+	}
+
+	Annotate(problems, deploys, DefaultWindow)
+
+	if got := problems[0].Labels[LikelyCauseLabel]; got != "deploy v1.4.0" {
+		t.Errorf("likely_cause = %q, want %q", got, "deploy v1.4.0")
+	}
+	for _, p := range problems[1:] {
+		if got := p.Labels[LikelyCauseLabel]; got != "" {
+			t.Errorf("problem %s: likely_cause = %q, want none", p.ID, got)
+		}
+	}
+	if _, ok := shared[LikelyCauseLabel]; ok {
+		t.Error("Annotate must not mutate the original label map")
+	}
+}
+
+func TestAnnotate_ServiceByLabel(t *testing.T) {
+	deployTime := time.Now().Add(-10 * time.Minute)
+	problems := []*models.Problem{
+		{Entity: "orders-db/primary", FirstSeen: deployTime.Add(time.Minute), Labels: map[string]string{"service": "orders"}},
+		{Entity: "anything", FirstSeen: deployTime.Add(time.Minute)},
+	}
+
+	Annotate(problems, []Deploy{{Timestamp: deployTime, Service: "orders", Version: "r42"}}, DefaultWindow)
+	if got := problems[0].Labels[LikelyCauseLabel]; got != "deploy r42" {
+		t.Errorf("likely_cause = %q, want %q", got, "deploy r42")
+	}
+	if problems[1].Labels[LikelyCauseLabel] != "" {
+		t.Error("unrelated problem should not be annotated")
+	}
+
+	// A deploy without a service applies to everything
+	Annotate(problems[1:], []Deploy{{Timestamp: deployTime, Version: "platform-7"}}, DefaultWindow)
+	if got := problems[1].Labels[LikelyCauseLabel]; got != "deploy platform-7" {
+		t.Errorf("global deploy likely_cause = %q", got)
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deploys.yaml")
+	data := []byte(`
+- timestamp: "2026-03-01T12:00:00Z"
+  service: api
+  version: v1.4.0
+`)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	deploys, err := FileSource{Path: path}.Deploys(context.Background())
+	if err != nil {
+		t.Fatalf("Deploys() error = %v", err)
+	}
+	if len(deploys) != 1 || deploys[0].Service != "api" || deploys[0].Version != "v1.4.0" {
+		t.Errorf("deploys = %+v", deploys)
+	}
+
+	if err := os.WriteFile(path, []byte("- service: api\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (FileSource{Path: path}).Deploys(context.Background()); err == nil {
+		t.Error("expected error for entry without timestamp")
+	}
+}
+
+func TestMetricSource(t *testing.T) {
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{
+				&model.Sample{
+					Metric: model.Metric{"service": "api", "version": "v2"},
+					Value:  1772366400,
+				},
+			}, nil
+		},
+	}
+
+	deploys, err := MetricSource{Provider: provider, Query: "deploy_timestamp_seconds"}.Deploys(context.Background())
+	if err != nil {
+		t.Fatalf("Deploys() error = %v", err)
+	}
+	if len(deploys) != 1 || deploys[0].Timestamp.Unix() != 1772366400 || deploys[0].Version != "v2" {
+		t.Errorf("deploys = %+v", deploys)
+	}
+}
@@ -40,6 +40,8 @@ func NewAirflowDAGFailureRateDetector() *AirflowDAGFailureRateDetector {
 func (d *AirflowDAGFailureRateDetector) Name() string            { return "airflow_dag_failure_rate" }
 func (d *AirflowDAGFailureRateDetector) EntityTypes() []string   { return []string{"airflow_dag"} }
 func (d *AirflowDAGFailureRateDetector) Interval() time.Duration { return d.interval }
+func (d *AirflowDAGFailureRateDetector) Threshold() float64      { return d.threshold }
+func (d *AirflowDAGFailureRateDetector) SetThreshold(v float64)  { d.threshold = v }
 
 func (d *AirflowDAGFailureRateDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`airflow_dag_failed_runs_ratio > %f`, d.threshold)
@@ -63,7 +65,6 @@ func (d *AirflowDAGFailureRateDetector) Detect(ctx context.Context, provider met
 		entity := fmt.Sprintf("%s/%s", instance, dag)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/airflow_dag_failure_rate", entity),
 			Entity:      entity,
 			EntityType:  "airflow_dag",
 			Type:        "airflow_dag_failure_rate",
@@ -95,6 +96,8 @@ func (d *AirflowSchedulerHeartbeatDetector) EntityTypes() []string {
 	return []string{"airflow_scheduler"}
 }
 func (d *AirflowSchedulerHeartbeatDetector) Interval() time.Duration { return d.interval }
+func (d *AirflowSchedulerHeartbeatDetector) Threshold() float64      { return d.threshold }
+func (d *AirflowSchedulerHeartbeatDetector) SetThreshold(v float64)  { d.threshold = v }
 
 func (d *AirflowSchedulerHeartbeatDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`airflow_scheduler_heartbeat_seconds > %f`, d.threshold)
@@ -113,7 +116,6 @@ func (d *AirflowSchedulerHeartbeatDetector) Detect(ctx context.Context, provider
 		seconds := float64(sample.Value)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/airflow_scheduler_heartbeat", instance),
 			Entity:      instance,
 			EntityType:  "airflow_scheduler",
 			Type:        "airflow_scheduler_heartbeat",
@@ -143,6 +145,8 @@ func NewAirflowTaskQueueBacklogDetector() *AirflowTaskQueueBacklogDetector {
 func (d *AirflowTaskQueueBacklogDetector) Name() string            { return "airflow_task_queue_backlog" }
 func (d *AirflowTaskQueueBacklogDetector) EntityTypes() []string   { return []string{"airflow_executor"} }
 func (d *AirflowTaskQueueBacklogDetector) Interval() time.Duration { return d.interval }
+func (d *AirflowTaskQueueBacklogDetector) Threshold() float64      { return float64(d.threshold) }
+func (d *AirflowTaskQueueBacklogDetector) SetThreshold(v float64)  { d.threshold = int(v) }
 
 func (d *AirflowTaskQueueBacklogDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`airflow_queued_tasks > %d`, d.threshold)
@@ -161,7 +165,6 @@ func (d *AirflowTaskQueueBacklogDetector) Detect(ctx context.Context, provider m
 		count := float64(sample.Value)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/airflow_task_queue_backlog", instance),
 			Entity:      instance,
 			EntityType:  "airflow_executor",
 			Type:        "airflow_task_queue_backlog",
@@ -191,6 +194,8 @@ func NewAirflowPoolExhaustionDetector() *AirflowPoolExhaustionDetector {
 func (d *AirflowPoolExhaustionDetector) Name() string            { return "airflow_pool_exhaustion" }
 func (d *AirflowPoolExhaustionDetector) EntityTypes() []string   { return []string{"airflow_pool"} }
 func (d *AirflowPoolExhaustionDetector) Interval() time.Duration { return d.interval }
+func (d *AirflowPoolExhaustionDetector) Threshold() float64      { return d.threshold }
+func (d *AirflowPoolExhaustionDetector) SetThreshold(v float64)  { d.threshold = v }
 
 func (d *AirflowPoolExhaustionDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`airflow_pool_used_ratio > %f`, d.threshold)
@@ -214,7 +219,6 @@ func (d *AirflowPoolExhaustionDetector) Detect(ctx context.Context, provider met
 		entity := fmt.Sprintf("%s/%s", instance, pool)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/airflow_pool_exhaustion", entity),
 			Entity:      entity,
 			EntityType:  "airflow_pool",
 			Type:        "airflow_pool_exhaustion",
@@ -261,7 +265,6 @@ func (d *AirflowZombieTasksDetector) Detect(ctx context.Context, provider metric
 		count := float64(sample.Value)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/airflow_zombie_tasks", instance),
 			Entity:      instance,
 			EntityType:  "airflow_task",
 			Type:        "airflow_zombie_tasks",
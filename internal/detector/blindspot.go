@@ -0,0 +1,100 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+const (
+	blindSpotCheckInterval = 60 * time.Second
+
+	// A missing exporter hides every problem the Kubernetes detectors cover
+	blastRadiusBlindSpot = 10
+
+	// kube-state-metrics scrape targets that are down
+	ksmDownQuery = `up{job="kube-state-metrics"} == 0`
+
+	// No cadvisor container series although this is a Kubernetes cluster
+	// (kube-state-metrics reports pods); plain Prometheus setups without
+	// Kubernetes never match
+	cadvisorMissingQuery = `absent(container_cpu_usage_seconds_total) and on() (count(kube_pod_info) > 0)`
+)
+
+// MonitoringBlindSpotDetector reports when kube-state-metrics or cadvisor
+// metrics are missing. Most detectors read those exporters, so without them
+// an empty problem list would be a false all-clear.
+type MonitoringBlindSpotDetector struct {
+	interval time.Duration
+}
+
+func NewMonitoringBlindSpotDetector() *MonitoringBlindSpotDetector {
+	return &MonitoringBlindSpotDetector{
+		interval: blindSpotCheckInterval,
+	}
+}
+
+func (d *MonitoringBlindSpotDetector) Name() string {
+	return "monitoring_blind_spot"
+}
+
+func (d *MonitoringBlindSpotDetector) EntityTypes() []string {
+	return []string{"monitoring_target"}
+}
+
+func (d *MonitoringBlindSpotDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *MonitoringBlindSpotDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	now := time.Now()
+	ksm, err := provider.QueryInstant(ctx, ksmDownQuery, now)
+	if err != nil {
+		return nil, fmt.Errorf("kube-state-metrics up query failed: %w", err)
+	}
+	cadvisor, err := provider.QueryInstant(ctx, cadvisorMissingQuery, now)
+	if err != nil {
+		return nil, fmt.Errorf("cadvisor absence query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0, len(ksm)+len(cadvisor))
+	for _, sample := range ksm {
+		instance := string(sample.Metric["instance"])
+		problems = append(problems, &models.Problem{
+			Entity:     "kube-state-metrics/" + instance,
+			EntityType: "monitoring_target",
+			Type:       "monitoring_blind_spot",
+			Severity:   models.SeverityCritical,
+			Title:      "Monitoring blind spot: kube-state-metrics down",
+			Message:    fmt.Sprintf("kube-state-metrics target %s is down; pod, deployment and quota detectors cannot see the cluster", instance),
+			Labels: map[string]string{
+				"job":      "kube-state-metrics",
+				"instance": instance,
+			},
+			Metrics:     map[string]float64{"up": float64(sample.Value)},
+			Hint:        "Check the kube-state-metrics pod and its scrape target in Prometheus",
+			RunbookURL:  models.RunbookBaseURL + "monitoring_blind_spot.md",
+			BlastRadius: blastRadiusBlindSpot,
+		})
+	}
+	if len(cadvisor) > 0 {
+		problems = append(problems, &models.Problem{
+			Entity:      "cadvisor",
+			EntityType:  "monitoring_target",
+			Type:        "monitoring_blind_spot",
+			Severity:    models.SeverityCritical,
+			Title:       "Monitoring blind spot: cadvisor metrics missing",
+			Message:     "No container_cpu_usage_seconds_total series while kube-state-metrics reports pods; container resource usage is not being scraped",
+			Labels:      map[string]string{"source": "cadvisor"},
+			Metrics:     map[string]float64{},
+			Hint:        "Check the kubelet /metrics/cadvisor scrape job",
+			RunbookURL:  models.RunbookBaseURL + "monitoring_blind_spot.md",
+			BlastRadius: blastRadiusBlindSpot,
+		})
+	}
+
+	return problems, nil
+}
@@ -0,0 +1,103 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestMonitoringBlindSpotDetector_KSMDown(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			if query != ksmDownQuery {
+				return model.Vector{}, nil
+			}
+			return model.Vector{
+				&model.Sample{
+					Metric: model.Metric{"job": "kube-state-metrics", "instance": "10.0.0.5:8080"},
+					Value:  0,
+				},
+			}, nil
+		},
+	}
+
+	d := NewMonitoringBlindSpotDetector()
+	problems, err := d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	if p.Severity != models.SeverityCritical {
+		t.Errorf("expected CRITICAL, got %v", p.Severity)
+	}
+	if p.Type != "monitoring_blind_spot" {
+		t.Errorf("expected type 'monitoring_blind_spot', got '%s'", p.Type)
+	}
+	if p.Entity != "kube-state-metrics/10.0.0.5:8080" {
+		t.Errorf("unexpected entity %q", p.Entity)
+	}
+}
+
+func TestMonitoringBlindSpotDetector_CadvisorMissing(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			if query != cadvisorMissingQuery {
+				return model.Vector{}, nil
+			}
+			// absent() returns a single label-less sample
+			return model.Vector{&model.Sample{Metric: model.Metric{}, Value: 1}}, nil
+		},
+	}
+
+	d := NewMonitoringBlindSpotDetector()
+	problems, err := d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+	if problems[0].Entity != "cadvisor" || problems[0].Severity != models.SeverityCritical {
+		t.Errorf("unexpected problem %s %s", problems[0].Severity, problems[0].Entity)
+	}
+}
+
+func TestMonitoringBlindSpotDetector_Healthy(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{}, nil
+		},
+	}
+
+	d := NewMonitoringBlindSpotDetector()
+	problems, err := d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("expected 0 problems, got %d", len(problems))
+	}
+}
+
+func TestMonitoringBlindSpotDetector_ProviderError(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	}
+
+	d := NewMonitoringBlindSpotDetector()
+	if _, err := d.Detect(context.Background(), mockProvider, 5*time.Minute); err == nil {
+		t.Fatal("expected error when provider fails")
+	}
+}
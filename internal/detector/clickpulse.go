@@ -38,6 +38,8 @@ func NewChMergePressureDetector() *ChMergePressureDetector {
 func (d *ChMergePressureDetector) Name() string            { return "ch_merge_pressure" }
 func (d *ChMergePressureDetector) EntityTypes() []string   { return []string{"clickhouse"} }
 func (d *ChMergePressureDetector) Interval() time.Duration { return d.interval }
+func (d *ChMergePressureDetector) Threshold() float64      { return float64(d.threshold) }
+func (d *ChMergePressureDetector) SetThreshold(v float64)  { d.threshold = int(v) }
 
 func (d *ChMergePressureDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`clickhouse_merges_active > %d`, d.threshold)
@@ -59,7 +61,6 @@ func (d *ChMergePressureDetector) Detect(ctx context.Context, provider metrics.M
 		count := float64(sample.Value)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/ch_merge_pressure", node),
 			Entity:      node,
 			EntityType:  "clickhouse",
 			Type:        "ch_merge_pressure",
@@ -109,7 +110,6 @@ func (d *ChStuckMutationsDetector) Detect(ctx context.Context, provider metrics.
 		count := float64(sample.Value)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/ch_stuck_mutations", node),
 			Entity:      node,
 			EntityType:  "clickhouse",
 			Type:        "ch_stuck_mutations",
@@ -139,6 +139,8 @@ func NewChReplicaLagDetector() *ChReplicaLagDetector {
 func (d *ChReplicaLagDetector) Name() string            { return "ch_replica_lag" }
 func (d *ChReplicaLagDetector) EntityTypes() []string   { return []string{"clickhouse"} }
 func (d *ChReplicaLagDetector) Interval() time.Duration { return d.interval }
+func (d *ChReplicaLagDetector) Threshold() float64      { return d.threshold }
+func (d *ChReplicaLagDetector) SetThreshold(v float64)  { d.threshold = v }
 
 func (d *ChReplicaLagDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`clickhouse_replica_lag_seconds > %f`, d.threshold)
@@ -160,7 +162,6 @@ func (d *ChReplicaLagDetector) Detect(ctx context.Context, provider metrics.Metr
 		lagSeconds := float64(sample.Value)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/ch_replica_lag", node),
 			Entity:      node,
 			EntityType:  "clickhouse",
 			Type:        "ch_replica_lag",
@@ -190,6 +191,8 @@ func NewChPartCountExplosionDetector() *ChPartCountExplosionDetector {
 func (d *ChPartCountExplosionDetector) Name() string            { return "ch_part_count_explosion" }
 func (d *ChPartCountExplosionDetector) EntityTypes() []string   { return []string{"clickhouse_table"} }
 func (d *ChPartCountExplosionDetector) Interval() time.Duration { return d.interval }
+func (d *ChPartCountExplosionDetector) Threshold() float64      { return float64(d.threshold) }
+func (d *ChPartCountExplosionDetector) SetThreshold(v float64)  { d.threshold = int(v) }
 
 func (d *ChPartCountExplosionDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`clickhouse_parts_per_partition > %d`, d.threshold)
@@ -226,7 +229,6 @@ func (d *ChPartCountExplosionDetector) Detect(ctx context.Context, provider metr
 		}
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/ch_part_count", entity),
 			Entity:      entity,
 			EntityType:  "clickhouse_table",
 			Type:        "ch_part_count_explosion",
@@ -276,7 +278,6 @@ func (d *ChDDLQueueStuckDetector) Detect(ctx context.Context, provider metrics.M
 		count := float64(sample.Value)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/ch_ddl_queue_stuck", node),
 			Entity:      node,
 			EntityType:  "clickhouse",
 			Type:        "ch_ddl_queue_stuck",
@@ -306,6 +307,8 @@ func NewChKeeperHighLatencyDetector() *ChKeeperHighLatencyDetector {
 func (d *ChKeeperHighLatencyDetector) Name() string            { return "ch_keeper_high_latency" }
 func (d *ChKeeperHighLatencyDetector) EntityTypes() []string   { return []string{"clickhouse_keeper"} }
 func (d *ChKeeperHighLatencyDetector) Interval() time.Duration { return d.interval }
+func (d *ChKeeperHighLatencyDetector) Threshold() float64      { return d.threshold }
+func (d *ChKeeperHighLatencyDetector) SetThreshold(v float64)  { d.threshold = v }
 
 func (d *ChKeeperHighLatencyDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`clickhouse_keeper_latency_seconds > %f`, d.threshold)
@@ -327,7 +330,6 @@ func (d *ChKeeperHighLatencyDetector) Detect(ctx context.Context, provider metri
 		latencyMs := float64(sample.Value) * 1000
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/ch_keeper_high_latency", keeper),
 			Entity:      keeper,
 			EntityType:  "clickhouse_keeper",
 			Type:        "ch_keeper_high_latency",
@@ -361,6 +363,8 @@ func (d *ChKeeperOutstandingRequestsDetector) EntityTypes() []string {
 	return []string{"clickhouse_keeper"}
 }
 func (d *ChKeeperOutstandingRequestsDetector) Interval() time.Duration { return d.interval }
+func (d *ChKeeperOutstandingRequestsDetector) Threshold() float64      { return float64(d.threshold) }
+func (d *ChKeeperOutstandingRequestsDetector) SetThreshold(v float64)  { d.threshold = int(v) }
 
 func (d *ChKeeperOutstandingRequestsDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`clickhouse_keeper_outstanding_requests > %d`, d.threshold)
@@ -382,7 +386,6 @@ func (d *ChKeeperOutstandingRequestsDetector) Detect(ctx context.Context, provid
 		count := float64(sample.Value)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/ch_keeper_outstanding_requests", keeper),
 			Entity:      keeper,
 			EntityType:  "clickhouse_keeper",
 			Type:        "ch_keeper_outstanding_requests",
@@ -0,0 +1,138 @@
+package detector
+
+// Defaults returns a new instance of every built-in detector. Adding a
+// detector to this list is all it takes to ship it: every command builds its
+// registry from here.
+func Defaults() []Detector {
+	return []Detector{
+		// Kubernetes detectors
+		NewOOMKillDetector(),
+		NewCrashLoopBackOffDetector(),
+		NewRestartRateDetector(),
+		NewImagePullBackOffDetector(),
+		NewPodPendingDetector(),
+		NewPodTerminatingDetector(),
+		NewNodeConditionDetector(),
+		NewRolloutStuckDetector(),
+		NewPodSprawlDetector(),
+		NewAPIServiceUnavailableDetector(),
+		NewPDBViolationDetector(),
+		NewResourceQuotaDetector(),
+		NewMonitoringBlindSpotDetector(),
+
+		// etcd detectors
+		NewEtcdHealthDetector(),
+
+		// HA leader election detectors
+		NewLeaderElectionDetector(),
+
+		// Generic detectors
+		NewHighErrorRateDetector(),
+		NewDiskSpaceDetector(),
+		NewHighMemoryPressureDetector(),
+		NewHighLoadAverageDetector(),
+
+		// Ingress detectors
+		NewIngressErrorRateDetector(),
+
+		// Prometheus self-monitoring detectors
+		NewPrometheusRuleEvaluationDetector(),
+		NewDataFreshnessDetector(),
+
+		// Service mesh control plane detectors
+		NewLinkerdControlPlaneDetector(),
+		NewLinkerdProxyInjectionDetector(),
+		NewIstioControlPlaneDetector(),
+		NewIstioSidecarInjectionDetector(),
+
+		// Service mesh certificate expiry detectors
+		NewLinkerdCertExpiryDetector(),
+		NewIstioCertExpiryDetector(),
+
+		// Trustwatch certificate detectors
+		NewTrustwatchCertExpiryDetector(),
+		NewTrustwatchProbeFailureDetector(),
+
+		// x509-certificate-exporter detectors
+		NewX509CertExpiryDetector(),
+
+		// Tote image salvage detectors
+		NewToteSalvageFailureDetector(),
+		NewTotePushFailureDetector(),
+		NewToteHighFailureRateDetector(),
+
+		// pgpulse (PostgreSQL) detectors
+		NewPgConnectionExhaustionDetector(),
+		NewPgReplicationLagDetector(),
+		NewPgDeadTupleRatioDetector(),
+		NewPgLockChainDepthDetector(),
+		NewPgSlowQueriesDetector(),
+
+		// clickpulse (ClickHouse) detectors
+		NewChMergePressureDetector(),
+		NewChStuckMutationsDetector(),
+		NewChReplicaLagDetector(),
+		NewChPartCountExplosionDetector(),
+		NewChDDLQueueStuckDetector(),
+		NewChKeeperHighLatencyDetector(),
+		NewChKeeperOutstandingRequestsDetector(),
+
+		// mongopulse (MongoDB) detectors
+		NewMongoConnectionExhaustionDetector(),
+		NewMongoReplicationLagDetector(),
+		NewMongoOplogWindowDetector(),
+		NewMongoLockPercentageDetector(),
+		NewMongoCursorTimeoutDetector(),
+
+		// mysqlpulse (MySQL/MariaDB) detectors
+		NewMySQLConnectionExhaustionDetector(),
+		NewMySQLReplicationLagDetector(),
+		NewMySQLDeadlocksDetector(),
+		NewMySQLSlowQueriesDetector(),
+		NewMySQLInnoDBBufferPoolPressureDetector(),
+
+		// airflowpulse (Apache Airflow) detectors
+		NewAirflowDAGFailureRateDetector(),
+		NewAirflowSchedulerHeartbeatDetector(),
+		NewAirflowTaskQueueBacklogDetector(),
+		NewAirflowPoolExhaustionDetector(),
+		NewAirflowZombieTasksDetector(),
+	}
+}
+
+// Optional returns a new instance of every built-in detector that only runs
+// when enabled by name, for hygiene checks that would report something on
+// most clusters and for hardware most clusters do not have
+func Optional() []Detector {
+	return []Detector{
+		NewMissingResourceLimitsDetector(),
+		NewNodeOvercommitDetector(),
+		NewGPUHealthDetector(),
+	}
+}
+
+// LookupOptional returns a new instance of the named optional detector
+func LookupOptional(name string) (Detector, bool) {
+	for _, d := range Optional() {
+		if d.Name() == name {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// IsOptional reports whether name is an optional built-in detector
+func IsOptional(name string) bool {
+	_, ok := LookupOptional(name)
+	return ok
+}
+
+// DefaultRegistry returns a registry with all default built-in detectors,
+// without the optional ones
+func DefaultRegistry() *Registry {
+	registry := NewRegistry()
+	for _, d := range Defaults() {
+		registry.Register(d)
+	}
+	return registry
+}
@@ -0,0 +1,137 @@
+package detector
+
+import (
+	"sort"
+	"testing"
+)
+
+// shippedDetectors is every built-in detector name. A detector missing from
+// Defaults() would silently never run.
+var shippedDetectors = []string{
+	"airflow_dag_failure_rate",
+	"airflow_pool_exhaustion",
+	"airflow_scheduler_heartbeat",
+	"airflow_task_queue_backlog",
+	"airflow_zombie_tasks",
+	"ch_ddl_queue_stuck",
+	"ch_keeper_high_latency",
+	"ch_keeper_outstanding_requests",
+	"ch_merge_pressure",
+	"ch_part_count_explosion",
+	"ch_replica_lag",
+	"ch_stuck_mutations",
+	"etcd_health",
+	"generic_disk_space",
+	"generic_high_error_rate",
+	"generic_load_average",
+	"generic_memory_pressure",
+	"ingress_error_rate",
+	"kubernetes_apiservice_unavailable",
+	"kubernetes_crashloop",
+	"kubernetes_imagepull",
+	"kubernetes_node_condition",
+	"kubernetes_oom_kills",
+	"kubernetes_pdb_violation",
+	"kubernetes_pending",
+	"kubernetes_pod_sprawl",
+	"kubernetes_pod_terminating",
+	"kubernetes_resource_quota",
+	"kubernetes_restart_rate",
+	"kubernetes_rollout_stuck",
+	"leader_election",
+	"mongo_connection_exhaustion",
+	"mongo_cursor_timeout",
+	"mongo_lock_percentage",
+	"mongo_oplog_window",
+	"mongo_replication_lag",
+	"monitoring_blind_spot",
+	"mysql_connection_exhaustion",
+	"mysql_deadlocks",
+	"mysql_innodb_buffer_pool_pressure",
+	"mysql_replication_lag",
+	"mysql_slow_queries",
+	"pg_connection_exhaustion",
+	"pg_dead_tuple_ratio",
+	"pg_lock_chain_depth",
+	"pg_replication_lag",
+	"pg_slow_queries",
+	"prometheus_data_freshness",
+	"prometheus_rule_evaluation",
+	"servicemesh_istio_cert_expiry",
+	"servicemesh_istio_controlplane",
+	"servicemesh_istio_injection",
+	"servicemesh_linkerd_cert_expiry",
+	"servicemesh_linkerd_controlplane",
+	"servicemesh_linkerd_injection",
+	"tote_high_failure_rate",
+	"tote_push_failure",
+	"tote_salvage_failure",
+	"trustwatch_cert_expiry",
+	"trustwatch_probe_failure",
+	"x509_cert_expiry",
+}
+
+// optionalDetectors only run when enabled by name
+var optionalDetectors = []string{
+	"gpu_health",
+	"kubernetes_missing_limits",
+	"kubernetes_node_overcommit",
+}
+
+func TestOptional_NotInDefaultRegistry(t *testing.T) {
+	registry := DefaultRegistry()
+	var names []string
+	for _, d := range Optional() {
+		names = append(names, d.Name())
+		if _, ok := registry.Get(d.Name()); ok {
+			t.Errorf("optional detector %s is in DefaultRegistry()", d.Name())
+		}
+		if !IsOptional(d.Name()) {
+			t.Errorf("IsOptional(%s) = false", d.Name())
+		}
+	}
+	sort.Strings(names)
+	if len(names) != len(optionalDetectors) {
+		t.Errorf("Optional() = %v, want %v", names, optionalDetectors)
+	}
+	if IsOptional("kubernetes_oom_kills") {
+		t.Error("a default detector is not optional")
+	}
+}
+
+func TestDefaultRegistry_ContainsShippedDetectors(t *testing.T) {
+	registry := DefaultRegistry()
+	for _, name := range shippedDetectors {
+		if _, ok := registry.Get(name); !ok {
+			t.Errorf("DefaultRegistry() is missing %s", name)
+		}
+	}
+	if got, want := registry.Count(), len(shippedDetectors); got != want {
+		var names []string
+		for _, d := range registry.All() {
+			names = append(names, d.Name())
+		}
+		sort.Strings(names)
+		t.Errorf("DefaultRegistry() has %d detectors, want %d: %v", got, want, names)
+	}
+}
+
+func TestDefaults_UniqueNamesAndFreshInstances(t *testing.T) {
+	seen := make(map[string]bool)
+	first := Defaults()
+	for _, d := range first {
+		if seen[d.Name()] {
+			t.Errorf("duplicate detector name %s", d.Name())
+		}
+		seen[d.Name()] = true
+	}
+
+	// Each call must return new instances: tuning one registry (thresholds,
+	// sustained window) must not leak into another
+	second := Defaults()
+	for i := range first {
+		if first[i] == second[i] {
+			t.Errorf("Defaults() reused the %s instance", first[i].Name())
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+const (
+	etcdCheckInterval = 30 * time.Second
+
+	// Leader elections over the detection window; a healthy cluster sees
+	// one per member restart at most
+	etcdLeaderChangesThreshold = 3
+
+	// WAL fsync p99 in seconds; etcd wants it under 10ms, and at 500ms
+	// heartbeats start missing and elections follow
+	etcdFsyncThreshold = 0.5
+
+	// etcd backs every object in the cluster: when it degrades, the API
+	// server and everything talking to it does too
+	blastRadiusEtcd = 30
+
+	etcdNoLeaderQuery = `etcd_server_has_leader == 0`
+)
+
+// etcdLeaderChangesQuery returns members that saw more leader changes than
+// the threshold in window
+func etcdLeaderChangesQuery(window time.Duration) string {
+	return fmt.Sprintf(`increase(etcd_server_leader_changes_seen_total%s) > %d`, rangeSelector(window), etcdLeaderChangesThreshold)
+}
+
+// etcdFsyncQuery returns members whose WAL fsync p99 over window exceeds
+// the threshold
+func etcdFsyncQuery(window time.Duration) string {
+	return fmt.Sprintf(`histogram_quantile(0.99, sum by (instance, le) (rate(etcd_disk_wal_fsync_duration_seconds_bucket%s))) > %g`,
+		rangeSelector(window), etcdFsyncThreshold)
+}
+
+// EtcdHealthDetector detects etcd members without a leader, leader election
+// churn and slow WAL fsyncs. etcd trouble is usually invisible until the API
+// server degrades, and then every controller and kubectl call with it.
+type EtcdHealthDetector struct {
+	interval time.Duration
+}
+
+func NewEtcdHealthDetector() *EtcdHealthDetector {
+	return &EtcdHealthDetector{
+		interval: etcdCheckInterval,
+	}
+}
+
+func (d *EtcdHealthDetector) Name() string {
+	return "etcd_health"
+}
+
+func (d *EtcdHealthDetector) EntityTypes() []string {
+	return []string{"etcd_member"}
+}
+
+func (d *EtcdHealthDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *EtcdHealthDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	now := time.Now()
+	noLeader, err := provider.QueryInstant(ctx, etcdNoLeaderQuery, now)
+	if err != nil {
+		return nil, fmt.Errorf("etcd leader query failed: %w", err)
+	}
+	leaderChanges, err := provider.QueryInstant(ctx, etcdLeaderChangesQuery(window), now)
+	if err != nil {
+		return nil, fmt.Errorf("etcd leader changes query failed: %w", err)
+	}
+	fsync, err := provider.QueryInstant(ctx, etcdFsyncQuery(window), now)
+	if err != nil {
+		return nil, fmt.Errorf("etcd fsync latency query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0)
+	for _, sample := range noLeader {
+		member := etcdMember(string(sample.Metric["instance"]))
+		problems = append(problems, &models.Problem{
+			Entity:      "etcd/" + member,
+			EntityType:  "etcd_member",
+			Type:        "etcd_no_leader",
+			Severity:    models.SeverityFatal,
+			Title:       "etcd member has no leader",
+			Message:     fmt.Sprintf("etcd member %s has no leader: it rejects writes, so the API server cannot persist changes", member),
+			Labels:      map[string]string{"instance": member},
+			Metrics:     map[string]float64{"has_leader": float64(sample.Value)},
+			Hint:        "Check quorum with etcdctl endpoint status --cluster; a majority of members must be up and reachable",
+			RunbookURL:  models.RunbookBaseURL + "etcd_no_leader.md",
+			BlastRadius: blastRadiusEtcd,
+		})
+	}
+	for _, sample := range leaderChanges {
+		member := etcdMember(string(sample.Metric["instance"]))
+		changes := float64(sample.Value)
+		problems = append(problems, &models.Problem{
+			Entity:      "etcd/" + member,
+			EntityType:  "etcd_member",
+			Type:        "etcd_leader_changes",
+			Severity:    models.SeverityCritical,
+			Title:       "Frequent etcd leader elections",
+			Message:     fmt.Sprintf("etcd member %s saw %.0f leader changes in the last %s; requests stall during every election", member, changes, windowLabel(window)),
+			Labels:      map[string]string{"instance": member},
+			Metrics:     map[string]float64{"leader_changes": changes},
+			Hint:        "Elections follow missed heartbeats: check disk latency and network between members",
+			RunbookURL:  models.RunbookBaseURL + "etcd_leader_changes.md",
+			BlastRadius: blastRadiusEtcd,
+		})
+	}
+	for _, sample := range fsync {
+		member := etcdMember(string(sample.Metric["instance"]))
+		seconds := float64(sample.Value)
+		problems = append(problems, &models.Problem{
+			Entity:      "etcd/" + member,
+			EntityType:  "etcd_member",
+			Type:        "etcd_slow_fsync",
+			Severity:    models.SeverityCritical,
+			Title:       "Slow etcd WAL fsync",
+			Message:     fmt.Sprintf("etcd member %s WAL fsync p99 is %.0fms over the last %s", member, seconds*1000, windowLabel(window)),
+			Labels:      map[string]string{"instance": member},
+			Metrics:     map[string]float64{"wal_fsync_p99_seconds": seconds},
+			Hint:        fmt.Sprintf("WAL fsync p99 above %.0fms: move etcd to dedicated SSD storage away from noisy neighbours", etcdFsyncThreshold*1000),
+			RunbookURL:  models.RunbookBaseURL + "etcd_slow_fsync.md",
+			BlastRadius: blastRadiusEtcd,
+		})
+	}
+
+	return problems, nil
+}
+
+// etcdMember names a member by its instance label
+func etcdMember(instance string) string {
+	if instance == "" {
+		return "unknown"
+	}
+	return instance
+}
@@ -0,0 +1,107 @@
+package detector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// etcdProvider answers each etcd query with its own samples
+func etcdProvider(answers map[string]model.Vector) *metrics.MockProvider {
+	return &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return answers[query], nil
+		},
+	}
+}
+
+func TestEtcdHealthDetector_NoLeader(t *testing.T) {
+	provider := etcdProvider(map[string]model.Vector{
+		etcdNoLeaderQuery: {
+			&model.Sample{Metric: model.Metric{"instance": "10.0.0.11:2379"}, Value: 0},
+		},
+	})
+
+	problems, err := NewEtcdHealthDetector().Detect(context.Background(), provider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	if p.Type != "etcd_no_leader" || p.EntityType != "etcd_member" {
+		t.Errorf("unexpected type %s/%s", p.EntityType, p.Type)
+	}
+	if p.Severity != models.SeverityFatal {
+		t.Errorf("expected FATAL, got %v", p.Severity)
+	}
+	if p.Entity != "etcd/10.0.0.11:2379" {
+		t.Errorf("unexpected entity %q", p.Entity)
+	}
+	if p.BlastRadius != blastRadiusEtcd {
+		t.Errorf("blast radius = %d, want %d", p.BlastRadius, blastRadiusEtcd)
+	}
+}
+
+func TestEtcdHealthDetector_HighFsyncLatency(t *testing.T) {
+	provider := etcdProvider(map[string]model.Vector{
+		etcdFsyncQuery(5 * time.Minute): {
+			&model.Sample{Metric: model.Metric{"instance": "10.0.0.12:2379"}, Value: 0.8},
+		},
+	})
+
+	problems, err := NewEtcdHealthDetector().Detect(context.Background(), provider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	if p.Type != "etcd_slow_fsync" || p.Severity != models.SeverityCritical {
+		t.Errorf("got %s %s, want CRITICAL etcd_slow_fsync", p.Severity, p.Type)
+	}
+	if p.Metrics["wal_fsync_p99_seconds"] != 0.8 {
+		t.Errorf("wal_fsync_p99_seconds = %g, want 0.8", p.Metrics["wal_fsync_p99_seconds"])
+	}
+	if p.Message != "etcd member 10.0.0.12:2379 WAL fsync p99 is 800ms over the last 5m" {
+		t.Errorf("unexpected message %q", p.Message)
+	}
+}
+
+func TestEtcdHealthDetector_LeaderChanges(t *testing.T) {
+	provider := etcdProvider(map[string]model.Vector{
+		etcdLeaderChangesQuery(10 * time.Minute): {
+			&model.Sample{Metric: model.Metric{"instance": "10.0.0.13:2379"}, Value: 7},
+		},
+	})
+
+	problems, err := NewEtcdHealthDetector().Detect(context.Background(), provider, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 || problems[0].Type != "etcd_leader_changes" {
+		t.Fatalf("expected one etcd_leader_changes problem, got %+v", problems)
+	}
+	if problems[0].Metrics["leader_changes"] != 7 {
+		t.Errorf("leader_changes = %g, want 7", problems[0].Metrics["leader_changes"])
+	}
+}
+
+func TestEtcdHealthDetector_Healthy(t *testing.T) {
+	problems, err := NewEtcdHealthDetector().Detect(context.Background(), etcdProvider(nil), 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("expected no problems, got %d", len(problems))
+	}
+}
@@ -0,0 +1,127 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+const (
+	dataFreshnessCheckInterval = 60 * time.Second
+
+	// Seconds a scrape target or remote-write queue may fall behind. Instant
+	// queries only look back 5m, so scrape staleness above that is reported
+	// by the target's series disappearing instead.
+	dataFreshnessThreshold = 120
+
+	// Stale data makes every other result for the target look current
+	blastRadiusDataFreshness = 5
+
+	// Targets whose last successful scrape is older than the threshold
+	scrapeStalenessQueryFmt = `max by (job, instance) (time() - timestamp(up)) > %f`
+
+	// Remote-write queues whose newest sent sample trails the newest
+	// ingested one
+	remoteWriteLagQueryFmt = `max by (remote_name, url) (prometheus_remote_storage_highest_timestamp_in_seconds - ignoring(remote_name, url) group_right prometheus_remote_storage_queue_highest_sent_timestamp_seconds) > %f`
+)
+
+// DataFreshnessDetector reports scrape targets and remote-write queues whose
+// data lags behind. Lagging data still looks current to every other
+// detector, so without it infranow can report a stale all-clear.
+type DataFreshnessDetector struct {
+	interval  time.Duration
+	threshold float64 // Seconds of lag
+}
+
+func NewDataFreshnessDetector() *DataFreshnessDetector {
+	return &DataFreshnessDetector{
+		interval:  dataFreshnessCheckInterval,
+		threshold: dataFreshnessThreshold,
+	}
+}
+
+func (d *DataFreshnessDetector) Name() string {
+	return "prometheus_data_freshness"
+}
+
+func (d *DataFreshnessDetector) EntityTypes() []string {
+	return []string{"monitoring_target", "prometheus_remote_write"}
+}
+
+func (d *DataFreshnessDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *DataFreshnessDetector) Threshold() float64 {
+	return d.threshold
+}
+
+func (d *DataFreshnessDetector) SetThreshold(v float64) {
+	d.threshold = v
+}
+
+func (d *DataFreshnessDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	now := time.Now()
+	scrapes, err := provider.QueryInstant(ctx, fmt.Sprintf(scrapeStalenessQueryFmt, d.threshold), now)
+	if err != nil {
+		return nil, fmt.Errorf("scrape staleness query failed: %w", err)
+	}
+	queues, err := provider.QueryInstant(ctx, fmt.Sprintf(remoteWriteLagQueryFmt, d.threshold), now)
+	if err != nil {
+		return nil, fmt.Errorf("remote-write lag query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0, len(scrapes)+len(queues))
+	for _, sample := range scrapes {
+		age := float64(sample.Value)
+		if age <= d.threshold {
+			continue
+		}
+		job := string(sample.Metric["job"])
+		instance := string(sample.Metric["instance"])
+		problems = append(problems, &models.Problem{
+			Entity:     job + "/" + instance,
+			EntityType: "monitoring_target",
+			Type:       "data_freshness",
+			Severity:   models.SeverityWarning,
+			Title:      "Stale scrape data",
+			Message:    fmt.Sprintf("Target %s/%s was last scraped %.0fs ago (threshold %.0fs); its metrics look current but are not", job, instance, age, d.threshold),
+			Labels: map[string]string{
+				"job":      job,
+				"instance": instance,
+			},
+			Metrics:     map[string]float64{"scrape_age_seconds": age},
+			Hint:        "Check the target's scrape duration and errors on the Prometheus /targets page",
+			RunbookURL:  models.RunbookBaseURL + "data_freshness.md",
+			BlastRadius: blastRadiusDataFreshness,
+		})
+	}
+	for _, sample := range queues {
+		lag := float64(sample.Value)
+		if lag <= d.threshold {
+			continue
+		}
+		remote := string(sample.Metric["remote_name"])
+		problems = append(problems, &models.Problem{
+			Entity:     "remote_write/" + remote,
+			EntityType: "prometheus_remote_write",
+			Type:       "data_freshness",
+			Severity:   models.SeverityWarning,
+			Title:      "Remote-write falling behind",
+			Message:    fmt.Sprintf("Remote-write queue %s is %.0fs behind ingestion (threshold %.0fs); data read from the remote store is stale", remote, lag, d.threshold),
+			Labels: map[string]string{
+				"remote_name": remote,
+				"url":         string(sample.Metric["url"]),
+			},
+			Metrics:     map[string]float64{"remote_write_lag_seconds": lag},
+			Hint:        "Check prometheus_remote_storage_samples_failed_total and shard counts for the queue",
+			RunbookURL:  models.RunbookBaseURL + "data_freshness.md",
+			BlastRadius: blastRadiusDataFreshness,
+		})
+	}
+
+	return problems, nil
+}
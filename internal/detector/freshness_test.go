@@ -0,0 +1,122 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestDataFreshnessDetector_StaleScrape(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			if !strings.Contains(query, "timestamp(up)") {
+				return model.Vector{}, nil
+			}
+			return model.Vector{
+				&model.Sample{Metric: model.Metric{"job": "node", "instance": "10.0.0.7:9100"}, Value: 240},
+			}, nil
+		},
+	}
+
+	d := NewDataFreshnessDetector()
+	problems, err := d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	if p.Severity != models.SeverityWarning {
+		t.Errorf("expected WARNING, got %v", p.Severity)
+	}
+	if p.Type != "data_freshness" || p.EntityType != "monitoring_target" {
+		t.Errorf("unexpected type %s/%s", p.EntityType, p.Type)
+	}
+	if p.Entity != "node/10.0.0.7:9100" {
+		t.Errorf("unexpected entity %q", p.Entity)
+	}
+	if p.Metrics["scrape_age_seconds"] != 240 {
+		t.Errorf("scrape_age_seconds = %g, want 240", p.Metrics["scrape_age_seconds"])
+	}
+}
+
+func TestDataFreshnessDetector_RemoteWriteLag(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			if !strings.Contains(query, "prometheus_remote_storage_highest_timestamp_in_seconds") {
+				return model.Vector{}, nil
+			}
+			return model.Vector{
+				&model.Sample{Metric: model.Metric{"remote_name": "mimir", "url": "https://mimir/api/v1/push"}, Value: 600},
+			}, nil
+		},
+	}
+
+	d := NewDataFreshnessDetector()
+	problems, err := d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+	p := problems[0]
+	if p.Entity != "remote_write/mimir" || p.EntityType != "prometheus_remote_write" {
+		t.Errorf("unexpected entity %s %q", p.EntityType, p.Entity)
+	}
+	if p.Metrics["remote_write_lag_seconds"] != 600 {
+		t.Errorf("remote_write_lag_seconds = %g, want 600", p.Metrics["remote_write_lag_seconds"])
+	}
+}
+
+func TestDataFreshnessDetector_Threshold(t *testing.T) {
+	var queries []string
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			queries = append(queries, query)
+			if !strings.Contains(query, "timestamp(up)") {
+				return model.Vector{}, nil
+			}
+			return model.Vector{
+				&model.Sample{Metric: model.Metric{"job": "node", "instance": "a"}, Value: 240},
+			}, nil
+		},
+	}
+
+	d := NewDataFreshnessDetector()
+	d.SetThreshold(300)
+	problems, err := d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("expected 240s lag under a 300s threshold to be ignored, got %d problems", len(problems))
+	}
+	for _, q := range queries {
+		if !strings.HasSuffix(q, "> 300.000000") {
+			t.Errorf("query does not use the threshold: %s", q)
+		}
+	}
+}
+
+func TestDataFreshnessDetector_ProviderError(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	}
+
+	d := NewDataFreshnessDetector()
+	if _, err := d.Detect(context.Background(), mockProvider, 5*time.Minute); err == nil {
+		t.Fatal("expected error when provider fails")
+	}
+}
@@ -14,6 +14,7 @@ const (
 	errorRateCheckInterval = 30 * time.Second
 	diskSpaceCheckInterval = 60 * time.Second
 	memoryCheckInterval    = 30 * time.Second
+	loadCheckInterval      = 30 * time.Second
 
 	// Error rate thresholds
 	errorRateThreshold = 0.05 // 5%
@@ -25,6 +26,11 @@ const (
 	// Memory pressure threshold (fraction of total)
 	memoryPressureThreshold = 0.90 // 90%
 
+	// Load average thresholds (5m load per CPU core). Critical is a
+	// multiple of the warning threshold so tuning one moves both.
+	loadPerCoreThreshold      = 1.5
+	loadPerCoreCriticalFactor = 2.0
+
 	// Blast radius estimates
 	blastRadiusService    = 5
 	blastRadiusFilesystem = 3
@@ -35,6 +41,7 @@ const (
 type HighErrorRateDetector struct {
 	interval  time.Duration
 	threshold float64
+	sustained time.Duration // Condition must hold this long (0 = instant)
 }
 
 func NewHighErrorRateDetector() *HighErrorRateDetector {
@@ -56,9 +63,22 @@ func (d *HighErrorRateDetector) Interval() time.Duration {
 	return d.interval
 }
 
+func (d *HighErrorRateDetector) Threshold() float64 {
+	return d.threshold
+}
+
+func (d *HighErrorRateDetector) SetThreshold(v float64) {
+	d.threshold = v
+}
+
+func (d *HighErrorRateDetector) SetSustainedWindow(window time.Duration) {
+	d.sustained = window
+}
+
 func (d *HighErrorRateDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
-	query := fmt.Sprintf(`(rate(http_requests_total{status=~"5.."}[5m]) / rate(http_requests_total[5m])) > %f`, d.threshold)
-	result, err := provider.QueryInstant(ctx, query, time.Now())
+	r := rangeSelector(window)
+	query := fmt.Sprintf(`(rate(http_requests_total{status=~"5.."}%s) / rate(http_requests_total%s))`, r, r)
+	result, err := queryAbove(ctx, provider, query, d.threshold, d.sustained)
 	if err != nil {
 		return nil, fmt.Errorf("error rate query failed: %w", err)
 	}
@@ -77,7 +97,6 @@ func (d *HighErrorRateDetector) Detect(ctx context.Context, provider metrics.Met
 
 		entity := service
 		problem := &models.Problem{
-			ID:         fmt.Sprintf("%s/high_error_rate", entity),
 			Entity:     entity,
 			EntityType: "service",
 			Type:       "high_error_rate",
@@ -90,7 +109,7 @@ func (d *HighErrorRateDetector) Detect(ctx context.Context, provider metrics.Met
 			Metrics: map[string]float64{
 				"error_rate": errorRate,
 			},
-			Hint:        fmt.Sprintf("5xx error rate above %.0f%% threshold", d.threshold*100),
+			Hint:        fmt.Sprintf("5xx error rate above %.0f%% threshold%s", d.threshold*100, sustainedSuffix(d.sustained)),
 			RunbookURL:  models.RunbookBaseURL + "high_error_rate.md",
 			BlastRadius: blastRadiusService,
 		}
@@ -155,7 +174,6 @@ func (d *DiskSpaceDetector) Detect(ctx context.Context, provider metrics.Metrics
 
 		entity := fmt.Sprintf("%s:%s", node, mountpoint)
 		problem := &models.Problem{
-			ID:         fmt.Sprintf("%s/disk_space", entity),
 			Entity:     entity,
 			EntityType: "filesystem",
 			Type:       "disk_full",
@@ -183,7 +201,8 @@ func (d *DiskSpaceDetector) Detect(ctx context.Context, provider metrics.Metrics
 // HighMemoryPressureDetector detects high memory pressure on nodes
 type HighMemoryPressureDetector struct {
 	interval  time.Duration
-	threshold float64 // Memory usage threshold (0.9 = 90%)
+	threshold float64       // Memory usage threshold (0.9 = 90%)
+	sustained time.Duration // Condition must hold this long (0 = instant)
 }
 
 func NewHighMemoryPressureDetector() *HighMemoryPressureDetector {
@@ -205,9 +224,21 @@ func (d *HighMemoryPressureDetector) Interval() time.Duration {
 	return d.interval
 }
 
+func (d *HighMemoryPressureDetector) Threshold() float64 {
+	return d.threshold
+}
+
+func (d *HighMemoryPressureDetector) SetThreshold(v float64) {
+	d.threshold = v
+}
+
+func (d *HighMemoryPressureDetector) SetSustainedWindow(window time.Duration) {
+	d.sustained = window
+}
+
 func (d *HighMemoryPressureDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
-	query := fmt.Sprintf(`(1 - (node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes)) > %f`, d.threshold)
-	result, err := provider.QueryInstant(ctx, query, time.Now())
+	query := `(1 - (node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes))`
+	result, err := queryAbove(ctx, provider, query, d.threshold, d.sustained)
 	if err != nil {
 		return nil, fmt.Errorf("memory pressure query failed: %w", err)
 	}
@@ -223,7 +254,6 @@ func (d *HighMemoryPressureDetector) Detect(ctx context.Context, provider metric
 
 		entity := node
 		problem := &models.Problem{
-			ID:         fmt.Sprintf("%s/memory_pressure", entity),
 			Entity:     entity,
 			EntityType: "node",
 			Type:       "high_memory",
@@ -236,7 +266,7 @@ func (d *HighMemoryPressureDetector) Detect(ctx context.Context, provider metric
 			Metrics: map[string]float64{
 				"memory_usage_percent": usagePercent,
 			},
-			Hint:        fmt.Sprintf("Memory pressure above %.0f%%", d.threshold*100),
+			Hint:        fmt.Sprintf("Memory pressure above %.0f%%%s", d.threshold*100, sustainedSuffix(d.sustained)),
 			RunbookURL:  models.RunbookBaseURL + "high_memory.md",
 			BlastRadius: blastRadiusNode,
 		}
@@ -245,3 +275,81 @@ func (d *HighMemoryPressureDetector) Detect(ctx context.Context, provider metric
 
 	return problems, nil
 }
+
+// HighLoadAverageDetector detects nodes under sustained CPU pressure: a 5m
+// load average well above the number of cores means runnable tasks are
+// queueing for CPU
+type HighLoadAverageDetector struct {
+	interval  time.Duration
+	threshold float64 // Load per core (1.5 = 50% more runnable tasks than cores)
+}
+
+func NewHighLoadAverageDetector() *HighLoadAverageDetector {
+	return &HighLoadAverageDetector{
+		interval:  loadCheckInterval,
+		threshold: loadPerCoreThreshold,
+	}
+}
+
+func (d *HighLoadAverageDetector) Name() string {
+	return "generic_load_average"
+}
+
+func (d *HighLoadAverageDetector) EntityTypes() []string {
+	return []string{"node"}
+}
+
+func (d *HighLoadAverageDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *HighLoadAverageDetector) Threshold() float64 {
+	return d.threshold
+}
+
+func (d *HighLoadAverageDetector) SetThreshold(v float64) {
+	d.threshold = v
+}
+
+func (d *HighLoadAverageDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	query := fmt.Sprintf(`node_load5 / on (instance) count by (instance) (node_cpu_seconds_total{mode="idle"}) > %f`, d.threshold)
+	result, err := provider.QueryInstant(ctx, query, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("load average query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0)
+	for _, sample := range result {
+		node := string(sample.Metric["instance"])
+		if node == "" {
+			node = "unknown"
+		}
+
+		loadPerCore := float64(sample.Value)
+		severity := models.SeverityWarning
+		if loadPerCore >= d.threshold*loadPerCoreCriticalFactor {
+			severity = models.SeverityCritical
+		}
+
+		problem := &models.Problem{
+			Entity:     node,
+			EntityType: "node",
+			Type:       "high_load",
+			Severity:   severity,
+			Title:      "High Load Average",
+			Message:    fmt.Sprintf("Node %s has a 5m load average of %.2f per CPU core", node, loadPerCore),
+			Labels: map[string]string{
+				"node": node,
+			},
+			Metrics: map[string]float64{
+				"load_per_core": loadPerCore,
+			},
+			Hint:        fmt.Sprintf("5m load above %.1f per core; critical above %.1f", d.threshold, d.threshold*loadPerCoreCriticalFactor),
+			RunbookURL:  models.RunbookBaseURL + "high_load.md",
+			BlastRadius: blastRadiusNode,
+		}
+		problems = append(problems, problem)
+	}
+
+	return problems, nil
+}
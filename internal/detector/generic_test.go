@@ -3,6 +3,7 @@ package detector
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,6 +24,7 @@ func TestDetectorMetadata(t *testing.T) {
 		{"high error rate", NewHighErrorRateDetector(), "generic_high_error_rate", 2, true},
 		{"disk space", NewDiskSpaceDetector(), "generic_disk_space", 2, true},
 		{"memory pressure", NewHighMemoryPressureDetector(), "generic_memory_pressure", 1, true},
+		{"load average", NewHighLoadAverageDetector(), "generic_load_average", 1, true},
 		{"oom kill", NewOOMKillDetector(), "kubernetes_oom_kills", 1, true},
 		{"crashloop", NewCrashLoopBackOffDetector(), "kubernetes_crashloop", 1, true},
 		{"imagepull", NewImagePullBackOffDetector(), "kubernetes_imagepull", 1, true},
@@ -235,3 +237,96 @@ func TestHighMemoryPressureDetector_NoProblems(t *testing.T) {
 		t.Fatalf("expected 0 problems, got %d", len(problems))
 	}
 }
+
+func TestHighLoadAverageDetector(t *testing.T) {
+	var gotQuery string
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			gotQuery = query
+			// The query only returns nodes over the threshold
+			return model.Vector{
+				&model.Sample{Metric: model.Metric{"instance": "node-1"}, Value: 1.8},
+				&model.Sample{Metric: model.Metric{"instance": "node-2"}, Value: 3.5},
+			}, nil
+		},
+	}
+
+	d := NewHighLoadAverageDetector()
+	problems, err := d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(gotQuery, `count by (instance) (node_cpu_seconds_total{mode="idle"}) > 1.5`) {
+		t.Errorf("query = %q, want load per core over the default threshold", gotQuery)
+	}
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %d", len(problems))
+	}
+
+	byNode := map[string]*models.Problem{}
+	for _, p := range problems {
+		byNode[p.Entity] = p
+	}
+	if p := byNode["node-1"]; p == nil || p.Severity != models.SeverityWarning || p.Type != "high_load" || p.EntityType != "node" {
+		t.Errorf("node-1 = %+v, want a WARNING high_load node problem", p)
+	}
+	if p := byNode["node-2"]; p == nil || p.Severity != models.SeverityCritical {
+		t.Errorf("node-2 = %+v, want CRITICAL at twice the threshold", p)
+	}
+	if got := byNode["node-2"].Metrics["load_per_core"]; got != 3.5 {
+		t.Errorf("load_per_core = %v, want 3.5", got)
+	}
+}
+
+func TestHighLoadAverageDetector_NormalNode(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{}, nil // Every node under the threshold
+		},
+	}
+
+	problems, err := NewHighLoadAverageDetector().Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("expected 0 problems, got %d", len(problems))
+	}
+}
+
+func TestHighLoadAverageDetector_Threshold(t *testing.T) {
+	var gotQuery string
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			gotQuery = query
+			return model.Vector{
+				&model.Sample{Metric: model.Metric{"instance": "node-1"}, Value: 4.5},
+			}, nil
+		},
+	}
+
+	d := NewHighLoadAverageDetector()
+	d.SetThreshold(3)
+	problems, err := d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(gotQuery, "> 3.000000") {
+		t.Errorf("query = %q, want the configured threshold", gotQuery)
+	}
+	if len(problems) != 1 || problems[0].Severity != models.SeverityWarning {
+		t.Errorf("problems = %+v, want one WARNING below twice the configured threshold", problems)
+	}
+}
+
+func TestHighLoadAverageDetector_ProviderError(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	}
+
+	if _, err := NewHighLoadAverageDetector().Detect(context.Background(), mockProvider, 5*time.Minute); err == nil {
+		t.Fatal("expected error when provider fails")
+	}
+}
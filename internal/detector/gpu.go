@@ -0,0 +1,180 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+const (
+	gpuCheckInterval = 30 * time.Second
+
+	// Framebuffer memory in use (0.99 = 99%); at this point the next
+	// allocation on the device fails with an out-of-memory error
+	gpuMemoryThreshold = 0.99
+
+	// Share of the detection window the GPU spent thermally throttled
+	// (0.05 = 5%)
+	gpuThermalThrottleThreshold = 0.05
+
+	// A GPU fault takes down every job scheduled on the device
+	blastRadiusGPU = 3
+
+	// DCGM_FI_DEV_XID_ERRORS holds the code of the last XID error the
+	// driver reported for the device, 0 if there was none
+	gpuXIDQuery = `DCGM_FI_DEV_XID_ERRORS > 0`
+
+	gpuMemoryQuery = `DCGM_FI_DEV_FB_USED / (DCGM_FI_DEV_FB_USED + DCGM_FI_DEV_FB_FREE)`
+)
+
+// gpuThermalQuery returns GPUs that spent more than the threshold share of
+// window thermally throttled. DCGM_FI_DEV_THERMAL_VIOLATION counts
+// microseconds of throttling.
+func gpuThermalQuery(window time.Duration) string {
+	return fmt.Sprintf(`rate(DCGM_FI_DEV_THERMAL_VIOLATION%s) / 1e6 > %g`, rangeSelector(window), gpuThermalThrottleThreshold)
+}
+
+// GPUHealthDetector detects GPUs reporting XID hardware errors, GPUs out of
+// framebuffer memory and GPUs held back by thermal throttling, from the
+// metrics NVIDIA's DCGM exporter publishes. It only runs when enabled, since
+// most clusters have no GPUs.
+type GPUHealthDetector struct {
+	interval  time.Duration
+	threshold float64 // Framebuffer memory usage threshold (0.99 = 99%)
+}
+
+func NewGPUHealthDetector() *GPUHealthDetector {
+	return &GPUHealthDetector{
+		interval:  gpuCheckInterval,
+		threshold: gpuMemoryThreshold,
+	}
+}
+
+func (d *GPUHealthDetector) Name() string {
+	return "gpu_health"
+}
+
+func (d *GPUHealthDetector) EntityTypes() []string {
+	return []string{"gpu"}
+}
+
+func (d *GPUHealthDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *GPUHealthDetector) Threshold() float64 {
+	return d.threshold
+}
+
+func (d *GPUHealthDetector) SetThreshold(v float64) {
+	d.threshold = v
+}
+
+func (d *GPUHealthDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	now := time.Now()
+	xid, err := provider.QueryInstant(ctx, gpuXIDQuery, now)
+	if err != nil {
+		return nil, fmt.Errorf("gpu xid query failed: %w", err)
+	}
+	memory, err := queryAbove(ctx, provider, gpuMemoryQuery, d.threshold, 0)
+	if err != nil {
+		return nil, fmt.Errorf("gpu memory query failed: %w", err)
+	}
+	thermal, err := provider.QueryInstant(ctx, gpuThermalQuery(window), now)
+	if err != nil {
+		return nil, fmt.Errorf("gpu thermal throttling query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0)
+	for _, sample := range xid {
+		entity, labels := gpuEntity(sample.Metric)
+		code := float64(sample.Value)
+		problems = append(problems, &models.Problem{
+			Entity:      entity,
+			EntityType:  "gpu",
+			Type:        "gpu_xid_error",
+			Severity:    models.SeverityCritical,
+			Title:       "GPU XID error",
+			Message:     fmt.Sprintf("GPU %s reported XID error %.0f", entity, code),
+			Labels:      labels,
+			Metrics:     map[string]float64{"xid": code},
+			Hint:        "Look the code up in NVIDIA's XID catalog: 48, 63, 64, 74, 79 and 94/95 point at failing hardware, drain the node",
+			RunbookURL:  models.RunbookBaseURL + "gpu_xid_error.md",
+			BlastRadius: blastRadiusGPU,
+		})
+	}
+	for _, sample := range memory {
+		entity, labels := gpuEntity(sample.Metric)
+		usagePercent := float64(sample.Value) * 100
+		problems = append(problems, &models.Problem{
+			Entity:      entity,
+			EntityType:  "gpu",
+			Type:        "gpu_memory_full",
+			Severity:    models.SeverityWarning,
+			Title:       "GPU memory full",
+			Message:     fmt.Sprintf("GPU %s has %.1f%% framebuffer memory in use", entity, usagePercent),
+			Labels:      labels,
+			Metrics:     map[string]float64{"memory_usage_percent": usagePercent},
+			Hint:        fmt.Sprintf("Framebuffer usage above %.0f%%: new allocations on this GPU will fail with CUDA out of memory", d.threshold*100),
+			RunbookURL:  models.RunbookBaseURL + "gpu_memory_full.md",
+			BlastRadius: blastRadiusGPU,
+		})
+	}
+	for _, sample := range thermal {
+		entity, labels := gpuEntity(sample.Metric)
+		throttledPercent := float64(sample.Value) * 100
+		problems = append(problems, &models.Problem{
+			Entity:      entity,
+			EntityType:  "gpu",
+			Type:        "gpu_thermal_throttling",
+			Severity:    models.SeverityWarning,
+			Title:       "GPU thermal throttling",
+			Message:     fmt.Sprintf("GPU %s was thermally throttled %.0f%% of the last %s", entity, throttledPercent, windowLabel(window)),
+			Labels:      labels,
+			Metrics:     map[string]float64{"thermal_throttled_percent": throttledPercent},
+			Hint:        "Throttled GPUs run at reduced clocks: check fans, airflow and DCGM_FI_DEV_GPU_TEMP on the node",
+			RunbookURL:  models.RunbookBaseURL + "gpu_thermal_throttling.md",
+			BlastRadius: blastRadiusGPU,
+		})
+	}
+
+	return problems, nil
+}
+
+// gpuEntity names a GPU <node>/gpu<index> from DCGM exporter labels, and
+// returns the labels worth keeping on the problem. The exporter reports the
+// node as Hostname; relabelled setups often carry node or instance instead.
+func gpuEntity(metric model.Metric) (string, map[string]string) {
+	node := string(metric["Hostname"])
+	for _, name := range []model.LabelName{"node", "instance"} {
+		if node != "" {
+			break
+		}
+		node = string(metric[name])
+	}
+	if node == "" {
+		node = "unknown"
+	}
+	gpu := string(metric["gpu"])
+	if gpu == "" {
+		gpu = "unknown"
+	}
+
+	labels := map[string]string{"node": node, "gpu": gpu}
+	for label, name := range map[string]model.LabelName{
+		"uuid":      "UUID",
+		"model":     "modelName",
+		"namespace": "namespace",
+		"pod":       "pod",
+	} {
+		if v := string(metric[name]); v != "" {
+			labels[label] = v
+		}
+	}
+	return node + "/gpu" + gpu, labels
+}
@@ -0,0 +1,130 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestGPUHealthDetector_XIDError(t *testing.T) {
+	provider := etcdProvider(map[string]model.Vector{
+		gpuXIDQuery: {
+			&model.Sample{Metric: model.Metric{
+				"Hostname":  "gpu-node-1",
+				"gpu":       "3",
+				"UUID":      "GPU-5fd4a1c2",
+				"modelName": "NVIDIA A100-SXM4-80GB",
+				"namespace": "ml",
+				"pod":       "trainer-0",
+			}, Value: 79},
+		},
+	})
+
+	problems, err := NewGPUHealthDetector().Detect(context.Background(), provider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	if p.Type != "gpu_xid_error" || p.EntityType != "gpu" {
+		t.Errorf("unexpected type %s/%s", p.EntityType, p.Type)
+	}
+	if p.Severity != models.SeverityCritical {
+		t.Errorf("expected CRITICAL, got %v", p.Severity)
+	}
+	if p.Entity != "gpu-node-1/gpu3" {
+		t.Errorf("unexpected entity %q", p.Entity)
+	}
+	if p.Metrics["xid"] != 79 || p.Message != "GPU gpu-node-1/gpu3 reported XID error 79" {
+		t.Errorf("metrics %v message %q", p.Metrics, p.Message)
+	}
+	want := map[string]string{
+		"node": "gpu-node-1", "gpu": "3", "uuid": "GPU-5fd4a1c2",
+		"model": "NVIDIA A100-SXM4-80GB", "namespace": "ml", "pod": "trainer-0",
+	}
+	for k, v := range want {
+		if p.Labels[k] != v {
+			t.Errorf("label %s = %q, want %q", k, p.Labels[k], v)
+		}
+	}
+}
+
+func TestGPUHealthDetector_HighMemory(t *testing.T) {
+	memoryQuery := fmt.Sprintf("%s > %f", gpuMemoryQuery, gpuMemoryThreshold)
+	provider := etcdProvider(map[string]model.Vector{
+		memoryQuery: {
+			&model.Sample{Metric: model.Metric{"instance": "10.0.3.7:9400", "gpu": "0"}, Value: 0.995},
+		},
+	})
+
+	problems, err := NewGPUHealthDetector().Detect(context.Background(), provider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	if p.Type != "gpu_memory_full" || p.Severity != models.SeverityWarning {
+		t.Errorf("got %s %s, want WARNING gpu_memory_full", p.Severity, p.Type)
+	}
+	if p.Entity != "10.0.3.7:9400/gpu0" {
+		t.Errorf("unexpected entity %q", p.Entity)
+	}
+	if p.Message != "GPU 10.0.3.7:9400/gpu0 has 99.5% framebuffer memory in use" {
+		t.Errorf("unexpected message %q", p.Message)
+	}
+}
+
+func TestGPUHealthDetector_MemoryThresholdIsTunable(t *testing.T) {
+	var queried []string
+	provider := etcdProvider(nil)
+	provider.QueryInstantFunc = func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+		queried = append(queried, query)
+		return nil, nil
+	}
+
+	d := NewGPUHealthDetector()
+	var tunable Tunable = d
+	tunable.SetThreshold(0.9)
+	if _, err := d.Detect(context.Background(), provider, 5*time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := fmt.Sprintf("%s > %f", gpuMemoryQuery, 0.9)
+	found := false
+	for _, q := range queried {
+		found = found || q == want
+	}
+	if !found {
+		t.Errorf("queries %v do not include %q", queried, want)
+	}
+}
+
+func TestGPUHealthDetector_ThermalThrottling(t *testing.T) {
+	provider := etcdProvider(map[string]model.Vector{
+		gpuThermalQuery(10 * time.Minute): {
+			&model.Sample{Metric: model.Metric{"node": "gpu-node-2", "gpu": "1"}, Value: 0.25},
+		},
+	})
+
+	problems, err := NewGPUHealthDetector().Detect(context.Background(), provider, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+	if p := problems[0]; p.Type != "gpu_thermal_throttling" || p.Message != "GPU gpu-node-2/gpu1 was thermally throttled 25% of the last 10m" {
+		t.Errorf("got %s %q", p.Type, p.Message)
+	}
+}
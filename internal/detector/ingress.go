@@ -0,0 +1,154 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+const (
+	ingressErrorRateCheckInterval = 30 * time.Second
+
+	// Fraction of requests answered with a 5xx at the edge
+	ingressErrorRateThreshold = 0.05 // 5%
+
+	// Every client reaching the host through the ingress sees the errors
+	blastRadiusIngress = 10
+)
+
+// IngressMetrics names the request counter and its labels. Defaults match
+// ingress-nginx; other controllers exporting a per-host request counter with
+// a status label can be used by overriding them.
+type IngressMetrics struct {
+	Requests       string // Request counter, e.g. nginx_ingress_controller_requests
+	StatusLabel    string // HTTP status code
+	HostLabel      string // Requested host
+	IngressLabel   string // Ingress object name
+	NamespaceLabel string // Ingress namespace
+}
+
+// DefaultIngressMetrics returns the ingress-nginx metric and label names
+func DefaultIngressMetrics() IngressMetrics {
+	return IngressMetrics{
+		Requests:       "nginx_ingress_controller_requests",
+		StatusLabel:    "status",
+		HostLabel:      "host",
+		IngressLabel:   "ingress",
+		NamespaceLabel: "namespace",
+	}
+}
+
+// IngressErrorRateDetector detects hosts whose ingress answers a high share
+// of requests with 5xx. Edge errors include upstreams that are down or
+// unreachable, which never show up in the services' own request metrics.
+type IngressErrorRateDetector struct {
+	interval  time.Duration
+	threshold float64
+	sustained time.Duration // Condition must hold this long (0 = instant)
+	metrics   IngressMetrics
+}
+
+func NewIngressErrorRateDetector() *IngressErrorRateDetector {
+	return &IngressErrorRateDetector{
+		interval:  ingressErrorRateCheckInterval,
+		threshold: ingressErrorRateThreshold,
+		metrics:   DefaultIngressMetrics(),
+	}
+}
+
+func (d *IngressErrorRateDetector) Name() string {
+	return "ingress_error_rate"
+}
+
+func (d *IngressErrorRateDetector) EntityTypes() []string {
+	return []string{"ingress"}
+}
+
+func (d *IngressErrorRateDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *IngressErrorRateDetector) Threshold() float64 {
+	return d.threshold
+}
+
+func (d *IngressErrorRateDetector) SetThreshold(v float64) {
+	d.threshold = v
+}
+
+func (d *IngressErrorRateDetector) SetSustainedWindow(window time.Duration) {
+	d.sustained = window
+}
+
+// SetMetrics overrides the metric and label names. Empty fields keep their
+// current value.
+func (d *IngressErrorRateDetector) SetMetrics(m IngressMetrics) {
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(&d.metrics.Requests, m.Requests)
+	set(&d.metrics.StatusLabel, m.StatusLabel)
+	set(&d.metrics.HostLabel, m.HostLabel)
+	set(&d.metrics.IngressLabel, m.IngressLabel)
+	set(&d.metrics.NamespaceLabel, m.NamespaceLabel)
+}
+
+// query returns the 5xx share per host and ingress over window
+func (d *IngressErrorRateDetector) query(window time.Duration) string {
+	m := d.metrics
+	by := fmt.Sprintf("%s, %s, %s", m.NamespaceLabel, m.IngressLabel, m.HostLabel)
+	r := rangeSelector(window)
+	return fmt.Sprintf(`(sum by (%s) (rate(%s{%s=~"5.."}%s)) / sum by (%s) (rate(%s%s)))`,
+		by, m.Requests, m.StatusLabel, r, by, m.Requests, r)
+}
+
+func (d *IngressErrorRateDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	result, err := queryAbove(ctx, provider, d.query(window), d.threshold, d.sustained)
+	if err != nil {
+		return nil, fmt.Errorf("ingress error rate query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0, len(result))
+	for _, sample := range result {
+		if float64(sample.Value) <= d.threshold {
+			continue
+		}
+		host := string(sample.Metric[model.LabelName(d.metrics.HostLabel)])
+		if host == "" {
+			host = "*" // Ingress rule without a host
+		}
+		ingress := string(sample.Metric[model.LabelName(d.metrics.IngressLabel)])
+		namespace := string(sample.Metric[model.LabelName(d.metrics.NamespaceLabel)])
+
+		errorRate := float64(sample.Value) * 100 // Convert to percentage
+
+		problems = append(problems, &models.Problem{
+			Entity:     host + "/" + ingress,
+			EntityType: "ingress",
+			Type:       "ingress_error_rate",
+			Severity:   models.SeverityCritical,
+			Title:      "High Ingress Error Rate",
+			Message:    fmt.Sprintf("Ingress %s/%s answers %.2f%% of requests for %s with 5xx", namespace, ingress, errorRate, host),
+			Labels: map[string]string{
+				"namespace": namespace,
+				"ingress":   ingress,
+				"host":      host,
+			},
+			Metrics: map[string]float64{
+				"error_rate": errorRate,
+			},
+			Hint:        fmt.Sprintf("Edge 5xx rate above %.0f%% threshold%s; check the ingress backends' endpoints and controller logs", d.threshold*100, sustainedSuffix(d.sustained)),
+			RunbookURL:  models.RunbookBaseURL + "ingress_error_rate.md",
+			BlastRadius: blastRadiusIngress,
+		})
+	}
+
+	return problems, nil
+}
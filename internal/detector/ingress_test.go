@@ -0,0 +1,146 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func ingressProvider(rate float64, queries *[]string) *metrics.MockProvider {
+	return &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			if queries != nil {
+				*queries = append(*queries, query)
+			}
+			return model.Vector{
+				&model.Sample{
+					Metric: model.Metric{"namespace": "shop", "ingress": "storefront", "host": "shop.example.com"},
+					Value:  model.SampleValue(rate),
+				},
+			}, nil
+		},
+	}
+}
+
+func TestIngressErrorRateDetector_AboveThreshold(t *testing.T) {
+	d := NewIngressErrorRateDetector()
+	problems, err := d.Detect(context.Background(), ingressProvider(0.12, nil), 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	if p.Severity != models.SeverityCritical {
+		t.Errorf("expected CRITICAL, got %v", p.Severity)
+	}
+	if p.Type != "ingress_error_rate" || p.EntityType != "ingress" {
+		t.Errorf("unexpected type %s/%s", p.EntityType, p.Type)
+	}
+	if p.Entity != "shop.example.com/storefront" {
+		t.Errorf("unexpected entity %q", p.Entity)
+	}
+	if p.Labels["namespace"] != "shop" {
+		t.Errorf("namespace label = %q, want shop", p.Labels["namespace"])
+	}
+	if p.Metrics["error_rate"] != 12 {
+		t.Errorf("error_rate = %g, want 12", p.Metrics["error_rate"])
+	}
+}
+
+func TestIngressErrorRateDetector_BelowThreshold(t *testing.T) {
+	d := NewIngressErrorRateDetector()
+	problems, err := d.Detect(context.Background(), ingressProvider(0.03, nil), 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("expected a 3%% error rate under the 5%% threshold to be ignored, got %d problems", len(problems))
+	}
+
+	d.SetThreshold(0.02)
+	problems, err = d.Detect(context.Background(), ingressProvider(0.03, nil), 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Errorf("expected 1 problem above a 2%% threshold, got %d", len(problems))
+	}
+}
+
+func TestIngressErrorRateDetector_Query(t *testing.T) {
+	var queries []string
+	d := NewIngressErrorRateDetector()
+	if _, err := d.Detect(context.Background(), ingressProvider(0.5, &queries), 5*time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `(sum by (namespace, ingress, host) (rate(nginx_ingress_controller_requests{status=~"5.."}[5m])) / sum by (namespace, ingress, host) (rate(nginx_ingress_controller_requests[5m]))) > 0.050000`
+	if len(queries) != 1 || queries[0] != want {
+		t.Errorf("query = %v, want %s", queries, want)
+	}
+}
+
+func TestIngressErrorRateDetector_CustomMetrics(t *testing.T) {
+	var queries []string
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			queries = append(queries, query)
+			return model.Vector{
+				&model.Sample{
+					Metric: model.Metric{"exported_namespace": "shop", "ingress": "storefront", "server_name": ""},
+					Value:  0.2,
+				},
+			}, nil
+		},
+	}
+
+	d := NewIngressErrorRateDetector()
+	d.SetMetrics(IngressMetrics{
+		Requests:       "edge_requests_total",
+		StatusLabel:    "code",
+		HostLabel:      "server_name",
+		NamespaceLabel: "exported_namespace",
+	})
+	problems, err := d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	q := queries[0]
+	for _, want := range []string{`edge_requests_total{code=~"5.."}`, "sum by (exported_namespace, ingress, server_name)"} {
+		if !strings.Contains(q, want) {
+			t.Errorf("query %s does not contain %s", q, want)
+		}
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+	if problems[0].Entity != "*/storefront" {
+		t.Errorf("entity = %q, want */storefront for an ingress without host", problems[0].Entity)
+	}
+	if problems[0].Labels["namespace"] != "shop" {
+		t.Errorf("namespace label = %q, want shop", problems[0].Labels["namespace"])
+	}
+}
+
+func TestIngressErrorRateDetector_ProviderError(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	}
+
+	d := NewIngressErrorRateDetector()
+	if _, err := d.Detect(context.Background(), mockProvider, 5*time.Minute); err == nil {
+		t.Fatal("expected error when provider fails")
+	}
+}
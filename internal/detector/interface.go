@@ -16,9 +16,17 @@ type Detector interface {
 	// EntityTypes returns which entity types this detector handles
 	EntityTypes() []string
 
-	// Detect runs detection logic and returns problems found
+	// Detect runs detection logic and returns problems found. Problem IDs
+	// are left empty: the watcher derives them with models.StableID.
 	Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error)
 
 	// Interval returns how often this detector should run
 	Interval() time.Duration
 }
+
+// Tunable is implemented by detectors with a single threshold that can be
+// overridden from the config file
+type Tunable interface {
+	Threshold() float64
+	SetThreshold(v float64)
+}
@@ -3,6 +3,7 @@ package detector
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/ppiankov/infranow/internal/metrics"
@@ -17,8 +18,56 @@ const (
 
 	// Seconds a pod must be pending before flagging
 	podPendingThresholdSeconds = 300 // 5 minutes
+
+	// Missing healthy pods at which a PDB deficit escalates to CRITICAL
+	pdbDeficitCritical = 3
+
+	// ResourceQuota utilization thresholds (fraction of hard limit)
+	quotaWarningThreshold  = 0.85 // 85%
+	quotaCriticalThreshold = 0.95 // 95%
+
+	// Blast radius for namespace-wide issues
+	blastRadiusNamespace = 5
+
+	// Container restarts per hour before flagging
+	restartRateThreshold = 3.0
+
+	// Window the restart rate is measured over
+	restartRateWindow = 15 * time.Minute
+
+	// Seconds a pod may stay Terminating before flagging
+	podTerminatingThresholdSeconds = 600 // 10 minutes
+
+	// Node pressure and network conditions that are currently true
+	nodeConditionQuery = `kube_node_status_condition{condition=~"MemoryPressure|DiskPressure|PIDPressure|NetworkUnavailable",status="true"} == 1`
+
+	// Deployments whose controller gave up progressing, i.e. the rollout
+	// exceeded progressDeadlineSeconds
+	rolloutStuckQuery = `kube_deployment_status_condition{condition="Progressing",status="false"} == 1`
+
+	// Blast radius for a deployment's replicas
+	blastRadiusDeployment = 3
+
+	// Pods per namespace before flagging sprawl
+	podSprawlThreshold = 1000
+
+	// Aggregated APIServices the kube-apiserver cannot reach. Every apiserver
+	// replica reports the gauge, so take the max per APIService.
+	apiServiceUnavailableQuery = `max by (name) (aggregator_unavailable_apiservice) > 0`
+
+	// Blast radius for an unavailable aggregated API (kubectl, HPA, namespace
+	// deletion all depend on discovery)
+	blastRadiusAPIService = 15
 )
 
+// nodeConditionHints explains the usual cause of each node condition
+var nodeConditionHints = map[string]string{
+	"MemoryPressure":     "Node is low on memory; the kubelet is evicting pods",
+	"DiskPressure":       "Node root or image filesystem is nearly full; the kubelet is evicting pods and pruning images",
+	"PIDPressure":        "Node is running out of process IDs; look for pods leaking processes",
+	"NetworkUnavailable": "Node network is not configured; check the CNI plugin on this node",
+}
+
 // OOMKillDetector detects containers that have been OOM killed
 type OOMKillDetector struct {
 	interval time.Duration
@@ -43,7 +92,7 @@ func (d *OOMKillDetector) Interval() time.Duration {
 }
 
 func (d *OOMKillDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
-	query := `increase(kube_pod_container_status_restarts_total{reason="OOMKilled"}[5m]) > 0`
+	query := fmt.Sprintf(`increase(kube_pod_container_status_restarts_total{reason="OOMKilled"}%s) > 0`, rangeSelector(window))
 	result, err := provider.QueryInstant(ctx, query, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("oom kill query failed: %w", err)
@@ -57,7 +106,6 @@ func (d *OOMKillDetector) Detect(ctx context.Context, provider metrics.MetricsPr
 
 		entity := fmt.Sprintf("%s/%s/%s", namespace, pod, container)
 		problem := &models.Problem{
-			ID:         fmt.Sprintf("%s/oomkill", entity),
 			Entity:     entity,
 			EntityType: "kubernetes_pod",
 			Type:       "oom_kill",
@@ -120,7 +168,6 @@ func (d *CrashLoopBackOffDetector) Detect(ctx context.Context, provider metrics.
 
 		entity := fmt.Sprintf("%s/%s/%s", namespace, pod, container)
 		problem := &models.Problem{
-			ID:         fmt.Sprintf("%s/crashloop", entity),
 			Entity:     entity,
 			EntityType: "kubernetes_pod",
 			Type:       "crashloopbackoff",
@@ -183,7 +230,6 @@ func (d *ImagePullBackOffDetector) Detect(ctx context.Context, provider metrics.
 
 		entity := fmt.Sprintf("%s/%s/%s", namespace, pod, container)
 		problem := &models.Problem{
-			ID:         fmt.Sprintf("%s/imagepull", entity),
 			Entity:     entity,
 			EntityType: "kubernetes_pod",
 			Type:       "imagepullbackoff",
@@ -252,7 +298,6 @@ func (d *PodPendingDetector) Detect(ctx context.Context, provider metrics.Metric
 
 		entity := fmt.Sprintf("%s/%s", namespace, pod)
 		problem := &models.Problem{
-			ID:         fmt.Sprintf("%s/pending", entity),
 			Entity:     entity,
 			EntityType: "kubernetes_pod",
 			Type:       "pending",
@@ -275,3 +320,569 @@ func (d *PodPendingDetector) Detect(ctx context.Context, provider metrics.Metric
 
 	return problems, nil
 }
+
+// PDBViolationDetector detects PodDisruptionBudgets with fewer healthy pods
+// than expected, which block node drains
+type PDBViolationDetector struct {
+	interval time.Duration
+}
+
+func NewPDBViolationDetector() *PDBViolationDetector {
+	return &PDBViolationDetector{
+		interval: kubeDetectorInterval,
+	}
+}
+
+func (d *PDBViolationDetector) Name() string {
+	return "kubernetes_pdb_violation"
+}
+
+func (d *PDBViolationDetector) EntityTypes() []string {
+	return []string{"kubernetes_pdb"}
+}
+
+func (d *PDBViolationDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *PDBViolationDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	query := `kube_poddisruptionbudget_status_expected_pods - kube_poddisruptionbudget_status_current_healthy > 0`
+	result, err := provider.QueryInstant(ctx, query, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("pdb violation query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0)
+	for _, sample := range result {
+		deficit := float64(sample.Value)
+		if deficit <= 0 {
+			continue
+		}
+
+		namespace := string(sample.Metric["namespace"])
+		pdb := string(sample.Metric["poddisruptionbudget"])
+
+		severity := models.SeverityWarning
+		if deficit >= pdbDeficitCritical {
+			severity = models.SeverityCritical
+		}
+
+		entity := fmt.Sprintf("%s/%s", namespace, pdb)
+		problem := &models.Problem{
+			Entity:     entity,
+			EntityType: "kubernetes_pdb",
+			Type:       "pdb_violation",
+			Severity:   severity,
+			Title:      "PodDisruptionBudget Violated",
+			Message:    fmt.Sprintf("PDB %s/%s is missing %.0f healthy pods; node drains will block", namespace, pdb, deficit),
+			Labels: map[string]string{
+				"namespace":           namespace,
+				"poddisruptionbudget": pdb,
+			},
+			Metrics: map[string]float64{
+				"unhealthy_pods": deficit,
+			},
+			Hint:        "Fix the unhealthy pods covered by the PDB before draining nodes",
+			RunbookURL:  models.RunbookBaseURL + "pdb_violation.md",
+			BlastRadius: blastRadiusPod,
+		}
+		problems = append(problems, problem)
+	}
+
+	return problems, nil
+}
+
+// ResourceQuotaDetector detects namespaces close to a ResourceQuota hard limit,
+// where new pods will fail admission
+type ResourceQuotaDetector struct {
+	interval          time.Duration
+	warningThreshold  float64 // Fraction of hard limit used (0.85 = 85%)
+	criticalThreshold float64 // Fraction of hard limit used (0.95 = 95%)
+}
+
+func NewResourceQuotaDetector() *ResourceQuotaDetector {
+	return &ResourceQuotaDetector{
+		interval:          kubeDetectorInterval,
+		warningThreshold:  quotaWarningThreshold,
+		criticalThreshold: quotaCriticalThreshold,
+	}
+}
+
+func (d *ResourceQuotaDetector) Name() string {
+	return "kubernetes_resource_quota"
+}
+
+func (d *ResourceQuotaDetector) EntityTypes() []string {
+	return []string{"kubernetes_namespace"}
+}
+
+func (d *ResourceQuotaDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *ResourceQuotaDetector) Threshold() float64 {
+	return d.warningThreshold
+}
+
+func (d *ResourceQuotaDetector) SetThreshold(v float64) {
+	d.warningThreshold = v
+}
+
+func (d *ResourceQuotaDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	query := fmt.Sprintf(`kube_resourcequota{type="used"} / ignoring(type) kube_resourcequota{type="hard"} > %f`, d.warningThreshold)
+	result, err := provider.QueryInstant(ctx, query, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("resource quota query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0)
+	for _, sample := range result {
+		utilization := float64(sample.Value)
+		// A zero hard limit divides to +Inf; nothing can be scheduled anyway
+		if math.IsInf(utilization, 0) || math.IsNaN(utilization) {
+			continue
+		}
+
+		namespace := string(sample.Metric["namespace"])
+		quota := string(sample.Metric["resourcequota"])
+		resource := string(sample.Metric["resource"])
+
+		severity := models.SeverityWarning
+		if utilization >= d.criticalThreshold {
+			severity = models.SeverityCritical
+		}
+
+		usagePercent := utilization * 100
+		entity := namespace
+		problem := &models.Problem{
+			Entity:     entity,
+			EntityType: "kubernetes_namespace",
+			Type:       "resource_quota",
+			Severity:   severity,
+			Title:      "ResourceQuota Near Limit",
+			Message:    fmt.Sprintf("Namespace %s has used %.1f%% of its %s quota (%s)", namespace, usagePercent, resource, quota),
+			Labels: map[string]string{
+				"namespace":     namespace,
+				"resourcequota": quota,
+				"resource":      resource,
+			},
+			Metrics: map[string]float64{
+				"usage_percent": usagePercent,
+			},
+			Hint:        fmt.Sprintf("Quota usage above %.0f%%; new pods will fail admission at 100%%", d.warningThreshold*100),
+			RunbookURL:  models.RunbookBaseURL + "resource_quota.md",
+			BlastRadius: blastRadiusNamespace,
+		}
+		problems = append(problems, problem)
+	}
+
+	return problems, nil
+}
+
+// RestartRateDetector detects containers restarting frequently before the
+// kubelet backs them off into CrashLoopBackOff
+type RestartRateDetector struct {
+	interval  time.Duration
+	threshold float64 // Restarts per hour
+}
+
+func NewRestartRateDetector() *RestartRateDetector {
+	return &RestartRateDetector{
+		interval:  kubeDetectorInterval,
+		threshold: restartRateThreshold,
+	}
+}
+
+func (d *RestartRateDetector) Name() string {
+	return "kubernetes_restart_rate"
+}
+
+func (d *RestartRateDetector) EntityTypes() []string {
+	return []string{"kubernetes_pod"}
+}
+
+func (d *RestartRateDetector) Interval() time.Duration {
+	return d.interval
+}
+
+// DefaultWindow is longer than usual: a per-hour rate extrapolated from 5
+// minutes fires on a single restart
+func (d *RestartRateDetector) DefaultWindow() time.Duration {
+	return restartRateWindow
+}
+
+func (d *RestartRateDetector) Threshold() float64 {
+	return d.threshold
+}
+
+func (d *RestartRateDetector) SetThreshold(v float64) {
+	d.threshold = v
+}
+
+func (d *RestartRateDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	query := fmt.Sprintf(`rate(kube_pod_container_status_restarts_total%s) * 3600 > %f`, rangeSelector(window), d.threshold)
+	result, err := provider.QueryInstant(ctx, query, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("restart rate query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0)
+	for _, sample := range result {
+		restartsPerHour := float64(sample.Value)
+		if restartsPerHour <= d.threshold {
+			continue
+		}
+
+		namespace := string(sample.Metric["namespace"])
+		pod := string(sample.Metric["pod"])
+		container := string(sample.Metric["container"])
+
+		entity := fmt.Sprintf("%s/%s/%s", namespace, pod, container)
+		problem := &models.Problem{
+			Entity:     entity,
+			EntityType: "kubernetes_pod",
+			Type:       "restart_rate",
+			Severity:   models.SeverityWarning,
+			Title:      "Frequent Container Restarts",
+			Message:    fmt.Sprintf("Container %s in pod %s/%s is restarting %.1f times/hour", container, namespace, pod, restartsPerHour),
+			Labels: map[string]string{
+				"namespace": namespace,
+				"pod":       pod,
+				"container": container,
+			},
+			Metrics: map[string]float64{
+				"restarts_per_hour": restartsPerHour,
+			},
+			Hint:        fmt.Sprintf("Restarting above %.0f/hour; likely heading for CrashLoopBackOff", d.threshold),
+			RunbookURL:  models.RunbookBaseURL + "restart_rate.md",
+			BlastRadius: blastRadiusPod,
+		}
+		problems = append(problems, problem)
+	}
+
+	return problems, nil
+}
+
+// PodTerminatingDetector detects pods stuck in Terminating, typically held by
+// finalizers or an unreachable node
+type PodTerminatingDetector struct {
+	interval time.Duration
+}
+
+func NewPodTerminatingDetector() *PodTerminatingDetector {
+	return &PodTerminatingDetector{
+		interval: kubeDetectorInterval,
+	}
+}
+
+func (d *PodTerminatingDetector) Name() string {
+	return "kubernetes_pod_terminating"
+}
+
+func (d *PodTerminatingDetector) EntityTypes() []string {
+	return []string{"kubernetes_pod"}
+}
+
+func (d *PodTerminatingDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *PodTerminatingDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	// kube_pod_deletion_timestamp only exists for pods with a deletion timestamp
+	// set, i.e. pods that are Terminating; the value is age since deletion
+	query := fmt.Sprintf(`(time() - kube_pod_deletion_timestamp) > %d`, podTerminatingThresholdSeconds)
+	result, err := provider.QueryInstant(ctx, query, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("terminating pod query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0)
+	for _, sample := range result {
+		terminatingSeconds := float64(sample.Value)
+		if terminatingSeconds <= podTerminatingThresholdSeconds {
+			continue
+		}
+
+		namespace := string(sample.Metric["namespace"])
+		pod := string(sample.Metric["pod"])
+
+		entity := fmt.Sprintf("%s/%s", namespace, pod)
+		problem := &models.Problem{
+			Entity:     entity,
+			EntityType: "kubernetes_pod",
+			Type:       "terminating",
+			Severity:   models.SeverityCritical,
+			Title:      "Pod Stuck Terminating",
+			Message:    fmt.Sprintf("Pod %s/%s has been terminating for %.0f minutes", namespace, pod, terminatingSeconds/60),
+			Labels: map[string]string{
+				"namespace": namespace,
+				"pod":       pod,
+			},
+			Metrics: map[string]float64{
+				"terminating_seconds": terminatingSeconds,
+			},
+			Hint:        "Blocking finalizer or unreachable node",
+			RunbookURL:  models.RunbookBaseURL + "terminating.md",
+			BlastRadius: blastRadiusPod,
+		}
+		problems = append(problems, problem)
+	}
+
+	return problems, nil
+}
+
+// NodeConditionDetector detects nodes reporting MemoryPressure, DiskPressure,
+// PIDPressure or NetworkUnavailable, which precede evictions and pod failures
+// on every pod the node runs
+type NodeConditionDetector struct {
+	interval time.Duration
+}
+
+func NewNodeConditionDetector() *NodeConditionDetector {
+	return &NodeConditionDetector{
+		interval: kubeDetectorInterval,
+	}
+}
+
+func (d *NodeConditionDetector) Name() string {
+	return "kubernetes_node_condition"
+}
+
+func (d *NodeConditionDetector) EntityTypes() []string {
+	return []string{"kubernetes_node"}
+}
+
+func (d *NodeConditionDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *NodeConditionDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	result, err := provider.QueryInstant(ctx, nodeConditionQuery, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("node condition query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0)
+	for _, sample := range result {
+		node := string(sample.Metric["node"])
+		condition := string(sample.Metric["condition"])
+
+		problem := &models.Problem{
+			Entity:     fmt.Sprintf("%s/%s", node, condition),
+			EntityType: "kubernetes_node",
+			Type:       "node_condition",
+			Severity:   models.SeverityCritical,
+			Title:      fmt.Sprintf("Node %s", condition),
+			Message:    fmt.Sprintf("Node %s reports %s", node, condition),
+			Labels: map[string]string{
+				"node":      node,
+				"condition": condition,
+			},
+			Metrics: map[string]float64{
+				"condition_status": float64(sample.Value),
+			},
+			Hint:        nodeConditionHints[condition],
+			RunbookURL:  models.RunbookBaseURL + "node_condition.md",
+			BlastRadius: blastRadiusNode,
+		}
+		problems = append(problems, problem)
+	}
+
+	return problems, nil
+}
+
+// RolloutStuckDetector detects Deployments whose rollout stopped progressing
+// (ProgressDeadlineExceeded), typically a bad image, config or failing probes
+// in the new ReplicaSet
+type RolloutStuckDetector struct {
+	interval time.Duration
+}
+
+func NewRolloutStuckDetector() *RolloutStuckDetector {
+	return &RolloutStuckDetector{
+		interval: kubeDetectorInterval,
+	}
+}
+
+func (d *RolloutStuckDetector) Name() string {
+	return "kubernetes_rollout_stuck"
+}
+
+func (d *RolloutStuckDetector) EntityTypes() []string {
+	return []string{"kubernetes_deployment"}
+}
+
+func (d *RolloutStuckDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *RolloutStuckDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	result, err := provider.QueryInstant(ctx, rolloutStuckQuery, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("rollout stuck query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0)
+	for _, sample := range result {
+		namespace := string(sample.Metric["namespace"])
+		deployment := string(sample.Metric["deployment"])
+
+		message := fmt.Sprintf("Deployment %s/%s is not progressing", namespace, deployment)
+		// reason is only exported by newer kube-state-metrics versions
+		if reason := string(sample.Metric["reason"]); reason != "" {
+			message += fmt.Sprintf(" (%s)", reason)
+		}
+
+		problem := &models.Problem{
+			Entity:     fmt.Sprintf("%s/%s", namespace, deployment),
+			EntityType: "kubernetes_deployment",
+			Type:       "rollout_stuck",
+			Severity:   models.SeverityCritical,
+			Title:      "Deployment Rollout Stuck",
+			Message:    message,
+			Labels: map[string]string{
+				"namespace":  namespace,
+				"deployment": deployment,
+			},
+			Metrics: map[string]float64{
+				"condition_status": float64(sample.Value),
+			},
+			Hint:        fmt.Sprintf("kubectl rollout status deployment/%s -n %s; roll back with kubectl rollout undo", deployment, namespace),
+			RunbookURL:  models.RunbookBaseURL + "rollout_stuck.md",
+			BlastRadius: blastRadiusDeployment,
+		}
+		problems = append(problems, problem)
+	}
+
+	return problems, nil
+}
+
+// PodSprawlDetector detects namespaces holding an abnormal number of pods,
+// usually a runaway controller or CronJob leaving completed Jobs behind
+type PodSprawlDetector struct {
+	interval  time.Duration
+	threshold float64 // Pods per namespace
+}
+
+func NewPodSprawlDetector() *PodSprawlDetector {
+	return &PodSprawlDetector{
+		interval:  kubeDetectorInterval,
+		threshold: podSprawlThreshold,
+	}
+}
+
+func (d *PodSprawlDetector) Name() string {
+	return "kubernetes_pod_sprawl"
+}
+
+func (d *PodSprawlDetector) EntityTypes() []string {
+	return []string{"kubernetes_namespace"}
+}
+
+func (d *PodSprawlDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *PodSprawlDetector) Threshold() float64 {
+	return d.threshold
+}
+
+func (d *PodSprawlDetector) SetThreshold(v float64) {
+	d.threshold = v
+}
+
+func (d *PodSprawlDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	query := fmt.Sprintf(`count(kube_pod_info) by (namespace) > %f`, d.threshold)
+	result, err := provider.QueryInstant(ctx, query, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("pod sprawl query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0)
+	for _, sample := range result {
+		podCount := float64(sample.Value)
+		if podCount <= d.threshold {
+			continue
+		}
+
+		namespace := string(sample.Metric["namespace"])
+		problem := &models.Problem{
+			Entity:     namespace,
+			EntityType: "kubernetes_namespace",
+			Type:       "pod_sprawl",
+			Severity:   models.SeverityWarning,
+			Title:      "Namespace Pod Sprawl",
+			Message:    fmt.Sprintf("Namespace %s has %.0f pods (threshold %.0f)", namespace, podCount, d.threshold),
+			Labels: map[string]string{
+				"namespace": namespace,
+			},
+			Metrics: map[string]float64{
+				"pod_count": podCount,
+			},
+			Hint:        fmt.Sprintf("kubectl get pods -n %s --sort-by=.metadata.creationTimestamp; look for a runaway controller or uncleaned Jobs", namespace),
+			RunbookURL:  models.RunbookBaseURL + "pod_sprawl.md",
+			BlastRadius: blastRadiusNamespace,
+		}
+		problems = append(problems, problem)
+	}
+
+	return problems, nil
+}
+
+// APIServiceUnavailableDetector detects aggregated APIServices (e.g.
+// v1beta1.metrics.k8s.io) the kube-apiserver reports as unavailable, usually
+// a failing backing service or an expired serving cert
+type APIServiceUnavailableDetector struct {
+	interval time.Duration
+}
+
+func NewAPIServiceUnavailableDetector() *APIServiceUnavailableDetector {
+	return &APIServiceUnavailableDetector{
+		interval: kubeDetectorInterval,
+	}
+}
+
+func (d *APIServiceUnavailableDetector) Name() string {
+	return "kubernetes_apiservice_unavailable"
+}
+
+func (d *APIServiceUnavailableDetector) EntityTypes() []string {
+	return []string{"kubernetes_apiservice"}
+}
+
+func (d *APIServiceUnavailableDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *APIServiceUnavailableDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	result, err := provider.QueryInstant(ctx, apiServiceUnavailableQuery, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("apiservice availability query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0)
+	for _, sample := range result {
+		name := string(sample.Metric["name"])
+
+		problem := &models.Problem{
+			Entity:     name,
+			EntityType: "kubernetes_apiservice",
+			Type:       "apiservice_unavailable",
+			Severity:   models.SeverityCritical,
+			Title:      "APIService Unavailable",
+			Message:    fmt.Sprintf("Aggregated APIService %s is unavailable; its API group fails discovery and requests", name),
+			Labels: map[string]string{
+				"apiservice": name,
+			},
+			Metrics: map[string]float64{
+				"unavailable": float64(sample.Value),
+			},
+			Hint:        fmt.Sprintf("kubectl get apiservice %s -o yaml; check the backing service's pods and serving cert", name),
+			RunbookURL:  models.RunbookBaseURL + "apiservice_unavailable.md",
+			BlastRadius: blastRadiusAPIService,
+		}
+		problems = append(problems, problem)
+	}
+
+	return problems, nil
+}
@@ -3,6 +3,8 @@ package detector
 import (
 	"context"
 	"fmt"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -236,3 +238,531 @@ func TestPodPendingDetector_ProviderError(t *testing.T) {
 		t.Fatal("expected error when provider fails")
 	}
 }
+
+func TestPDBViolationDetector(t *testing.T) {
+	tests := []struct {
+		name         string
+		deficit      model.SampleValue
+		wantProblem  bool
+		wantSeverity models.Severity
+	}{
+		{"satisfied", 0, false, ""},
+		{"one pod missing", 1, true, models.SeverityWarning},
+		{"many pods missing", 3, true, models.SeverityCritical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := &metrics.MockProvider{
+				QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+					return model.Vector{
+						&model.Sample{
+							Metric: model.Metric{
+								"namespace":           "prod",
+								"poddisruptionbudget": "api-pdb",
+							},
+							Value: tt.deficit,
+						},
+					}, nil
+				},
+			}
+
+			problems, err := NewPDBViolationDetector().Detect(context.Background(), mockProvider, 5*time.Minute)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !tt.wantProblem {
+				if len(problems) != 0 {
+					t.Fatalf("expected no problems, got %d", len(problems))
+				}
+				return
+			}
+			if len(problems) != 1 {
+				t.Fatalf("expected 1 problem, got %d", len(problems))
+			}
+			p := problems[0]
+			if p.Severity != tt.wantSeverity {
+				t.Errorf("expected %s severity, got %v", tt.wantSeverity, p.Severity)
+			}
+			if p.Entity != "prod/api-pdb" {
+				t.Errorf("expected entity 'prod/api-pdb', got '%s'", p.Entity)
+			}
+			if p.Metrics["unhealthy_pods"] != float64(tt.deficit) {
+				t.Errorf("expected unhealthy_pods %v, got %v", tt.deficit, p.Metrics["unhealthy_pods"])
+			}
+		})
+	}
+}
+
+func TestPDBViolationDetector_ProviderError(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	}
+
+	if _, err := NewPDBViolationDetector().Detect(context.Background(), mockProvider, 5*time.Minute); err == nil {
+		t.Fatal("expected error when provider fails")
+	}
+}
+
+func TestResourceQuotaDetector(t *testing.T) {
+	tests := []struct {
+		name         string
+		resource     string
+		utilization  model.SampleValue
+		wantSeverity models.Severity
+	}{
+		{"cpu warning", "requests.cpu", 0.88, models.SeverityWarning},
+		{"cpu critical", "requests.cpu", 0.97, models.SeverityCritical},
+		{"memory warning", "limits.memory", 0.90, models.SeverityWarning},
+		{"memory critical", "limits.memory", 1.0, models.SeverityCritical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := &metrics.MockProvider{
+				QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+					return model.Vector{
+						&model.Sample{
+							Metric: model.Metric{
+								"namespace":     "team-a",
+								"resourcequota": "compute",
+								"resource":      model.LabelValue(tt.resource),
+							},
+							Value: tt.utilization,
+						},
+					}, nil
+				},
+			}
+
+			problems, err := NewResourceQuotaDetector().Detect(context.Background(), mockProvider, 5*time.Minute)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(problems) != 1 {
+				t.Fatalf("expected 1 problem, got %d", len(problems))
+			}
+
+			p := problems[0]
+			if p.Severity != tt.wantSeverity {
+				t.Errorf("expected %s severity, got %v", tt.wantSeverity, p.Severity)
+			}
+			if p.Entity != "team-a" {
+				t.Errorf("expected entity 'team-a', got '%s'", p.Entity)
+			}
+			if p.Labels["resource"] != tt.resource {
+				t.Errorf("expected resource label '%s', got '%s'", tt.resource, p.Labels["resource"])
+			}
+		})
+	}
+}
+
+func TestResourceQuotaDetector_ZeroHardLimit(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{
+				&model.Sample{
+					Metric: model.Metric{"namespace": "team-a", "resource": "pods"},
+					Value:  model.SampleValue(math.Inf(1)),
+				},
+			}, nil
+		},
+	}
+
+	problems, err := NewResourceQuotaDetector().Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("expected no problems for a zero hard limit, got %d", len(problems))
+	}
+}
+
+func TestRestartRateDetector(t *testing.T) {
+	tests := []struct {
+		name        string
+		rate        model.SampleValue
+		wantProblem bool
+	}{
+		{"below threshold", 2, false},
+		{"at threshold", 3, false},
+		{"above threshold", 6, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := &metrics.MockProvider{
+				QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+					return model.Vector{
+						&model.Sample{
+							Metric: model.Metric{
+								"namespace": "prod",
+								"pod":       "api-7d9f8-xk2",
+								"container": "app",
+							},
+							Value: tt.rate,
+						},
+					}, nil
+				},
+			}
+
+			problems, err := NewRestartRateDetector().Detect(context.Background(), mockProvider, 5*time.Minute)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !tt.wantProblem {
+				if len(problems) != 0 {
+					t.Fatalf("expected no problems, got %d", len(problems))
+				}
+				return
+			}
+			if len(problems) != 1 {
+				t.Fatalf("expected 1 problem, got %d", len(problems))
+			}
+			p := problems[0]
+			if p.Severity != models.SeverityWarning {
+				t.Errorf("expected WARNING severity, got %v", p.Severity)
+			}
+			if p.Entity != "prod/api-7d9f8-xk2/app" {
+				t.Errorf("expected entity 'prod/api-7d9f8-xk2/app', got '%s'", p.Entity)
+			}
+			if p.Metrics["restarts_per_hour"] != float64(tt.rate) {
+				t.Errorf("expected restarts_per_hour %v, got %v", tt.rate, p.Metrics["restarts_per_hour"])
+			}
+		})
+	}
+}
+
+func TestPodTerminatingDetector(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{
+				&model.Sample{
+					Metric: model.Metric{
+						"namespace": "prod",
+						"pod":       "worker-0",
+					},
+					Value: 3600, // Terminating for an hour
+				},
+				&model.Sample{
+					Metric: model.Metric{
+						"namespace": "prod",
+						"pod":       "worker-1",
+					},
+					Value: 30, // Normal graceful shutdown
+				},
+			}, nil
+		},
+	}
+
+	problems, err := NewPodTerminatingDetector().Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	if p.Severity != models.SeverityCritical {
+		t.Errorf("expected CRITICAL severity, got %v", p.Severity)
+	}
+	if p.Entity != "prod/worker-0" {
+		t.Errorf("expected entity 'prod/worker-0', got '%s'", p.Entity)
+	}
+	if p.Metrics["terminating_seconds"] != 3600 {
+		t.Errorf("expected terminating_seconds 3600, got %v", p.Metrics["terminating_seconds"])
+	}
+}
+
+func TestNodeConditionDetector(t *testing.T) {
+	for _, condition := range []string{"MemoryPressure", "DiskPressure", "PIDPressure", "NetworkUnavailable"} {
+		t.Run(condition, func(t *testing.T) {
+			mockProvider := &metrics.MockProvider{
+				QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+					if query != nodeConditionQuery {
+						t.Errorf("unexpected query %q", query)
+					}
+					return model.Vector{
+						&model.Sample{
+							Metric: model.Metric{
+								"node":      "worker-3",
+								"condition": model.LabelValue(condition),
+								"status":    "true",
+							},
+							Value: 1,
+						},
+					}, nil
+				},
+			}
+
+			problems, err := NewNodeConditionDetector().Detect(context.Background(), mockProvider, 5*time.Minute)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(problems) != 1 {
+				t.Fatalf("expected 1 problem, got %d", len(problems))
+			}
+
+			p := problems[0]
+			if p.Severity != models.SeverityCritical {
+				t.Errorf("expected CRITICAL severity, got %v", p.Severity)
+			}
+			if p.Entity != "worker-3/"+condition {
+				t.Errorf("unexpected entity %q", p.Entity)
+			}
+			if p.Labels["condition"] != condition || p.Labels["node"] != "worker-3" {
+				t.Errorf("unexpected labels %v", p.Labels)
+			}
+			if p.BlastRadius != blastRadiusNode {
+				t.Errorf("expected blast radius %d, got %d", blastRadiusNode, p.BlastRadius)
+			}
+			if p.Hint == "" {
+				t.Error("expected a condition-specific hint")
+			}
+		})
+	}
+}
+
+func TestNodeConditionDetector_ProviderError(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	}
+
+	if _, err := NewNodeConditionDetector().Detect(context.Background(), mockProvider, 5*time.Minute); err == nil {
+		t.Fatal("expected error when provider fails")
+	}
+}
+
+func TestRolloutStuckDetector_Stuck(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			if query != rolloutStuckQuery {
+				t.Errorf("unexpected query %q", query)
+			}
+			return model.Vector{
+				&model.Sample{
+					Metric: model.Metric{
+						"namespace":  "prod",
+						"deployment": "checkout",
+						"condition":  "Progressing",
+						"status":     "false",
+						"reason":     "ProgressDeadlineExceeded",
+					},
+					Value: 1,
+				},
+			}, nil
+		},
+	}
+
+	problems, err := NewRolloutStuckDetector().Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	if p.Severity != models.SeverityCritical {
+		t.Errorf("expected CRITICAL severity, got %v", p.Severity)
+	}
+	if p.Entity != "prod/checkout" || p.EntityType != "kubernetes_deployment" {
+		t.Errorf("unexpected entity %s %q", p.EntityType, p.Entity)
+	}
+	if !strings.Contains(p.Message, "ProgressDeadlineExceeded") {
+		t.Errorf("message should carry the reason, got %q", p.Message)
+	}
+	if !strings.Contains(p.Hint, "kubectl rollout status deployment/checkout -n prod") {
+		t.Errorf("unexpected hint %q", p.Hint)
+	}
+}
+
+func TestRolloutStuckDetector_Healthy(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{}, nil
+		},
+	}
+
+	problems, err := NewRolloutStuckDetector().Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("expected 0 problems for progressing deployments, got %d", len(problems))
+	}
+}
+
+func TestRolloutStuckDetector_ProviderError(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	}
+
+	if _, err := NewRolloutStuckDetector().Detect(context.Background(), mockProvider, 5*time.Minute); err == nil {
+		t.Fatal("expected error when provider fails")
+	}
+}
+
+func TestPodSprawlDetector(t *testing.T) {
+	tests := []struct {
+		name        string
+		count       model.SampleValue
+		wantProblem bool
+	}{
+		{"below threshold", 400, false},
+		{"at threshold", 1000, false},
+		{"above threshold", 2500, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := &metrics.MockProvider{
+				QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+					return model.Vector{
+						&model.Sample{
+							Metric: model.Metric{"namespace": "batch"},
+							Value:  tt.count,
+						},
+					}, nil
+				},
+			}
+
+			problems, err := NewPodSprawlDetector().Detect(context.Background(), mockProvider, 5*time.Minute)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !tt.wantProblem {
+				if len(problems) != 0 {
+					t.Fatalf("expected no problems, got %d", len(problems))
+				}
+				return
+			}
+			if len(problems) != 1 {
+				t.Fatalf("expected 1 problem, got %d", len(problems))
+			}
+			p := problems[0]
+			if p.Severity != models.SeverityWarning {
+				t.Errorf("expected WARNING severity, got %v", p.Severity)
+			}
+			if p.Entity != "batch" || p.EntityType != "kubernetes_namespace" {
+				t.Errorf("unexpected entity %s %q", p.EntityType, p.Entity)
+			}
+			if p.Metrics["pod_count"] != float64(tt.count) {
+				t.Errorf("expected pod_count %v, got %v", tt.count, p.Metrics["pod_count"])
+			}
+		})
+	}
+}
+
+func TestPodSprawlDetector_SetThreshold(t *testing.T) {
+	var gotQuery string
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			gotQuery = query
+			return model.Vector{
+				&model.Sample{Metric: model.Metric{"namespace": "batch"}, Value: 300},
+			}, nil
+		},
+	}
+
+	d := NewPodSprawlDetector()
+	d.SetThreshold(200)
+	problems, err := d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem above the lowered threshold, got %d", len(problems))
+	}
+	if !strings.Contains(gotQuery, "> 200") {
+		t.Errorf("expected threshold in query, got %q", gotQuery)
+	}
+}
+
+func TestPodSprawlDetector_ProviderError(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	}
+
+	if _, err := NewPodSprawlDetector().Detect(context.Background(), mockProvider, 5*time.Minute); err == nil {
+		t.Fatal("expected error when provider fails")
+	}
+}
+
+func TestAPIServiceUnavailableDetector(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			if query != apiServiceUnavailableQuery {
+				t.Errorf("unexpected query %q", query)
+			}
+			return model.Vector{
+				&model.Sample{
+					Metric: model.Metric{"name": "v1beta1.metrics.k8s.io"},
+					Value:  1,
+				},
+			}, nil
+		},
+	}
+
+	problems, err := NewAPIServiceUnavailableDetector().Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	if p.Severity != models.SeverityCritical {
+		t.Errorf("expected CRITICAL severity, got %v", p.Severity)
+	}
+	if p.Entity != "v1beta1.metrics.k8s.io" || p.EntityType != "kubernetes_apiservice" {
+		t.Errorf("unexpected entity %s %q", p.EntityType, p.Entity)
+	}
+	if p.Type != "apiservice_unavailable" {
+		t.Errorf("expected type 'apiservice_unavailable', got '%s'", p.Type)
+	}
+	if !strings.Contains(p.Hint, "kubectl get apiservice v1beta1.metrics.k8s.io") {
+		t.Errorf("unexpected hint %q", p.Hint)
+	}
+}
+
+func TestAPIServiceUnavailableDetector_AllAvailable(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{}, nil
+		},
+	}
+
+	problems, err := NewAPIServiceUnavailableDetector().Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("expected 0 problems, got %d", len(problems))
+	}
+}
+
+func TestAPIServiceUnavailableDetector_ProviderError(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	}
+
+	if _, err := NewAPIServiceUnavailableDetector().Detect(context.Background(), mockProvider, 5*time.Minute); err == nil {
+		t.Fatal("expected error when provider fails")
+	}
+}
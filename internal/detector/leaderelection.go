@@ -0,0 +1,117 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+const (
+	leaderElectionCheckInterval = 30 * time.Second
+
+	// An HA component without a leader stops reconciling altogether, one
+	// with two leaders acts on everything it owns twice
+	blastRadiusLeaderElection = 10
+
+	// leader_election_master_status is exported by every client-go leader
+	// election candidate: 1 on the leader, 0 on the others. Summed per lease,
+	// it counts the leaders.
+	leaderCountQuery = `sum by (namespace, name) (leader_election_master_status)`
+
+	leaderNoneQuery  = leaderCountQuery + ` == 0`
+	leaderSplitQuery = leaderCountQuery + ` > 1`
+)
+
+// LeaderElectionDetector detects HA components whose candidates report no
+// leader, or more than one (split brain), from client-go leader election
+// metrics. A component without a leader looks healthy to every other
+// detector: its pods run, nothing crashes, it just stops doing its job.
+type LeaderElectionDetector struct {
+	interval time.Duration
+}
+
+func NewLeaderElectionDetector() *LeaderElectionDetector {
+	return &LeaderElectionDetector{
+		interval: leaderElectionCheckInterval,
+	}
+}
+
+func (d *LeaderElectionDetector) Name() string {
+	return "leader_election"
+}
+
+func (d *LeaderElectionDetector) EntityTypes() []string {
+	return []string{"component"}
+}
+
+func (d *LeaderElectionDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *LeaderElectionDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	now := time.Now()
+	none, err := provider.QueryInstant(ctx, leaderNoneQuery, now)
+	if err != nil {
+		return nil, fmt.Errorf("no leader query failed: %w", err)
+	}
+	split, err := provider.QueryInstant(ctx, leaderSplitQuery, now)
+	if err != nil {
+		return nil, fmt.Errorf("split brain query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0)
+	for _, sample := range none {
+		component, labels := leaderElectionComponent(sample.Metric)
+		problems = append(problems, &models.Problem{
+			Entity:      component,
+			EntityType:  "component",
+			Type:        "leader_election_no_leader",
+			Severity:    models.SeverityFatal,
+			Title:       "No leader elected",
+			Message:     fmt.Sprintf("No %s candidate holds the leader lease: the component is running but doing nothing", component),
+			Labels:      labels,
+			Metrics:     map[string]float64{"leaders": float64(sample.Value)},
+			Hint:        fmt.Sprintf("Check the %s lease holder and renew time, and the candidates' logs for lease update errors", component),
+			RunbookURL:  models.RunbookBaseURL + "leader_election_no_leader.md",
+			BlastRadius: blastRadiusLeaderElection,
+		})
+	}
+	for _, sample := range split {
+		component, labels := leaderElectionComponent(sample.Metric)
+		leaders := float64(sample.Value)
+		problems = append(problems, &models.Problem{
+			Entity:      component,
+			EntityType:  "component",
+			Type:        "leader_election_split_brain",
+			Severity:    models.SeverityFatal,
+			Title:       "Multiple leaders (split brain)",
+			Message:     fmt.Sprintf("%.0f %s candidates each believe they are the leader: they act on the same objects concurrently", leaders, component),
+			Labels:      labels,
+			Metrics:     map[string]float64{"leaders": leaders},
+			Hint:        "Compare leader_election_master_status per pod with the lease holder; restart the candidates that are not the holder",
+			RunbookURL:  models.RunbookBaseURL + "leader_election_split_brain.md",
+			BlastRadius: blastRadiusLeaderElection,
+		})
+	}
+
+	return problems, nil
+}
+
+// leaderElectionComponent names a component by its leader election lease,
+// and returns the labels worth keeping on the problem
+func leaderElectionComponent(metric model.Metric) (string, map[string]string) {
+	name := string(metric["name"])
+	if name == "" {
+		name = "unknown"
+	}
+	labels := map[string]string{"name": name}
+	if ns := string(metric["namespace"]); ns != "" {
+		labels["namespace"] = ns
+	}
+	return name, labels
+}
@@ -0,0 +1,81 @@
+package detector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestLeaderElectionDetector_NoLeader(t *testing.T) {
+	provider := etcdProvider(map[string]model.Vector{
+		leaderNoneQuery: {
+			&model.Sample{Metric: model.Metric{"namespace": "cert-manager", "name": "cert-manager-controller"}, Value: 0},
+		},
+	})
+
+	problems, err := NewLeaderElectionDetector().Detect(context.Background(), provider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	if p.Type != "leader_election_no_leader" || p.EntityType != "component" {
+		t.Errorf("unexpected type %s/%s", p.EntityType, p.Type)
+	}
+	if p.Severity != models.SeverityFatal {
+		t.Errorf("expected FATAL, got %v", p.Severity)
+	}
+	if p.Entity != "cert-manager-controller" {
+		t.Errorf("unexpected entity %q", p.Entity)
+	}
+	if p.Labels["namespace"] != "cert-manager" || p.Metrics["leaders"] != 0 {
+		t.Errorf("labels %v metrics %v", p.Labels, p.Metrics)
+	}
+}
+
+func TestLeaderElectionDetector_SplitBrain(t *testing.T) {
+	provider := etcdProvider(map[string]model.Vector{
+		leaderSplitQuery: {
+			&model.Sample{Metric: model.Metric{"name": "kube-controller-manager"}, Value: 2},
+		},
+	})
+
+	problems, err := NewLeaderElectionDetector().Detect(context.Background(), provider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	if p.Type != "leader_election_split_brain" || p.Severity != models.SeverityFatal {
+		t.Errorf("got %s %s, want FATAL leader_election_split_brain", p.Severity, p.Type)
+	}
+	if p.Entity != "kube-controller-manager" || p.Metrics["leaders"] != 2 {
+		t.Errorf("entity %q metrics %v", p.Entity, p.Metrics)
+	}
+	if _, ok := p.Labels["namespace"]; ok {
+		t.Errorf("unexpected empty namespace label in %v", p.Labels)
+	}
+	if p.Message != "2 kube-controller-manager candidates each believe they are the leader: they act on the same objects concurrently" {
+		t.Errorf("unexpected message %q", p.Message)
+	}
+}
+
+func TestLeaderElectionDetector_SingleLeader(t *testing.T) {
+	problems, err := NewLeaderElectionDetector().Detect(context.Background(), etcdProvider(nil), 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("expected no problems, got %d", len(problems))
+	}
+}
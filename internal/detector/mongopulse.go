@@ -38,6 +38,8 @@ func NewMongoConnectionExhaustionDetector() *MongoConnectionExhaustionDetector {
 func (d *MongoConnectionExhaustionDetector) Name() string            { return "mongo_connection_exhaustion" }
 func (d *MongoConnectionExhaustionDetector) EntityTypes() []string   { return []string{"mongodb"} }
 func (d *MongoConnectionExhaustionDetector) Interval() time.Duration { return d.interval }
+func (d *MongoConnectionExhaustionDetector) Threshold() float64      { return d.threshold }
+func (d *MongoConnectionExhaustionDetector) SetThreshold(v float64)  { d.threshold = v }
 
 func (d *MongoConnectionExhaustionDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`mongodb_connections_used_ratio > %f`, d.threshold)
@@ -55,7 +57,6 @@ func (d *MongoConnectionExhaustionDetector) Detect(ctx context.Context, provider
 		ratio := float64(sample.Value) * 100
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/mongo_connection_exhaustion", instance),
 			Entity:      instance,
 			EntityType:  "mongodb",
 			Type:        "mongo_connection_exhaustion",
@@ -85,6 +86,8 @@ func NewMongoReplicationLagDetector() *MongoReplicationLagDetector {
 func (d *MongoReplicationLagDetector) Name() string            { return "mongo_replication_lag" }
 func (d *MongoReplicationLagDetector) EntityTypes() []string   { return []string{"mongodb"} }
 func (d *MongoReplicationLagDetector) Interval() time.Duration { return d.interval }
+func (d *MongoReplicationLagDetector) Threshold() float64      { return d.threshold }
+func (d *MongoReplicationLagDetector) SetThreshold(v float64)  { d.threshold = v }
 
 func (d *MongoReplicationLagDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`mongodb_replication_lag_seconds > %f`, d.threshold)
@@ -110,7 +113,6 @@ func (d *MongoReplicationLagDetector) Detect(ctx context.Context, provider metri
 		}
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/mongo_replication_lag", entity),
 			Entity:      entity,
 			EntityType:  "mongodb",
 			Type:        "mongo_replication_lag",
@@ -140,6 +142,8 @@ func NewMongoOplogWindowDetector() *MongoOplogWindowDetector {
 func (d *MongoOplogWindowDetector) Name() string            { return "mongo_oplog_window" }
 func (d *MongoOplogWindowDetector) EntityTypes() []string   { return []string{"mongodb"} }
 func (d *MongoOplogWindowDetector) Interval() time.Duration { return d.interval }
+func (d *MongoOplogWindowDetector) Threshold() float64      { return d.threshold }
+func (d *MongoOplogWindowDetector) SetThreshold(v float64)  { d.threshold = v }
 
 func (d *MongoOplogWindowDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`mongodb_oplog_window_hours < %f`, d.threshold)
@@ -158,7 +162,6 @@ func (d *MongoOplogWindowDetector) Detect(ctx context.Context, provider metrics.
 		windowHours := float64(sample.Value)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/mongo_oplog_window", instance),
 			Entity:      instance,
 			EntityType:  "mongodb",
 			Type:        "mongo_oplog_window",
@@ -188,6 +191,8 @@ func NewMongoLockPercentageDetector() *MongoLockPercentageDetector {
 func (d *MongoLockPercentageDetector) Name() string            { return "mongo_lock_percentage" }
 func (d *MongoLockPercentageDetector) EntityTypes() []string   { return []string{"mongodb"} }
 func (d *MongoLockPercentageDetector) Interval() time.Duration { return d.interval }
+func (d *MongoLockPercentageDetector) Threshold() float64      { return d.threshold }
+func (d *MongoLockPercentageDetector) SetThreshold(v float64)  { d.threshold = v }
 
 func (d *MongoLockPercentageDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`mongodb_global_lock_ratio > %f`, d.threshold)
@@ -206,7 +211,6 @@ func (d *MongoLockPercentageDetector) Detect(ctx context.Context, provider metri
 		ratio := float64(sample.Value) * 100
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/mongo_lock_percentage", instance),
 			Entity:      instance,
 			EntityType:  "mongodb",
 			Type:        "mongo_lock_percentage",
@@ -236,6 +240,8 @@ func NewMongoCursorTimeoutDetector() *MongoCursorTimeoutDetector {
 func (d *MongoCursorTimeoutDetector) Name() string            { return "mongo_cursor_timeout" }
 func (d *MongoCursorTimeoutDetector) EntityTypes() []string   { return []string{"mongodb"} }
 func (d *MongoCursorTimeoutDetector) Interval() time.Duration { return d.interval }
+func (d *MongoCursorTimeoutDetector) Threshold() float64      { return float64(d.threshold) }
+func (d *MongoCursorTimeoutDetector) SetThreshold(v float64)  { d.threshold = int(v) }
 
 func (d *MongoCursorTimeoutDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`mongodb_cursors_timed_out > %d`, d.threshold)
@@ -254,7 +260,6 @@ func (d *MongoCursorTimeoutDetector) Detect(ctx context.Context, provider metric
 		count := float64(sample.Value)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/mongo_cursor_timeout", instance),
 			Entity:      instance,
 			EntityType:  "mongodb",
 			Type:        "mongo_cursor_timeout",
@@ -37,6 +37,8 @@ func NewMySQLConnectionExhaustionDetector() *MySQLConnectionExhaustionDetector {
 func (d *MySQLConnectionExhaustionDetector) Name() string            { return "mysql_connection_exhaustion" }
 func (d *MySQLConnectionExhaustionDetector) EntityTypes() []string   { return []string{"mysql"} }
 func (d *MySQLConnectionExhaustionDetector) Interval() time.Duration { return d.interval }
+func (d *MySQLConnectionExhaustionDetector) Threshold() float64      { return d.threshold }
+func (d *MySQLConnectionExhaustionDetector) SetThreshold(v float64)  { d.threshold = v }
 
 func (d *MySQLConnectionExhaustionDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`mysql_connections_used_ratio > %f`, d.threshold)
@@ -54,7 +56,6 @@ func (d *MySQLConnectionExhaustionDetector) Detect(ctx context.Context, provider
 		ratio := float64(sample.Value) * 100
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/mysql_connection_exhaustion", instance),
 			Entity:      instance,
 			EntityType:  "mysql",
 			Type:        "mysql_connection_exhaustion",
@@ -84,6 +85,8 @@ func NewMySQLReplicationLagDetector() *MySQLReplicationLagDetector {
 func (d *MySQLReplicationLagDetector) Name() string            { return "mysql_replication_lag" }
 func (d *MySQLReplicationLagDetector) EntityTypes() []string   { return []string{"mysql"} }
 func (d *MySQLReplicationLagDetector) Interval() time.Duration { return d.interval }
+func (d *MySQLReplicationLagDetector) Threshold() float64      { return d.threshold }
+func (d *MySQLReplicationLagDetector) SetThreshold(v float64)  { d.threshold = v }
 
 func (d *MySQLReplicationLagDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`mysql_replication_lag_seconds > %f`, d.threshold)
@@ -112,7 +115,6 @@ func (d *MySQLReplicationLagDetector) Detect(ctx context.Context, provider metri
 		}
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/mysql_replication_lag", entity),
 			Entity:      entity,
 			EntityType:  "mysql",
 			Type:        "mysql_replication_lag",
@@ -142,9 +144,11 @@ func NewMySQLDeadlocksDetector() *MySQLDeadlocksDetector {
 func (d *MySQLDeadlocksDetector) Name() string            { return "mysql_deadlocks" }
 func (d *MySQLDeadlocksDetector) EntityTypes() []string   { return []string{"mysql"} }
 func (d *MySQLDeadlocksDetector) Interval() time.Duration { return d.interval }
+func (d *MySQLDeadlocksDetector) Threshold() float64      { return float64(d.threshold) }
+func (d *MySQLDeadlocksDetector) SetThreshold(v float64)  { d.threshold = int(v) }
 
-func (d *MySQLDeadlocksDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
-	query := fmt.Sprintf(`rate(mysql_deadlocks_total[5m]) * 60 > %d`, d.threshold)
+func (d *MySQLDeadlocksDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	query := fmt.Sprintf(`rate(mysql_deadlocks_total%s) * 60 > %d`, rangeSelector(window), d.threshold)
 	result, err := provider.QueryInstant(ctx, query, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("mysql deadlocks query failed: %w", err)
@@ -160,7 +164,6 @@ func (d *MySQLDeadlocksDetector) Detect(ctx context.Context, provider metrics.Me
 		ratePerMin := float64(sample.Value)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/mysql_deadlocks", instance),
 			Entity:      instance,
 			EntityType:  "mysql",
 			Type:        "mysql_deadlocks",
@@ -190,6 +193,8 @@ func NewMySQLSlowQueriesDetector() *MySQLSlowQueriesDetector {
 func (d *MySQLSlowQueriesDetector) Name() string            { return "mysql_slow_queries" }
 func (d *MySQLSlowQueriesDetector) EntityTypes() []string   { return []string{"mysql"} }
 func (d *MySQLSlowQueriesDetector) Interval() time.Duration { return d.interval }
+func (d *MySQLSlowQueriesDetector) Threshold() float64      { return float64(d.threshold) }
+func (d *MySQLSlowQueriesDetector) SetThreshold(v float64)  { d.threshold = int(v) }
 
 func (d *MySQLSlowQueriesDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`mysql_slow_queries_active > %d`, d.threshold)
@@ -208,7 +213,6 @@ func (d *MySQLSlowQueriesDetector) Detect(ctx context.Context, provider metrics.
 		count := float64(sample.Value)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/mysql_slow_queries", instance),
 			Entity:      instance,
 			EntityType:  "mysql",
 			Type:        "mysql_slow_queries",
@@ -240,6 +244,8 @@ func (d *MySQLInnoDBBufferPoolPressureDetector) Name() string {
 }
 func (d *MySQLInnoDBBufferPoolPressureDetector) EntityTypes() []string   { return []string{"mysql"} }
 func (d *MySQLInnoDBBufferPoolPressureDetector) Interval() time.Duration { return d.interval }
+func (d *MySQLInnoDBBufferPoolPressureDetector) Threshold() float64      { return d.threshold }
+func (d *MySQLInnoDBBufferPoolPressureDetector) SetThreshold(v float64)  { d.threshold = v }
 
 func (d *MySQLInnoDBBufferPoolPressureDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`mysql_innodb_buffer_pool_hit_ratio < %f`, d.threshold)
@@ -258,7 +264,6 @@ func (d *MySQLInnoDBBufferPoolPressureDetector) Detect(ctx context.Context, prov
 		hitRatio := float64(sample.Value) * 100
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/mysql_innodb_buffer_pool_pressure", instance),
 			Entity:      instance,
 			EntityType:  "mysql",
 			Type:        "mysql_innodb_buffer_pool_pressure",
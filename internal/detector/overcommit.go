@@ -0,0 +1,101 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+const (
+	// Requests only change when pods are scheduled, capacity planning does
+	// not need a faster check
+	overcommitCheckInterval = 5 * time.Minute
+
+	// Share of a node's allocatable memory requested by its pods
+	// (0.9 = 90%); above it the next replica of most workloads no longer fits
+	overcommitThreshold = 0.9
+
+	// Requests above allocatable: only possible for pods the scheduler did
+	// not place (static pods) or after the node's allocatable shrank
+	overcommitCriticalRatio = 1.0
+
+	// A full node blocks scheduling, it does not break what already runs
+	blastRadiusOvercommit = 2
+
+	// Memory requested by the pending and running pods on each node, over
+	// the node's allocatable memory. Completed pods keep their request
+	// series in kube-state-metrics but no longer hold the memory.
+	overcommitQuery = `sum by (node) (kube_pod_container_resource_requests{resource="memory"} and on (namespace, pod) (kube_pod_status_phase{phase=~"Pending|Running"} == 1)) / sum by (node) (kube_node_status_allocatable{resource="memory"})`
+)
+
+// NodeOvercommitDetector detects nodes whose pods request more of the
+// node's allocatable memory than the threshold. Nothing is broken yet, but
+// the node has stopped taking new pods, and a cluster full of such nodes
+// fails the next rollout or failover. Optional: packing nodes tightly is a
+// legitimate choice, so it is off unless enabled.
+type NodeOvercommitDetector struct {
+	interval  time.Duration
+	threshold float64 // Requested share of allocatable memory (0.9 = 90%)
+}
+
+func NewNodeOvercommitDetector() *NodeOvercommitDetector {
+	return &NodeOvercommitDetector{
+		interval:  overcommitCheckInterval,
+		threshold: overcommitThreshold,
+	}
+}
+
+func (d *NodeOvercommitDetector) Name() string {
+	return "kubernetes_node_overcommit"
+}
+
+func (d *NodeOvercommitDetector) EntityTypes() []string {
+	return []string{"kubernetes_node"}
+}
+
+func (d *NodeOvercommitDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *NodeOvercommitDetector) Threshold() float64 {
+	return d.threshold
+}
+
+func (d *NodeOvercommitDetector) SetThreshold(v float64) {
+	d.threshold = v
+}
+
+func (d *NodeOvercommitDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	result, err := queryAbove(ctx, provider, overcommitQuery, d.threshold, 0)
+	if err != nil {
+		return nil, fmt.Errorf("node overcommit query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0, len(result))
+	for _, sample := range result {
+		node := string(sample.Metric["node"])
+		ratio := float64(sample.Value)
+		severity := models.SeverityWarning
+		if ratio >= overcommitCriticalRatio {
+			severity = models.SeverityCritical
+		}
+		problems = append(problems, &models.Problem{
+			Entity:      node,
+			EntityType:  "kubernetes_node",
+			Type:        "node_memory_overcommit",
+			Severity:    severity,
+			Title:       "Node memory overcommitted",
+			Message:     fmt.Sprintf("Pods on node %s request %.0f%% of its allocatable memory", node, ratio*100),
+			Labels:      map[string]string{"node": node},
+			Metrics:     map[string]float64{"overcommit_ratio": ratio},
+			Hint:        fmt.Sprintf("Requests above %.0f%% of allocatable: add nodes or right-size the largest requests before the next rollout fails to schedule", d.threshold*100),
+			RunbookURL:  models.RunbookBaseURL + "node_memory_overcommit.md",
+			BlastRadius: blastRadiusOvercommit,
+		})
+	}
+
+	return problems, nil
+}
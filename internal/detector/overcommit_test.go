@@ -0,0 +1,86 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// overcommitProvider answers the overcommit query with the nodes whose
+// ratio is above the threshold in the query, like Prometheus would
+func overcommitProvider(ratios map[string]float64) *metrics.MockProvider {
+	return &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			var threshold float64
+			if _, err := fmt.Sscanf(query[len(overcommitQuery):], " > %f", &threshold); err != nil {
+				return nil, fmt.Errorf("unexpected query %q", query)
+			}
+			var result model.Vector
+			for node, ratio := range ratios {
+				if ratio > threshold {
+					result = append(result, &model.Sample{Metric: model.Metric{"node": model.LabelValue(node)}, Value: model.SampleValue(ratio)})
+				}
+			}
+			return result, nil
+		},
+	}
+}
+
+func TestNodeOvercommitDetector(t *testing.T) {
+	provider := overcommitProvider(map[string]float64{
+		"worker-1": 0.62, // balanced
+		"worker-2": 0.94,
+		"worker-3": 1.08,
+	})
+
+	problems, err := NewNodeOvercommitDetector().Detect(context.Background(), provider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %d", len(problems))
+	}
+
+	byNode := make(map[string]*models.Problem)
+	for _, p := range problems {
+		byNode[p.Entity] = p
+	}
+	if _, ok := byNode["worker-1"]; ok {
+		t.Error("balanced node worker-1 should not be reported")
+	}
+
+	p := byNode["worker-2"]
+	if p == nil || p.Severity != models.SeverityWarning || p.Type != "node_memory_overcommit" || p.EntityType != "kubernetes_node" {
+		t.Fatalf("worker-2 = %+v, want WARNING node_memory_overcommit", p)
+	}
+	if p.Metrics["overcommit_ratio"] != 0.94 || p.Labels["node"] != "worker-2" {
+		t.Errorf("metrics %v labels %v", p.Metrics, p.Labels)
+	}
+	if p.Message != "Pods on node worker-2 request 94% of its allocatable memory" {
+		t.Errorf("unexpected message %q", p.Message)
+	}
+	if p := byNode["worker-3"]; p == nil || p.Severity != models.SeverityCritical {
+		t.Errorf("worker-3 = %+v, want CRITICAL above allocatable", p)
+	}
+}
+
+func TestNodeOvercommitDetector_ThresholdIsTunable(t *testing.T) {
+	provider := overcommitProvider(map[string]float64{"worker-1": 0.62, "worker-2": 0.94})
+
+	d := NewNodeOvercommitDetector()
+	var tunable Tunable = d
+	tunable.SetThreshold(0.5)
+	problems, err := d.Detect(context.Background(), provider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 2 {
+		t.Errorf("expected both nodes above 50%%, got %d problems", len(problems))
+	}
+}
@@ -38,6 +38,8 @@ func NewPgConnectionExhaustionDetector() *PgConnectionExhaustionDetector {
 func (d *PgConnectionExhaustionDetector) Name() string            { return "pg_connection_exhaustion" }
 func (d *PgConnectionExhaustionDetector) EntityTypes() []string   { return []string{"postgresql"} }
 func (d *PgConnectionExhaustionDetector) Interval() time.Duration { return d.interval }
+func (d *PgConnectionExhaustionDetector) Threshold() float64      { return d.threshold }
+func (d *PgConnectionExhaustionDetector) SetThreshold(v float64)  { d.threshold = v }
 
 func (d *PgConnectionExhaustionDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`pg_connections_used_ratio > %f`, d.threshold)
@@ -55,7 +57,6 @@ func (d *PgConnectionExhaustionDetector) Detect(ctx context.Context, provider me
 		ratio := float64(sample.Value) * 100
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/pg_connection_exhaustion", instance),
 			Entity:      instance,
 			EntityType:  "postgresql",
 			Type:        "pg_connection_exhaustion",
@@ -85,6 +86,8 @@ func NewPgReplicationLagDetector() *PgReplicationLagDetector {
 func (d *PgReplicationLagDetector) Name() string            { return "pg_replication_lag" }
 func (d *PgReplicationLagDetector) EntityTypes() []string   { return []string{"postgresql"} }
 func (d *PgReplicationLagDetector) Interval() time.Duration { return d.interval }
+func (d *PgReplicationLagDetector) Threshold() float64      { return d.threshold }
+func (d *PgReplicationLagDetector) SetThreshold(v float64)  { d.threshold = v }
 
 func (d *PgReplicationLagDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`pg_replication_lag_seconds > %f`, d.threshold)
@@ -114,7 +117,6 @@ func (d *PgReplicationLagDetector) Detect(ctx context.Context, provider metrics.
 		}
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/pg_replication_lag", entity),
 			Entity:      entity,
 			EntityType:  "postgresql",
 			Type:        "pg_replication_lag",
@@ -144,6 +146,8 @@ func NewPgDeadTupleRatioDetector() *PgDeadTupleRatioDetector {
 func (d *PgDeadTupleRatioDetector) Name() string            { return "pg_dead_tuple_ratio" }
 func (d *PgDeadTupleRatioDetector) EntityTypes() []string   { return []string{"postgresql_table"} }
 func (d *PgDeadTupleRatioDetector) Interval() time.Duration { return d.interval }
+func (d *PgDeadTupleRatioDetector) Threshold() float64      { return d.threshold }
+func (d *PgDeadTupleRatioDetector) SetThreshold(v float64)  { d.threshold = v }
 
 func (d *PgDeadTupleRatioDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`pg_dead_tuple_ratio > %f`, d.threshold)
@@ -167,7 +171,6 @@ func (d *PgDeadTupleRatioDetector) Detect(ctx context.Context, provider metrics.
 		entity := fmt.Sprintf("%s/%s", instance, table)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/pg_dead_tuples", entity),
 			Entity:      entity,
 			EntityType:  "postgresql_table",
 			Type:        "pg_dead_tuple_ratio",
@@ -197,6 +200,8 @@ func NewPgLockChainDepthDetector() *PgLockChainDepthDetector {
 func (d *PgLockChainDepthDetector) Name() string            { return "pg_lock_chain_depth" }
 func (d *PgLockChainDepthDetector) EntityTypes() []string   { return []string{"postgresql"} }
 func (d *PgLockChainDepthDetector) Interval() time.Duration { return d.interval }
+func (d *PgLockChainDepthDetector) Threshold() float64      { return float64(d.threshold) }
+func (d *PgLockChainDepthDetector) SetThreshold(v float64)  { d.threshold = int(v) }
 
 func (d *PgLockChainDepthDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`pg_lock_chain_max_depth > %d`, d.threshold)
@@ -215,7 +220,6 @@ func (d *PgLockChainDepthDetector) Detect(ctx context.Context, provider metrics.
 		depth := float64(sample.Value)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/pg_lock_chain_depth", instance),
 			Entity:      instance,
 			EntityType:  "postgresql",
 			Type:        "pg_lock_chain_depth",
@@ -245,6 +249,8 @@ func NewPgSlowQueriesDetector() *PgSlowQueriesDetector {
 func (d *PgSlowQueriesDetector) Name() string            { return "pg_slow_queries" }
 func (d *PgSlowQueriesDetector) EntityTypes() []string   { return []string{"postgresql"} }
 func (d *PgSlowQueriesDetector) Interval() time.Duration { return d.interval }
+func (d *PgSlowQueriesDetector) Threshold() float64      { return float64(d.threshold) }
+func (d *PgSlowQueriesDetector) SetThreshold(v float64)  { d.threshold = int(v) }
 
 func (d *PgSlowQueriesDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`pg_slow_queries > %d`, d.threshold)
@@ -263,7 +269,6 @@ func (d *PgSlowQueriesDetector) Detect(ctx context.Context, provider metrics.Met
 		count := float64(sample.Value)
 
 		problems = append(problems, &models.Problem{
-			ID:          fmt.Sprintf("%s/pg_slow_queries", instance),
 			Entity:      instance,
 			EntityType:  "postgresql",
 			Type:        "pg_slow_queries",
@@ -0,0 +1,132 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+const (
+	ruleEvaluationCheckInterval = 60 * time.Second
+
+	// Broken recording rules starve the dashboards, alerts and detectors
+	// built on top of them
+	blastRadiusRuleEvaluation = 3
+
+	// Rule groups whose last evaluation took longer than their interval, so
+	// evaluations are being skipped
+	ruleSlowQuery = `max by (rule_group) (prometheus_rule_group_last_duration_seconds / prometheus_rule_group_interval_seconds) > 1`
+)
+
+// ruleFailuresQuery returns rule groups with evaluation failures in window
+func ruleFailuresQuery(window time.Duration) string {
+	return fmt.Sprintf(`sum by (rule_group) (increase(prometheus_rule_evaluation_failures_total%s)) > 0`, rangeSelector(window))
+}
+
+// PrometheusRuleEvaluationDetector reports rule groups that fail to evaluate
+// or take longer than their evaluation interval. Either way recording rules
+// stop producing fresh series and everything reading them breaks silently.
+type PrometheusRuleEvaluationDetector struct {
+	interval time.Duration
+}
+
+func NewPrometheusRuleEvaluationDetector() *PrometheusRuleEvaluationDetector {
+	return &PrometheusRuleEvaluationDetector{
+		interval: ruleEvaluationCheckInterval,
+	}
+}
+
+func (d *PrometheusRuleEvaluationDetector) Name() string {
+	return "prometheus_rule_evaluation"
+}
+
+func (d *PrometheusRuleEvaluationDetector) EntityTypes() []string {
+	return []string{"prometheus_rule_group"}
+}
+
+func (d *PrometheusRuleEvaluationDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *PrometheusRuleEvaluationDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	now := time.Now()
+	failures, err := provider.QueryInstant(ctx, ruleFailuresQuery(window), now)
+	if err != nil {
+		return nil, fmt.Errorf("rule evaluation failures query failed: %w", err)
+	}
+	slow, err := provider.QueryInstant(ctx, ruleSlowQuery, now)
+	if err != nil {
+		return nil, fmt.Errorf("rule group duration query failed: %w", err)
+	}
+
+	// One problem per rule group, even when it is both failing and slow
+	byGroup := make(map[string]map[string]float64)
+	for _, sample := range failures {
+		group := string(sample.Metric["rule_group"])
+		if byGroup[group] == nil {
+			byGroup[group] = make(map[string]float64)
+		}
+		byGroup[group]["evaluation_failures"] = float64(sample.Value)
+	}
+	for _, sample := range slow {
+		group := string(sample.Metric["rule_group"])
+		if byGroup[group] == nil {
+			byGroup[group] = make(map[string]float64)
+		}
+		byGroup[group]["duration_to_interval_ratio"] = float64(sample.Value)
+	}
+
+	groups := make([]string, 0, len(byGroup))
+	for group := range byGroup {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	problems := make([]*models.Problem, 0, len(groups))
+	for _, group := range groups {
+		values := byGroup[group]
+		entity := ruleGroupEntity(group)
+
+		var reasons []string
+		title := "Rule group evaluation too slow"
+		if n, ok := values["evaluation_failures"]; ok {
+			title = "Rule group failing to evaluate"
+			reasons = append(reasons, fmt.Sprintf("%.0f evaluation failures in the last %s", n, windowLabel(window)))
+		}
+		if ratio, ok := values["duration_to_interval_ratio"]; ok {
+			reasons = append(reasons, fmt.Sprintf("last evaluation took %.1fx its interval", ratio))
+		}
+
+		problems = append(problems, &models.Problem{
+			Entity:      entity,
+			EntityType:  "prometheus_rule_group",
+			Type:        "prometheus_rule_evaluation",
+			Severity:    models.SeverityWarning,
+			Title:       title,
+			Message:     fmt.Sprintf("Rule group %s: %s; recording rules in it are stale", entity, strings.Join(reasons, ", ")),
+			Labels:      map[string]string{"rule_group": group},
+			Metrics:     values,
+			Hint:        "Check the rule group on the Prometheus /rules page for the evaluation error",
+			RunbookURL:  models.RunbookBaseURL + "prometheus_rule_evaluation.md",
+			BlastRadius: blastRadiusRuleEvaluation,
+		})
+	}
+
+	return problems, nil
+}
+
+// ruleGroupEntity shortens a rule_group label ("/etc/prometheus/rules/app.yaml;app.rules")
+// to "app.yaml/app.rules"
+func ruleGroupEntity(group string) string {
+	file, name, ok := strings.Cut(group, ";")
+	if !ok {
+		return group
+	}
+	return filepath.Base(file) + "/" + name
+}
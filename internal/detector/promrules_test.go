@@ -0,0 +1,128 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+const testRuleGroup = "/etc/prometheus/rules/api.yaml;api.recording"
+
+func TestPrometheusRuleEvaluationDetector_FailingGroup(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			if query != ruleFailuresQuery(5*time.Minute) {
+				return model.Vector{}, nil
+			}
+			return model.Vector{
+				&model.Sample{Metric: model.Metric{"rule_group": testRuleGroup}, Value: 12},
+			}, nil
+		},
+	}
+
+	d := NewPrometheusRuleEvaluationDetector()
+	problems, err := d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	if p.Severity != models.SeverityWarning {
+		t.Errorf("expected WARNING, got %v", p.Severity)
+	}
+	if p.Type != "prometheus_rule_evaluation" || p.EntityType != "prometheus_rule_group" {
+		t.Errorf("unexpected type %s/%s", p.EntityType, p.Type)
+	}
+	if p.Entity != "api.yaml/api.recording" {
+		t.Errorf("unexpected entity %q", p.Entity)
+	}
+	if p.Labels["rule_group"] != testRuleGroup {
+		t.Errorf("rule_group label = %q", p.Labels["rule_group"])
+	}
+	if p.Metrics["evaluation_failures"] != 12 {
+		t.Errorf("evaluation_failures = %g, want 12", p.Metrics["evaluation_failures"])
+	}
+	if p.Title != "Rule group failing to evaluate" {
+		t.Errorf("unexpected title %q", p.Title)
+	}
+}
+
+func TestPrometheusRuleEvaluationDetector_FailingAndSlowMerged(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			switch query {
+			case ruleFailuresQuery(5 * time.Minute):
+				return model.Vector{
+					&model.Sample{Metric: model.Metric{"rule_group": testRuleGroup}, Value: 3},
+				}, nil
+			case ruleSlowQuery:
+				return model.Vector{
+					&model.Sample{Metric: model.Metric{"rule_group": testRuleGroup}, Value: 2.5},
+					&model.Sample{Metric: model.Metric{"rule_group": "node.yaml;node.rules"}, Value: 1.2},
+				}, nil
+			}
+			return model.Vector{}, nil
+		},
+	}
+
+	d := NewPrometheusRuleEvaluationDetector()
+	problems, err := d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems (one per group), got %d", len(problems))
+	}
+
+	byEntity := make(map[string]*models.Problem)
+	for _, p := range problems {
+		byEntity[p.Entity] = p
+	}
+	api := byEntity["api.yaml/api.recording"]
+	if api == nil || api.Metrics["evaluation_failures"] != 3 || api.Metrics["duration_to_interval_ratio"] != 2.5 {
+		t.Errorf("failing and slow group not merged: %+v", api)
+	}
+	node := byEntity["node.yaml/node.rules"]
+	if node == nil || node.Title != "Rule group evaluation too slow" {
+		t.Errorf("unexpected slow-only problem: %+v", node)
+	}
+}
+
+func TestPrometheusRuleEvaluationDetector_Healthy(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{}, nil
+		},
+	}
+
+	d := NewPrometheusRuleEvaluationDetector()
+	problems, err := d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("expected 0 problems, got %d", len(problems))
+	}
+}
+
+func TestPrometheusRuleEvaluationDetector_ProviderError(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	}
+
+	d := NewPrometheusRuleEvaluationDetector()
+	if _, err := d.Detect(context.Background(), mockProvider, 5*time.Minute); err == nil {
+		t.Fatal("expected error when provider fails")
+	}
+}
@@ -1,6 +1,9 @@
 package detector
 
-import "sync"
+import (
+	"sort"
+	"sync"
+)
 
 // Registry manages detector lifecycle
 type Registry struct {
@@ -55,3 +58,23 @@ func (r *Registry) Count() int {
 	defer r.mu.RUnlock()
 	return len(r.detectors)
 }
+
+// EntityTypes returns the sorted union of entity types declared by all
+// registered detectors
+func (r *Registry) EntityTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var types []string
+	for _, d := range r.detectors {
+		for _, t := range d.EntityTypes() {
+			if !seen[t] {
+				seen[t] = true
+				types = append(types, t)
+			}
+		}
+	}
+	sort.Strings(types)
+	return types
+}
@@ -60,3 +60,26 @@ func TestRegistry(t *testing.T) {
 		t.Errorf("count after unregister = %d, want 1", r.Count())
 	}
 }
+
+func TestRegistry_EntityTypes(t *testing.T) {
+	types := DefaultRegistry().EntityTypes()
+
+	for i := 1; i < len(types); i++ {
+		if types[i-1] >= types[i] {
+			t.Fatalf("entity types not sorted and unique: %v", types)
+		}
+	}
+
+	have := make(map[string]bool)
+	for _, et := range types {
+		have[et] = true
+	}
+	for _, want := range []string{
+		"kubernetes_pod", "node", "service", "service_mesh_certificate",
+		"trustwatch_certificate", "monitoring_target", "postgresql", "mysql", "mongodb",
+	} {
+		if !have[want] {
+			t.Errorf("EntityTypes() missing %s: %v", want, types)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+const (
+	// Missing limits are a standing condition, not an outage
+	resourceHygieneInterval = 5 * time.Minute
+
+	// Running containers without any limit, or without any request, per pod
+	// and owner
+	missingLimitsQuery   = `count by (namespace, pod, owner_kind, owner_name) (((kube_pod_container_info unless on (namespace, pod, container) kube_pod_container_resource_limits) and on (namespace, pod) (kube_pod_status_phase{phase="Running"} == 1)) * on (namespace, pod) group_left (owner_kind, owner_name) kube_pod_owner)`
+	missingRequestsQuery = `count by (namespace, pod, owner_kind, owner_name) (((kube_pod_container_info unless on (namespace, pod, container) kube_pod_container_resource_requests) and on (namespace, pod) (kube_pod_status_phase{phase="Running"} == 1)) * on (namespace, pod) group_left (owner_kind, owner_name) kube_pod_owner)`
+)
+
+// MissingResourceLimitsDetector reports workloads running containers
+// without resource limits or requests. They are not broken yet, but they
+// become noisy neighbours, get scheduled onto full nodes and end up OOM
+// killed. Optional: most clusters have some, so it is off unless enabled.
+type MissingResourceLimitsDetector struct {
+	interval time.Duration
+}
+
+func NewMissingResourceLimitsDetector() *MissingResourceLimitsDetector {
+	return &MissingResourceLimitsDetector{interval: resourceHygieneInterval}
+}
+
+func (d *MissingResourceLimitsDetector) Name() string {
+	return "kubernetes_missing_limits"
+}
+
+func (d *MissingResourceLimitsDetector) EntityTypes() []string {
+	return []string{"kubernetes_workload"}
+}
+
+func (d *MissingResourceLimitsDetector) Interval() time.Duration {
+	return d.interval
+}
+
+// workloadResources counts what a workload's running containers are missing
+type workloadResources struct {
+	namespace, workload, kind string
+	pods                      map[string]bool
+	noLimits, noRequests      float64
+}
+
+func (d *MissingResourceLimitsDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	now := time.Now()
+	noLimits, err := provider.QueryInstant(ctx, missingLimitsQuery, now)
+	if err != nil {
+		return nil, fmt.Errorf("missing limits query failed: %w", err)
+	}
+	noRequests, err := provider.QueryInstant(ctx, missingRequestsQuery, now)
+	if err != nil {
+		return nil, fmt.Errorf("missing requests query failed: %w", err)
+	}
+
+	// One problem per workload, not per replica
+	workloads := make(map[string]*workloadResources)
+	add := func(sample *model.Sample) *workloadResources {
+		namespace := string(sample.Metric["namespace"])
+		pod := string(sample.Metric["pod"])
+		workload, kind := podWorkload(pod, string(sample.Metric["owner_kind"]), string(sample.Metric["owner_name"]))
+		key := namespace + "/" + workload
+		w, ok := workloads[key]
+		if !ok {
+			w = &workloadResources{namespace: namespace, workload: workload, kind: kind, pods: make(map[string]bool)}
+			workloads[key] = w
+		}
+		w.pods[pod] = true
+		return w
+	}
+	for _, sample := range noLimits {
+		add(sample).noLimits += float64(sample.Value)
+	}
+	for _, sample := range noRequests {
+		add(sample).noRequests += float64(sample.Value)
+	}
+
+	keys := make([]string, 0, len(workloads))
+	for key := range workloads {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	problems := make([]*models.Problem, 0, len(keys))
+	for _, key := range keys {
+		w := workloads[key]
+		var missing string
+		switch {
+		case w.noLimits > 0 && w.noRequests > 0:
+			missing = "resource requests or limits"
+		case w.noLimits > 0:
+			missing = "resource limits"
+		default:
+			missing = "resource requests"
+		}
+
+		problems = append(problems, &models.Problem{
+			Entity:     key,
+			EntityType: "kubernetes_workload",
+			Type:       "missing_resource_limits",
+			Severity:   models.SeverityWarning,
+			Title:      "No " + missing + " set",
+			Message:    fmt.Sprintf("%s %s runs containers without %s in %d pods", w.kind, key, missing, len(w.pods)),
+			Labels: map[string]string{
+				"namespace":     w.namespace,
+				"workload":      w.workload,
+				"workload_kind": w.kind,
+			},
+			Metrics: map[string]float64{
+				"containers_without_limits":   w.noLimits,
+				"containers_without_requests": w.noRequests,
+			},
+			Hint:        fmt.Sprintf("Set resources.requests and resources.limits on every container, or a LimitRange in namespace %s", w.namespace),
+			RunbookURL:  models.RunbookBaseURL + "missing_resource_limits.md",
+			BlastRadius: blastRadiusPod,
+		})
+	}
+	return problems, nil
+}
+
+// podWorkload names the workload a pod belongs to. Deployment pods are
+// owned by a ReplicaSet named after the Deployment plus a pod-template hash,
+// so the hash is dropped; pods without an owner are their own workload.
+func podWorkload(pod, ownerKind, ownerName string) (name, kind string) {
+	switch ownerKind {
+	case "", "<none>":
+		return pod, "Pod"
+	case "ReplicaSet":
+		if i := strings.LastIndex(ownerName, "-"); i > 0 {
+			return ownerName[:i], "Deployment"
+		}
+	}
+	return ownerName, ownerKind
+}
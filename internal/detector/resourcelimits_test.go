@@ -0,0 +1,75 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestMissingResourceLimitsDetector(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			switch query {
+			case missingLimitsQuery:
+				return model.Vector{
+					// Two replicas of one Deployment, one bare pod
+					&model.Sample{Metric: model.Metric{"namespace": "prod", "pod": "api-7d9f8c6b5-xk2lp", "owner_kind": "ReplicaSet", "owner_name": "api-7d9f8c6b5"}, Value: 2},
+					&model.Sample{Metric: model.Metric{"namespace": "prod", "pod": "api-7d9f8c6b5-q8w4n", "owner_kind": "ReplicaSet", "owner_name": "api-7d9f8c6b5"}, Value: 2},
+					&model.Sample{Metric: model.Metric{"namespace": "prod", "pod": "debug", "owner_kind": "<none>", "owner_name": "<none>"}, Value: 1},
+				}, nil
+			case missingRequestsQuery:
+				return model.Vector{
+					&model.Sample{Metric: model.Metric{"namespace": "prod", "pod": "db-0", "owner_kind": "StatefulSet", "owner_name": "db"}, Value: 1},
+				}, nil
+			}
+			return nil, errors.New("unexpected query")
+		},
+	}
+
+	problems, err := NewMissingResourceLimitsDetector().Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 3 {
+		t.Fatalf("expected one problem per workload, got %d", len(problems))
+	}
+
+	api := problems[0]
+	if api.Entity != "prod/api" || api.Labels["workload_kind"] != "Deployment" {
+		t.Errorf("entity %q kind %q, want prod/api Deployment", api.Entity, api.Labels["workload_kind"])
+	}
+	if api.Severity != models.SeverityWarning || api.Title != "No resource limits set" {
+		t.Errorf("got %s %q, want WARNING \"No resource limits set\"", api.Severity, api.Title)
+	}
+	if api.Metrics["containers_without_limits"] != 4 || api.Metrics["containers_without_requests"] != 0 {
+		t.Errorf("metrics = %v", api.Metrics)
+	}
+	if api.Message != "Deployment prod/api runs containers without resource limits in 2 pods" {
+		t.Errorf("message = %q", api.Message)
+	}
+
+	if db := problems[1]; db.Entity != "prod/db" || db.Title != "No resource requests set" {
+		t.Errorf("statefulset problem = %q %q", db.Entity, db.Title)
+	}
+	if bare := problems[2]; bare.Entity != "prod/debug" || bare.Labels["workload_kind"] != "Pod" {
+		t.Errorf("bare pod problem = %q %q", bare.Entity, bare.Labels["workload_kind"])
+	}
+}
+
+func TestMissingResourceLimitsDetector_ProviderError(t *testing.T) {
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	if _, err := NewMissingResourceLimitsDetector().Detect(context.Background(), mockProvider, 5*time.Minute); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -56,7 +56,6 @@ func (d *LinkerdControlPlaneDetector) Detect(ctx context.Context, provider metri
 
 		entity := fmt.Sprintf("%s/%s", namespace, deployment)
 		problem := &models.Problem{
-			ID:         fmt.Sprintf("%s/linkerd_cp_down", entity),
 			Entity:     entity,
 			EntityType: "service_mesh_control_plane",
 			Type:       "linkerd_control_plane_down",
@@ -119,7 +118,6 @@ func (d *LinkerdProxyInjectionDetector) Detect(ctx context.Context, provider met
 
 		entity := fmt.Sprintf("%s/%s/%s", namespace, pod, container)
 		problem := &models.Problem{
-			ID:         fmt.Sprintf("%s/linkerd_crash", entity),
 			Entity:     entity,
 			EntityType: "service_mesh_control_plane",
 			Type:       "linkerd_component_crash",
@@ -182,7 +180,6 @@ func (d *IstioControlPlaneDetector) Detect(ctx context.Context, provider metrics
 
 		entity := fmt.Sprintf("%s/%s", namespace, deployment)
 		problem := &models.Problem{
-			ID:         fmt.Sprintf("%s/istio_cp_down", entity),
 			Entity:     entity,
 			EntityType: "service_mesh_control_plane",
 			Type:       "istio_control_plane_down",
@@ -245,7 +242,6 @@ func (d *IstioSidecarInjectionDetector) Detect(ctx context.Context, provider met
 
 		entity := fmt.Sprintf("%s/%s/%s", namespace, pod, container)
 		problem := &models.Problem{
-			ID:         fmt.Sprintf("%s/istio_crash", entity),
 			Entity:     entity,
 			EntityType: "service_mesh_control_plane",
 			Type:       "istio_component_crash",
@@ -64,7 +64,6 @@ func (d *LinkerdCertExpiryDetector) Detect(ctx context.Context, provider metrics
 
 		entity := fmt.Sprintf("%s/identity-cert", namespace)
 		problem := &models.Problem{
-			ID:         fmt.Sprintf("%s/linkerd_cert_expiry", entity),
 			Entity:     entity,
 			EntityType: "service_mesh_certificate",
 			Type:       "linkerd_cert_expiry",
@@ -133,7 +132,6 @@ func (d *IstioCertExpiryDetector) Detect(ctx context.Context, provider metrics.M
 
 		entity := fmt.Sprintf("%s/root-cert", namespace)
 		problem := &models.Problem{
-			ID:         fmt.Sprintf("%s/istio_cert_expiry", entity),
 			Entity:     entity,
 			EntityType: "service_mesh_certificate",
 			Type:       "istio_cert_expiry",
@@ -0,0 +1,70 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+)
+
+// sustainedStep is the resolution sustained conditions are evaluated at
+const sustainedStep = 30 * time.Second
+
+// Sustainable is implemented by detectors that can require their condition to
+// hold for a whole window before reporting, ignoring momentary spikes
+type Sustainable interface {
+	SetSustainedWindow(window time.Duration)
+}
+
+// queryAbove returns the series of expr whose value is above threshold.
+// With sustained == 0 this is a single instant query. Otherwise expr is
+// range-queried over the last sustained window and a series only counts if it
+// was above threshold at every step; the returned sample is its latest value.
+func queryAbove(ctx context.Context, provider metrics.MetricsProvider, expr string, threshold float64, sustained time.Duration) (model.Vector, error) {
+	now := time.Now()
+	if sustained <= 0 {
+		return provider.QueryInstant(ctx, fmt.Sprintf("%s > %f", expr, threshold), now)
+	}
+
+	matrix, err := provider.QueryRange(ctx, expr, now.Add(-sustained), now, sustainedStep)
+	if err != nil {
+		return nil, err
+	}
+
+	// A gap (missing scrape, series only just appeared) breaks the streak
+	// just like a value under the threshold does
+	steps := int(sustained/sustainedStep) + 1
+	result := make(model.Vector, 0)
+	for _, series := range matrix {
+		if len(series.Values) < steps || !allAbove(series.Values, threshold) {
+			continue
+		}
+		last := series.Values[len(series.Values)-1]
+		result = append(result, &model.Sample{
+			Metric:    series.Metric,
+			Value:     last.Value,
+			Timestamp: last.Timestamp,
+		})
+	}
+	return result, nil
+}
+
+func allAbove(values []model.SamplePair, threshold float64) bool {
+	for _, v := range values {
+		if float64(v.Value) <= threshold {
+			return false
+		}
+	}
+	return true
+}
+
+// sustainedSuffix describes the sustained window in hints, " for 5m0s"
+func sustainedSuffix(sustained time.Duration) string {
+	if sustained <= 0 {
+		return ""
+	}
+	return " for " + sustained.String()
+}
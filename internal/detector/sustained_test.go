@@ -0,0 +1,83 @@
+package detector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+)
+
+// rangeProvider serves one series per node with the given per-step values
+func rangeProvider(t *testing.T, series map[string][]float64) *metrics.MockProvider {
+	return &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			t.Errorf("sustained detection should not run instant query %q", query)
+			return nil, nil
+		},
+		QueryRangeFunc: func(ctx context.Context, query string, start, end time.Time, step time.Duration) (model.Matrix, error) {
+			matrix := model.Matrix{}
+			for node, values := range series {
+				s := &model.SampleStream{Metric: model.Metric{"instance": model.LabelValue(node)}}
+				for i, v := range values {
+					ts := start.Add(time.Duration(i) * step)
+					s.Values = append(s.Values, model.SamplePair{Timestamp: model.TimeFromUnixNano(ts.UnixNano()), Value: model.SampleValue(v)})
+				}
+				matrix = append(matrix, s)
+			}
+			return matrix, nil
+		},
+	}
+}
+
+func TestHighMemoryPressureDetector_Sustained(t *testing.T) {
+	// 5 minutes at 30s steps = 11 points
+	provider := rangeProvider(t, map[string][]float64{
+		"sustained":    {0.95, 0.95, 0.96, 0.94, 0.95, 0.97, 0.95, 0.96, 0.95, 0.94, 0.96},
+		"intermittent": {0.95, 0.70, 0.96, 0.60, 0.95, 0.97, 0.65, 0.96, 0.95, 0.70, 0.99},
+		"recent-spike": {0.97, 0.98, 0.99},
+	})
+
+	d := NewHighMemoryPressureDetector()
+	d.SetSustainedWindow(5 * time.Minute)
+	problems, err := d.Detect(context.Background(), provider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+	if problems[0].Entity != "sustained" {
+		t.Errorf("entity = %q, want sustained", problems[0].Entity)
+	}
+	if got := problems[0].Metrics["memory_usage_percent"]; got != 96 {
+		t.Errorf("memory_usage_percent = %v, want latest value 96", got)
+	}
+}
+
+func TestHighErrorRateDetector_Sustained(t *testing.T) {
+	sustained := []float64{0.2, 0.2, 0.2, 0.2, 0.2}
+	intermittent := []float64{0.2, 0.01, 0.2, 0.01, 0.2}
+
+	d := NewHighErrorRateDetector()
+	d.SetSustainedWindow(2 * time.Minute)
+
+	problems, err := d.Detect(context.Background(), rangeProvider(t, map[string][]float64{"svc": intermittent}), 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("intermittent error rate should not be reported, got %d problems", len(problems))
+	}
+
+	problems, err = d.Detect(context.Background(), rangeProvider(t, map[string][]float64{"svc": sustained}), 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Errorf("sustained error rate should be reported, got %d problems", len(problems))
+	}
+}
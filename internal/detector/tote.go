@@ -14,6 +14,10 @@ const (
 	blastRadiusSalvage   = 5
 	blastRadiusPush      = 3
 	blastRadiusDetection = 3
+
+	// Push failures and the salvageable share are judged over a longer
+	// window than DefaultWindow
+	toteLongWindow = 10 * time.Minute
 )
 
 // ToteSalvageFailureDetector detects failing tote image salvage operations
@@ -29,8 +33,8 @@ func (d *ToteSalvageFailureDetector) Name() string            { return "tote_sal
 func (d *ToteSalvageFailureDetector) EntityTypes() []string   { return []string{"tote_salvage"} }
 func (d *ToteSalvageFailureDetector) Interval() time.Duration { return d.interval }
 
-func (d *ToteSalvageFailureDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
-	query := `increase(tote_salvage_failures_total[5m]) > 0`
+func (d *ToteSalvageFailureDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	query := fmt.Sprintf(`increase(tote_salvage_failures_total%s) > 0`, rangeSelector(window))
 	result, err := provider.QueryInstant(ctx, query, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("tote salvage failure query failed: %w", err)
@@ -40,13 +44,12 @@ func (d *ToteSalvageFailureDetector) Detect(ctx context.Context, provider metric
 	for _, sample := range result {
 		failures := float64(sample.Value)
 		problems = append(problems, &models.Problem{
-			ID:          "tote/salvage_failure",
 			Entity:      "tote/salvage",
 			EntityType:  "tote_salvage",
 			Type:        "tote_salvage_failure",
 			Severity:    models.SeverityCritical,
-			Title:       fmt.Sprintf("Image salvage failing (%.0f failures in 5m)", failures),
-			Message:     fmt.Sprintf("tote: %.0f image salvage operations failed in the last 5 minutes", failures),
+			Title:       fmt.Sprintf("Image salvage failing (%.0f failures in %s)", failures, windowLabel(window)),
+			Message:     fmt.Sprintf("tote: %.0f image salvage operations failed in the last %s", failures, windowLabel(window)),
 			Labels:      map[string]string{},
 			Metrics:     map[string]float64{"failures_5m": failures},
 			Hint:        "Check tote controller logs and agent connectivity",
@@ -70,8 +73,11 @@ func (d *TotePushFailureDetector) Name() string            { return "tote_push_f
 func (d *TotePushFailureDetector) EntityTypes() []string   { return []string{"tote_push"} }
 func (d *TotePushFailureDetector) Interval() time.Duration { return d.interval }
 
-func (d *TotePushFailureDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
-	query := `increase(tote_push_failures_total[10m]) > 0`
+// DefaultWindow spans a couple of push retries
+func (d *TotePushFailureDetector) DefaultWindow() time.Duration { return toteLongWindow }
+
+func (d *TotePushFailureDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	query := fmt.Sprintf(`increase(tote_push_failures_total%s) > 0`, rangeSelector(window))
 	result, err := provider.QueryInstant(ctx, query, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("tote push failure query failed: %w", err)
@@ -81,13 +87,12 @@ func (d *TotePushFailureDetector) Detect(ctx context.Context, provider metrics.M
 	for _, sample := range result {
 		failures := float64(sample.Value)
 		problems = append(problems, &models.Problem{
-			ID:          "tote/push_failure",
 			Entity:      "tote/push",
 			EntityType:  "tote_push",
 			Type:        "tote_push_failure",
 			Severity:    models.SeverityWarning,
-			Title:       fmt.Sprintf("Backup registry push failing (%.0f failures in 10m)", failures),
-			Message:     fmt.Sprintf("tote: %.0f backup registry push operations failed in the last 10 minutes", failures),
+			Title:       fmt.Sprintf("Backup registry push failing (%.0f failures in %s)", failures, windowLabel(window)),
+			Message:     fmt.Sprintf("tote: %.0f backup registry push operations failed in the last %s", failures, windowLabel(window)),
 			Labels:      map[string]string{},
 			Metrics:     map[string]float64{"failures_10m": failures},
 			Hint:        "Check backup registry connectivity and credentials",
@@ -111,9 +116,13 @@ func (d *ToteHighFailureRateDetector) Name() string            { return "tote_hi
 func (d *ToteHighFailureRateDetector) EntityTypes() []string   { return []string{"tote_detection"} }
 func (d *ToteHighFailureRateDetector) Interval() time.Duration { return d.interval }
 
-func (d *ToteHighFailureRateDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, _ time.Duration) ([]*models.Problem, error) {
+// DefaultWindow collects enough pull failures for the ratio to mean something
+func (d *ToteHighFailureRateDetector) DefaultWindow() time.Duration { return toteLongWindow }
+
+func (d *ToteHighFailureRateDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
 	// Only fire when there are detected failures AND most are not actionable (tag-based, not digest)
-	query := `increase(tote_not_actionable_total[10m]) > increase(tote_salvageable_images_total[10m]) and increase(tote_detected_failures_total[10m]) > 0`
+	r := rangeSelector(window)
+	query := fmt.Sprintf(`increase(tote_not_actionable_total%s) > increase(tote_salvageable_images_total%s) and increase(tote_detected_failures_total%s) > 0`, r, r, r)
 	result, err := provider.QueryInstant(ctx, query, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("tote high failure rate query failed: %w", err)
@@ -123,12 +132,11 @@ func (d *ToteHighFailureRateDetector) Detect(ctx context.Context, provider metri
 	for _, sample := range result {
 		notActionable := float64(sample.Value)
 		problems = append(problems, &models.Problem{
-			ID:          "tote/high_failure_rate",
 			Entity:      "tote/detection",
 			EntityType:  "tote_detection",
 			Type:        "tote_high_failure_rate",
 			Severity:    models.SeverityWarning,
-			Title:       fmt.Sprintf("Most image failures not salvageable (%.0f tag-based in 10m)", notActionable),
+			Title:       fmt.Sprintf("Most image failures not salvageable (%.0f tag-based in %s)", notActionable, windowLabel(window)),
 			Message:     "tote: more image pull failures use tags than digests — tote cannot salvage tag-based references",
 			Labels:      map[string]string{},
 			Metrics:     map[string]float64{"not_actionable_10m": notActionable},
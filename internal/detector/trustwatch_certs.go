@@ -5,18 +5,45 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/prometheus/common/model"
+
 	"github.com/ppiankov/infranow/internal/metrics"
 	"github.com/ppiankov/infranow/internal/models"
 )
 
+const (
+	// trustwatchIssuerSource is the trustwatch source for CA/issuer certs
+	trustwatchIssuerSource = "mesh-issuer"
+
+	// Blast radius by certificate source
+	blastRadiusIssuerCert     = 50 // Invalidates every leaf it signed
+	blastRadiusAPIServiceCert = 15 // Breaks an aggregated API (kubectl, HPA)
+	blastRadiusWebhookCert    = 10 // Blocks admission for matching resources
+	blastRadiusLeafCert       = 3  // Single endpoint
+)
+
+// DefaultTrustwatchBlastRadii returns the blast radius per trustwatch source.
+// Sources not listed are treated as leaf certificates.
+func DefaultTrustwatchBlastRadii() map[string]int {
+	return map[string]int{
+		trustwatchIssuerSource: blastRadiusIssuerCert,
+		"apiservice":           blastRadiusAPIServiceCert,
+		"webhook":              blastRadiusWebhookCert,
+	}
+}
+
 // TrustwatchCertExpiryDetector detects certificates nearing expiry via trustwatch metrics
 type TrustwatchCertExpiryDetector struct {
-	interval time.Duration
+	interval    time.Duration
+	blastRadii  map[string]int  // Keyed by trustwatch source
+	skipWarning map[string]bool // Sources reported only from CRITICAL up
 }
 
 func NewTrustwatchCertExpiryDetector() *TrustwatchCertExpiryDetector {
 	return &TrustwatchCertExpiryDetector{
-		interval: certCheckInterval,
+		interval:    certCheckInterval,
+		blastRadii:  DefaultTrustwatchBlastRadii(),
+		skipWarning: make(map[string]bool),
 	}
 }
 
@@ -32,6 +59,32 @@ func (d *TrustwatchCertExpiryDetector) Interval() time.Duration {
 	return d.interval
 }
 
+// SetSourceBlastRadius overrides the blast radius for certificates from source
+func (d *TrustwatchCertExpiryDetector) SetSourceBlastRadius(source string, radius int) {
+	d.blastRadii[source] = radius
+}
+
+// SkipSourceWarnings drops WARNING-level expiry problems for certificates
+// from source. For short-lived certificates (SPIFFE, cert-manager with a few
+// days' lifetime) the 7 day warning window covers their whole normal life;
+// they are still reported once CRITICAL, i.e. when rotation is overdue.
+func (d *TrustwatchCertExpiryDetector) SkipSourceWarnings(source string) {
+	d.skipWarning[source] = true
+}
+
+// blastRadius returns the blast radius for a certificate. Issuers reported
+// under another source still get at least the issuer blast radius.
+func (d *TrustwatchCertExpiryDetector) blastRadius(source string, issuer bool) int {
+	radius, ok := d.blastRadii[source]
+	if !ok {
+		radius = blastRadiusLeafCert
+	}
+	if issuer {
+		radius = max(radius, d.blastRadii[trustwatchIssuerSource])
+	}
+	return radius
+}
+
 func (d *TrustwatchCertExpiryDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
 	query := fmt.Sprintf(`trustwatch_cert_expires_in_seconds < %d`, certWarningThreshold)
 	result, err := provider.QueryInstant(ctx, query, time.Now())
@@ -48,14 +101,26 @@ func (d *TrustwatchCertExpiryDetector) Detect(ctx context.Context, provider metr
 		namespace := string(sample.Metric["namespace"])
 		name := string(sample.Metric["name"])
 
+		title := fmt.Sprintf("Certificate expiring in %s", formatDuration(remainingSeconds))
+		issuer := isIssuerCert(sample.Metric)
+		if issuer {
+			// A CA expiry takes down every leaf it signed at once
+			if severity == models.SeverityWarning {
+				severity = models.SeverityCritical
+			}
+			title = fmt.Sprintf("Issuer certificate expiring in %s", formatDuration(remainingSeconds))
+		}
+		if severity == models.SeverityWarning && d.skipWarning[source] {
+			continue
+		}
+
 		entity := fmt.Sprintf("trustwatch/%s/%s/%s", source, namespace, name)
 		problem := &models.Problem{
-			ID:         fmt.Sprintf("%s/trustwatch_cert_expiry", entity),
 			Entity:     entity,
 			EntityType: "trustwatch_certificate",
 			Type:       "trustwatch_cert_expiry",
 			Severity:   severity,
-			Title:      fmt.Sprintf("Certificate expiring in %s", formatDuration(remainingSeconds)),
+			Title:      title,
 			Message:    fmt.Sprintf("trustwatch: %s/%s cert expires in %s", namespace, name, formatDuration(remainingSeconds)),
 			Labels: map[string]string{
 				"source":    source,
@@ -67,7 +132,11 @@ func (d *TrustwatchCertExpiryDetector) Detect(ctx context.Context, provider metr
 			},
 			Hint:        "Run: trustwatch now",
 			RunbookURL:  models.RunbookBaseURL + "trustwatch_cert_expiry.md",
-			BlastRadius: blastRadiusMeshComponent,
+			BlastRadius: d.blastRadius(source, issuer),
+		}
+		if issuer {
+			problem.Labels["kind"] = "issuer"
+			problem.Hint = "Rotate the issuer before it expires; every certificate it signed stops validating"
 		}
 		problems = append(problems, problem)
 	}
@@ -75,6 +144,12 @@ func (d *TrustwatchCertExpiryDetector) Detect(ctx context.Context, provider metr
 	return problems, nil
 }
 
+// isIssuerCert reports whether a trustwatch sample describes a CA/issuer
+// certificate rather than a leaf
+func isIssuerCert(metric model.Metric) bool {
+	return metric["kind"] == "issuer" || metric["source"] == trustwatchIssuerSource
+}
+
 // TrustwatchProbeFailureDetector detects TLS endpoints that trustwatch cannot reach
 type TrustwatchProbeFailureDetector struct {
 	interval time.Duration
@@ -113,7 +188,6 @@ func (d *TrustwatchProbeFailureDetector) Detect(ctx context.Context, provider me
 
 		entity := fmt.Sprintf("trustwatch/%s/%s/%s", source, namespace, name)
 		problem := &models.Problem{
-			ID:         fmt.Sprintf("%s/trustwatch_probe_failure", entity),
 			Entity:     entity,
 			EntityType: "trustwatch_certificate",
 			Type:       "trustwatch_probe_failure",
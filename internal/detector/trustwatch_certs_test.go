@@ -228,6 +228,159 @@ func TestTrustwatchCertExpiryDetector_MultipleCerts(t *testing.T) {
 	}
 }
 
+func TestTrustwatchCertExpiryDetector_IssuerOutranksLeaf(t *testing.T) {
+	remainingSeconds := model.SampleValue(5 * 24 * 3600) // WARNING for a leaf
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{
+				&model.Sample{
+					Metric: model.Metric{
+						"source":    "webhook",
+						"namespace": "kube-system",
+						"name":      "cert-manager-webhook",
+					},
+					Value: remainingSeconds,
+				},
+				&model.Sample{
+					Metric: model.Metric{
+						"source":    "mesh-issuer",
+						"namespace": "linkerd",
+						"name":      "linkerd-identity-issuer",
+					},
+					Value: remainingSeconds,
+				},
+				&model.Sample{
+					Metric: model.Metric{
+						"source":    "secret",
+						"namespace": "cert-manager",
+						"name":      "root-ca",
+						"kind":      "issuer",
+					},
+					Value: remainingSeconds,
+				},
+			}, nil
+		},
+	}
+
+	problems, err := NewTrustwatchCertExpiryDetector().Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 3 {
+		t.Fatalf("expected 3 problems, got %d", len(problems))
+	}
+
+	leaf := problems[0]
+	if leaf.Severity != models.SeverityWarning {
+		t.Errorf("leaf severity = %v, want WARNING", leaf.Severity)
+	}
+	for _, issuer := range problems[1:] {
+		if issuer.BlastRadius <= leaf.BlastRadius {
+			t.Errorf("%s: issuer blast radius %d should exceed leaf %d", issuer.Entity, issuer.BlastRadius, leaf.BlastRadius)
+		}
+		if issuer.Severity != models.SeverityCritical {
+			t.Errorf("%s: issuer severity = %v, want at least CRITICAL", issuer.Entity, issuer.Severity)
+		}
+		if issuer.Labels["kind"] != "issuer" {
+			t.Errorf("%s: expected kind=issuer label", issuer.Entity)
+		}
+		if issuer.Score() <= leaf.Score() {
+			t.Errorf("%s: issuer should outrank leaf", issuer.Entity)
+		}
+	}
+}
+
+func TestTrustwatchCertExpiryDetector_BlastRadiusBySource(t *testing.T) {
+	sources := []string{"mesh-issuer", "apiservice", "webhook", "ingress"}
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			var vec model.Vector
+			for _, source := range sources {
+				vec = append(vec, &model.Sample{
+					Metric: model.Metric{"source": model.LabelValue(source), "namespace": "ns", "name": "cert"},
+					Value:  model.SampleValue(12 * 3600),
+				})
+			}
+			return vec, nil
+		},
+	}
+
+	d := NewTrustwatchCertExpiryDetector()
+	problems, err := d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]int{"mesh-issuer": 50, "apiservice": 15, "webhook": 10, "ingress": 3}
+	for _, p := range problems {
+		if got := p.BlastRadius; got != want[p.Labels["source"]] {
+			t.Errorf("source %s: blast radius %d, want %d", p.Labels["source"], got, want[p.Labels["source"]])
+		}
+	}
+
+	// Overrides replace the default for that source only
+	d.SetSourceBlastRadius("webhook", 30)
+	problems, err = d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, p := range problems {
+		if p.Labels["source"] == "webhook" && p.BlastRadius != 30 {
+			t.Errorf("webhook override: blast radius %d, want 30", p.BlastRadius)
+		}
+		if p.Labels["source"] == "apiservice" && p.BlastRadius != 15 {
+			t.Errorf("apiservice should keep default, got %d", p.BlastRadius)
+		}
+	}
+}
+
+func TestTrustwatchCertExpiryDetector_SkipSourceWarnings(t *testing.T) {
+	// A 7 day cert renewed at 2/3 of its life spends its whole normal life
+	// inside the 7 day warning window
+	samples := []struct {
+		source    string
+		name      string
+		remaining float64
+	}{
+		{"spiffe", "rotating", 4 * 86400},    // Normal rotation: WARNING range
+		{"spiffe", "stuck", 20 * 3600},       // Failed to rotate: FATAL
+		{"ingress", "long-lived", 5 * 86400}, // Other sources keep warnings
+	}
+	mockProvider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			var vec model.Vector
+			for _, s := range samples {
+				vec = append(vec, &model.Sample{
+					Metric: model.Metric{"source": model.LabelValue(s.source), "namespace": "ns", "name": model.LabelValue(s.name)},
+					Value:  model.SampleValue(s.remaining),
+				})
+			}
+			return vec, nil
+		},
+	}
+
+	d := NewTrustwatchCertExpiryDetector()
+	d.SkipSourceWarnings("spiffe")
+	problems, err := d.Detect(context.Background(), mockProvider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[string]models.Severity{}
+	for _, p := range problems {
+		got[p.Labels["name"]] = p.Severity
+	}
+	if _, ok := got["rotating"]; ok {
+		t.Error("short-lived cert within normal rotation should not produce a WARNING")
+	}
+	if got["stuck"] != models.SeverityFatal {
+		t.Errorf("stuck short-lived cert severity = %q, want FATAL", got["stuck"])
+	}
+	if got["long-lived"] != models.SeverityWarning {
+		t.Errorf("other source severity = %q, want WARNING", got["long-lived"])
+	}
+}
+
 func TestTrustwatchProbeFailureDetector_Failure(t *testing.T) {
 	mockProvider := &metrics.MockProvider{
 		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
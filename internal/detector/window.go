@@ -0,0 +1,47 @@
+package detector
+
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// DefaultWindow is the range detectors look back over unless configured
+// otherwise
+const DefaultWindow = 5 * time.Minute
+
+// Windowed is implemented by detectors whose queries need a different
+// default window than DefaultWindow, e.g. a per-hour restart rate that is
+// too noisy over 5 minutes
+type Windowed interface {
+	DefaultWindow() time.Duration
+}
+
+// EffectiveWindow returns the window passed to d.Detect: an override for the
+// detector by name, else base (DefaultWindow if unset), widened to the
+// detector's own default if that is longer
+func EffectiveWindow(d Detector, base time.Duration, overrides map[string]time.Duration) time.Duration {
+	if w, ok := overrides[d.Name()]; ok && w > 0 {
+		return w
+	}
+	if base <= 0 {
+		base = DefaultWindow
+	}
+	if w, ok := d.(Windowed); ok {
+		return max(base, w.DefaultWindow())
+	}
+	return base
+}
+
+// windowLabel renders window for titles and messages, e.g. 5m or 1h30m
+func windowLabel(window time.Duration) string {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return model.Duration(window).String()
+}
+
+// rangeSelector renders window as a PromQL range selector, e.g. [5m]
+func rangeSelector(window time.Duration) string {
+	return "[" + windowLabel(window) + "]"
+}
@@ -0,0 +1,91 @@
+package detector
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+)
+
+func TestEffectiveWindow(t *testing.T) {
+	oom := NewOOMKillDetector()
+	restarts := NewRestartRateDetector()
+	overrides := map[string]time.Duration{"kubernetes_oom_kills": 20 * time.Minute}
+
+	tests := []struct {
+		name      string
+		d         Detector
+		base      time.Duration
+		overrides map[string]time.Duration
+		want      time.Duration
+	}{
+		{"base", oom, DefaultWindow, nil, 5 * time.Minute},
+		{"unset base", oom, 0, nil, 5 * time.Minute},
+		{"wider base", oom, 10 * time.Minute, nil, 10 * time.Minute},
+		{"override", oom, DefaultWindow, overrides, 20 * time.Minute},
+		{"detector default", restarts, DefaultWindow, nil, 15 * time.Minute},
+		{"base wider than detector default", restarts, 30 * time.Minute, nil, 30 * time.Minute},
+		{"override narrower than detector default", restarts, DefaultWindow, map[string]time.Duration{"kubernetes_restart_rate": time.Hour}, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := EffectiveWindow(tt.d, tt.base, tt.overrides); got != tt.want {
+			t.Errorf("%s: EffectiveWindow() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRangeSelector(t *testing.T) {
+	tests := []struct {
+		window time.Duration
+		want   string
+	}{
+		{5 * time.Minute, "[5m]"},
+		{90 * time.Minute, "[1h30m]"},
+		{30 * time.Second, "[30s]"},
+		{0, "[5m]"},
+	}
+	for _, tt := range tests {
+		if got := rangeSelector(tt.window); got != tt.want {
+			t.Errorf("rangeSelector(%s) = %q, want %q", tt.window, got, tt.want)
+		}
+	}
+}
+
+// The window passed to Detect must end up in every range selector the
+// detector queries with
+func TestDetect_UsesWindow(t *testing.T) {
+	for _, d := range []Detector{
+		NewOOMKillDetector(),
+		NewRestartRateDetector(),
+		NewHighErrorRateDetector(),
+		NewIngressErrorRateDetector(),
+		NewMySQLDeadlocksDetector(),
+		NewPrometheusRuleEvaluationDetector(),
+		NewToteSalvageFailureDetector(),
+		NewTotePushFailureDetector(),
+		NewToteHighFailureRateDetector(),
+	} {
+		var queries []string
+		provider := &metrics.MockProvider{
+			QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+				queries = append(queries, query)
+				return model.Vector{}, nil
+			},
+		}
+		if _, err := d.Detect(context.Background(), provider, 20*time.Minute); err != nil {
+			t.Fatalf("%s: unexpected error: %v", d.Name(), err)
+		}
+		if len(queries) == 0 || !strings.Contains(queries[0], "[20m]") {
+			t.Errorf("%s: queries %q do not use the 20m window", d.Name(), queries)
+		}
+		for _, q := range queries {
+			if strings.Contains(q, "[5m]") || strings.Contains(q, "[10m]") || strings.Contains(q, "[15m]") {
+				t.Errorf("%s: query still hard-codes its window: %s", d.Name(), q)
+			}
+		}
+	}
+}
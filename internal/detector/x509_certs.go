@@ -0,0 +1,141 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// X509CertExpiryDetector detects certificates nearing expiry via the
+// x509-certificate-exporter, which watches certificate files on nodes
+// (kubelet, etcd, control plane PKI) and in Kubernetes secrets
+type X509CertExpiryDetector struct {
+	interval time.Duration
+}
+
+func NewX509CertExpiryDetector() *X509CertExpiryDetector {
+	return &X509CertExpiryDetector{
+		interval: certCheckInterval,
+	}
+}
+
+func (d *X509CertExpiryDetector) Name() string {
+	return "x509_cert_expiry"
+}
+
+func (d *X509CertExpiryDetector) EntityTypes() []string {
+	return []string{"x509_certificate"}
+}
+
+func (d *X509CertExpiryDetector) Interval() time.Duration {
+	return d.interval
+}
+
+func (d *X509CertExpiryDetector) Detect(ctx context.Context, provider metrics.MetricsProvider, window time.Duration) ([]*models.Problem, error) {
+	query := fmt.Sprintf(`(x509_cert_not_after - time()) < %d`, certWarningThreshold)
+	result, err := provider.QueryInstant(ctx, query, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("x509 cert expiry query failed: %w", err)
+	}
+
+	problems := make([]*models.Problem, 0)
+	for _, sample := range result {
+		remainingSeconds := float64(sample.Value)
+		severity := certSeverity(remainingSeconds)
+
+		subject := string(sample.Metric["subject_CN"])
+		location := x509Location(sample.Metric)
+		entity := "x509/cn/" + subject
+		if location != "" {
+			// A bundle holds several certificates at one location
+			entity = "x509/" + location + "/" + subject
+		} else {
+			location = "unknown location"
+		}
+
+		title := fmt.Sprintf("Certificate expiring in %s", formatDuration(remainingSeconds))
+		blastRadius := blastRadiusLeafCert
+		ca := isSelfSignedCert(sample.Metric)
+		if ca {
+			// A root CA expiry breaks every certificate chained to it
+			if severity == models.SeverityWarning {
+				severity = models.SeverityCritical
+			}
+			title = fmt.Sprintf("CA certificate expiring in %s", formatDuration(remainingSeconds))
+			blastRadius = blastRadiusIssuerCert
+		}
+
+		labels := map[string]string{
+			"subject_cn": subject,
+			"issuer_cn":  string(sample.Metric["issuer_CN"]),
+		}
+		for label, key := range map[model.LabelName]string{
+			"secret_namespace": "namespace",
+			"secret_name":      "secret",
+			"filepath":         "filepath",
+			"node":             "node",
+			"serial_number":    "serial",
+		} {
+			if v := sample.Metric[label]; v != "" {
+				labels[key] = string(v)
+			}
+		}
+		if ca {
+			labels["kind"] = "issuer"
+		}
+
+		problem := &models.Problem{
+			Entity:     entity,
+			EntityType: "x509_certificate",
+			Type:       "x509_cert_expiry",
+			Severity:   severity,
+			Title:      title,
+			Message:    fmt.Sprintf("x509: certificate %q at %s expires in %s", subject, location, formatDuration(remainingSeconds)),
+			Labels:     labels,
+			Metrics: map[string]float64{
+				"remaining_seconds": remainingSeconds,
+			},
+			Hint:        "Renew the certificate, e.g. kubeadm certs renew for control plane PKI",
+			RunbookURL:  models.RunbookBaseURL + "x509_cert_expiry.md",
+			BlastRadius: blastRadius,
+		}
+		problems = append(problems, problem)
+	}
+
+	return problems, nil
+}
+
+// x509Location names where a certificate lives: secret/<namespace>/<name>
+// for secrets, file/<node><path> for files on a node; empty if neither
+func x509Location(metric model.Metric) string {
+	if name := metric["secret_name"]; name != "" {
+		location := fmt.Sprintf("secret/%s/%s", metric["secret_namespace"], name)
+		if key := metric["secret_key"]; key != "" {
+			location += "/" + string(key)
+		}
+		return location
+	}
+	if path := string(metric["filepath"]); path != "" {
+		node := string(metric["node"])
+		if node == "" {
+			node = string(metric["instance"])
+		}
+		if node == "" {
+			return "file" + path
+		}
+		return "file/" + node + path
+	}
+	return ""
+}
+
+// isSelfSignedCert reports whether the certificate is its own issuer, i.e. a
+// root CA
+func isSelfSignedCert(metric model.Metric) bool {
+	subject := metric["subject_CN"]
+	return subject != "" && subject == metric["issuer_CN"]
+}
@@ -0,0 +1,164 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// x509Provider returns samples as x509_cert_not_after - time() would
+func x509Provider(samples ...*model.Sample) *metrics.MockProvider {
+	return &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector(samples), nil
+		},
+	}
+}
+
+func TestX509CertExpiryDetector_Warning(t *testing.T) {
+	provider := x509Provider(&model.Sample{
+		Metric: model.Metric{
+			"subject_CN":       "api.example.com",
+			"issuer_CN":        "Example Intermediate CA",
+			"secret_namespace": "prod",
+			"secret_name":      "api-tls",
+			"secret_key":       "tls.crt",
+			"serial_number":    "4f3a",
+		},
+		Value: model.SampleValue(5 * 24 * 3600.0), // 5 days
+	})
+
+	problems, err := NewX509CertExpiryDetector().Detect(context.Background(), provider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	if p.Severity != models.SeverityWarning {
+		t.Errorf("expected WARNING severity for 5 days remaining, got %v", p.Severity)
+	}
+	if p.Entity != "x509/secret/prod/api-tls/tls.crt/api.example.com" {
+		t.Errorf("unexpected entity: %s", p.Entity)
+	}
+	if p.Type != "x509_cert_expiry" || p.EntityType != "x509_certificate" {
+		t.Errorf("unexpected type %q / entity type %q", p.Type, p.EntityType)
+	}
+	if p.Labels["namespace"] != "prod" || p.Labels["secret"] != "api-tls" || p.Labels["serial"] != "4f3a" {
+		t.Errorf("unexpected labels: %v", p.Labels)
+	}
+	if p.BlastRadius != blastRadiusLeafCert {
+		t.Errorf("expected leaf blast radius, got %d", p.BlastRadius)
+	}
+}
+
+func TestX509CertExpiryDetector_Severities(t *testing.T) {
+	tests := []struct {
+		remaining float64
+		want      models.Severity
+	}{
+		{36 * 3600, models.SeverityCritical},
+		{12 * 3600, models.SeverityFatal},
+		{-3600, models.SeverityFatal}, // Already expired
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%.0fs", tt.remaining), func(t *testing.T) {
+			provider := x509Provider(&model.Sample{
+				Metric: model.Metric{
+					"subject_CN": "kubelet",
+					"issuer_CN":  "kubernetes",
+					"filepath":   "/var/lib/kubelet/pki/kubelet.crt",
+					"node":       "node-1",
+				},
+				Value: model.SampleValue(tt.remaining),
+			})
+			problems, err := NewX509CertExpiryDetector().Detect(context.Background(), provider, 5*time.Minute)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(problems) != 1 {
+				t.Fatalf("expected 1 problem, got %d", len(problems))
+			}
+			if problems[0].Severity != tt.want {
+				t.Errorf("severity = %v, want %v", problems[0].Severity, tt.want)
+			}
+			if problems[0].Entity != "x509/file/node-1/var/lib/kubelet/pki/kubelet.crt/kubelet" {
+				t.Errorf("unexpected entity: %s", problems[0].Entity)
+			}
+		})
+	}
+}
+
+func TestX509CertExpiryDetector_SelfSignedCA(t *testing.T) {
+	provider := x509Provider(&model.Sample{
+		Metric: model.Metric{
+			"subject_CN": "etcd-ca",
+			"issuer_CN":  "etcd-ca",
+			"filepath":   "/etc/kubernetes/pki/etcd/ca.crt",
+			"instance":   "10.0.0.5:9793",
+		},
+		Value: model.SampleValue(6 * 24 * 3600.0),
+	})
+
+	problems, err := NewX509CertExpiryDetector().Detect(context.Background(), provider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+	p := problems[0]
+	if p.Severity != models.SeverityCritical || p.BlastRadius != blastRadiusIssuerCert || p.Labels["kind"] != "issuer" {
+		t.Errorf("self-signed CA should be CRITICAL with the issuer blast radius, got %v / %d / %v", p.Severity, p.BlastRadius, p.Labels)
+	}
+	if p.Entity != "x509/file/10.0.0.5:9793/etc/kubernetes/pki/etcd/ca.crt/etcd-ca" {
+		t.Errorf("unexpected entity: %s", p.Entity)
+	}
+}
+
+func TestX509CertExpiryDetector_BundleCertsAreDistinct(t *testing.T) {
+	metric := func(cn string) model.Metric {
+		return model.Metric{"subject_CN": model.LabelValue(cn), "issuer_CN": "root", "filepath": "/etc/ssl/bundle.pem", "node": "node-1"}
+	}
+	provider := x509Provider(
+		&model.Sample{Metric: metric("leaf-a"), Value: 3600 * 30},
+		&model.Sample{Metric: metric("leaf-b"), Value: 3600 * 30},
+	)
+
+	problems, err := NewX509CertExpiryDetector().Detect(context.Background(), provider, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 2 || problems[0].Entity == problems[1].Entity {
+		t.Errorf("certificates sharing a file should get distinct entities, got %d problems", len(problems))
+	}
+}
+
+func TestX509CertExpiryDetector_NoProblems(t *testing.T) {
+	problems, err := NewX509CertExpiryDetector().Detect(context.Background(), x509Provider(), 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("expected 0 problems, got %d", len(problems))
+	}
+}
+
+func TestX509CertExpiryDetector_ProviderError(t *testing.T) {
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	}
+	if _, err := NewX509CertExpiryDetector().Detect(context.Background(), provider, 5*time.Minute); err == nil {
+		t.Fatal("expected error when provider fails")
+	}
+}
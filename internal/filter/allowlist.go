@@ -0,0 +1,93 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// AllowRule matches permanently accepted problems. Every field that is set
+// must match; Entity is a glob like the namespace filters ("test/*").
+type AllowRule struct {
+	ID     string            `json:"id,omitempty"`
+	Entity string            `json:"entity,omitempty"`
+	Type   string            `json:"type,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Reason string            `json:"reason,omitempty"` // Why the problem is accepted
+}
+
+// AllowList holds known-good problems. Unlike a silence it never expires:
+// matching problems are kept out of counts, --fail-on and notifications.
+type AllowList struct {
+	Rules []AllowRule `json:"allow"`
+}
+
+// LoadAllowList reads and validates an allow-list file (YAML or JSON)
+func LoadAllowList(path string) (*AllowList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read allow-list: %w", err)
+	}
+	var list AllowList
+	if err := yaml.UnmarshalStrict(data, &list); err != nil {
+		return nil, fmt.Errorf("parse allow-list: %w", err)
+	}
+	for i, r := range list.Rules {
+		if r.ID == "" && r.Entity == "" && r.Type == "" && len(r.Labels) == 0 {
+			return nil, fmt.Errorf("allow[%d]: set at least one of id, entity, type, labels", i)
+		}
+		if _, err := filepath.Match(r.Entity, ""); err != nil {
+			return nil, fmt.Errorf("allow[%d].entity: invalid pattern %q", i, r.Entity)
+		}
+	}
+	return &list, nil
+}
+
+// Match returns the first rule matching p
+func (a *AllowList) Match(p *models.Problem) (AllowRule, bool) {
+	for _, r := range a.Rules {
+		if r.matches(p) {
+			return r, true
+		}
+	}
+	return AllowRule{}, false
+}
+
+func (r AllowRule) matches(p *models.Problem) bool {
+	if r.ID != "" && r.ID != p.ID {
+		return false
+	}
+	if r.Entity != "" && !matchPattern(r.Entity, p.Entity) {
+		return false
+	}
+	if r.Type != "" && r.Type != p.Type {
+		return false
+	}
+	for k, v := range r.Labels {
+		if p.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Split separates allow-listed problems from the rest. The allowed ones are
+// marked Allowed with the rule's reason.
+func (a *AllowList) Split(problems []*models.Problem) (kept, allowed []*models.Problem) {
+	kept = make([]*models.Problem, 0, len(problems))
+	for _, p := range problems {
+		rule, ok := a.Match(p)
+		if !ok {
+			kept = append(kept, p)
+			continue
+		}
+		p.Allowed = true
+		p.AllowReason = rule.Reason
+		allowed = append(allowed, p)
+	}
+	return kept, allowed
+}
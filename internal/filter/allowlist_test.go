@@ -0,0 +1,90 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func writeAllowList(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "allow.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAllowList(t *testing.T) {
+	path := writeAllowList(t, `
+allow:
+  - entity: "sandbox/*"
+    type: crashloopbackoff
+    reason: sandbox demo pod always crashes
+  - labels:
+      namespace: load-test
+`)
+	list, err := LoadAllowList(path)
+	if err != nil {
+		t.Fatalf("LoadAllowList: %v", err)
+	}
+	if len(list.Rules) != 2 || list.Rules[0].Reason != "sandbox demo pod always crashes" {
+		t.Errorf("unexpected rules %+v", list.Rules)
+	}
+}
+
+func TestLoadAllowList_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"empty rule":  "allow:\n  - reason: nothing to match\n",
+		"bad glob":    "allow:\n  - entity: \"[\"\n",
+		"unknown key": "allow:\n  - namespace: test\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadAllowList(writeAllowList(t, content)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestAllowList_Split(t *testing.T) {
+	list := &AllowList{Rules: []AllowRule{
+		{ID: "fixed-id"},
+		{Entity: "sandbox/*", Type: "crashloopbackoff", Reason: "demo"},
+		{Labels: map[string]string{"namespace": "load-test"}},
+	}}
+	problems := []*models.Problem{
+		{ID: "fixed-id", Entity: "prod/db", Type: "disk_space"},
+		{ID: "a", Entity: "sandbox/demo", Type: "crashloopbackoff"},
+		{ID: "b", Entity: "sandbox/demo", Type: "oom_kill"},
+		{ID: "c", Entity: "load-test/gen", Labels: map[string]string{"namespace": "load-test"}},
+		{ID: "d", Entity: "prod/api", Labels: map[string]string{"namespace": "prod"}},
+	}
+
+	kept, allowed := list.Split(problems)
+	if len(kept) != 2 || kept[0].ID != "b" || kept[1].ID != "d" {
+		t.Errorf("kept = %v, want b and d", ids(kept))
+	}
+	if len(allowed) != 3 {
+		t.Fatalf("allowed = %v, want fixed-id, a and c", ids(allowed))
+	}
+	for _, p := range allowed {
+		if !p.Allowed {
+			t.Errorf("%s should be marked allowed", p.ID)
+		}
+	}
+	if allowed[1].AllowReason != "demo" {
+		t.Errorf("reason = %q, want the matching rule's", allowed[1].AllowReason)
+	}
+}
+
+func ids(problems []*models.Problem) []string {
+	out := make([]string, 0, len(problems))
+	for _, p := range problems {
+		out = append(out, p.ID)
+	}
+	return out
+}
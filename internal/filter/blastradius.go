@@ -0,0 +1,20 @@
+package filter
+
+import "github.com/ppiankov/infranow/internal/models"
+
+// MinBlastRadius drops problems whose blast radius is below threshold. A
+// threshold of 0 or 1 keeps every problem.
+func MinBlastRadius(problems []*models.Problem, threshold int) []*models.Problem {
+	if threshold <= 1 {
+		return problems
+	}
+
+	filtered := make([]*models.Problem, 0)
+	for _, p := range problems {
+		if p.BlastRadius >= threshold {
+			filtered = append(filtered, p)
+		}
+	}
+
+	return filtered
+}
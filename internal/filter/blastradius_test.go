@@ -0,0 +1,40 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestMinBlastRadius(t *testing.T) {
+	problems := []*models.Problem{
+		{ID: "pod", BlastRadius: 1},
+		{ID: "deployment", BlastRadius: 3},
+		{ID: "node", BlastRadius: 10},
+	}
+
+	tests := []struct {
+		threshold int
+		want      []string
+	}{
+		{0, []string{"pod", "deployment", "node"}},
+		{1, []string{"pod", "deployment", "node"}},
+		{2, []string{"deployment", "node"}},
+		{3, []string{"deployment", "node"}},
+		{5, []string{"node"}},
+		{11, nil},
+	}
+
+	for _, tt := range tests {
+		got := MinBlastRadius(problems, tt.threshold)
+		if len(got) != len(tt.want) {
+			t.Errorf("MinBlastRadius(%d) kept %d problems, want %d", tt.threshold, len(got), len(tt.want))
+			continue
+		}
+		for i, p := range got {
+			if p.ID != tt.want[i] {
+				t.Errorf("MinBlastRadius(%d)[%d] = %s, want %s", tt.threshold, i, p.ID, tt.want[i])
+			}
+		}
+	}
+}
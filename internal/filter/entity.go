@@ -0,0 +1,61 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// EntityFilter filters problems by regular expressions matched against
+// Problem.Entity. Like NamespaceFilter, exclude wins over include.
+type EntityFilter struct {
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+// NewEntityFilter creates an entity filter. Empty expressions are ignored;
+// patterns are unanchored, so "-canary-" matches anywhere in the entity.
+func NewEntityFilter(include, exclude string) (*EntityFilter, error) {
+	f := &EntityFilter{}
+	var err error
+	if include != "" {
+		if f.include, err = regexp.Compile(include); err != nil {
+			return nil, fmt.Errorf("invalid include entity pattern: %w", err)
+		}
+	}
+	if exclude != "" {
+		if f.exclude, err = regexp.Compile(exclude); err != nil {
+			return nil, fmt.Errorf("invalid exclude entity pattern: %w", err)
+		}
+	}
+	return f, nil
+}
+
+// Matches checks if an entity matches the filter
+func (f *EntityFilter) Matches(entity string) bool {
+	// Check exclude first (more restrictive)
+	if f.exclude != nil && f.exclude.MatchString(entity) {
+		return false
+	}
+	if f.include != nil {
+		return f.include.MatchString(entity)
+	}
+	return true
+}
+
+// Apply filters a list of problems by entity
+func (f *EntityFilter) Apply(problems []*models.Problem) []*models.Problem {
+	if f.include == nil && f.exclude == nil {
+		return problems
+	}
+
+	filtered := make([]*models.Problem, 0)
+	for _, p := range problems {
+		if f.Matches(p.Entity) {
+			filtered = append(filtered, p)
+		}
+	}
+
+	return filtered
+}
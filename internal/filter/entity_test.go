@@ -0,0 +1,66 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestEntityMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		include string
+		exclude string
+		entity  string
+		want    bool
+	}{
+		{"no patterns matches all", "", "", "prod/api-7d9f8/app", true},
+		{"include match", `.*-canary-.*`, "", "prod/api-canary-7d9f8/app", true},
+		{"include no match", `.*-canary-.*`, "", "prod/api-7d9f8/app", false},
+		{"include unanchored", `canary`, "", "prod/api-canary-7d9f8/app", true},
+		{"include anchored", `^prod/`, "", "staging/prod/app", false},
+		{"exclude match", "", `^kube-system/`, "kube-system/coredns", false},
+		{"exclude no match", "", `^kube-system/`, "prod/api", true},
+		{"exclude wins over include", `api`, `canary`, "prod/api-canary-1/app", false},
+		{"include and exclude only include matches", `api`, `canary`, "prod/api-1/app", true},
+		{"empty entity with include", `prod`, "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewEntityFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("NewEntityFilter() error = %v", err)
+			}
+			if got := f.Matches(tt.entity); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v (include=%q exclude=%q)", tt.entity, got, tt.want, tt.include, tt.exclude)
+			}
+		})
+	}
+}
+
+func TestEntityApply(t *testing.T) {
+	problems := []*models.Problem{
+		{ID: "1", Entity: "prod/api-canary-1/app"},
+		{ID: "2", Entity: "prod/api-1/app"},
+		{ID: "3", Entity: "prod/web-canary-1/app"},
+	}
+
+	f, err := NewEntityFilter(`-canary-`, `^prod/web`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := f.Apply(problems)
+	if len(result) != 1 || result[0].ID != "1" {
+		t.Errorf("Apply() = %v, want only problem 1", result)
+	}
+}
+
+func TestNewEntityFilter_InvalidPattern(t *testing.T) {
+	if _, err := NewEntityFilter("(", ""); err == nil {
+		t.Error("expected error for invalid include pattern")
+	}
+	if _, err := NewEntityFilter("", "[a-"); err == nil {
+		t.Error("expected error for invalid exclude pattern")
+	}
+}
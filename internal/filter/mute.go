@@ -0,0 +1,86 @@
+package filter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// Mutes holds problems muted for a while, e.g. "I'll deal with this later"
+// from the TUI. Unlike the allow-list a mute expires on its own: once its
+// time is up the problem counts and notifies again. Safe for concurrent use.
+type Mutes struct {
+	now func() time.Time
+
+	mu    sync.Mutex
+	until map[string]time.Time // Problem ID -> end of the mute
+}
+
+// NewMutes returns an empty mute store. now is the clock (nil = time.Now).
+func NewMutes(now func() time.Time) *Mutes {
+	if now == nil {
+		now = time.Now
+	}
+	return &Mutes{now: now, until: make(map[string]time.Time)}
+}
+
+// Mute mutes the problem with id for d from now, replacing any earlier mute,
+// and returns when it ends
+func (m *Mutes) Mute(id string, d time.Duration) time.Time {
+	until := m.now().Add(d)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.until[id] = until
+	return until
+}
+
+// Clear unmutes every problem
+func (m *Mutes) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.until)
+}
+
+// Active returns how many mutes have not expired yet. A nil store has none.
+func (m *Mutes) Active() int {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneLocked()
+	return len(m.until)
+}
+
+// Split separates muted problems from the rest. Expired mutes are dropped.
+func (m *Mutes) Split(problems []*models.Problem) (kept, muted []*models.Problem) {
+	return m.SplitBy(problems, func(p *models.Problem) string { return p.ID })
+}
+
+// SplitBy is Split for problems whose ID is not the one they were muted by,
+// e.g. redacted copies: key returns the muted ID of each problem.
+func (m *Mutes) SplitBy(problems []*models.Problem, key func(*models.Problem) string) (kept, muted []*models.Problem) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneLocked()
+
+	kept = make([]*models.Problem, 0, len(problems))
+	for _, p := range problems {
+		if _, ok := m.until[key(p)]; ok {
+			muted = append(muted, p)
+			continue
+		}
+		kept = append(kept, p)
+	}
+	return kept, muted
+}
+
+func (m *Mutes) pruneLocked() {
+	now := m.now()
+	for id, until := range m.until {
+		if !now.Before(until) {
+			delete(m.until, id)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestMutes_ExpireAfterDuration(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	mutes := NewMutes(func() time.Time { return now })
+	problems := []*models.Problem{{ID: "a"}, {ID: "b"}}
+
+	until := mutes.Mute("a", time.Hour)
+	if !until.Equal(now.Add(time.Hour)) {
+		t.Errorf("Mute() = %s, want an hour from now", until)
+	}
+
+	kept, muted := mutes.Split(problems)
+	if len(kept) != 1 || kept[0].ID != "b" || len(muted) != 1 || muted[0].ID != "a" {
+		t.Fatalf("Split() kept %v, muted %v; want a muted", kept, muted)
+	}
+	if mutes.Active() != 1 {
+		t.Errorf("Active() = %d, want 1", mutes.Active())
+	}
+
+	// Still muted a moment before the end, back exactly at it
+	now = now.Add(time.Hour - time.Second)
+	if _, muted := mutes.Split(problems); len(muted) != 1 {
+		t.Errorf("problem unmuted %s early", time.Second)
+	}
+	now = now.Add(time.Second)
+	kept, muted = mutes.Split(problems)
+	if len(kept) != 2 || len(muted) != 0 {
+		t.Errorf("Split() after expiry kept %d, muted %d; want both back", len(kept), len(muted))
+	}
+	if mutes.Active() != 0 {
+		t.Errorf("Active() = %d after expiry, want 0", mutes.Active())
+	}
+}
+
+func TestMutes_RemuteAndClear(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	mutes := NewMutes(func() time.Time { return now })
+	problems := []*models.Problem{{ID: "a"}}
+
+	mutes.Mute("a", time.Hour)
+	mutes.Mute("a", 24*time.Hour) // Replaces the shorter mute
+	now = now.Add(4 * time.Hour)
+	if _, muted := mutes.Split(problems); len(muted) != 1 {
+		t.Error("re-muting should extend the mute")
+	}
+
+	mutes.Clear()
+	if kept, _ := mutes.Split(problems); len(kept) != 1 {
+		t.Error("Clear() should unmute everything")
+	}
+}
+
+func TestMutes_NilActive(t *testing.T) {
+	var mutes *Mutes
+	if mutes.Active() != 0 {
+		t.Error("nil store should have no active mutes")
+	}
+}
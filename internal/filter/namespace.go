@@ -11,6 +11,11 @@ import (
 type NamespaceFilter struct {
 	includePatterns []string
 	excludePatterns []string
+
+	// IncludeUnnamespaced passes problems without a namespace label (nodes,
+	// services, certificates, etcd) through the filter untouched, so
+	// filtering to one team's namespaces keeps cluster-wide problems
+	IncludeUnnamespaced bool
 }
 
 // NewNamespaceFilter creates a new namespace filter
@@ -72,6 +77,10 @@ func (f *NamespaceFilter) Apply(problems []*models.Problem) []*models.Problem {
 
 	filtered := make([]*models.Problem, 0)
 	for _, p := range problems {
+		if f.IncludeUnnamespaced && p.Labels["namespace"] == "" {
+			filtered = append(filtered, p)
+			continue
+		}
 		// Extract namespace from entity (format: "namespace/pod/container")
 		parts := strings.Split(p.Entity, "/")
 		if len(parts) > 0 {
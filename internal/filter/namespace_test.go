@@ -1,6 +1,7 @@
 package filter
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/ppiankov/infranow/internal/models"
@@ -92,3 +93,35 @@ func TestApply(t *testing.T) {
 		})
 	}
 }
+
+func TestApply_IncludeUnnamespaced(t *testing.T) {
+	problems := []*models.Problem{
+		{ID: "team-pod", Entity: "team-x/api-1", Labels: map[string]string{"namespace": "team-x"}},
+		{ID: "other-pod", Entity: "team-y/api-1", Labels: map[string]string{"namespace": "team-y"}},
+		{ID: "node-down", Entity: "node-1", Labels: map[string]string{"node": "node-1"}},
+		{ID: "etcd", Entity: "etcd/10.0.0.11:2379", Labels: map[string]string{"instance": "10.0.0.11:2379"}},
+	}
+
+	tests := []struct {
+		name         string
+		unnamespaced bool
+		want         string
+	}{
+		{"unnamespaced kept", true, "team-pod,node-down,etcd"},
+		{"unnamespaced dropped", false, "team-pod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewNamespaceFilter("team-x", "")
+			f.IncludeUnnamespaced = tt.unnamespaced
+
+			var ids []string
+			for _, p := range f.Apply(problems) {
+				ids = append(ids, p.ID)
+			}
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Errorf("Apply() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
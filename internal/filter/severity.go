@@ -0,0 +1,20 @@
+package filter
+
+import "github.com/ppiankov/infranow/internal/models"
+
+// MinSeverity drops problems below threshold. WARNING (or an empty threshold)
+// keeps every problem.
+func MinSeverity(problems []*models.Problem, threshold models.Severity) []*models.Problem {
+	if threshold == "" || threshold == models.SeverityWarning {
+		return problems
+	}
+
+	filtered := make([]*models.Problem, 0)
+	for _, p := range problems {
+		if p.Severity.AtLeast(threshold) {
+			filtered = append(filtered, p)
+		}
+	}
+
+	return filtered
+}
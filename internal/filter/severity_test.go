@@ -0,0 +1,38 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestMinSeverity(t *testing.T) {
+	problems := []*models.Problem{
+		{ID: "warn", Severity: models.SeverityWarning},
+		{ID: "crit", Severity: models.SeverityCritical},
+		{ID: "fatal", Severity: models.SeverityFatal},
+	}
+
+	tests := []struct {
+		threshold models.Severity
+		want      []string
+	}{
+		{"", []string{"warn", "crit", "fatal"}},
+		{models.SeverityWarning, []string{"warn", "crit", "fatal"}},
+		{models.SeverityCritical, []string{"crit", "fatal"}},
+		{models.SeverityFatal, []string{"fatal"}},
+	}
+
+	for _, tt := range tests {
+		got := MinSeverity(problems, tt.threshold)
+		if len(got) != len(tt.want) {
+			t.Errorf("threshold %q: got %d problems, want %d", tt.threshold, len(got), len(tt.want))
+			continue
+		}
+		for i, p := range got {
+			if p.ID != tt.want[i] {
+				t.Errorf("threshold %q: got[%d] = %s, want %s", tt.threshold, i, p.ID, tt.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	// DefaultBatchLinger is how long the first query of a batch waits for
+	// others to join. Detectors start together each cycle, so their first
+	// queries arrive within a few milliseconds of each other.
+	DefaultBatchLinger = 20 * time.Millisecond
+
+	// DefaultBatchSize caps the queries combined into one request, keeping
+	// the combined expression well inside URL and query-timeout limits
+	DefaultBatchSize = 16
+
+	// batchLabel tags each combined query's series with its position in the
+	// batch; it is removed before results are returned
+	batchLabel = "__infranow_batch"
+)
+
+// BatchingProvider combines instant queries issued at about the same time
+// into one Prometheus request. Each query is tagged with label_replace and
+// the tagged queries are joined with "or", so one evaluation returns every
+// query's series. If the combined query fails (a scalar result, a backend
+// without label_replace, a sample limit) each query is retried on its own,
+// so errors stay per query. Range queries and health checks pass through.
+type BatchingProvider struct {
+	next     MetricsProvider
+	linger   time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	pending *queryBatch
+
+	requests atomic.Int64
+}
+
+// NewBatchingProvider wraps next with DefaultBatchLinger and DefaultBatchSize
+func NewBatchingProvider(next MetricsProvider) *BatchingProvider {
+	return &BatchingProvider{next: next, linger: DefaultBatchLinger, maxBatch: DefaultBatchSize}
+}
+
+// queryBatch collects queries until it is full or its linger expires
+type queryBatch struct {
+	ctx     context.Context
+	ts      time.Time
+	queries []string
+	results []batchResult
+	done    chan struct{}
+	timer   *time.Timer
+}
+
+type batchResult struct {
+	vector   model.Vector
+	err      error
+	warnings []string
+}
+
+// Requests returns how many instant queries were sent to the backend,
+// combined or not
+func (b *BatchingProvider) Requests() int64 {
+	return b.requests.Load()
+}
+
+// QueryRange is not batched
+func (b *BatchingProvider) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (model.Matrix, error) {
+	return b.next.QueryRange(ctx, query, start, end, step)
+}
+
+// Health is not batched
+func (b *BatchingProvider) Health(ctx context.Context) error {
+	return b.next.Health(ctx)
+}
+
+// QueryInstant joins the pending batch, or starts one, and waits for its
+// share of the result
+func (b *BatchingProvider) QueryInstant(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+	b.mu.Lock()
+	batch := b.pending
+	// Queries at a noticeably different time are evaluated on their own
+	if batch != nil && absDuration(ts.Sub(batch.ts)) > b.linger {
+		b.mu.Unlock()
+		b.requests.Add(1)
+		return b.next.QueryInstant(ctx, query, ts)
+	}
+	if batch == nil {
+		batch = &queryBatch{ctx: ctx, ts: ts, done: make(chan struct{})}
+		b.pending = batch
+		batch.timer = time.AfterFunc(b.linger, func() { b.flush(batch) })
+	}
+	index := len(batch.queries)
+	batch.queries = append(batch.queries, query)
+	if len(batch.queries) >= b.maxBatch {
+		batch.timer.Stop()
+		b.pending = nil
+		go b.run(batch)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-batch.done:
+		r := batch.results[index]
+		ReportWarnings(ctx, query, r.warnings)
+		return r.vector, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// flush runs batch unless it already filled up and ran
+func (b *BatchingProvider) flush(batch *queryBatch) {
+	b.mu.Lock()
+	if b.pending != batch {
+		b.mu.Unlock()
+		return
+	}
+	b.pending = nil
+	b.mu.Unlock()
+	b.run(batch)
+}
+
+// run evaluates a closed batch and wakes its callers
+func (b *BatchingProvider) run(batch *queryBatch) {
+	defer close(batch.done)
+	batch.results = make([]batchResult, len(batch.queries))
+
+	// One caller giving up must not cancel the others' queries
+	ctx := context.WithoutCancel(batch.ctx)
+	if deadline, ok := batch.ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	// Warnings go back to each caller rather than to the caller whose
+	// context the batch runs with
+	evaluate := func(query string) batchResult {
+		qctx, warnings := WithWarnings(ctx)
+		b.requests.Add(1)
+		vector, err := b.next.QueryInstant(qctx, query, batch.ts)
+		return batchResult{vector: vector, err: err, warnings: warningTexts(warnings.All())}
+	}
+
+	if len(batch.queries) == 1 {
+		batch.results[0] = evaluate(batch.queries[0])
+		return
+	}
+
+	// A warning on the combined query cannot be traced to one of its parts,
+	// so every part gets it
+	combined := evaluate(combineQueries(batch.queries))
+	err := combined.err
+	if err == nil {
+		err = splitResults(combined.vector, batch.results)
+	}
+	if err == nil {
+		for i := range batch.results {
+			batch.results[i].warnings = combined.warnings
+		}
+		return
+	}
+
+	// Fall back to one request per query so each gets its own error
+	var wg sync.WaitGroup
+	for i, query := range batch.queries {
+		wg.Add(1)
+		go func(i int, query string) {
+			defer wg.Done()
+			batch.results[i] = evaluate(query)
+		}(i, query)
+	}
+	wg.Wait()
+}
+
+// combineQueries tags each query's series with its index and joins them
+func combineQueries(queries []string) string {
+	parts := make([]string, len(queries))
+	for i, query := range queries {
+		parts[i] = fmt.Sprintf(`label_replace((%s), %q, "%d", "", "")`, query, batchLabel, i)
+	}
+	return strings.Join(parts, " or ")
+}
+
+// splitResults hands each series back to the query that produced it
+func splitResults(vector model.Vector, results []batchResult) error {
+	for i := range results {
+		results[i].vector = model.Vector{}
+	}
+	for _, sample := range vector {
+		i, err := strconv.Atoi(string(sample.Metric[batchLabel]))
+		if err != nil || i < 0 || i >= len(results) {
+			return fmt.Errorf("combined query returned a series without a valid %s label", batchLabel)
+		}
+		delete(sample.Metric, batchLabel)
+		results[i].vector = append(results[i].vector, sample)
+	}
+	return nil
+}
+
+func warningTexts(warnings []QueryWarning) []string {
+	texts := make([]string, len(warnings))
+	for i, w := range warnings {
+		texts[i] = w.Warning
+	}
+	return texts
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,225 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// batchedPart matches one tagged query of a combined expression
+var batchedPart = regexp.MustCompile(`label_replace\(\((.*?)\), "__infranow_batch", "(\d+)", "", ""\)`)
+
+// echoBackend answers each query with one series naming it, evaluating
+// combined queries like Prometheus would, and counts requests
+type echoBackend struct {
+	mu       sync.Mutex
+	requests int
+	fail     func(query string) bool
+}
+
+func (e *echoBackend) provider() *MockProvider {
+	return &MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			e.mu.Lock()
+			e.requests++
+			e.mu.Unlock()
+			if e.fail != nil && e.fail(query) {
+				return nil, errors.New("bad_data: " + query)
+			}
+
+			parts := batchedPart.FindAllStringSubmatch(query, -1)
+			if parts == nil {
+				return model.Vector{&model.Sample{Metric: model.Metric{"query": model.LabelValue(query)}}}, nil
+			}
+			var vector model.Vector
+			for _, part := range parts {
+				vector = append(vector, &model.Sample{Metric: model.Metric{
+					"query":    model.LabelValue(part[1]),
+					batchLabel: model.LabelValue(part[2]),
+				}})
+			}
+			return vector, nil
+		},
+	}
+}
+
+func (e *echoBackend) count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.requests
+}
+
+// queryConcurrently runs n distinct queries at once and checks each caller
+// got back only its own series
+func queryConcurrently(t *testing.T, provider MetricsProvider, n int) {
+	t.Helper()
+	now := time.Now()
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(query string) {
+			defer wg.Done()
+			vector, err := provider.QueryInstant(context.Background(), query, now)
+			switch {
+			case err != nil:
+				errs <- fmt.Errorf("%s: %w", query, err)
+			case len(vector) != 1 || string(vector[0].Metric["query"]) != query:
+				errs <- fmt.Errorf("%s: got %v", query, vector)
+			case vector[0].Metric[batchLabel] != "":
+				errs <- fmt.Errorf("%s: batch label not removed", query)
+			}
+		}(fmt.Sprintf("up{job=\"j%d\"}", i))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestBatchingProvider_CombinesConcurrentQueries(t *testing.T) {
+	backend := &echoBackend{}
+	b := NewBatchingProvider(backend.provider())
+
+	queryConcurrently(t, b, 10)
+
+	if got := backend.count(); got != 1 {
+		t.Errorf("backend requests = %d, want 1 for 10 concurrent queries", got)
+	}
+	if b.Requests() != 1 {
+		t.Errorf("Requests() = %d, want 1", b.Requests())
+	}
+}
+
+func TestBatchingProvider_CapsBatchSize(t *testing.T) {
+	backend := &echoBackend{}
+	b := NewBatchingProvider(backend.provider())
+
+	queryConcurrently(t, b, 2*DefaultBatchSize+1)
+
+	// Two full batches plus the straggler, whichever way they fell
+	if got := backend.count(); got < 3 || got > 5 {
+		t.Errorf("backend requests = %d, want about 3 for %d queries", got, 2*DefaultBatchSize+1)
+	}
+}
+
+func TestBatchingProvider_SingleQueryNotRewritten(t *testing.T) {
+	var got string
+	b := NewBatchingProvider(&MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			got = query
+			return model.Vector{}, nil
+		},
+	})
+
+	if _, err := b.QueryInstant(context.Background(), "up", time.Now()); err != nil {
+		t.Fatalf("QueryInstant() error = %v", err)
+	}
+	if got != "up" {
+		t.Errorf("backend saw %q, want the query unchanged", got)
+	}
+}
+
+func TestBatchingProvider_FallsBackPerQuery(t *testing.T) {
+	backend := &echoBackend{fail: func(query string) bool {
+		return strings.Contains(query, "broken")
+	}}
+	b := NewBatchingProvider(backend.provider())
+
+	now := time.Now()
+	var wg sync.WaitGroup
+	results := make(map[string]error)
+	var mu sync.Mutex
+	for _, query := range []string{"up", "broken(", "node_load1"} {
+		wg.Add(1)
+		go func(query string) {
+			defer wg.Done()
+			_, err := b.QueryInstant(context.Background(), query, now)
+			mu.Lock()
+			results[query] = err
+			mu.Unlock()
+		}(query)
+	}
+	wg.Wait()
+
+	if results["up"] != nil || results["node_load1"] != nil {
+		t.Errorf("valid queries failed: %v", results)
+	}
+	if err := results["broken("]; err == nil || !strings.Contains(err.Error(), "broken(") {
+		t.Errorf("broken query error = %v, want its own error", err)
+	}
+	if got := backend.count(); got != 4 {
+		t.Errorf("backend requests = %d, want 1 combined + 3 retried", got)
+	}
+}
+
+func TestBatchingProvider_DifferentTimestampsNotCombined(t *testing.T) {
+	backend := &echoBackend{}
+	b := NewBatchingProvider(backend.provider())
+
+	now := time.Now()
+	var wg sync.WaitGroup
+	for _, ts := range []time.Time{now, now.Add(-time.Hour)} {
+		wg.Add(1)
+		go func(ts time.Time) {
+			defer wg.Done()
+			if _, err := b.QueryInstant(context.Background(), "up", ts); err != nil {
+				t.Error(err)
+			}
+		}(ts)
+	}
+	wg.Wait()
+
+	if got := backend.count(); got != 2 {
+		t.Errorf("backend requests = %d, want queries an hour apart evaluated separately", got)
+	}
+}
+
+func TestBatchingProvider_CallerCancelDoesNotCancelBatch(t *testing.T) {
+	release := make(chan struct{})
+	b := NewBatchingProvider(&MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			<-release
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			parts := batchedPart.FindAllStringSubmatch(query, -1)
+			var vector model.Vector
+			for _, part := range parts {
+				vector = append(vector, &model.Sample{Metric: model.Metric{batchLabel: model.LabelValue(part[2])}})
+			}
+			return vector, nil
+		},
+	})
+
+	now := time.Now()
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := b.QueryInstant(first, "a", now)
+		firstErr <- err
+	}()
+	secondErr := make(chan error, 1)
+	go func() {
+		_, err := b.QueryInstant(context.Background(), "b", now)
+		secondErr <- err
+	}()
+
+	time.Sleep(2 * DefaultBatchLinger)
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller error = %v, want context.Canceled", err)
+	}
+	close(release)
+	if err := <-secondErr; err != nil {
+		t.Errorf("other caller error = %v, want its result", err)
+	}
+}
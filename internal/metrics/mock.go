@@ -12,10 +12,15 @@ type MockProvider struct {
 	QueryRangeFunc   func(ctx context.Context, query string, start, end time.Time, step time.Duration) (model.Matrix, error)
 	QueryInstantFunc func(ctx context.Context, query string, ts time.Time) (model.Vector, error)
 	HealthFunc       func(ctx context.Context) error
+
+	// Warnings are reported for every query, like the warnings Prometheus
+	// returns with a result
+	Warnings []string
 }
 
 // QueryRange calls the mock function if set, otherwise returns empty result
 func (m *MockProvider) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (model.Matrix, error) {
+	ReportWarnings(ctx, query, m.Warnings)
 	if m.QueryRangeFunc != nil {
 		return m.QueryRangeFunc(ctx, query, start, end, step)
 	}
@@ -24,6 +29,7 @@ func (m *MockProvider) QueryRange(ctx context.Context, query string, start, end
 
 // QueryInstant calls the mock function if set, otherwise returns empty result
 func (m *MockProvider) QueryInstant(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+	ReportWarnings(ctx, query, m.Warnings)
 	if m.QueryInstantFunc != nil {
 		return m.QueryInstantFunc(ctx, query, ts)
 	}
@@ -2,7 +2,13 @@ package metrics
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/api"
@@ -17,11 +23,102 @@ type PrometheusClient struct {
 	api    promv1.API
 }
 
+// ClientOption configures optional PrometheusClient behavior
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	headers http.Header
+	caFile  string
+}
+
+// WithHeaders attaches headers to every request, e.g. X-Scope-OrgID for
+// Mimir/Cortex tenant isolation
+func WithHeaders(headers http.Header) ClientOption {
+	return func(o *clientOptions) {
+		if o.headers == nil {
+			o.headers = make(http.Header)
+		}
+		for k, vs := range headers {
+			for _, v := range vs {
+				o.headers.Add(k, v)
+			}
+		}
+	}
+}
+
+// WithCAFile trusts the PEM certificates in path in addition to the system
+// roots, e.g. a cluster's service CA
+func WithCAFile(path string) ClientOption {
+	return func(o *clientOptions) {
+		o.caFile = path
+	}
+}
+
+// caTransport returns a transport that trusts the certificates in caFile
+func caTransport(caFile string) (http.RoundTripper, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", caFile)
+	}
+
+	base, ok := api.DefaultRoundTripper.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected default transport %T", api.DefaultRoundTripper)
+	}
+	transport := base.Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	return transport, nil
+}
+
+// headerRoundTripper sets fixed headers on every outgoing request
+type headerRoundTripper struct {
+	headers http.Header
+	next    http.RoundTripper
+}
+
+func (rt *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	for k, vs := range rt.headers {
+		req.Header.Del(k)
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	return rt.next.RoundTrip(req)
+}
+
 // NewPrometheusClient creates a new Prometheus metrics provider
-func NewPrometheusClient(url string, timeout time.Duration) (*PrometheusClient, error) {
-	client, err := api.NewClient(api.Config{
+func NewPrometheusClient(url string, timeout time.Duration, opts ...ClientOption) (*PrometheusClient, error) {
+	var o clientOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	cfg := api.Config{
 		Address: url,
-	})
+	}
+	next := api.DefaultRoundTripper
+	if o.caFile != "" {
+		transport, err := caTransport(o.caFile)
+		if err != nil {
+			return nil, err
+		}
+		next = transport
+		cfg.RoundTripper = transport
+	}
+	if len(o.headers) > 0 {
+		cfg.RoundTripper = &headerRoundTripper{headers: o.headers, next: next}
+	}
+
+	client, err := api.NewClient(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create prometheus client: %w", err)
 	}
@@ -43,9 +140,7 @@ func (p *PrometheusClient) QueryRange(ctx context.Context, query string, start,
 	if err != nil {
 		return nil, fmt.Errorf("query range failed: %w", err)
 	}
-
-	// Prometheus warnings are informational (e.g., query hints) — not actionable for infranow
-	_ = warnings
+	ReportWarnings(ctx, query, warnings)
 
 	matrix, ok := result.(model.Matrix)
 	if !ok {
@@ -61,9 +156,7 @@ func (p *PrometheusClient) QueryInstant(ctx context.Context, query string, ts ti
 	if err != nil {
 		return nil, fmt.Errorf("instant query failed: %w", err)
 	}
-
-	// Prometheus warnings are informational (e.g., query hints) — not actionable for infranow
-	_ = warnings
+	ReportWarnings(ctx, query, warnings)
 
 	vector, ok := result.(model.Vector)
 	if !ok {
@@ -73,11 +166,69 @@ func (p *PrometheusClient) QueryInstant(ctx context.Context, query string, ts ti
 	return vector, nil
 }
 
+// ErrUnauthorized is returned by Health when Prometheus, or a proxy in front
+// of it, answers 401 or 403
+var ErrUnauthorized = errors.New("request rejected as unauthorized")
+
 // Health checks if the Prometheus server is reachable
 func (p *PrometheusClient) Health(ctx context.Context) error {
 	_, err := p.api.Runtimeinfo(ctx)
 	if err != nil {
+		if code, ok := authStatus(err); ok {
+			return fmt.Errorf("prometheus health check failed: HTTP %d: %w", code, ErrUnauthorized)
+		}
 		return fmt.Errorf("prometheus health check failed: %w", err)
 	}
 	return nil
 }
+
+// authStatus reports whether err is a 401/403 response. The client library
+// only exposes the status code in the error message.
+func authStatus(err error) (int, bool) {
+	var apiErr *promv1.Error
+	if !errors.As(err, &apiErr) || apiErr.Type != promv1.ErrClient {
+		return 0, false
+	}
+	for _, code := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		if apiErr.Msg == fmt.Sprintf("client error: %d", code) {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// DefaultQueryPaths are the base paths DiscoverQueryURL probes, in order:
+// plain Prometheus, then the prefixes Mimir, Cortex and proxies commonly use
+var DefaultQueryPaths = []string{"/", "/prometheus", "/api"}
+
+// discoveryQuery is evaluated by every Prometheus-compatible query API
+const discoveryQuery = "vector(1)"
+
+// DiscoverQueryURL finds where the query API lives under baseURL by running
+// a trivial instant query against each of paths. It returns baseURL joined
+// with the first path that answers.
+func DiscoverQueryURL(ctx context.Context, baseURL string, paths []string, timeout time.Duration, opts ...ClientOption) (string, error) {
+	var errs []string
+	for _, path := range paths {
+		candidate := JoinQueryPath(baseURL, path)
+		client, err := NewPrometheusClient(candidate, timeout, opts...)
+		if err != nil {
+			return "", err
+		}
+		if _, err := client.QueryInstant(ctx, discoveryQuery, time.Now()); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		return candidate, nil
+	}
+	return "", fmt.Errorf("no query API found (%s)", strings.Join(errs, "; "))
+}
+
+// JoinQueryPath appends a query API base path to a Prometheus URL
+func JoinQueryPath(baseURL, path string) string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return strings.TrimRight(baseURL, "/")
+	}
+	return strings.TrimRight(baseURL, "/") + "/" + path
+}
@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPrometheusClient_QueryHeaders(t *testing.T) {
+	var mu sync.Mutex
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		got = r.Header.Clone()
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer srv.Close()
+
+	headers := http.Header{}
+	headers.Set("X-Scope-OrgID", "tenant-a")
+	headers.Set("X-Custom", "value")
+
+	client, err := NewPrometheusClient(srv.URL, 5*time.Second, WithHeaders(headers))
+	if err != nil {
+		t.Fatalf("NewPrometheusClient() error = %v", err)
+	}
+
+	if _, err := client.QueryInstant(context.Background(), "up", time.Now()); err != nil {
+		t.Fatalf("QueryInstant() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if v := got.Get("X-Scope-OrgID"); v != "tenant-a" {
+		t.Errorf("X-Scope-OrgID = %q, want tenant-a", v)
+	}
+	if v := got.Get("X-Custom"); v != "value" {
+		t.Errorf("X-Custom = %q, want value", v)
+	}
+}
+
+func TestPrometheusClient_NoHeaders(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewPrometheusClient(srv.URL, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewPrometheusClient() error = %v", err)
+	}
+	if _, err := client.QueryInstant(context.Background(), "up", time.Now()); err != nil {
+		t.Fatalf("QueryInstant() error = %v", err)
+	}
+	if v := got.Get("X-Scope-OrgID"); v != "" {
+		t.Errorf("X-Scope-OrgID = %q, want unset", v)
+	}
+}
+
+// queryAPIServer serves the query API only under prefix
+func queryAPIServer(prefix string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != prefix+"/api/v1/query" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+}
+
+func TestDiscoverQueryURL(t *testing.T) {
+	for _, prefix := range []string{"", "/prometheus", "/api"} {
+		t.Run("prefix "+prefix, func(t *testing.T) {
+			srv := queryAPIServer(prefix)
+			defer srv.Close()
+
+			got, err := DiscoverQueryURL(context.Background(), srv.URL+"/", DefaultQueryPaths, 5*time.Second)
+			if err != nil {
+				t.Fatalf("DiscoverQueryURL() error = %v", err)
+			}
+			if want := srv.URL + prefix; got != want {
+				t.Errorf("DiscoverQueryURL() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestDiscoverQueryURL_NotFound(t *testing.T) {
+	srv := queryAPIServer("/select/0/prometheus")
+	defer srv.Close()
+
+	_, err := DiscoverQueryURL(context.Background(), srv.URL, DefaultQueryPaths, 5*time.Second)
+	if err == nil {
+		t.Fatal("expected error when no path serves the query API")
+	}
+	if !strings.Contains(err.Error(), "/prometheus") {
+		t.Errorf("error should list the probed paths, got %v", err)
+	}
+
+	// The explicit path works
+	got, err := DiscoverQueryURL(context.Background(), srv.URL, []string{"select/0/prometheus"}, 5*time.Second)
+	if err != nil || got != srv.URL+"/select/0/prometheus" {
+		t.Errorf("DiscoverQueryURL() = %q, %v", got, err)
+	}
+}
+
+func TestJoinQueryPath(t *testing.T) {
+	tests := []struct{ base, path, want string }{
+		{"http://prom:9090", "/", "http://prom:9090"},
+		{"http://prom:9090/", "", "http://prom:9090"},
+		{"http://mimir", "/prometheus", "http://mimir/prometheus"},
+		{"http://mimir/", "prometheus/", "http://mimir/prometheus"},
+	}
+	for _, tt := range tests {
+		if got := JoinQueryPath(tt.base, tt.path); got != tt.want {
+			t.Errorf("JoinQueryPath(%q, %q) = %q, want %q", tt.base, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPrometheusClient_HealthUnauthorized(t *testing.T) {
+	for _, code := range []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(code), code)
+		}))
+
+		client, err := NewPrometheusClient(srv.URL, 5*time.Second)
+		if err != nil {
+			t.Fatalf("NewPrometheusClient() error = %v", err)
+		}
+		err = client.Health(context.Background())
+		srv.Close()
+
+		if err == nil {
+			t.Fatalf("HTTP %d: expected error", code)
+		}
+		wantAuth := code != http.StatusNotFound
+		if got := errors.Is(err, ErrUnauthorized); got != wantAuth {
+			t.Errorf("HTTP %d: errors.Is(ErrUnauthorized) = %v, want %v (%v)", code, got, wantAuth, err)
+		}
+	}
+}
+
+func TestPrometheusClient_CAFile(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{}}`))
+	}))
+	defer srv.Close()
+
+	// Without the CA the self-signed test certificate is rejected
+	client, err := NewPrometheusClient(srv.URL, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewPrometheusClient() error = %v", err)
+	}
+	if err := client.Health(context.Background()); err == nil {
+		t.Fatal("expected TLS verification to fail without the CA")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "service-ca.crt")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, cert, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	client, err = NewPrometheusClient(srv.URL, 5*time.Second, WithCAFile(caFile), WithHeaders(http.Header{"Authorization": {"Bearer t"}}))
+	if err != nil {
+		t.Fatalf("NewPrometheusClient() error = %v", err)
+	}
+	if err := client.Health(context.Background()); err != nil {
+		t.Errorf("Health() with CA file error = %v", err)
+	}
+
+	if _, err := NewPrometheusClient(srv.URL, 5*time.Second, WithCAFile(filepath.Join(t.TempDir(), "missing.crt"))); err == nil {
+		t.Error("expected error for missing CA file")
+	}
+}
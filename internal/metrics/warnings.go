@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// QueryWarning is a warning the backend returned along with a query's result
+type QueryWarning struct {
+	Query   string
+	Warning string
+}
+
+// Warnings collects the warnings returned for the queries made with a
+// context from WithWarnings. Providers keep their signatures: the context
+// carries the collector through every wrapper down to the client.
+type Warnings struct {
+	mu   sync.Mutex
+	list []QueryWarning
+}
+
+type warningsKey struct{}
+
+// WithWarnings returns a context whose queries report their warnings to the
+// returned collector
+func WithWarnings(ctx context.Context) (context.Context, *Warnings) {
+	w := &Warnings{}
+	return context.WithValue(ctx, warningsKey{}, w), w
+}
+
+// ReportWarnings records warnings for query with the collector in ctx, if any
+func ReportWarnings(ctx context.Context, query string, warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	w, ok := ctx.Value(warningsKey{}).(*Warnings)
+	if !ok {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, warning := range warnings {
+		w.list = append(w.list, QueryWarning{Query: query, Warning: warning})
+	}
+}
+
+// All returns every warning collected so far, in the order reported
+func (w *Warnings) All() []QueryWarning {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]QueryWarning(nil), w.list...)
+}
+
+// Truncated returns the collected warnings saying a result is incomplete
+func (w *Warnings) Truncated() []QueryWarning {
+	var truncated []QueryWarning
+	for _, qw := range w.All() {
+		if IsTruncation(qw.Warning) {
+			truncated = append(truncated, qw)
+		}
+	}
+	return truncated
+}
+
+// truncationMarkers appear in warnings about incomplete results: Prometheus'
+// "results truncated due to limit", Thanos' partial responses and the
+// series and sample limits of Mimir, Cortex and VictoriaMetrics
+var truncationMarkers = []string{"truncated", "partial response", "limit"}
+
+// IsTruncation reports whether a backend warning says the result may be
+// incomplete, as opposed to hints such as "metric might not be a counter"
+func IsTruncation(warning string) bool {
+	warning = strings.ToLower(warning)
+	for _, marker := range truncationMarkers {
+		if strings.Contains(warning, marker) {
+			return true
+		}
+	}
+	return false
+}
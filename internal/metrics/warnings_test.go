@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIsTruncation(t *testing.T) {
+	tests := []struct {
+		warning string
+		want    bool
+	}{
+		{"results truncated due to limit", true},
+		{"partial response: store unavailable", true},
+		{"the query exceeded the maximum number of series (limit: 100000)", true},
+		{`PromQL info: metric might not be a counter, name does not end in _total/_sum/_count/_bucket: "up"`, false},
+		{"PromQL warning: encountered a mix of histograms and floats", false},
+	}
+	for _, tt := range tests {
+		if got := IsTruncation(tt.warning); got != tt.want {
+			t.Errorf("IsTruncation(%q) = %v, want %v", tt.warning, got, tt.want)
+		}
+	}
+}
+
+func TestReportWarnings(t *testing.T) {
+	// Without a collector warnings are dropped
+	ReportWarnings(context.Background(), "up", []string{"results truncated due to limit"})
+
+	ctx, warnings := WithWarnings(context.Background())
+	ReportWarnings(ctx, "up", []string{"PromQL info: metric might not be a counter"})
+	ReportWarnings(ctx, "kube_pod_info", []string{"results truncated due to limit"})
+
+	if all := warnings.All(); len(all) != 2 || all[0].Query != "up" {
+		t.Errorf("All() = %v", all)
+	}
+	truncated := warnings.Truncated()
+	if len(truncated) != 1 || truncated[0].Query != "kube_pod_info" {
+		t.Errorf("Truncated() = %v", truncated)
+	}
+}
+
+func TestBatchingProvider_WarningsReachEveryCaller(t *testing.T) {
+	backend := &echoBackend{}
+	mock := backend.provider()
+	mock.Warnings = []string{"results truncated due to limit"}
+	b := NewBatchingProvider(mock)
+
+	now := time.Now()
+	var wg sync.WaitGroup
+	collectors := make([]*Warnings, 3)
+	for i := range collectors {
+		var ctx context.Context
+		ctx, collectors[i] = WithWarnings(context.Background())
+		wg.Add(1)
+		go func(query string) {
+			defer wg.Done()
+			if _, err := b.QueryInstant(ctx, query, now); err != nil {
+				t.Errorf("%s: %v", query, err)
+			}
+		}([]string{"up", "kube_pod_info", "node_load5"}[i])
+	}
+	wg.Wait()
+
+	if backend.count() != 1 {
+		t.Errorf("expected one combined request, got %d", backend.count())
+	}
+	for i, w := range collectors {
+		got := w.All()
+		if len(got) != 1 || got[0].Warning != "results truncated due to limit" {
+			t.Errorf("caller %d warnings = %v", i, got)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+)
+
+// Number of hash bytes kept in a problem ID
+const idHashBytes = 8
+
+// StableID derives a problem ID from what the problem is about rather than
+// how a detector formats its entity: the entity type, the problem type and
+// the label set. Labels are hashed in sorted key order, so the same logical
+// problem always gets the same ID. The problem type is kept as a readable
+// prefix, e.g. "oom_kill/3f9a1c0b7e2d4a65".
+func StableID(entityType, problemType string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// NUL separators keep ("a", "bc") and ("ab", "c") apart
+	h := sha256.New()
+	h.Write([]byte(entityType))
+	h.Write([]byte{0})
+	h.Write([]byte(problemType))
+	for _, k := range keys {
+		h.Write([]byte{0})
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(labels[k]))
+	}
+	return problemType + "/" + hex.EncodeToString(h.Sum(nil)[:idHashBytes])
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStableID_IndependentOfLabelOrder(t *testing.T) {
+	a := map[string]string{}
+	a["namespace"] = "prod"
+	a["pod"] = "api-7d9f8c6b5-xk2lp"
+	a["container"] = "app"
+
+	b := map[string]string{}
+	b["container"] = "app"
+	b["pod"] = "api-7d9f8c6b5-xk2lp"
+	b["namespace"] = "prod"
+
+	idA := StableID("kubernetes_pod", "oom_kill", a)
+	for i := 0; i < 20; i++ { // Map iteration order is randomized per range
+		if idB := StableID("kubernetes_pod", "oom_kill", b); idB != idA {
+			t.Fatalf("StableID differs by label order: %s vs %s", idA, idB)
+		}
+	}
+	if !strings.HasPrefix(idA, "oom_kill/") {
+		t.Errorf("StableID = %s, want oom_kill/ prefix", idA)
+	}
+}
+
+func TestStableID_Distinguishes(t *testing.T) {
+	base := StableID("kubernetes_pod", "oom_kill", map[string]string{"namespace": "prod", "pod": "api"})
+	others := map[string]string{
+		"label value": StableID("kubernetes_pod", "oom_kill", map[string]string{"namespace": "prod", "pod": "web"}),
+		"extra label": StableID("kubernetes_pod", "oom_kill", map[string]string{"namespace": "prod", "pod": "api", "container": "app"}),
+		"entity type": StableID("service_mesh_control_plane", "oom_kill", map[string]string{"namespace": "prod", "pod": "api"}),
+		"type":        StableID("kubernetes_pod", "crashloopbackoff", map[string]string{"namespace": "prod", "pod": "api"}),
+		"boundary":    StableID("kubernetes_pod", "oom_kill", map[string]string{"namespace": "pro", "pod": "dapi"}),
+		"no labels":   StableID("kubernetes_pod", "oom_kill", nil),
+	}
+	for name, id := range others {
+		if id == base {
+			t.Errorf("%s: got same ID %s", name, id)
+		}
+	}
+}
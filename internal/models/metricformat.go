@@ -0,0 +1,169 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetricFormatter renders a raw Problem.Metrics value for people
+type MetricFormatter func(v float64) string
+
+// Formatters for the common metric units
+var (
+	FormatSeconds      MetricFormatter = durationFormatter(time.Second)
+	FormatMilliseconds MetricFormatter = durationFormatter(time.Millisecond)
+	FormatHours        MetricFormatter = durationFormatter(time.Hour)
+	FormatPercent      MetricFormatter = formatPercent // Value already in percent (93 = 93%)
+	FormatRatio        MetricFormatter = formatRatio   // Fraction of one (0.93 = 93%)
+	FormatBytes        MetricFormatter = formatBytes
+)
+
+// metricFormatters is keyed by metric name. Names not listed fall back to
+// their unit suffix (see metricSuffixFormatters), then to the plain number.
+var metricFormatters = map[string]MetricFormatter{
+	"error_rate":         FormatPercent, // Converted to percent by the detectors
+	"latency_ms":         FormatMilliseconds,
+	"oplog_window_hours": FormatHours,
+}
+
+// metricSuffixFormatters follow the Prometheus base unit naming conventions
+var metricSuffixFormatters = []struct {
+	suffix    string
+	formatter MetricFormatter
+}{
+	{"_seconds", FormatSeconds},
+	{"_percent", FormatPercent},
+	{"_ratio", FormatRatio},
+	{"_bytes", FormatBytes},
+}
+
+// RegisterMetricFormatter sets the formatter for a metric name, replacing
+// any previous one. It must be called at startup, before any output is
+// produced.
+func RegisterMetricFormatter(name string, f MetricFormatter) {
+	metricFormatters[name] = f
+}
+
+// FormatMetric renders the value of the named metric, e.g. 432000 for
+// remaining_seconds as "5d". Metrics without a known unit keep their number.
+func FormatMetric(name string, v float64) string {
+	if f, ok := metricFormatters[name]; ok {
+		return f(v)
+	}
+	for _, s := range metricSuffixFormatters {
+		if strings.HasSuffix(name, s.suffix) {
+			return s.formatter(v)
+		}
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// FormattedMetrics returns every metric of p rendered with FormatMetric, or
+// nil if p has none
+func (p *Problem) FormattedMetrics() map[string]string {
+	if len(p.Metrics) == 0 {
+		return nil
+	}
+	formatted := make(map[string]string, len(p.Metrics))
+	for name, v := range p.Metrics {
+		formatted[name] = FormatMetric(name, v)
+	}
+	return formatted
+}
+
+// MetricsSummary renders the metrics of p as "name=value" pairs sorted by
+// name, e.g. "remaining_seconds=5d, usage_percent=93%"
+func (p *Problem) MetricsSummary() string {
+	names := make([]string, 0, len(p.Metrics))
+	for name := range p.Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + FormatMetric(name, p.Metrics[name])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// durationFormatter renders values counted in unit as a duration with at
+// most two components: 5d, 1d2h, 3h15m, 1.5s, 250ms
+func durationFormatter(unit time.Duration) MetricFormatter {
+	return func(v float64) string {
+		d := time.Duration(v * float64(unit))
+		sign := ""
+		if d < 0 {
+			sign, d = "-", -d
+		}
+		switch {
+		case d < time.Second:
+			return sign + d.Round(time.Millisecond).String()
+		case d < time.Minute:
+			return sign + d.Round(100*time.Millisecond).String()
+		}
+
+		d = d.Round(time.Second)
+		units := []struct {
+			size   time.Duration
+			suffix string
+		}{
+			{24 * time.Hour, "d"},
+			{time.Hour, "h"},
+			{time.Minute, "m"},
+			{time.Second, "s"},
+		}
+		var b strings.Builder
+		parts := 0
+		for _, u := range units {
+			n := d / u.size
+			if n == 0 {
+				if parts > 0 {
+					break // Only adjacent units: 1d0h5m is shown as 1d
+				}
+				continue
+			}
+			fmt.Fprintf(&b, "%d%s", n, u.suffix)
+			d -= n * u.size
+			if parts++; parts == 2 {
+				break
+			}
+		}
+		return sign + b.String()
+	}
+}
+
+func formatPercent(v float64) string {
+	return strconv.FormatFloat(roundTo(v, 1), 'f', -1, 64) + "%"
+}
+
+func formatRatio(v float64) string {
+	return formatPercent(v * 100)
+}
+
+// formatBytes uses binary (IEC) units: 1.2 GiB
+func formatBytes(v float64) string {
+	const unit = 1024
+	sign := ""
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	if v < unit {
+		return fmt.Sprintf("%s%.0f B", sign, v)
+	}
+	suffixes := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	i := 0
+	for v /= unit; v >= unit && i < len(suffixes)-1; i++ {
+		v /= unit
+	}
+	return sign + strconv.FormatFloat(roundTo(v, 1), 'f', -1, 64) + " " + suffixes[i]
+}
+
+// roundTo rounds v to the given number of decimals
+func roundTo(v float64, decimals int) float64 {
+	f, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'f', decimals, 64), 64)
+	return f
+}
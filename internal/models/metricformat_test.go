@@ -0,0 +1,88 @@
+package models
+
+import "testing"
+
+func TestFormatMetric_Duration(t *testing.T) {
+	tests := []struct {
+		name string
+		v    float64
+		want string
+	}{
+		{"remaining_seconds", 432000, "5d"},
+		{"remaining_seconds", 93600, "1d2h"},
+		{"lag_seconds", 11700, "3h15m"},
+		{"scrape_age_seconds", 45, "45s"},
+		{"remaining_seconds", -7200, "-2h"},
+		{"lag_seconds", 0.25, "250ms"},
+		{"latency_ms", 250, "250ms"},
+		{"latency_ms", 1500, "1.5s"},
+		{"oplog_window_hours", 36, "1d12h"},
+	}
+	for _, tt := range tests {
+		if got := FormatMetric(tt.name, tt.v); got != tt.want {
+			t.Errorf("FormatMetric(%s, %g) = %q, want %q", tt.name, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMetric_Percent(t *testing.T) {
+	tests := []struct {
+		name string
+		v    float64
+		want string
+	}{
+		{"usage_percent", 93, "93%"},
+		{"memory_usage_percent", 91.237, "91.2%"},
+		{"error_rate", 8.5, "8.5%"},
+		{"hit_ratio", 0.93, "93%"},
+		{"hit_ratio", 0.5, "50%"},
+	}
+	for _, tt := range tests {
+		if got := FormatMetric(tt.name, tt.v); got != tt.want {
+			t.Errorf("FormatMetric(%s, %g) = %q, want %q", tt.name, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMetric_Bytes(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want string
+	}{
+		{512, "512 B"},
+		{1536, "1.5 KiB"},
+		{1288490188.8, "1.2 GiB"},
+		{5 << 40, "5 TiB"},
+	}
+	for _, tt := range tests {
+		if got := FormatMetric("free_bytes", tt.v); got != tt.want {
+			t.Errorf("FormatMetric(free_bytes, %g) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMetric_UnknownAndRegistered(t *testing.T) {
+	if got := FormatMetric("restart_count", 7); got != "7" {
+		t.Errorf("unknown metric = %q, want the plain number", got)
+	}
+
+	t.Cleanup(func() { delete(metricFormatters, "cache_size") })
+	RegisterMetricFormatter("cache_size", FormatBytes)
+	if got := FormatMetric("cache_size", 2048); got != "2 KiB" {
+		t.Errorf("registered metric = %q, want 2 KiB", got)
+	}
+}
+
+func TestProblem_FormattedMetrics(t *testing.T) {
+	p := &Problem{Metrics: map[string]float64{"remaining_seconds": 432000, "usage_percent": 93}}
+	got := p.FormattedMetrics()
+	if got["remaining_seconds"] != "5d" || got["usage_percent"] != "93%" {
+		t.Errorf("FormattedMetrics() = %v", got)
+	}
+	if s := p.MetricsSummary(); s != "remaining_seconds=5d, usage_percent=93%" {
+		t.Errorf("MetricsSummary() = %q", s)
+	}
+	if (&Problem{}).FormattedMetrics() != nil {
+		t.Error("expected nil for a problem without metrics")
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"net"
+	"strings"
+)
+
+// Label normalization rules, applied in the order listed for a label
+const (
+	NormalizeStripPort = "strip_port" // "node-1:9100" -> "node-1"
+	NormalizeLowercase = "lowercase"  // "Node-1" -> "node-1"
+)
+
+// ValidNormalization reports whether rule is a known label normalization rule
+func ValidNormalization(rule string) bool {
+	return rule == NormalizeStripPort || rule == NormalizeLowercase
+}
+
+// NormalizeLabelValue applies rules to value in order. Unknown rules are
+// ignored; config validation rejects them.
+func NormalizeLabelValue(value string, rules []string) string {
+	for _, rule := range rules {
+		switch rule {
+		case NormalizeStripPort:
+			if host, _, err := net.SplitHostPort(value); err == nil {
+				value = host
+			}
+		case NormalizeLowercase:
+			value = strings.ToLower(value)
+		}
+	}
+	return value
+}
+
+// NormalizeLabels rewrites the labels listed in rules so problems reported
+// under inconsistent label values (an instance with and without its port)
+// get the same ID. The rewritten values are also replaced in the entity.
+// Must run before the ID is derived.
+func (p *Problem) NormalizeLabels(rules map[string][]string) {
+	for name, labelRules := range rules {
+		value, ok := p.Labels[name]
+		if !ok {
+			continue
+		}
+		normalized := NormalizeLabelValue(value, labelRules)
+		if normalized == value {
+			continue
+		}
+		p.Labels[name] = normalized
+		p.Entity = strings.ReplaceAll(p.Entity, value, normalized)
+	}
+}
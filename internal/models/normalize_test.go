@@ -0,0 +1,53 @@
+package models
+
+import "testing"
+
+func TestNormalizeLabelValue(t *testing.T) {
+	tests := []struct {
+		value string
+		rules []string
+		want  string
+	}{
+		{"node-1:9100", []string{NormalizeStripPort}, "node-1"},
+		{"node-1", []string{NormalizeStripPort}, "node-1"},
+		{"10.0.0.5:8080", []string{NormalizeStripPort}, "10.0.0.5"},
+		{"[::1]:9100", []string{NormalizeStripPort}, "::1"},
+		{"prod/api", []string{NormalizeStripPort}, "prod/api"},
+		{"Node-1:9100", []string{NormalizeStripPort, NormalizeLowercase}, "node-1"},
+		{"Node-1", nil, "Node-1"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeLabelValue(tt.value, tt.rules); got != tt.want {
+			t.Errorf("NormalizeLabelValue(%q, %v) = %q, want %q", tt.value, tt.rules, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeLabels_MergesInstanceWithAndWithoutPort(t *testing.T) {
+	rules := map[string][]string{"instance": {NormalizeStripPort, NormalizeLowercase}}
+
+	withPort := &Problem{
+		Entity:     "node-1:9100:/var",
+		EntityType: "filesystem",
+		Type:       "disk_full",
+		Labels:     map[string]string{"instance": "node-1:9100", "mountpoint": "/var"},
+	}
+	withoutPort := &Problem{
+		Entity:     "Node-1:/var",
+		EntityType: "filesystem",
+		Type:       "disk_full",
+		Labels:     map[string]string{"instance": "Node-1", "mountpoint": "/var"},
+	}
+	withPort.NormalizeLabels(rules)
+	withoutPort.NormalizeLabels(rules)
+
+	if withPort.Entity != "node-1:/var" || withoutPort.Entity != "node-1:/var" {
+		t.Errorf("entities = %q, %q, want both node-1:/var", withPort.Entity, withoutPort.Entity)
+	}
+	idA := StableID(withPort.EntityType, withPort.Type, withPort.Labels)
+	idB := StableID(withoutPort.EntityType, withoutPort.Type, withoutPort.Labels)
+	if idA != idB {
+		t.Errorf("IDs differ after normalization: %s vs %s", idA, idB)
+	}
+}
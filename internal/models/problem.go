@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
 )
@@ -15,32 +16,70 @@ const (
 	SeverityWarning  Severity = "WARNING"  // Anomaly detected, no immediate impact
 )
 
+// State is a problem's lifecycle state in output
+type State string
+
+const (
+	StateFiring   State = "firing"   // Currently reported by a detector
+	StateResolved State = "resolved" // No longer reported, e.g. gone since a baseline
+)
+
 // RunbookBaseURL is the base URL for detector runbook documentation.
 const RunbookBaseURL = "https://github.com/ppiankov/infranow/blob/main/docs/runbooks/"
 
-// Scoring weights for problem importance ranking
+// Scoring weights for problem importance ranking. Each severity owns a band
+// of scoreBand points; blast radius and persistence only move a problem
+// within its band, so severity always dominates.
 const (
-	scoreFatal    = 100.0
-	scoreCritical = 50.0
-	scoreWarning  = 10.0
+	scoreBand = 100.0
 
 	// Per-unit blast radius weight applied to base score
 	blastRadiusWeight = 0.1
 
 	// Persistence is normalized to hours for scoring
 	secondsPerHour = 3600.0
+
+	// DefaultPersistenceCap bounds the persistence multiplier so age does
+	// not drown out blast radius within a severity band
+	DefaultPersistenceCap = 5.0
+
+	// The recency boost halves for every recencyHalfLife seconds a problem
+	// has persisted
+	recencyHalfLife = 600.0
 )
 
+// persistenceCap is the maximum persistence multiplier, <= 0 for uncapped
+var persistenceCap = DefaultPersistenceCap
+
+// SetPersistenceCap sets the maximum persistence multiplier used by Score
+// (<= 0 removes the cap). It must be called before problems are scored, at
+// startup.
+func SetPersistenceCap(v float64) {
+	persistenceCap = v
+}
+
+// recencyWeight is the score boost of a newly first-seen problem, 0 for none
+var recencyWeight float64
+
+// SetRecencyWeight sets the recency boost used by Score: a new problem's
+// multiplier is 1 + v, halving every 10 minutes it persists (0 turns it
+// off). With 5, a new problem outranks one at the default persistence cap.
+// It must be called before problems are scored, at startup.
+func SetRecencyWeight(v float64) {
+	recencyWeight = v
+}
+
 // Problem represents a unified infrastructure issue
 type Problem struct {
 	// Identity
-	ID         string // Unique identifier (entity + type hash)
+	ID         string // Unique identifier, see StableID
 	Entity     string // What: "namespace/deployment/pod", "kafka/broker-1", "postgres/primary"
 	EntityType string // Kind: "kubernetes_pod", "kafka_broker", "database"
 	Type       string // Issue type: "high_error_rate", "disk_full", "replication_lag"
 
 	// Classification
 	Severity Severity
+	State    State  `json:"state,omitempty"` // Set on output, empty inside the watcher
 	Title    string // Short description
 	Message  string // Detailed message
 
@@ -49,6 +88,11 @@ type Problem struct {
 	LastSeen  time.Time
 	Count     int // How many times detected
 
+	// When the problem was first seen by an earlier run, carried over from
+	// --state-file (zero when this session saw it first). FirstSeen, and
+	// everything timed from it, restarts with every session.
+	OriginalFirstSeen time.Time `json:"original_first_seen,omitzero"`
+
 	// Impact
 	BlastRadius int     // Estimated affected entities
 	Persistence float64 // Duration in seconds
@@ -60,6 +104,15 @@ type Problem struct {
 	Hint       string             `json:"hint,omitempty"`        // One-line actionable guidance
 	RunbookURL string             `json:"runbook_url,omitempty"` // Link to detailed runbook
 
+	// PromQL that produced the problem and a Prometheus/Grafana link opening
+	// it (populated with --prometheus-ui-url)
+	Query    string `json:"query,omitempty"`
+	QueryURL string `json:"query_url,omitempty"`
+
+	// Metrics rendered for people, e.g. "5d" or "93%" (set on JSON output,
+	// nil inside the watcher)
+	MetricsFormatted map[string]string `json:"metrics_formatted,omitempty"`
+
 	// Correlation (set by correlator, zero value = uncorrelated)
 	IncidentID   string   `json:"incident_id,omitempty"`
 	IncidentType string   `json:"incident_type,omitempty"`
@@ -67,6 +120,28 @@ type Problem struct {
 
 	// History (populated when --history is enabled, nil otherwise)
 	History *HistoryAnnotation `json:"history,omitempty"`
+
+	// Recent detections, oldest first (populated with --include-history)
+	Observations []Observation `json:"observations,omitempty"`
+
+	// Evaluation time of the underlying samples (populated with --verbose);
+	// StaleSample means they lagged the detection, e.g. scrape lag
+	EvaluatedAt time.Time `json:"evaluated_at,omitzero"`
+	StaleSample bool      `json:"stale_sample,omitempty"`
+
+	// Matched by the --allow-list file (only set on problems shown with
+	// --show-allowed, which never count towards totals or --fail-on)
+	Allowed     bool   `json:"allowed,omitempty"`
+	AllowReason string `json:"allow_reason,omitempty"`
+}
+
+// Observation is a single detection of a problem, kept for trend exports
+// and the TUI trajectory
+type Observation struct {
+	Timestamp time.Time          `json:"timestamp"`
+	Severity  Severity           `json:"severity"`
+	Count     int                `json:"count,omitempty"`
+	Metrics   map[string]float64 `json:"metrics,omitempty"`
 }
 
 // HistoryAnnotation holds cross-session recurrence data from the history database
@@ -78,17 +153,49 @@ type HistoryAnnotation struct {
 
 // Score calculates problem importance for ranking
 func (p *Problem) Score() float64 {
-	severityWeight := map[Severity]float64{
-		SeverityFatal:    scoreFatal,
-		SeverityCritical: scoreCritical,
-		SeverityWarning:  scoreWarning,
+	severityRank := map[Severity]float64{
+		SeverityFatal:    3,
+		SeverityCritical: 2,
+		SeverityWarning:  1,
 	}
 
-	base := severityWeight[p.Severity]
 	blastRadiusMultiplier := 1.0 + (float64(p.BlastRadius) * blastRadiusWeight)
 	persistenceMultiplier := 1.0 + (p.Persistence / secondsPerHour)
+	if persistenceCap > 0 && persistenceMultiplier > persistenceCap {
+		persistenceMultiplier = persistenceCap
+	}
+	recencyMultiplier := 1.0 + recencyWeight*math.Exp2(-p.Persistence/recencyHalfLife)
 
-	return base * blastRadiusMultiplier * persistenceMultiplier
+	// 1 - 1/m maps the multipliers (m >= 1) onto [0, 1): never into the next band
+	withinBand := 1.0 - 1.0/(blastRadiusMultiplier*persistenceMultiplier*recencyMultiplier)
+	return scoreBand * (severityRank[p.Severity] + withinBand)
+}
+
+// AddStaticLabels adds labels p does not already carry. Labels the detector
+// set from the series win, so static tags never rewrite identity labels
+// like namespace or pod.
+func (p *Problem) AddStaticLabels(labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+	if p.Labels == nil {
+		p.Labels = make(map[string]string, len(labels))
+	}
+	for k, v := range labels {
+		if _, ok := p.Labels[k]; !ok {
+			p.Labels[k] = v
+		}
+	}
+}
+
+// Since returns when the problem was first seen by any run: the
+// OriginalFirstSeen carried over from an earlier one, else FirstSeen. Ages
+// shown to people are measured from it.
+func (p *Problem) Since() time.Time {
+	if !p.OriginalFirstSeen.IsZero() && p.OriginalFirstSeen.Before(p.FirstSeen) {
+		return p.OriginalFirstSeen
+	}
+	return p.FirstSeen
 }
 
 // UpdatePersistence calculates the persistence duration based on first and last seen times
@@ -18,21 +18,21 @@ func TestProblemScore(t *testing.T) {
 			severity:    SeverityFatal,
 			blastRadius: 10,
 			persistence: 3600, // 1 hour
-			minScore:    200,  // 100 * 2.0 * 2.0
+			minScore:    375,  // 100 * (3 + 1 - 1/(2.0*2.0))
 		},
 		{
 			name:        "critical with no blast radius",
 			severity:    SeverityCritical,
 			blastRadius: 0,
 			persistence: 0,
-			minScore:    50, // 50 * 1.0 * 1.0
+			minScore:    200, // 100 * (2 + 0)
 		},
 		{
 			name:        "warning",
 			severity:    SeverityWarning,
 			blastRadius: 0,
 			persistence: 0,
-			minScore:    10, // 10 * 1.0 * 1.0
+			minScore:    100, // 100 * (1 + 0)
 		},
 	}
 
@@ -52,6 +52,83 @@ func TestProblemScore(t *testing.T) {
 	}
 }
 
+func TestProblemScore_PersistenceCap(t *testing.T) {
+	weekOld := &Problem{Severity: SeverityWarning, Persistence: 7 * 24 * 3600}
+	dayOld := &Problem{Severity: SeverityWarning, Persistence: 24 * 3600}
+	if weekOld.Score() != dayOld.Score() {
+		t.Errorf("both are past the cap: week-old %.2f, day-old %.2f", weekOld.Score(), dayOld.Score())
+	}
+
+	// Persistence below the cap still counts
+	hourOld := &Problem{Severity: SeverityWarning, Persistence: 3600}
+	fresh := &Problem{Severity: SeverityWarning}
+	if hourOld.Score() <= fresh.Score() || hourOld.Score() >= dayOld.Score() {
+		t.Errorf("scores not ordered by age: fresh %.2f, 1h %.2f, 1d %.2f", fresh.Score(), hourOld.Score(), dayOld.Score())
+	}
+
+	// A wide blast radius beats age once age is capped
+	wide := &Problem{Severity: SeverityWarning, BlastRadius: 50}
+	if wide.Score() <= weekOld.Score() {
+		t.Errorf("blast radius 50 (%.2f) should outrank a week-old single problem (%.2f)", wide.Score(), weekOld.Score())
+	}
+
+	SetPersistenceCap(0)
+	defer SetPersistenceCap(DefaultPersistenceCap)
+	if weekOld.Score() <= dayOld.Score() {
+		t.Error("with the cap removed persistence keeps growing")
+	}
+}
+
+func TestProblemScore_SeverityDominates(t *testing.T) {
+	extremes := func(severity Severity) (lowest, highest *Problem) {
+		return &Problem{Severity: severity},
+			&Problem{Severity: severity, BlastRadius: 10000, Persistence: 365 * 24 * 3600}
+	}
+
+	for _, limit := range []float64{DefaultPersistenceCap, 0} {
+		SetPersistenceCap(limit)
+		fatal, _ := extremes(SeverityFatal)
+		critical, oldCritical := extremes(SeverityCritical)
+		_, oldWarning := extremes(SeverityWarning)
+
+		if oldCritical.Score() >= fatal.Score() {
+			t.Errorf("cap %.0f: year-old wide CRITICAL (%.2f) outranks fresh FATAL (%.2f)", limit, oldCritical.Score(), fatal.Score())
+		}
+		if oldWarning.Score() >= critical.Score() {
+			t.Errorf("cap %.0f: year-old wide WARNING (%.2f) outranks fresh CRITICAL (%.2f)", limit, oldWarning.Score(), critical.Score())
+		}
+		if oldWarning.Score() >= fatal.Score() {
+			t.Errorf("cap %.0f: year-old wide WARNING (%.2f) outranks fresh FATAL (%.2f)", limit, oldWarning.Score(), fatal.Score())
+		}
+	}
+	SetPersistenceCap(DefaultPersistenceCap)
+}
+
+func TestProblemScore_RecencyWeight(t *testing.T) {
+	fresh := &Problem{Severity: SeverityCritical, Persistence: 30}
+	hourOld := &Problem{Severity: SeverityCritical, Persistence: 3600}
+	dayOld := &Problem{Severity: SeverityCritical, Persistence: 24 * 3600}
+
+	if fresh.Score() >= hourOld.Score() {
+		t.Fatalf("without recency weight the older problem ranks first: fresh %.2f, 1h %.2f", fresh.Score(), hourOld.Score())
+	}
+
+	SetRecencyWeight(5)
+	defer SetRecencyWeight(0)
+	if fresh.Score() <= hourOld.Score() || fresh.Score() <= dayOld.Score() {
+		t.Errorf("with recency weight the newer problem ranks first: fresh %.2f, 1h %.2f, 1d %.2f", fresh.Score(), hourOld.Score(), dayOld.Score())
+	}
+
+	// Recency only reorders within a severity
+	oldFatal := &Problem{Severity: SeverityFatal, Persistence: 24 * 3600}
+	newWarning := &Problem{Severity: SeverityWarning, BlastRadius: 10000}
+	SetRecencyWeight(1000)
+	if fresh.Score() >= oldFatal.Score() || newWarning.Score() >= dayOld.Score() {
+		t.Errorf("recency crossed a severity band: fresh CRITICAL %.2f vs old FATAL %.2f, fresh WARNING %.2f vs old CRITICAL %.2f",
+			fresh.Score(), oldFatal.Score(), newWarning.Score(), dayOld.Score())
+	}
+}
+
 func TestUpdatePersistence(t *testing.T) {
 	firstSeen := time.Now().Add(-5 * time.Minute)
 	lastSeen := time.Now()
@@ -135,3 +212,36 @@ func TestSeverityOrdering(t *testing.T) {
 		t.Error("critical should score higher than warning")
 	}
 }
+
+func TestAddStaticLabels(t *testing.T) {
+	p := &Problem{Labels: map[string]string{"namespace": "prod"}}
+	p.AddStaticLabels(map[string]string{"category": "capacity", "namespace": "static"})
+	if p.Labels["category"] != "capacity" || p.Labels["namespace"] != "prod" {
+		t.Errorf("labels = %v, want category added and namespace kept", p.Labels)
+	}
+
+	bare := &Problem{}
+	bare.AddStaticLabels(map[string]string{"team": "storage"})
+	if bare.Labels["team"] != "storage" {
+		t.Errorf("labels = %v, want team added to a problem without labels", bare.Labels)
+	}
+}
+
+func TestProblemSince(t *testing.T) {
+	session := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	p := &Problem{FirstSeen: session}
+	if !p.Since().Equal(session) {
+		t.Errorf("Since() = %v, want FirstSeen without an original", p.Since())
+	}
+
+	p.OriginalFirstSeen = session.Add(-72 * time.Hour)
+	if !p.Since().Equal(p.OriginalFirstSeen) {
+		t.Errorf("Since() = %v, want OriginalFirstSeen", p.Since())
+	}
+
+	// An original after FirstSeen (clock skew between runs) is ignored
+	p.OriginalFirstSeen = session.Add(time.Hour)
+	if !p.Since().Equal(session) {
+		t.Errorf("Since() = %v, want FirstSeen", p.Since())
+	}
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// ProblemTemplate rewords problems of one type. Title and Message are Go
+// text/template sources over the problem, e.g.
+// "{{.Labels.pod}} ran out of memory ({{.Metrics.restart_count}} restarts)".
+// .Title and .Message hold the detector's wording; an empty field keeps it.
+type ProblemTemplate struct {
+	Title   string `json:"title,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ProblemTemplates are compiled ProblemTemplates keyed by problem type
+type ProblemTemplates struct {
+	byType map[string]compiledTemplate
+}
+
+type compiledTemplate struct {
+	title   *template.Template
+	message *template.Template
+}
+
+// CompileTemplates parses templates keyed by problem type
+func CompileTemplates(templates map[string]ProblemTemplate) (*ProblemTemplates, error) {
+	compiled := &ProblemTemplates{byType: make(map[string]compiledTemplate, len(templates))}
+	for problemType, t := range templates {
+		if t.Title == "" && t.Message == "" {
+			return nil, fmt.Errorf("%s: title or message is required", problemType)
+		}
+		var c compiledTemplate
+		var err error
+		if c.title, err = parseTemplate(problemType+".title", t.Title); err != nil {
+			return nil, err
+		}
+		if c.message, err = parseTemplate(problemType+".message", t.Message); err != nil {
+			return nil, err
+		}
+		compiled.byType[problemType] = c
+	}
+	return compiled, nil
+}
+
+// parseTemplate parses src; empty src yields nil
+func parseTemplate(name, src string) (*template.Template, error) {
+	if src == "" {
+		return nil, nil
+	}
+	t, err := template.New(name).Option("missingkey=zero").Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return t, nil
+}
+
+// Apply rewrites p's title and message from its type's template. A template
+// that fails to render leaves the detector's wording in place, so a typo
+// never blanks a problem. Safe on a nil receiver.
+func (t *ProblemTemplates) Apply(p *Problem) {
+	if t == nil {
+		return
+	}
+	c, ok := t.byType[p.Type]
+	if !ok {
+		return
+	}
+	// Both render from the detector's wording, not each other's output
+	title, message := render(c.title, p, p.Title), render(c.message, p, p.Message)
+	p.Title, p.Message = title, message
+}
+
+// render executes t over p, returning fallback when t is nil or fails
+func render(t *template.Template, p *Problem, fallback string) string {
+	if t == nil {
+		return fallback
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, p); err != nil {
+		return fallback
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package models
+
+import "testing"
+
+func oomProblem() *Problem {
+	return &Problem{
+		Type:    "oom_kill",
+		Title:   "Container OOM Killed",
+		Message: "Container api in pod prod/api-0 was OOM killed",
+		Labels:  map[string]string{"namespace": "prod", "pod": "api-0", "container": "api"},
+		Metrics: map[string]float64{"restart_count": 2},
+	}
+}
+
+func TestProblemTemplates_OverrideOOMKillMessage(t *testing.T) {
+	templates, err := CompileTemplates(map[string]ProblemTemplate{
+		"oom_kill": {Message: "{{.Labels.pod}} ({{.Labels.namespace}}) hat den Speicher überschritten: {{.Metrics.restart_count}} Neustarts"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := oomProblem()
+	templates.Apply(p)
+	if want := "api-0 (prod) hat den Speicher überschritten: 2 Neustarts"; p.Message != want {
+		t.Errorf("message = %q, want %q", p.Message, want)
+	}
+	if p.Title != "Container OOM Killed" {
+		t.Errorf("title without a template = %q, want the default", p.Title)
+	}
+
+	other := &Problem{Type: "crashloopbackoff", Message: "default"}
+	templates.Apply(other)
+	if other.Message != "default" {
+		t.Errorf("problem type without a template was rewritten: %q", other.Message)
+	}
+}
+
+func TestProblemTemplates_DefaultWordingAvailable(t *testing.T) {
+	templates, err := CompileTemplates(map[string]ProblemTemplate{
+		"oom_kill": {Title: "[{{.Labels.namespace}}] {{.Title}}", Message: "{{.Message}} (title: {{.Title}})"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := oomProblem()
+	templates.Apply(p)
+	if p.Title != "[prod] Container OOM Killed" {
+		t.Errorf("title = %q", p.Title)
+	}
+	if p.Message != "Container api in pod prod/api-0 was OOM killed (title: Container OOM Killed)" {
+		t.Errorf("message should render from the default title, got %q", p.Message)
+	}
+}
+
+func TestProblemTemplates_FailedRenderKeepsDefault(t *testing.T) {
+	templates, err := CompileTemplates(map[string]ProblemTemplate{
+		"oom_kill": {Message: "{{.Labels.pod.Missing}}"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := oomProblem()
+	templates.Apply(p)
+	if p.Message != "Container api in pod prod/api-0 was OOM killed" {
+		t.Errorf("message = %q, want the detector's", p.Message)
+	}
+
+	var none *ProblemTemplates
+	none.Apply(p) // nil templates are a no-op
+}
+
+func TestCompileTemplates_Errors(t *testing.T) {
+	if _, err := CompileTemplates(map[string]ProblemTemplate{"oom_kill": {Message: "{{.Labels.pod"}}); err == nil {
+		t.Error("expected a parse error")
+	}
+	if _, err := CompileTemplates(map[string]ProblemTemplate{"oom_kill": {}}); err == nil {
+		t.Error("expected an error for an empty template")
+	}
+}
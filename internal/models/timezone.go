@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// displayLocation is the zone timestamps are displayed and exported in.
+// Durations ("5m ago") do not depend on it.
+var displayLocation = time.UTC
+
+// SetDisplayLocation sets the zone used by FormatTime and InDisplayLocation.
+// It must be called at startup, before any output is produced.
+func SetDisplayLocation(loc *time.Location) {
+	displayLocation = loc
+}
+
+// DisplayTime returns t in the display location
+func DisplayTime(t time.Time) time.Time {
+	return t.In(displayLocation)
+}
+
+// FormatTime formats t as RFC3339 in the display location
+func FormatTime(t time.Time) string {
+	return DisplayTime(t).Format(time.RFC3339)
+}
+
+// InDisplayLocation converts the timestamps of problems to the display
+// location so they encode consistently. The instants are unchanged.
+// Observations and history are copied before conversion, so problems that
+// share them with the watcher can be passed safely.
+func InDisplayLocation(problems []*Problem) {
+	for _, p := range problems {
+		p.FirstSeen = p.FirstSeen.In(displayLocation)
+		p.LastSeen = p.LastSeen.In(displayLocation)
+		if !p.OriginalFirstSeen.IsZero() {
+			p.OriginalFirstSeen = p.OriginalFirstSeen.In(displayLocation)
+		}
+		if !p.EvaluatedAt.IsZero() {
+			p.EvaluatedAt = p.EvaluatedAt.In(displayLocation)
+		}
+		if len(p.Observations) > 0 {
+			obs := make([]Observation, len(p.Observations))
+			for i, o := range p.Observations {
+				o.Timestamp = o.Timestamp.In(displayLocation)
+				obs[i] = o
+			}
+			p.Observations = obs
+		}
+		if p.History != nil {
+			h := *p.History
+			h.FirstSeenGlobal = h.FirstSeenGlobal.In(displayLocation)
+			p.History = &h
+		}
+	}
+}
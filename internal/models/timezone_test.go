@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatTime_DisplayLocation(t *testing.T) {
+	defer SetDisplayLocation(time.UTC)
+
+	ts := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	if got := FormatTime(ts); got != "2026-04-01T12:00:00Z" {
+		t.Errorf("FormatTime() in UTC = %s", got)
+	}
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	SetDisplayLocation(tokyo)
+	if got := FormatTime(ts); got != "2026-04-01T21:00:00+09:00" {
+		t.Errorf("FormatTime() in Asia/Tokyo = %s", got)
+	}
+}
+
+func TestInDisplayLocation(t *testing.T) {
+	defer SetDisplayLocation(time.UTC)
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	SetDisplayLocation(newYork)
+
+	first := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	shared := []Observation{{Timestamp: first}}
+	p := &Problem{
+		FirstSeen:    first,
+		LastSeen:     first.Add(5 * time.Minute),
+		Observations: shared,
+		History:      &HistoryAnnotation{FirstSeenGlobal: first},
+	}
+	InDisplayLocation([]*Problem{p})
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	for _, want := range []string{
+		`"FirstSeen":"2026-04-01T08:00:00-04:00"`,
+		`"LastSeen":"2026-04-01T08:05:00-04:00"`,
+		`"timestamp":"2026-04-01T08:00:00-04:00"`,
+		`"first_seen_global":"2026-04-01T08:00:00-04:00"`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("JSON missing %s: %s", want, data)
+		}
+	}
+
+	if !p.FirstSeen.Equal(first) {
+		t.Error("conversion must not change the instant")
+	}
+	if shared[0].Timestamp.Location() != time.UTC {
+		t.Error("shared observations must not be modified")
+	}
+}
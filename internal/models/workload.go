@@ -0,0 +1,18 @@
+package models
+
+import "regexp"
+
+// podHashSuffix matches the ReplicaSet and pod hash suffixes Kubernetes
+// appends to controller-managed pod names (api-7d9f8c6b5-xk2lp, agent-x7k2q).
+// Hashes use the vowel-free alphabet of k8s.io/apimachinery rand.SafeEncodeString,
+// so ordinary words like "cache" are not mistaken for one.
+var podHashSuffix = regexp.MustCompile(`(-[bcdfghjklmnpqrstvwxz2456789]{6,10})?-[bcdfghjklmnpqrstvwxz2456789]{5}$`)
+
+// WorkloadName strips the controller hash suffixes from a pod name.
+// StatefulSet ordinals (db-0) are kept: those pods have stable identities.
+func WorkloadName(pod string) string {
+	if name := podHashSuffix.ReplaceAllString(pod, ""); name != "" {
+		return name
+	}
+	return pod
+}
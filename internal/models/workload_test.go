@@ -0,0 +1,20 @@
+package models
+
+import "testing"
+
+func TestWorkloadName(t *testing.T) {
+	tests := map[string]string{
+		"api-7d9f8c6b5-xk2lp": "api",         // Deployment
+		"node-agent-x7k2q":    "node-agent",  // DaemonSet
+		"postgres-0":          "postgres-0",  // StatefulSet keeps ordinal
+		"standalone":          "standalone",  // Bare pod
+		"":                    "",            // No pod label
+		"bcdfg":               "bcdfg",       // Never strip to empty
+		"redis-cache":         "redis-cache", // Not a hash
+	}
+	for pod, want := range tests {
+		if got := WorkloadName(pod); got != want {
+			t.Errorf("WorkloadName(%q) = %q, want %q", pod, got, want)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package monitor
+
+import "github.com/ppiankov/infranow/internal/models"
+
+// allowedMarker prefixes the severity of allow-listed problems shown with
+// --show-allowed
+const allowedMarker = "~"
+
+// countAllowed returns how many of problems are allow-listed
+func countAllowed(problems []*models.Problem) int {
+	n := 0
+	for _, p := range problems {
+		if p.Allowed {
+			n++
+		}
+	}
+	return n
+}
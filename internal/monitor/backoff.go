@@ -0,0 +1,74 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/ppiankov/infranow/internal/detector"
+)
+
+const (
+	// backoffAfterFailures is how many runs in a row a detector may fail
+	// before its interval starts doubling
+	backoffAfterFailures = 3
+
+	// maxBackoffInterval caps a failing detector's interval
+	maxBackoffInterval = 10 * time.Minute
+)
+
+// DetectorInterval returns how long d waits between runs. A detector failing
+// on every backend (e.g. its metric is gone after an uninstall) has its
+// interval doubled per failure from the backoffAfterFailures-th in a row,
+// capped at maxBackoffInterval, so it stops adding an error every cycle
+// without slowing healthy detectors. One success restores its own interval.
+// While the backend itself fails its health check every detector fails, and
+// none backs off: detection must resume as soon as the backend is back.
+func (w *Watcher) DetectorInterval(d detector.Detector) time.Duration {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.detectorIntervalLocked(d)
+}
+
+// detectorIntervalLocked is DetectorInterval. Caller must hold w.mu.
+func (w *Watcher) detectorIntervalLocked(d detector.Detector) time.Duration {
+	base := d.Interval()
+	if base >= maxBackoffInterval || w.backendDownLocked() {
+		return base
+	}
+	interval := base
+	streak := w.detectorFailureStreakLocked(d.Name())
+	for i := backoffAfterFailures; i <= streak && interval < maxBackoffInterval; i++ {
+		interval *= 2
+	}
+	return min(interval, maxBackoffInterval)
+}
+
+// backendDownLocked reports whether the last health check failed on every
+// backend, as opposed to a detector failing on its own. Caller must hold
+// w.mu.
+func (w *Watcher) backendDownLocked() bool {
+	if len(w.tenants) == 0 {
+		return w.failureStreaks["/"+healthCheckSource] > 0
+	}
+	for _, t := range w.tenants {
+		if w.failureStreaks[t.name+"/"+healthCheckSource] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// detectorFailureStreakLocked returns how many runs in a row name has failed
+// on every backend: the shortest streak across tenants, since a detector
+// that still works for one tenant is not broken. Caller must hold w.mu.
+func (w *Watcher) detectorFailureStreakLocked(name string) int {
+	if len(w.tenants) == 0 {
+		return w.failureStreaks["/"+name]
+	}
+	streak := -1
+	for _, t := range w.tenants {
+		if s := w.failureStreaks[t.name+"/"+name]; streak < 0 || s < streak {
+			streak = s
+		}
+	}
+	return streak
+}
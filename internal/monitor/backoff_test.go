@@ -0,0 +1,134 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+)
+
+// failingProvider errors on every query while fail is set
+func failingProvider(fail *bool) *metrics.MockProvider {
+	return &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			if *fail {
+				return nil, errors.New("bad_data: unknown metric")
+			}
+			return nil, nil
+		},
+	}
+}
+
+func TestDetectorInterval_GrowsWhileFailing(t *testing.T) {
+	fail := true
+	d := detector.NewOOMKillDetector()
+	registry := detector.NewRegistry()
+	registry.Register(d)
+	w := NewWatcher(failingProvider(&fail), registry, 0, 30*time.Second)
+	base := d.Interval()
+
+	var intervals []time.Duration
+	for range 12 {
+		w.executeDetector(context.Background(), d)
+		intervals = append(intervals, w.DetectorInterval(d))
+	}
+
+	// Two failures are tolerated, then the interval doubles per failure
+	if intervals[0] != base || intervals[1] != base {
+		t.Errorf("first failures backed off: %v", intervals[:2])
+	}
+	if intervals[2] != 2*base || intervals[3] != 4*base {
+		t.Errorf("intervals after 3 and 4 failures = %s, %s, want %s, %s", intervals[2], intervals[3], 2*base, 4*base)
+	}
+	for i := 1; i < len(intervals); i++ {
+		if intervals[i] < intervals[i-1] {
+			t.Fatalf("interval shrank while failing: %v", intervals)
+		}
+	}
+	if last := intervals[len(intervals)-1]; last != maxBackoffInterval {
+		t.Errorf("interval after %d failures = %s, want the %s cap", len(intervals), last, maxBackoffInterval)
+	}
+	if stats := w.DetectorStats(); stats[0].Interval != maxBackoffInterval {
+		t.Errorf("detector stats interval = %s, want the backed-off %s", stats[0].Interval, maxBackoffInterval)
+	}
+
+	// One success restores the detector's own interval
+	fail = false
+	w.executeDetector(context.Background(), d)
+	if got := w.DetectorInterval(d); got != base {
+		t.Errorf("interval after success = %s, want %s", got, base)
+	}
+}
+
+func TestDetectorInterval_HealthyDetectorUnaffected(t *testing.T) {
+	fail := true
+	w := NewWatcher(failingProvider(&fail), detector.NewRegistry(), 0, 30*time.Second)
+	broken := detector.NewOOMKillDetector()
+	for range 5 {
+		w.executeDetector(context.Background(), broken)
+	}
+
+	healthy := detector.NewDiskSpaceDetector()
+	if got := w.DetectorInterval(healthy); got != healthy.Interval() {
+		t.Errorf("healthy detector interval = %s, want %s", got, healthy.Interval())
+	}
+}
+
+func TestDetectorInterval_TenantStillWorking(t *testing.T) {
+	fail, ok := true, false
+	providers := map[string]metrics.MetricsProvider{
+		"tenant-a": failingProvider(&fail),
+		"tenant-b": failingProvider(&ok),
+	}
+	w := NewWatcher(providers["tenant-a"], detector.NewRegistry(), 0, 30*time.Second, WithTenants(providers))
+	d := detector.NewOOMKillDetector()
+	for range 5 {
+		w.executeDetector(context.Background(), d)
+	}
+	if got := w.DetectorInterval(d); got != d.Interval() {
+		t.Errorf("interval = %s, want %s while one tenant succeeds", got, d.Interval())
+	}
+}
+
+func TestDetectorInterval_NoBackoffWhileBackendDown(t *testing.T) {
+	down := true
+	provider := failingProvider(&down)
+	provider.HealthFunc = func(ctx context.Context) error {
+		if down {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	d := detector.NewOOMKillDetector()
+	registry := detector.NewRegistry()
+	registry.Register(d)
+	clock := newFakeClock()
+	w := NewWatcher(provider, registry, 0, 30*time.Second, WithClock(clock.now), WithUnavailableProblem())
+
+	// A long outage: every run fails, the health check with it
+	for range 12 {
+		w.executeDetector(context.Background(), d)
+		if got := w.DetectorInterval(d); got != d.Interval() {
+			t.Fatalf("interval = %s while the backend is down, want %s", got, d.Interval())
+		}
+		clock.advance(d.Interval())
+	}
+	if problems := w.GetProblems(); len(problems) != 1 || problems[0].Type != UnavailableProblemType {
+		t.Fatalf("expected monitoring_unavailable during the outage, got %v", problems)
+	}
+
+	// Back within one interval: the next run succeeds and clears the outage
+	down = false
+	w.executeDetector(context.Background(), d)
+	if got := w.DetectorInterval(d); got != d.Interval() {
+		t.Errorf("interval after recovery = %s, want %s", got, d.Interval())
+	}
+	if problems := w.GetProblems(); len(problems) != 0 {
+		t.Errorf("monitoring_unavailable should clear on recovery, got %d problems", len(problems))
+	}
+}
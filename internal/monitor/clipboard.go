@@ -2,7 +2,9 @@ package monitor
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 )
@@ -10,6 +12,28 @@ import (
 // copyToClipboard writes text to the system clipboard.
 // Returns a user-facing status message.
 func copyToClipboard(text string) string {
+	if err := writeClipboard(text); err != nil {
+		return err.Error()
+	}
+	return "Copied to clipboard"
+}
+
+// copyOrSave writes text to the system clipboard or, without one (SSH
+// sessions, containers), to name in dir. Returns a user-facing status
+// message.
+func copyOrSave(text, dir, name string) string {
+	clipErr := writeClipboard(text)
+	if clipErr == nil {
+		return "Copied to clipboard"
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+		return fmt.Sprintf("%v; saving to %s failed: %v", clipErr, path, err)
+	}
+	return "No clipboard, saved to " + path
+}
+
+func writeClipboard(text string) error {
 	var cmd *exec.Cmd
 	switch runtime.GOOS {
 	case "darwin":
@@ -17,12 +41,12 @@ func copyToClipboard(text string) string {
 	case "linux":
 		cmd = exec.Command("xclip", "-selection", "clipboard") //nolint:gosec // no user input
 	default:
-		return "clipboard not supported on " + runtime.GOOS
+		return fmt.Errorf("clipboard not supported on %s", runtime.GOOS)
 	}
 
 	cmd.Stdin = strings.NewReader(text)
 	if err := cmd.Run(); err != nil {
-		return fmt.Sprintf("clipboard error: %v", err)
+		return fmt.Errorf("clipboard error: %w", err)
 	}
-	return "Copied to clipboard"
+	return nil
 }
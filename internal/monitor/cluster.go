@@ -0,0 +1,33 @@
+package monitor
+
+import "github.com/ppiankov/infranow/internal/models"
+
+// ClusterLabel is the problem label --cluster-label stamps the cluster name
+// into
+const ClusterLabel = "cluster"
+
+// WithClusterName names the monitored cluster for the TUI header. With label
+// set, every detected problem also gets a ClusterLabel label before its ID is
+// derived, so problems from several clusters stay distinct when merged.
+func WithClusterName(name string, label bool) WatcherOption {
+	return func(w *Watcher) {
+		w.clusterName = name
+		w.clusterLabel = label
+	}
+}
+
+// ClusterName returns the --cluster-name (empty when unset)
+func (w *Watcher) ClusterName() string {
+	return w.clusterName
+}
+
+// labelCluster stamps the cluster name onto p. A cluster label the detector
+// already copied from the series (e.g. a Thanos external label) is kept.
+func labelCluster(p *models.Problem, cluster string) {
+	if p.Labels == nil {
+		p.Labels = make(map[string]string)
+	}
+	if _, ok := p.Labels[ClusterLabel]; !ok {
+		p.Labels[ClusterLabel] = cluster
+	}
+}
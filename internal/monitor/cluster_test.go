@@ -0,0 +1,80 @@
+package monitor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+)
+
+func TestWithClusterName_LabelsProblems(t *testing.T) {
+	provider := oomSamplesAt(time.Now(), time.Now())
+	plain := NewWatcher(provider, detector.NewRegistry(), 0, 30*time.Second)
+	labeled := NewWatcher(provider, detector.NewRegistry(), 0, 30*time.Second, WithClusterName("prod-eu", true))
+
+	want, _ := plain.detect(context.Background(), detector.NewOOMKillDetector(), provider, "")
+	got, ok := labeled.detect(context.Background(), detector.NewOOMKillDetector(), provider, "")
+	if !ok || len(got) != len(want) || len(got) == 0 {
+		t.Fatalf("expected %d problems, got %d (ok=%v)", len(want), len(got), ok)
+	}
+	for i, p := range got {
+		if p.Labels[ClusterLabel] != "prod-eu" {
+			t.Errorf("expected cluster label prod-eu, got %q", p.Labels[ClusterLabel])
+		}
+		if p.ID == want[i].ID {
+			t.Errorf("expected the cluster label to be part of the ID, got %s for both", p.ID)
+		}
+	}
+	if labeled.ClusterName() != "prod-eu" {
+		t.Errorf("ClusterName() = %q", labeled.ClusterName())
+	}
+}
+
+func TestWithClusterName_NoLabelByDefault(t *testing.T) {
+	provider := oomSamplesAt(time.Now(), time.Now())
+	w := NewWatcher(provider, detector.NewRegistry(), 0, 30*time.Second, WithClusterName("prod-eu", false))
+
+	problems, _ := w.detect(context.Background(), detector.NewOOMKillDetector(), provider, "")
+	for _, p := range problems {
+		if _, ok := p.Labels[ClusterLabel]; ok {
+			t.Errorf("expected no cluster label without label stamping, got %q", p.Labels[ClusterLabel])
+		}
+	}
+}
+
+func TestWithClusterName_KeepsSeriesClusterLabel(t *testing.T) {
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{
+				&model.Sample{Metric: model.Metric{"namespace": "prod", "pod": "api", "container": "app"}, Value: 1},
+			}, nil
+		},
+	}
+	w := NewWatcher(provider, detector.NewRegistry(), 0, 30*time.Second, WithClusterName("prod-eu", true))
+	problems, _ := w.detect(context.Background(), detector.NewOOMKillDetector(), provider, "")
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	p.Labels[ClusterLabel] = "from-series"
+	labelCluster(p, "prod-eu")
+	if p.Labels[ClusterLabel] != "from-series" {
+		t.Errorf("expected an existing cluster label to be kept, got %q", p.Labels[ClusterLabel])
+	}
+}
+
+func TestModel_HeaderShowsClusterName(t *testing.T) {
+	for _, height := range []int{30, shortTerminal - 2} {
+		m := newTestModel(120, height)
+		m.watcher.clusterName = "prod-eu"
+		if header := m.renderHeader(); !strings.Contains(header, "[prod-eu]") {
+			t.Errorf("height %d: expected cluster name in header, got:\n%s", height, header)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// CompactText renders one line per problem for grep-friendly logs:
+//
+//	FATAL prod/api/app crashloopbackoff count=12 5m
+//
+// Fields are space-separated; whitespace inside a field is replaced with "_"
+// so every problem stays on exactly one line. No problems renders nothing.
+func CompactText(problems []*models.Problem, now time.Time) string {
+	var b strings.Builder
+	for _, p := range problems {
+		fmt.Fprintf(&b, "%s %s %s count=%d %s\n",
+			compactField(string(p.Severity)),
+			compactField(p.Entity),
+			compactField(p.Type),
+			p.Count,
+			humanAge(now.Sub(p.Since())))
+	}
+	return b.String()
+}
+
+// compactField keeps a value a single space-free token
+func compactField(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.Join(strings.Fields(s), "_")
+}
@@ -0,0 +1,42 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestCompactText_OneLinePerProblem(t *testing.T) {
+	now := time.Now()
+	problems := []*models.Problem{
+		{Severity: models.SeverityFatal, Entity: "prod/api/app", Type: "crashloopbackoff", Count: 12, FirstSeen: now.Add(-5 * time.Minute)},
+		{Severity: models.SeverityWarning, Entity: "odd entity\nname", Type: "disk_space", Count: 1, FirstSeen: now},
+		{Severity: models.SeverityCritical, Entity: "", Type: "", Count: 3, FirstSeen: now.Add(-2 * time.Hour)},
+	}
+
+	got := CompactText(problems, now)
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) != len(problems) {
+		t.Fatalf("got %d lines for %d problems:\n%s", len(lines), len(problems), got)
+	}
+
+	if lines[0] != "FATAL prod/api/app crashloopbackoff count=12 5m" {
+		t.Errorf("line 0 = %q", lines[0])
+	}
+	if lines[1] != "WARNING odd_entity_name disk_space count=1 0s" {
+		t.Errorf("line 1 = %q", lines[1])
+	}
+	for i, line := range lines {
+		if n := len(strings.Fields(line)); n != 5 {
+			t.Errorf("line %d has %d fields, want 5: %q", i, n, line)
+		}
+	}
+}
+
+func TestCompactText_Empty(t *testing.T) {
+	if got := CompactText(nil, time.Now()); got != "" {
+		t.Errorf("CompactText(nil) = %q, want empty", got)
+	}
+}
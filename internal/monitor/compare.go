@@ -0,0 +1,44 @@
+package monitor
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// baselineTimeLayout formats the compared baseline's capture time
+const baselineTimeLayout = "2006-01-02 15:04"
+
+// baselineSummary is the header text while comparing to a baseline
+func (m Model) baselineSummary() string {
+	var added, resolved int
+	for _, change := range m.markChanges {
+		switch change {
+		case changeNew:
+			added++
+		case changeResolved:
+			resolved++
+		}
+	}
+	return fmt.Sprintf("vs baseline %s: %d new, %d resolved",
+		models.DisplayTime(m.compareTo.Timestamp).Format(baselineTimeLayout), added, resolved)
+}
+
+// baselineDetail renders the detail panel line saying how p differs from the
+// compared baseline, in green for new problems
+func (m Model) baselineDetail(p *models.Problem) string {
+	text, color := "  Unchanged since baseline", "8"
+	if change, ok := m.markChanges[p.ID]; ok {
+		switch change {
+		case changeNew:
+			text, color = "  New since baseline", "10"
+		case changeEscalated:
+			text, color = "  Escalated since baseline", "214"
+		case changeResolved:
+			text = "  Resolved since baseline"
+		}
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(color != "8").Render(text)
+}
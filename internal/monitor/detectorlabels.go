@@ -0,0 +1,20 @@
+package monitor
+
+// WithDetectorLabels adds static labels to every problem a detector reports,
+// keyed by detector name (e.g. category=capacity on the disk detectors), for
+// routing and filtering downstream. They are added after the problem ID is
+// derived, so tagging a detector never changes its problems' identity.
+func WithDetectorLabels(labels map[string]map[string]string) WatcherOption {
+	return func(w *Watcher) {
+		w.detectorLabels = labels
+	}
+}
+
+// SetDetectorLabels replaces the per-detector static labels (config
+// hot-reload). Problems already tracked keep their labels; new ones get the
+// new set.
+func (w *Watcher) SetDetectorLabels(labels map[string]map[string]string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.detectorLabels = labels
+}
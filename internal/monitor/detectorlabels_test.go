@@ -0,0 +1,45 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/detector"
+)
+
+func TestWatcher_DetectorLabels(t *testing.T) {
+	labels := map[string]map[string]string{
+		"generic_disk_space": {"category": "capacity", "node": "static-node"},
+	}
+	w := NewWatcher(diskProvider("node-1:9100"), detector.NewRegistry(), 0, 30*time.Second, WithDetectorLabels(labels))
+	plain := NewWatcher(diskProvider("node-1:9100"), detector.NewRegistry(), 0, 30*time.Second)
+
+	w.executeDetector(context.Background(), detector.NewDiskSpaceDetector())
+	plain.executeDetector(context.Background(), detector.NewDiskSpaceDetector())
+	problems, untagged := w.GetProblems(), plain.GetProblems()
+	if len(problems) != 1 || len(untagged) != 1 {
+		t.Fatalf("expected 1 problem each, got %d and %d", len(problems), len(untagged))
+	}
+
+	p := problems[0]
+	if p.Labels["category"] != "capacity" {
+		t.Errorf("category = %q, want the static label", p.Labels["category"])
+	}
+	// Dynamic labels stay and win over a static label of the same name
+	if p.Labels["mountpoint"] != "/var" || p.Labels["node"] != untagged[0].Labels["node"] {
+		t.Errorf("dynamic labels changed: %v", p.Labels)
+	}
+	if p.ID != untagged[0].ID {
+		t.Errorf("static labels changed the problem ID: %s vs %s", p.ID, untagged[0].ID)
+	}
+
+	// Other detectors' problems are not tagged
+	w.provider = oomProvider("api-0")
+	w.executeDetector(context.Background(), detector.NewOOMKillDetector())
+	for _, p := range w.GetProblems() {
+		if p.Type == "oom_kill" && p.Labels["category"] != "" {
+			t.Errorf("oom_kill problem tagged with %q", p.Labels["category"])
+		}
+	}
+}
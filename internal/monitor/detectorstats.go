@@ -0,0 +1,93 @@
+package monitor
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+)
+
+// DetectorStat is the outcome of a detector's most recent run against one
+// backend
+type DetectorStat struct {
+	Detector    string
+	Tenant      string        // Empty in single-provider mode
+	Interval    time.Duration // How often the detector runs, backoff included
+	Window      time.Duration // Range its queries look back over
+	LastRun     time.Time     // Zero until the detector has run
+	LastSuccess time.Time     // Zero until a run succeeded
+	Series      int           // Series its queries returned in the last run
+	Problems    int           // Problems it reported in the last run
+	Err         string        // Error of the last run, empty on success
+}
+
+// countingProvider counts the series a detector's queries return
+type countingProvider struct {
+	metrics.MetricsProvider
+	series atomic.Int64
+}
+
+func (p *countingProvider) QueryInstant(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+	result, err := p.MetricsProvider.QueryInstant(ctx, query, ts)
+	p.series.Add(int64(len(result)))
+	return result, err
+}
+
+func (p *countingProvider) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (model.Matrix, error) {
+	result, err := p.MetricsProvider.QueryRange(ctx, query, start, end, step)
+	p.series.Add(int64(len(result)))
+	return result, err
+}
+
+// recordDetectorStatLocked stores the outcome of one detector run. Caller
+// must hold w.mu.
+func (w *Watcher) recordDetectorStatLocked(tenant, name string, series, problems int, err error) {
+	key := tenant + "/" + name
+	stat, ok := w.detectorStats[key]
+	if !ok {
+		stat = &DetectorStat{Detector: name, Tenant: tenant}
+		w.detectorStats[key] = stat
+	}
+	stat.LastRun = w.now()
+	stat.Series = series
+	stat.Problems = problems
+	stat.Err = ""
+	if err != nil {
+		stat.Err = err.Error()
+		return
+	}
+	stat.LastSuccess = stat.LastRun
+}
+
+// DetectorStats returns the schedule and last run of every scheduled
+// detector (per tenant in multi-tenant mode), sorted by detector name.
+// Detectors that have not run yet are included with a zero LastRun.
+func (w *Watcher) DetectorStats() []DetectorStat {
+	detectors := w.currentRegistry().All()
+	sort.Slice(detectors, func(i, j int) bool { return detectors[i].Name() < detectors[j].Name() })
+	tenants := w.Tenants()
+	if tenants == nil {
+		tenants = []string{""}
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	stats := make([]DetectorStat, 0, len(detectors)*len(tenants))
+	for _, d := range detectors {
+		for _, tenant := range tenants {
+			stat := DetectorStat{Detector: d.Name(), Tenant: tenant}
+			if recorded, ok := w.detectorStats[tenant+"/"+d.Name()]; ok {
+				stat = *recorded
+			}
+			stat.Interval = w.detectorIntervalLocked(d)
+			stat.Window = detector.EffectiveWindow(d, w.window, w.windows)
+			stats = append(stats, stat)
+		}
+	}
+	return stats
+}
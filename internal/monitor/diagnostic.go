@@ -0,0 +1,80 @@
+package monitor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Diagnostic renders what the watcher is actually seeing, for when an
+// all-clear looks too good to be true: backend health, and per detector
+// when it last ran, how many series its queries returned and any error.
+// Failing detectors are listed first, then ones that never ran. No ANSI
+// colors, so it can be shown in the TUI and in logs alike.
+func Diagnostic(prom PrometheusStats, detectors []DetectorStat, now time.Time) string {
+	var b strings.Builder
+
+	health := "healthy"
+	if !prom.Healthy {
+		health = "DOWN"
+	}
+	lastQuery := "never"
+	if !prom.LastSuccessfulQuery.IsZero() {
+		lastQuery = humanAge(now.Sub(prom.LastSuccessfulQuery)) + " ago"
+	}
+	fmt.Fprintf(&b, "Prometheus: %s | last successful query: %s | %d queries, %d errors\n",
+		health, lastQuery, prom.QueryCount, prom.ErrorCount)
+
+	var ran, failing, series int
+	for _, d := range detectors {
+		if !d.LastRun.IsZero() {
+			ran++
+		}
+		if d.Err != "" {
+			failing++
+		}
+		series += d.Series
+	}
+	fmt.Fprintf(&b, "Detectors: %d registered, %d ran, %d failing, %d series returned last cycle\n\n",
+		len(detectors), ran, failing, series)
+
+	rows := slices.Clone(detectors)
+	slices.SortStableFunc(rows, func(a, b DetectorStat) int {
+		return diagnosticRank(a) - diagnosticRank(b)
+	})
+
+	fmt.Fprintf(&b, "%-40s %-10s %-7s %-9s %s\n", "DETECTOR", "LAST RUN", "SERIES", "PROBLEMS", "STATUS")
+	for _, d := range rows {
+		name := d.Detector
+		if d.Tenant != "" {
+			name = d.Tenant + ":" + name
+		}
+		if d.LastRun.IsZero() {
+			fmt.Fprintf(&b, "%-40s %-10s %-7s %-9s %s\n", name, "never", "-", "-", "pending")
+			continue
+		}
+		status := "ok"
+		if d.Err != "" {
+			status = "error: " + d.Err
+		}
+		fmt.Fprintf(&b, "%-40s %-10s %-7d %-9d %s\n",
+			name, humanAge(now.Sub(d.LastRun))+" ago", d.Series, d.Problems, status)
+	}
+
+	b.WriteString("\nMost queries only return series over a threshold, so 0 series is normal while healthy. ")
+	b.WriteString("Errors, \"never\" and an old last successful query are not.")
+	return b.String()
+}
+
+// diagnosticRank orders failing detectors before pending ones before the rest
+func diagnosticRank(d DetectorStat) int {
+	switch {
+	case d.Err != "":
+		return 0
+	case d.LastRun.IsZero():
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+)
+
+// diagnosticWatcher has three detectors: OOM kills ran and saw two series,
+// disk space failed, crashloop never ran
+func diagnosticWatcher(t *testing.T) (*Watcher, *fakeClock) {
+	t.Helper()
+	clock := newFakeClock()
+	registry := detector.NewRegistry()
+	oom := detector.NewOOMKillDetector()
+	disk := detector.NewDiskSpaceDetector()
+	registry.Register(oom)
+	registry.Register(disk)
+	registry.Register(detector.NewCrashLoopBackOffDetector())
+
+	ok := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{
+				&model.Sample{Metric: model.Metric{"namespace": "prod", "pod": "api-0", "container": "app"}, Value: 1},
+				&model.Sample{Metric: model.Metric{"namespace": "prod", "pod": "api-1", "container": "app"}, Value: 1},
+			}, nil
+		},
+	}
+	failing := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	w := NewWatcher(ok, registry, 0, time.Second, WithClock(clock.now))
+	w.detect(context.Background(), oom, ok, "")
+	clock.advance(30 * time.Second)
+	w.detect(context.Background(), disk, failing, "")
+	clock.advance(10 * time.Second)
+	return w, clock
+}
+
+func TestWatcher_DetectorStats(t *testing.T) {
+	w, clock := diagnosticWatcher(t)
+
+	stats := w.DetectorStats()
+	if len(stats) != 3 {
+		t.Fatalf("got %d stats, want one per registered detector", len(stats))
+	}
+	byName := make(map[string]DetectorStat)
+	for _, s := range stats {
+		byName[s.Detector] = s
+	}
+
+	oom := byName["kubernetes_oom_kills"]
+	if oom.Series != 2 || oom.Problems != 2 || oom.Err != "" {
+		t.Errorf("oom stat = %+v, want 2 series, 2 problems, no error", oom)
+	}
+	if oom.Interval != 30*time.Second || oom.Window != detector.DefaultWindow {
+		t.Errorf("oom stat interval %s, window %s, want 30s and the default window", oom.Interval, oom.Window)
+	}
+	if !oom.LastSuccess.Equal(clock.now().Add(-40 * time.Second)) {
+		t.Errorf("oom last success = %s", oom.LastSuccess)
+	}
+
+	disk := byName["generic_disk_space"]
+	if !strings.Contains(disk.Err, "connection refused") || disk.LastRun.IsZero() || !disk.LastSuccess.IsZero() {
+		t.Errorf("disk stat = %+v, want a failed run", disk)
+	}
+	if crash := byName["kubernetes_crashloop"]; !crash.LastRun.IsZero() {
+		t.Errorf("crashloop stat = %+v, want never run", crash)
+	}
+}
+
+func TestDiagnostic_RendersWatcherStats(t *testing.T) {
+	w, clock := diagnosticWatcher(t)
+
+	out := Diagnostic(w.GetPrometheusStats(), w.DetectorStats(), clock.now())
+	for _, want := range []string{
+		"last successful query: 40s ago | 2 queries, 1 errors",
+		"Detectors: 3 registered, 2 ran, 1 failing, 2 series returned last cycle",
+		"kubernetes_oom_kills",
+		"40s ago",
+		"kubernetes_crashloop",
+		"pending",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("diagnostic missing %q:\n%s", want, out)
+		}
+	}
+
+	// Failing, then never run, then healthy
+	disk := strings.Index(out, "generic_disk_space")
+	crash := strings.Index(out, "kubernetes_crashloop")
+	oom := strings.Index(out, "kubernetes_oom_kills")
+	if disk >= crash || crash >= oom {
+		t.Errorf("expected failing and pending detectors first:\n%s", out)
+	}
+	if !strings.Contains(out, "error: ") || !strings.Contains(out, "connection refused") {
+		t.Errorf("diagnostic does not show the detector error:\n%s", out)
+	}
+}
+
+func TestModel_DiagnosticToggle(t *testing.T) {
+	m := newTestModel(120, 40)
+	if !strings.Contains(m.View(), "d: why no problems?") {
+		t.Error("empty state should point to the diagnostic")
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	m = updated.(Model)
+	view := m.View()
+	if !strings.Contains(view, "Detectors: 0 registered") || !strings.Contains(view, "d/esc: close") {
+		t.Errorf("expected the diagnostic view:\n%s", view)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if strings.Contains(m.View(), "Detectors:") {
+		t.Error("esc should close the diagnostic")
+	}
+}
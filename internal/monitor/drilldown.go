@@ -0,0 +1,78 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// drillLabels are the co-location labels a drill-down can scope by, most
+// specific first: a node problem drills to the node, a pod problem to its
+// namespace.
+var drillLabels = []string{"node", "namespace"}
+
+// drillScope restricts the problem list to problems sharing one label value
+type drillScope struct {
+	label string
+	value string
+}
+
+func (s drillScope) String() string {
+	return fmt.Sprintf("%s=%s", s.label, s.value)
+}
+
+// drillScopeFor returns the next scope to drill into from p, skipping labels
+// the stack already scopes by. ok is false when nothing is left to drill into.
+func drillScopeFor(p *models.Problem, stack []drillScope) (drillScope, bool) {
+	for _, label := range drillLabels {
+		v := p.Labels[label]
+		if v == "" || scopedBy(stack, label) {
+			continue
+		}
+		return drillScope{label: label, value: v}, true
+	}
+	return drillScope{}, false
+}
+
+func scopedBy(stack []drillScope, label string) bool {
+	for _, s := range stack {
+		if s.label == label {
+			return true
+		}
+	}
+	return false
+}
+
+// drillDownFilter returns the problems matching every scope in the stack
+func drillDownFilter(problems []*models.Problem, stack []drillScope) []*models.Problem {
+	if len(stack) == 0 {
+		return problems
+	}
+	filtered := make([]*models.Problem, 0, len(problems))
+	for _, p := range problems {
+		if inScopes(p, stack) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+func inScopes(p *models.Problem, stack []drillScope) bool {
+	for _, s := range stack {
+		if p.Labels[s.label] != s.value {
+			return false
+		}
+	}
+	return true
+}
+
+// breadcrumb renders the drill-down path, e.g. "all > node=worker-1"
+func breadcrumb(stack []drillScope) string {
+	parts := make([]string, 0, len(stack)+1)
+	parts = append(parts, "all")
+	for _, s := range stack {
+		parts = append(parts, s.String())
+	}
+	return strings.Join(parts, " > ")
+}
@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func drillTestProblems() []*models.Problem {
+	return []*models.Problem{
+		{ID: "node-pressure", Labels: map[string]string{"node": "worker-1"}},
+		{ID: "pod-a", Labels: map[string]string{"node": "worker-1", "namespace": "prod", "pod": "a"}},
+		{ID: "pod-b", Labels: map[string]string{"node": "worker-1", "namespace": "staging", "pod": "b"}},
+		{ID: "pod-c", Labels: map[string]string{"node": "worker-2", "namespace": "prod", "pod": "c"}},
+		{ID: "disk", Labels: map[string]string{"instance": "db-1:9100"}},
+	}
+}
+
+func problemIDs(problems []*models.Problem) []string {
+	ids := make([]string, len(problems))
+	for i, p := range problems {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+func TestDrillDownFilter_CoLocated(t *testing.T) {
+	problems := drillTestProblems()
+
+	scope, ok := drillScopeFor(problems[0], nil)
+	if !ok || scope != (drillScope{label: "node", value: "worker-1"}) {
+		t.Fatalf("drillScopeFor(node problem) = %v, %v; want node=worker-1", scope, ok)
+	}
+
+	got := problemIDs(drillDownFilter(problems, []drillScope{scope}))
+	want := []string{"node-pressure", "pod-a", "pod-b"}
+	if len(got) != len(want) {
+		t.Fatalf("drill node=worker-1 = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("drill node=worker-1 = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestDrillDownFilter_Nested(t *testing.T) {
+	problems := drillTestProblems()
+	stack := []drillScope{{label: "node", value: "worker-1"}}
+
+	// From a pod already scoped by node, the next level is its namespace
+	scope, ok := drillScopeFor(problems[1], stack)
+	if !ok || scope != (drillScope{label: "namespace", value: "prod"}) {
+		t.Fatalf("drillScopeFor(pod, node scoped) = %v, %v; want namespace=prod", scope, ok)
+	}
+	stack = append(stack, scope)
+
+	got := problemIDs(drillDownFilter(problems, stack))
+	if len(got) != 1 || got[0] != "pod-a" {
+		t.Errorf("drill node=worker-1 > namespace=prod = %v, want [pod-a]", got)
+	}
+	if bc := breadcrumb(stack); bc != "all > node=worker-1 > namespace=prod" {
+		t.Errorf("breadcrumb = %q", bc)
+	}
+}
+
+func TestDrillScopeFor_NoLabels(t *testing.T) {
+	problems := drillTestProblems()
+	if _, ok := drillScopeFor(problems[4], nil); ok {
+		t.Error("problem without node/namespace labels should not be drillable")
+	}
+	if got := drillDownFilter(problems, nil); len(got) != len(problems) {
+		t.Errorf("empty stack should keep all problems, got %d", len(got))
+	}
+}
@@ -0,0 +1,120 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// DefaultEvalStaleAfter is how far a sample's timestamp may lag the
+// detection before the problem is flagged as built on stale data
+const DefaultEvalStaleAfter = 2 * time.Minute
+
+// WithEvalTime annotates every detected problem with the evaluation time of
+// the samples it was built from and flags it StaleSample when that lags the
+// detection by more than staleAfter (0 = disabled). Scrape lag or a lagging
+// query frontend otherwise looks like a current problem.
+func WithEvalTime(staleAfter time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		w.evalStaleAfter = staleAfter
+	}
+}
+
+// evalSample is the label set, timestamp and query of one returned series
+type evalSample struct {
+	metric model.Metric
+	at     time.Time
+	query  string
+}
+
+// evalRecorder remembers the queries and returned samples of one detection
+// cycle
+type evalRecorder struct {
+	metrics.MetricsProvider
+
+	mu      sync.Mutex
+	queries []string
+	samples []evalSample
+}
+
+func (r *evalRecorder) QueryInstant(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+	result, err := r.MetricsProvider.QueryInstant(ctx, query, ts)
+	if err == nil {
+		r.mu.Lock()
+		r.queries = append(r.queries, query)
+		for _, s := range result {
+			r.samples = append(r.samples, evalSample{metric: s.Metric, at: s.Timestamp.Time(), query: query})
+		}
+		r.mu.Unlock()
+	}
+	return result, err
+}
+
+func (r *evalRecorder) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (model.Matrix, error) {
+	result, err := r.MetricsProvider.QueryRange(ctx, query, start, end, step)
+	if err == nil {
+		r.mu.Lock()
+		r.queries = append(r.queries, query)
+		for _, s := range result {
+			if len(s.Values) > 0 {
+				r.samples = append(r.samples, evalSample{metric: s.Metric, at: s.Values[len(s.Values)-1].Timestamp.Time(), query: query})
+			}
+		}
+		r.mu.Unlock()
+	}
+	return result, err
+}
+
+// annotate sets EvaluatedAt on each problem to the oldest timestamp of the
+// samples matching its labels (of all samples if none match) and flags
+// StaleSample when that is more than staleAfter before now
+func (r *evalRecorder) annotate(problems []*models.Problem, now time.Time, staleAfter time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var oldest time.Time
+	for _, s := range r.samples {
+		if oldest.IsZero() || s.at.Before(oldest) {
+			oldest = s.at
+		}
+	}
+
+	for _, p := range problems {
+		at := time.Time{}
+		for _, s := range r.samples {
+			if matchesLabels(s.metric, p.Labels) && (at.IsZero() || s.at.Before(at)) {
+				at = s.at
+			}
+		}
+		if at.IsZero() {
+			at = oldest
+		}
+		if at.IsZero() {
+			continue
+		}
+		p.EvaluatedAt = at
+		p.StaleSample = now.Sub(at) > staleAfter
+	}
+}
+
+// matchesLabels reports whether metric agrees with every problem label it
+// carries and shares at least one of them
+func matchesLabels(metric model.Metric, labels map[string]string) bool {
+	shared := 0
+	for k, v := range labels {
+		mv, ok := metric[model.LabelName(k)]
+		if !ok {
+			continue
+		}
+		if string(mv) != v {
+			return false
+		}
+		shared++
+	}
+	return shared > 0
+}
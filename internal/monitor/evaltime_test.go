@@ -0,0 +1,69 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+)
+
+// oomSamplesAt reports OOM kills for two pods, evaluated at the given times
+func oomSamplesAt(fresh, old time.Time) *metrics.MockProvider {
+	return &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{
+				&model.Sample{
+					Metric:    model.Metric{"namespace": "prod", "pod": "api", "container": "app"},
+					Value:     1,
+					Timestamp: model.TimeFromUnixNano(fresh.UnixNano()),
+				},
+				&model.Sample{
+					Metric:    model.Metric{"namespace": "prod", "pod": "worker", "container": "app"},
+					Value:     1,
+					Timestamp: model.TimeFromUnixNano(old.UnixNano()),
+				},
+			}, nil
+		},
+	}
+}
+
+func TestWithEvalTime_AnnotatesAndFlagsStale(t *testing.T) {
+	now := time.Now()
+	fresh, old := now.Add(-5*time.Second), now.Add(-10*time.Minute)
+	provider := oomSamplesAt(fresh, old)
+	w := NewWatcher(provider, detector.NewRegistry(), 0, 30*time.Second, WithEvalTime(DefaultEvalStaleAfter))
+
+	problems, ok := w.detect(context.Background(), detector.NewOOMKillDetector(), provider, "")
+	if !ok || len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %d (ok=%v)", len(problems), ok)
+	}
+	for _, p := range problems {
+		switch p.Labels["pod"] {
+		case "api":
+			if !p.EvaluatedAt.Equal(fresh.Truncate(time.Millisecond)) || p.StaleSample {
+				t.Errorf("api: evaluated %v stale %v, want %v and fresh", p.EvaluatedAt, p.StaleSample, fresh)
+			}
+		case "worker":
+			if !p.EvaluatedAt.Equal(old.Truncate(time.Millisecond)) || !p.StaleSample {
+				t.Errorf("worker: evaluated %v stale %v, want %v and stale", p.EvaluatedAt, p.StaleSample, old)
+			}
+		}
+	}
+}
+
+func TestWithEvalTime_DisabledByDefault(t *testing.T) {
+	now := time.Now()
+	provider := oomSamplesAt(now, now.Add(-time.Hour))
+	w := NewWatcher(provider, detector.NewRegistry(), 0, 30*time.Second)
+
+	problems, _ := w.detect(context.Background(), detector.NewOOMKillDetector(), provider, "")
+	for _, p := range problems {
+		if !p.EvaluatedAt.IsZero() || p.StaleSample {
+			t.Errorf("%s annotated without WithEvalTime", p.Entity)
+		}
+	}
+}
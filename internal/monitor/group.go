@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// ProblemGroup is the problems sharing one value of a grouping label
+type ProblemGroup struct {
+	Name     string // Label value, "" for problems without the label
+	Problems []*models.Problem
+}
+
+// GroupByLabel splits problems by the value of label, keeping their order
+// within each group. Groups come in the order of their first problem, so the
+// group of the top-ranked problem leads; problems without the label form the
+// last group.
+func GroupByLabel(problems []*models.Problem, label string) []ProblemGroup {
+	index := make(map[string]int)
+	var groups []ProblemGroup
+	var unlabeled []*models.Problem
+	for _, p := range problems {
+		name := p.Labels[label]
+		if name == "" {
+			unlabeled = append(unlabeled, p)
+			continue
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, ProblemGroup{Name: name})
+		}
+		groups[i].Problems = append(groups[i].Problems, p)
+	}
+	if len(unlabeled) > 0 {
+		groups = append(groups, ProblemGroup{Problems: unlabeled})
+	}
+	return groups
+}
+
+// Grouped wraps a text renderer (PlainText, CompactText) to render the
+// problems of each label value under its own header line
+func Grouped(render func([]*models.Problem, time.Time) string, label string) func([]*models.Problem, time.Time) string {
+	return func(problems []*models.Problem, now time.Time) string {
+		if len(problems) == 0 {
+			return render(problems, now)
+		}
+
+		var b strings.Builder
+		for i, g := range GroupByLabel(problems, label) {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			name := label + " " + g.Name
+			if g.Name == "" {
+				name = "no " + label
+			}
+			fmt.Fprintf(&b, "=== %s (%d problems) ===\n", name, len(g.Problems))
+			b.WriteString(render(g.Problems, now))
+		}
+		return b.String()
+	}
+}
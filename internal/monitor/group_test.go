@@ -0,0 +1,55 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestGroupByLabel(t *testing.T) {
+	problems := []*models.Problem{
+		{Entity: "prod/checkout-1", Labels: map[string]string{"service": "checkout"}},
+		{Entity: "node-1", Labels: map[string]string{"node": "node-1"}},
+		{Entity: "prod/payments-1", Labels: map[string]string{"service": "payments"}},
+		{Entity: "prod/checkout-2", Labels: map[string]string{"service": "checkout"}},
+	}
+
+	groups := GroupByLabel(problems, "service")
+
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(groups))
+	}
+	if groups[0].Name != "checkout" || len(groups[0].Problems) != 2 || groups[0].Problems[1].Entity != "prod/checkout-2" {
+		t.Errorf("first group = %s %d problems", groups[0].Name, len(groups[0].Problems))
+	}
+	if groups[1].Name != "payments" {
+		t.Errorf("second group = %q, want payments", groups[1].Name)
+	}
+	if groups[2].Name != "" || groups[2].Problems[0].Entity != "node-1" {
+		t.Errorf("problems without the label should come last, got %q", groups[2].Name)
+	}
+}
+
+func TestGrouped_RendersHeaderPerGroup(t *testing.T) {
+	now := time.Now()
+	problems := []*models.Problem{
+		{Entity: "prod/checkout-1", Type: "oom_kill", Severity: models.SeverityCritical, FirstSeen: now, Count: 1, Labels: map[string]string{"service": "checkout"}},
+		{Entity: "node-1", Type: "disk_full", Severity: models.SeverityWarning, FirstSeen: now, Count: 1},
+	}
+
+	out := Grouped(CompactText, "service")(problems, now)
+
+	want := "=== service checkout (1 problems) ===\n" +
+		"CRITICAL prod/checkout-1 oom_kill count=1 0s\n" +
+		"\n" +
+		"=== no service (1 problems) ===\n" +
+		"WARNING node-1 disk_full count=1 0s\n"
+	if out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+	if empty := Grouped(PlainText, "service")(nil, now); !strings.Contains(empty, noProblemsMessage) {
+		t.Errorf("no problems rendered %q", empty)
+	}
+}
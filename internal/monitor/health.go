@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"net/http"
+)
+
+// healthCheckSource keys periodic backend health checks in failureStreaks,
+// next to detector names
+const healthCheckSource = "health"
+
+// WithDownAfterFailures reports the backend down only after a detector or
+// the periodic health check fails n times in a row, so a single transient
+// error does not flip the TUI to DOWN. n < 1 is treated as 1 (immediately).
+func WithDownAfterFailures(n int) WatcherOption {
+	return func(w *Watcher) {
+		w.downAfterFailures = max(n, 1)
+	}
+}
+
+// recordHealthLocked counts a success or failure from source (a detector
+// name or healthCheckSource) and updates backend health. Any success marks
+// the backend healthy; a failure marks it down once the source's streak
+// reaches downAfterFailures. Caller must hold w.mu.
+func (w *Watcher) recordHealthLocked(tenant, source string, ok bool) {
+	key := tenant + "/" + source
+	if ok {
+		delete(w.failureStreaks, key)
+		w.setHealthLocked(tenant, true)
+		return
+	}
+	w.failureStreaks[key]++
+	if w.failureStreaks[key] >= w.downAfterFailures {
+		w.setHealthLocked(tenant, false)
+	}
+}
+
+// Ready reports whether the watcher is ready to serve traffic: the backend
+// answers health checks and at least one detector query has succeeded.
+// reason explains a false result.
+func (w *Watcher) Ready() (ready bool, reason string) {
+	healthy, _ := w.GetPrometheusHealth()
+	switch {
+	case !healthy:
+		return false, "prometheus unreachable"
+	case !w.HasData():
+		return false, "no detection cycle completed yet"
+	default:
+		return true, ""
+	}
+}
+
+// HealthHandler serves Kubernetes probes: /livez answers 200 while the
+// process is up, /readyz answers 200 once Ready and 503 with the reason
+// otherwise. Nothing is queried per request.
+func HealthHandler(w *Watcher) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/livez", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = rw.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("/readyz", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if ready, reason := w.Ready(); !ready {
+			http.Error(rw, reason, http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = rw.Write([]byte("ok\n"))
+	})
+	return mux
+}
@@ -0,0 +1,142 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+)
+
+func probe(t *testing.T, handler http.Handler, path string) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code, rec.Body.String()
+}
+
+func TestHealthHandler_NotReadyBeforeFirstCycle(t *testing.T) {
+	handler := HealthHandler(newTestWatcher(0))
+
+	if code, _ := probe(t, handler, "/livez"); code != http.StatusOK {
+		t.Errorf("/livez = %d, want 200", code)
+	}
+	code, body := probe(t, handler, "/readyz")
+	if code != http.StatusServiceUnavailable || !strings.Contains(body, "no detection cycle") {
+		t.Errorf("/readyz = %d %q, want 503 before the first detection", code, body)
+	}
+}
+
+func TestHealthHandler_ReadyAfterDetection(t *testing.T) {
+	w := newTestWatcher(0)
+	w.executeDetector(context.Background(), detector.NewOOMKillDetector())
+
+	if code, body := probe(t, HealthHandler(w), "/readyz"); code != http.StatusOK {
+		t.Errorf("/readyz = %d %q, want 200", code, body)
+	}
+}
+
+func TestHealthHandler_NotReadyWhenPrometheusDown(t *testing.T) {
+	w := newTestWatcher(0)
+	w.executeDetector(context.Background(), detector.NewOOMKillDetector())
+
+	// The backend stops answering health checks
+	w.provider = &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, errors.New("connection refused")
+		},
+		HealthFunc: func(ctx context.Context) error { return errors.New("connection refused") },
+	}
+	w.mu.Lock()
+	w.lastPrometheusCheck = time.Time{} // Force the next health check
+	w.mu.Unlock()
+	w.executeDetector(context.Background(), detector.NewOOMKillDetector())
+
+	handler := HealthHandler(w)
+	code, body := probe(t, handler, "/readyz")
+	if code != http.StatusServiceUnavailable || !strings.Contains(body, "prometheus unreachable") {
+		t.Errorf("/readyz = %d %q, want 503 prometheus unreachable", code, body)
+	}
+	if code, _ := probe(t, handler, "/livez"); code != http.StatusOK {
+		t.Errorf("/livez = %d, want 200 while the process runs", code)
+	}
+}
+
+func TestDownAfterFailures_GraceBeforeDown(t *testing.T) {
+	fail := true
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			if fail {
+				return nil, errors.New("connection refused")
+			}
+			return model.Vector{}, nil
+		},
+	}
+	w := NewWatcher(provider, detector.NewRegistry(), 0, 30*time.Second, WithDownAfterFailures(3))
+	m := NewModel(w, "http://localhost:9090", 2*time.Second, nil, WithFixedWidth(120))
+	d := detector.NewOOMKillDetector()
+
+	for i := 1; i <= 2; i++ {
+		w.detect(context.Background(), d, provider, "")
+		if healthy, _ := w.GetPrometheusHealth(); !healthy {
+			t.Fatalf("down after %d failures, want grace until 3", i)
+		}
+		if strings.Contains(m.View(), "Prometheus DOWN") {
+			t.Fatalf("header shows DOWN after %d failures", i)
+		}
+	}
+
+	w.detect(context.Background(), d, provider, "")
+	if healthy, _ := w.GetPrometheusHealth(); healthy {
+		t.Fatal("expected down after 3 consecutive failures")
+	}
+	if !strings.Contains(m.View(), "Prometheus DOWN") {
+		t.Error("header should show DOWN after 3 consecutive failures")
+	}
+
+	// A success recovers and restarts the streak
+	fail = false
+	w.detect(context.Background(), d, provider, "")
+	fail = true
+	w.detect(context.Background(), d, provider, "")
+	if healthy, _ := w.GetPrometheusHealth(); !healthy {
+		t.Error("one failure after recovery should not mark the backend down")
+	}
+}
+
+func TestDownAfterFailures_CountsPerSource(t *testing.T) {
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	w := NewWatcher(provider, detector.NewRegistry(), 0, 30*time.Second, WithDownAfterFailures(2))
+
+	// One cycle of a blip fails every detector once
+	w.detect(context.Background(), detector.NewOOMKillDetector(), provider, "")
+	w.detect(context.Background(), detector.NewCrashLoopBackOffDetector(), provider, "")
+	if healthy, _ := w.GetPrometheusHealth(); !healthy {
+		t.Error("one failure per detector should stay within the grace")
+	}
+}
+
+func TestDownAfterFailures_DefaultIsImmediate(t *testing.T) {
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	w := NewWatcher(provider, detector.NewRegistry(), 0, 30*time.Second, WithDownAfterFailures(0))
+
+	w.detect(context.Background(), detector.NewOOMKillDetector(), provider, "")
+	if healthy, _ := w.GetPrometheusHealth(); healthy {
+		t.Error("expected down on the first failure without a grace")
+	}
+}
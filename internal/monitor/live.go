@@ -0,0 +1,58 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// ANSI sequences for in-place redraws: cursor up N lines, then carriage
+// return and clear to the end of the screen
+const (
+	cursorUpFormat = "\x1b[%dA"
+	clearBelow     = "\r\x1b[J"
+)
+
+// LiveTable reprints a rendered problem table on every update without the
+// alternate screen. In place, each frame overwrites the previous one using
+// cursor moves, so the terminal scrollback and screen recordings stay
+// readable; otherwise frames are appended one after another.
+type LiveTable struct {
+	out     io.Writer
+	render  func([]*models.Problem, time.Time) string
+	inPlace bool
+	lines   int // Lines written by the previous frame
+}
+
+// NewLiveTable creates a LiveTable that renders frames with render (e.g.
+// PlainText) to out
+func NewLiveTable(out io.Writer, render func([]*models.Problem, time.Time) string, inPlace bool) *LiveTable {
+	return &LiveTable{out: out, render: render, inPlace: inPlace}
+}
+
+// Update draws a new frame for problems, replacing the previous one
+func (l *LiveTable) Update(problems []*models.Problem, now time.Time) error {
+	var b strings.Builder
+	if l.inPlace && l.lines > 0 {
+		fmt.Fprintf(&b, cursorUpFormat, l.lines)
+		b.WriteString(clearBelow)
+	} else if l.lines > 0 {
+		b.WriteString("\n")
+	}
+
+	frame := fmt.Sprintf("infranow %s  %s\n\n", models.DisplayTime(now).Format(time.TimeOnly), PlainTextSummary(problems))
+	frame += l.render(problems, now)
+	if !strings.HasSuffix(frame, "\n") {
+		frame += "\n"
+	}
+	b.WriteString(frame)
+
+	if _, err := io.WriteString(l.out, b.String()); err != nil {
+		return err
+	}
+	l.lines = strings.Count(frame, "\n")
+	return nil
+}
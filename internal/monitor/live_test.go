@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestLiveTable_RedrawsInPlace(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	var out bytes.Buffer
+	live := NewLiveTable(&out, PlainText, true)
+
+	first := []*models.Problem{
+		{Entity: "prod/api", Title: "OOM kill", Severity: models.SeverityCritical, FirstSeen: now, Count: 1},
+	}
+	if err := live.Update(first, now); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if strings.Contains(out.String(), "\x1b[") {
+		t.Error("first frame should not move the cursor")
+	}
+	firstLines := strings.Count(out.String(), "\n")
+
+	out.Reset()
+	second := []*models.Problem{
+		first[0],
+		{Entity: "prod/db", Title: "Replication lag", Severity: models.SeverityWarning, FirstSeen: now, Count: 3},
+	}
+	if err := live.Update(second, now.Add(10*time.Second)); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	frame := out.String()
+	wantPrefix := fmt.Sprintf(cursorUpFormat, firstLines) + clearBelow
+	if !strings.HasPrefix(frame, wantPrefix) {
+		t.Errorf("second frame should start with %q, got %q", wantPrefix, frame)
+	}
+	for _, want := range []string{"prod/api", "prod/db", "Replication lag", "2 problems", "12:00:10"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("second frame missing %q:\n%s", want, frame)
+		}
+	}
+}
+
+func TestLiveTable_AppendsWhenNotInPlace(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	var out bytes.Buffer
+	live := NewLiveTable(&out, PlainText, false)
+
+	for i := 0; i < 2; i++ {
+		if err := live.Update(nil, now); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+	if strings.Contains(out.String(), "\x1b[") {
+		t.Error("appended frames must not contain escape sequences")
+	}
+	if n := strings.Count(out.String(), noProblemsMessage); n != 4 {
+		// Summary line and table body per frame
+		t.Errorf("expected 2 frames, got output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/ppiankov/infranow/internal/baseline"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// markChange is how a problem differs from the TUI mark
+type markChange int
+
+const (
+	changeNew markChange = iota
+	changeResolved
+	changeEscalated
+)
+
+// symbol prefixes the severity cell of a changed problem
+func (c markChange) symbol() string {
+	switch c {
+	case changeNew:
+		return "+"
+	case changeResolved:
+		return "-"
+	default:
+		return "↑"
+	}
+}
+
+// snapshotMark is an in-memory baseline captured with the TUI mark key.
+// While set, the TUI lists only what changed since the mark.
+type snapshotMark struct {
+	at       time.Time
+	baseline *baseline.Baseline
+}
+
+// newSnapshotMark captures problems as they are now. Problems are copied so
+// later severity changes on the live set do not leak into the mark.
+func newSnapshotMark(problems []*models.Problem, now time.Time) *snapshotMark {
+	frozen := make([]*models.Problem, len(problems))
+	for i, p := range problems {
+		cp := *p
+		frozen[i] = &cp
+	}
+	return &snapshotMark{at: now, baseline: &baseline.Baseline{Timestamp: now, Problems: frozen}}
+}
+
+// diff returns the problems that are new or escalated since the mark, in
+// current order, followed by the problems resolved since, in mark order.
+// Unchanged problems are dropped.
+func (s *snapshotMark) diff(current []*models.Problem) ([]*models.Problem, map[string]markChange) {
+	changes := classifyChanges(current, s.baseline)
+
+	changed := make([]*models.Problem, 0, len(changes))
+	for _, p := range current {
+		if _, ok := changes[p.ID]; ok {
+			changed = append(changed, p)
+		}
+	}
+	return append(changed, resolvedSince(s.baseline, changes)...), changes
+}
+
+// compareToBaseline returns every current problem, in current order,
+// followed by the problems resolved since b, in baseline order. Unlike a
+// mark, unchanged problems stay listed without a change symbol.
+func compareToBaseline(current []*models.Problem, b *baseline.Baseline) ([]*models.Problem, map[string]markChange) {
+	changes := classifyChanges(current, b)
+	listed := make([]*models.Problem, 0, len(current))
+	listed = append(listed, current...)
+	return append(listed, resolvedSince(b, changes)...), changes
+}
+
+// classifyChanges compares current to b by problem ID. Unchanged problems
+// that did not escalate have no entry.
+func classifyChanges(current []*models.Problem, b *baseline.Baseline) map[string]markChange {
+	comparison := baseline.Compare(current, b)
+
+	before := make(map[string]*models.Problem, len(b.Problems))
+	for _, p := range b.Problems {
+		before[p.ID] = p
+	}
+	changes := make(map[string]markChange)
+	for _, p := range comparison.New {
+		changes[p.ID] = changeNew
+	}
+	for _, p := range comparison.Unchanged {
+		if was := before[p.ID]; p.Severity != was.Severity && p.Severity.AtLeast(was.Severity) {
+			changes[p.ID] = changeEscalated
+		}
+	}
+	for _, p := range comparison.Resolved {
+		changes[p.ID] = changeResolved
+	}
+	return changes
+}
+
+// resolvedSince returns the problems of b classified as resolved, in
+// baseline order
+func resolvedSince(b *baseline.Baseline, changes map[string]markChange) []*models.Problem {
+	var resolved []*models.Problem
+	for _, p := range b.Problems {
+		if change, ok := changes[p.ID]; ok && change == changeResolved {
+			resolved = append(resolved, p)
+		}
+	}
+	return resolved
+}
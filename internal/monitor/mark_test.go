@@ -0,0 +1,150 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ppiankov/infranow/internal/baseline"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestSnapshotMark_Diff(t *testing.T) {
+	now := time.Now()
+	mark := newSnapshotMark([]*models.Problem{
+		{ID: "steady", Severity: models.SeverityWarning},
+		{ID: "worse", Severity: models.SeverityWarning},
+		{ID: "gone", Severity: models.SeverityCritical},
+	}, now)
+
+	current := []*models.Problem{
+		{ID: "fresh", Severity: models.SeverityCritical},
+		{ID: "steady", Severity: models.SeverityWarning},
+		{ID: "worse", Severity: models.SeverityFatal},
+	}
+	changed, changes := mark.diff(current)
+
+	var ids []string
+	for _, p := range changed {
+		ids = append(ids, p.ID)
+	}
+	if got := strings.Join(ids, ","); got != "fresh,worse,gone" {
+		t.Fatalf("changed = %s, want fresh,worse,gone", got)
+	}
+	if changes["fresh"] != changeNew || changes["worse"] != changeEscalated || changes["gone"] != changeResolved {
+		t.Errorf("unexpected changes %v", changes)
+	}
+	if _, ok := changes["steady"]; ok {
+		t.Error("unchanged problems should not be listed")
+	}
+}
+
+func TestSnapshotMark_FreezesSeverity(t *testing.T) {
+	live := &models.Problem{ID: "p", Severity: models.SeverityWarning}
+	mark := newSnapshotMark([]*models.Problem{live}, time.Now())
+
+	// The live problem escalates in place after the mark was taken
+	live.Severity = models.SeverityCritical
+	if _, changes := mark.diff([]*models.Problem{live}); changes["p"] != changeEscalated {
+		t.Errorf("escalation after the mark should be detected, got %v", changes)
+	}
+}
+
+func TestModel_MarkKeyShowsOnlyChanges(t *testing.T) {
+	w := newTestWatcher(1)
+	now := time.Now()
+	w.problems["a"] = &models.Problem{ID: "a", Entity: "prod/a", Severity: models.SeverityWarning, LastSeen: now}
+
+	m := newTestModel(120, 30)
+	m.watcher = w
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	m = updated.(Model)
+	if m.mark == nil || len(m.problems) != 0 {
+		t.Fatalf("right after the mark nothing has changed, got %d problems", len(m.problems))
+	}
+
+	w.mu.Lock()
+	w.problems["b"] = &models.Problem{ID: "b", Entity: "prod/b", Severity: models.SeverityCritical, LastSeen: now}
+	w.mu.Unlock()
+	updated, _ = m.Update(updateMsg{})
+	m = updated.(Model)
+	if len(m.problems) != 1 || m.problems[0].ID != "b" {
+		t.Fatalf("expected only the new problem, got %v", m.problems)
+	}
+	if got := m.tbl.Rows()[0][1]; got != "+CRIT" {
+		t.Errorf("severity cell = %q, want +CRIT", got)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	m = updated.(Model)
+	if m.mark != nil || len(m.problems) != 2 {
+		t.Errorf("clearing the mark should list all problems, got %d", len(m.problems))
+	}
+}
+
+func TestCompareToBaseline_KeepsUnchanged(t *testing.T) {
+	b := &baseline.Baseline{Problems: []*models.Problem{
+		{ID: "steady", Severity: models.SeverityWarning},
+		{ID: "worse", Severity: models.SeverityWarning},
+		{ID: "gone", Severity: models.SeverityCritical},
+	}}
+	current := []*models.Problem{
+		{ID: "fresh", Severity: models.SeverityCritical},
+		{ID: "steady", Severity: models.SeverityWarning},
+		{ID: "worse", Severity: models.SeverityFatal},
+	}
+
+	listed, changes := compareToBaseline(current, b)
+	var ids []string
+	for _, p := range listed {
+		ids = append(ids, p.ID)
+	}
+	if got := strings.Join(ids, ","); got != "fresh,steady,worse,gone" {
+		t.Fatalf("listed = %s, want fresh,steady,worse,gone", got)
+	}
+	if changes["fresh"] != changeNew || changes["worse"] != changeEscalated || changes["gone"] != changeResolved {
+		t.Errorf("unexpected changes %v", changes)
+	}
+	if _, ok := changes["steady"]; ok {
+		t.Error("unchanged problems should carry no change")
+	}
+}
+
+func TestModel_BaselineReclassifiesEachCycle(t *testing.T) {
+	w := newTestWatcher(1)
+	now := time.Now()
+	w.problems["known"] = &models.Problem{ID: "known", Entity: "prod/known", Severity: models.SeverityWarning, LastSeen: now}
+	b := &baseline.Baseline{Timestamp: now.Add(-time.Hour), Problems: []*models.Problem{
+		{ID: "known", Entity: "prod/known", Severity: models.SeverityWarning},
+		{ID: "fixed", Entity: "prod/fixed", Severity: models.SeverityCritical},
+	}}
+
+	m := NewModel(w, "http://localhost:9090", 2*time.Second, nil, WithBaseline(b))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	updated, _ = updated.(Model).Update(updateMsg{})
+	m = updated.(Model)
+	if len(m.problems) != 2 {
+		t.Fatalf("expected the live problem plus the resolved one, got %d", len(m.problems))
+	}
+	if got := m.tbl.Rows()[0][1]; got != "WARN" {
+		t.Errorf("unchanged severity cell = %q, want WARN", got)
+	}
+	if got := m.tbl.Rows()[1][1]; got != "-CRIT" {
+		t.Errorf("resolved severity cell = %q, want -CRIT", got)
+	}
+
+	// A problem appearing in a later cycle is classified as new
+	w.mu.Lock()
+	w.problems["fresh"] = &models.Problem{ID: "fresh", Entity: "prod/fresh", Severity: models.SeverityCritical, LastSeen: now.Add(time.Second)}
+	w.mu.Unlock()
+	updated, _ = m.Update(updateMsg{})
+	m = updated.(Model)
+	if len(m.problems) != 3 || m.markChanges["fresh"] != changeNew {
+		t.Fatalf("expected fresh to be listed as new, got %d problems, changes %v", len(m.problems), m.markChanges)
+	}
+	if header := m.renderHeader(); !strings.Contains(header, "1 new, 1 resolved") {
+		t.Errorf("header missing baseline summary:\n%s", header)
+	}
+}
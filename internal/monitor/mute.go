@@ -0,0 +1,77 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// muteChoices are the durations offered after M, by key
+var muteChoices = []struct {
+	key   string
+	label string
+	d     time.Duration
+}{
+	{"1", "1h", time.Hour},
+	{"4", "4h", 4 * time.Hour},
+	{"d", "1d", 24 * time.Hour},
+}
+
+// muteChoicesHelp renders the duration keys for the footer, e.g. "1: 1h"
+func muteChoicesHelp() string {
+	parts := make([]string, len(muteChoices))
+	for i, c := range muteChoices {
+		parts[i] = c.key + ": " + c.label
+	}
+	return strings.Join(parts, "  ")
+}
+
+// startMute asks for a duration to mute the selected problem for. Returns a
+// user-facing status message.
+func (m *Model) startMute() string {
+	if m.mutes == nil {
+		return "Muting is not available"
+	}
+	p := m.selectedProblem()
+	if p == nil {
+		return "No problem selected"
+	}
+	m.muteTarget = p
+	return ""
+}
+
+// unmuteAll lifts every mute and returns a user-facing status message
+func (m *Model) unmuteAll() string {
+	if m.mutes == nil {
+		return "Muting is not available"
+	}
+	n := m.mutes.Active()
+	m.mutes.Clear()
+	m.updateProblems()
+	return fmt.Sprintf("Unmuted %d problems", n)
+}
+
+// handleMuteKey applies the duration picked for muteTarget, or cancels
+func (m Model) handleMuteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	target := m.muteTarget
+	m.muteTarget = nil
+
+	switch key := msg.String(); key {
+	case "esc", "ctrl+c":
+		m.statusMsg = "Mute cancelled"
+	default:
+		for _, c := range muteChoices {
+			if key == c.key {
+				until := m.mutes.Mute(m.muteID(target), c.d)
+				m.updateProblems()
+				m.statusMsg = fmt.Sprintf("Muted %s for %s (until %s)", target.Entity, c.label, until.Format("Jan 2 15:04"))
+				return m, nil
+			}
+		}
+		// Any other key keeps the picker open
+		m.muteTarget = target
+	}
+	return m, nil
+}
@@ -0,0 +1,99 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ppiankov/infranow/internal/filter"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func pressKey(m Model, k string) Model {
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	return updated.(Model)
+}
+
+func TestModel_MuteUntilExpiry(t *testing.T) {
+	clock := newFakeClock()
+	w := newTestWatcher(1)
+	now := time.Now()
+	w.problems["crash"] = &models.Problem{ID: "crash", Entity: "prod/api", Severity: models.SeverityCritical, LastSeen: now}
+	w.problems["disk"] = &models.Problem{ID: "disk", Entity: "node-1:/", Severity: models.SeverityWarning, LastSeen: now}
+
+	m := NewModel(w, "http://localhost:9090", 2*time.Second, nil, WithMutes(filter.NewMutes(clock.now)))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	m = updated.(Model)
+	m.updateProblems()
+	if len(m.problems) != 2 || m.problems[0].ID != "crash" {
+		t.Fatalf("expected both problems, CRITICAL first, got %d", len(m.problems))
+	}
+
+	m = pressKey(m, "M")
+	if !strings.Contains(m.View(), "Mute prod/api for:") {
+		t.Fatalf("expected the duration picker after M:\n%s", m.View())
+	}
+	m = pressKey(m, "x") // Not a duration: picker stays open
+	if m.muteTarget == nil {
+		t.Fatal("unknown key should keep the picker open")
+	}
+	m = pressKey(m, "4")
+	if len(m.problems) != 1 || m.problems[0].ID != "disk" {
+		t.Fatalf("muted problem still listed: %d problems", len(m.problems))
+	}
+	if !strings.Contains(m.statusMsg, "Muted prod/api for 4h") {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+	if view := m.View(); !strings.Contains(view, "Problems: 1 +1 muted") {
+		t.Errorf("header should count the mute:\n%s", view)
+	}
+
+	// Still muted just before expiry, back on the first refresh after
+	clock.advance(4*time.Hour - time.Minute)
+	updated, _ = m.Update(updateMsg{})
+	m = updated.(Model)
+	if len(m.problems) != 1 {
+		t.Errorf("problem unmuted early: %d problems", len(m.problems))
+	}
+	clock.advance(time.Minute)
+	updated, _ = m.Update(updateMsg{})
+	m = updated.(Model)
+	if len(m.problems) != 2 {
+		t.Errorf("mute did not expire: %d problems", len(m.problems))
+	}
+}
+
+func TestModel_MuteCancelAndUnmuteAll(t *testing.T) {
+	w := newTestWatcher(1)
+	w.problems["crash"] = &models.Problem{ID: "crash", Entity: "prod/api", Severity: models.SeverityCritical, LastSeen: time.Now()}
+
+	m := NewModel(w, "http://localhost:9090", 2*time.Second, nil, WithMutes(filter.NewMutes(nil)))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	m = updated.(Model)
+	m.updateProblems()
+
+	updated, _ = pressKey(m, "M").Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if len(m.problems) != 1 || m.muteTarget != nil {
+		t.Fatal("esc should cancel without muting")
+	}
+
+	m = pressKey(pressKey(m, "M"), "d")
+	if len(m.problems) != 0 {
+		t.Fatal("expected the problem muted for a day")
+	}
+	m = pressKey(m, "U")
+	if len(m.problems) != 1 || m.statusMsg != "Unmuted 1 problems" {
+		t.Errorf("U should bring muted problems back: %d problems, status %q", len(m.problems), m.statusMsg)
+	}
+}
+
+func TestModel_MuteDisabledWithoutStore(t *testing.T) {
+	m := newTestModel(120, 30)
+	m = pressKey(m, "M")
+	if m.muteTarget != nil || m.statusMsg != "Muting is not available" {
+		t.Errorf("M without a mute store: target %v, status %q", m.muteTarget, m.statusMsg)
+	}
+}
@@ -0,0 +1,43 @@
+package monitor
+
+import (
+	"sort"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// TopNamespaces is how many of the most-affected namespaces summaries list
+const TopNamespaces = 3
+
+// NamespaceCount is the number of problems in one namespace
+type NamespaceCount struct {
+	Namespace string `json:"namespace"`
+	Problems  int    `json:"problems"`
+}
+
+// AffectedNamespaces returns how many distinct namespaces have problems and
+// the top most-affected ones, by problem count then name. Problems without a
+// namespace label (nodes, databases, ...) are not counted.
+func AffectedNamespaces(problems []*models.Problem, top int) (int, []NamespaceCount) {
+	counts := make(map[string]int)
+	for _, p := range problems {
+		if ns := p.Labels["namespace"]; ns != "" {
+			counts[ns]++
+		}
+	}
+
+	ranked := make([]NamespaceCount, 0, len(counts))
+	for ns, n := range counts {
+		ranked = append(ranked, NamespaceCount{Namespace: ns, Problems: n})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Problems != ranked[j].Problems {
+			return ranked[i].Problems > ranked[j].Problems
+		}
+		return ranked[i].Namespace < ranked[j].Namespace
+	})
+	if len(ranked) > top {
+		ranked = ranked[:top]
+	}
+	return len(counts), ranked
+}
@@ -0,0 +1,44 @@
+package monitor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func nsProblem(ns string) *models.Problem {
+	p := &models.Problem{Labels: map[string]string{}}
+	if ns != "" {
+		p.Labels["namespace"] = ns
+	}
+	return p
+}
+
+func TestAffectedNamespaces(t *testing.T) {
+	problems := []*models.Problem{
+		nsProblem("prod"), nsProblem("prod"), nsProblem("prod"),
+		nsProblem("staging"), nsProblem("staging"),
+		nsProblem("dev"),
+		nsProblem("batch"),
+		nsProblem(""),          // Node-level problem
+		{Entity: "pg-primary"}, // No labels at all
+		{Labels: map[string]string{"instance": "db"}},
+	}
+
+	count, top := AffectedNamespaces(problems, TopNamespaces)
+	if count != 4 {
+		t.Errorf("affected namespaces = %d, want 4", count)
+	}
+	want := []NamespaceCount{{"prod", 3}, {"staging", 2}, {"batch", 1}} // Ties by name
+	if !reflect.DeepEqual(top, want) {
+		t.Errorf("top namespaces = %v, want %v", top, want)
+	}
+}
+
+func TestAffectedNamespaces_Empty(t *testing.T) {
+	count, top := AffectedNamespaces(nil, TopNamespaces)
+	if count != 0 || len(top) != 0 {
+		t.Errorf("got %d, %v for no problems", count, top)
+	}
+}
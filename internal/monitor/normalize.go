@@ -0,0 +1,45 @@
+package monitor
+
+import "github.com/ppiankov/infranow/internal/models"
+
+// WithLabelNormalization rewrites label values (keyed by label name, see
+// models.NormalizeLabels) before problem IDs are derived, so one logical
+// problem reported under inconsistent labels is tracked once
+func WithLabelNormalization(rules map[string][]string) WatcherOption {
+	return func(w *Watcher) {
+		w.labelNormalization = rules
+	}
+}
+
+// SetLabelNormalization replaces the label normalization rules (config
+// hot-reload). Problems already tracked under their old IDs go stale.
+func (w *Watcher) SetLabelNormalization(rules map[string][]string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.labelNormalization = rules
+}
+
+// assignIDsLocked normalizes labels and sets problem IDs. With normalization
+// active, problems that collapse into the same ID within one detection are
+// merged, keeping the first. Caller must hold w.mu.
+func (w *Watcher) assignIDsLocked(problems []*models.Problem) []*models.Problem {
+	if len(w.labelNormalization) == 0 {
+		for _, p := range problems {
+			p.ID = models.StableID(p.EntityType, p.Type, p.Labels)
+		}
+		return problems
+	}
+
+	seen := make(map[string]bool, len(problems))
+	merged := problems[:0]
+	for _, p := range problems {
+		p.NormalizeLabels(w.labelNormalization)
+		p.ID = models.StableID(p.EntityType, p.Type, p.Labels)
+		if seen[p.ID] {
+			continue
+		}
+		seen[p.ID] = true
+		merged = append(merged, p)
+	}
+	return merged
+}
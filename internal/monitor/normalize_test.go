@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+)
+
+// diskProvider reports the same full filesystem once per instance value
+func diskProvider(instances ...string) *metrics.MockProvider {
+	return &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			vector := make(model.Vector, 0, len(instances))
+			for _, instance := range instances {
+				vector = append(vector, &model.Sample{
+					Metric: model.Metric{"instance": model.LabelValue(instance), "mountpoint": "/var", "device": "sda1"},
+					Value:  0.97,
+				})
+			}
+			return vector, nil
+		},
+	}
+}
+
+func TestWatcher_LabelNormalizationMergesProblems(t *testing.T) {
+	rules := map[string][]string{"node": {"strip_port", "lowercase"}}
+	w := NewWatcher(diskProvider("node-1:9100"), detector.NewRegistry(), 0, 30*time.Second, WithLabelNormalization(rules))
+
+	// Same cycle: both label variants collapse into one problem
+	w.provider = diskProvider("node-1:9100", "Node-1")
+	w.executeDetector(context.Background(), detector.NewDiskSpaceDetector())
+	problems := w.GetProblems()
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 merged problem, got %d", len(problems))
+	}
+	if problems[0].Entity != "node-1:/var" || problems[0].Labels["node"] != "node-1" {
+		t.Errorf("entity = %q, node = %q, want node-1:/var and node-1", problems[0].Entity, problems[0].Labels["node"])
+	}
+
+	// Next cycle reports the port-less variant: same problem, counted again
+	w.provider = diskProvider("node-1")
+	w.executeDetector(context.Background(), detector.NewDiskSpaceDetector())
+	problems = w.GetProblems()
+	if len(problems) != 1 || problems[0].Count != 2 {
+		t.Fatalf("expected 1 problem seen twice, got %d problems", len(problems))
+	}
+}
+
+func TestWatcher_NoLabelNormalization(t *testing.T) {
+	w := NewWatcher(diskProvider("node-1:9100", "node-1"), detector.NewRegistry(), 0, 30*time.Second)
+
+	w.executeDetector(context.Background(), detector.NewDiskSpaceDetector())
+	if n := len(w.GetProblems()); n != 2 {
+		t.Errorf("without normalization label variants stay separate: got %d problems, want 2", n)
+	}
+}
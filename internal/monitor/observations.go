@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// DefaultObservationHistory is how many recent detections are kept per
+// problem when observation history is enabled
+const DefaultObservationHistory = 10
+
+// WithObservationHistory keeps the last size detections of every active
+// problem for AnnotateObservations (0 = disabled)
+func WithObservationHistory(size int) WatcherOption {
+	return func(w *Watcher) {
+		w.observationSize = size
+	}
+}
+
+// observationRing is a fixed-size buffer of a problem's recent detections
+type observationRing struct {
+	buf  []models.Observation
+	next int
+	full bool
+}
+
+func newObservationRing(size int) *observationRing {
+	return &observationRing{buf: make([]models.Observation, size)}
+}
+
+func (r *observationRing) add(o models.Observation) {
+	r.buf[r.next] = o
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// snapshot returns the buffered observations, oldest first
+func (r *observationRing) snapshot() []models.Observation {
+	if !r.full {
+		return append([]models.Observation(nil), r.buf[:r.next]...)
+	}
+	out := make([]models.Observation, 0, len(r.buf))
+	out = append(out, r.buf[r.next:]...)
+	return append(out, r.buf[:r.next]...)
+}
+
+// recordObservation appends a detection of p at the severity detected this
+// time, which may differ from the one p was first reported at. Caller must
+// hold w.mu.
+func (w *Watcher) recordObservation(p *models.Problem, detected models.Severity) {
+	if w.observationSize <= 0 {
+		return
+	}
+	ring, ok := w.observations[p.ID]
+	if !ok {
+		ring = newObservationRing(w.observationSize)
+		w.observations[p.ID] = ring
+	}
+	ring.add(models.Observation{
+		Timestamp: p.LastSeen,
+		Severity:  detected,
+		Count:     p.Count,
+		Metrics:   p.Metrics,
+	})
+}
+
+// pruneObservations drops history of problems no longer tracked. Caller must
+// hold w.mu.
+func (w *Watcher) pruneObservations() {
+	for id := range w.observations {
+		if _, ok := w.problems[id]; !ok {
+			delete(w.observations, id)
+		}
+	}
+}
+
+// formatTrajectory renders how a problem evolved over its observations,
+// oldest first, e.g. "W W C C | count 3->6". Empty with fewer than two.
+func formatTrajectory(observations []models.Observation) string {
+	if len(observations) < 2 {
+		return ""
+	}
+	steps := make([]string, len(observations))
+	for i, o := range observations {
+		steps[i] = "?"
+		if o.Severity != "" {
+			steps[i] = string(o.Severity)[:1]
+		}
+	}
+	first, last := observations[0], observations[len(observations)-1]
+	return fmt.Sprintf("%s | count %d->%d", strings.Join(steps, " "), first.Count, last.Count)
+}
+
+// AnnotateObservations attaches each problem's recent detections for export
+func (w *Watcher) AnnotateObservations(problems []*models.Problem) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for _, p := range problems {
+		if ring, ok := w.observations[p.ID]; ok {
+			p.Observations = ring.snapshot()
+		}
+	}
+}
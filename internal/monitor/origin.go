@@ -0,0 +1,14 @@
+package monitor
+
+import "time"
+
+// WithOriginalFirstSeen carries over when an earlier run first saw each
+// problem, keyed by problem ID (see baseline.State.FirstSeen). A problem
+// detected again gets it as OriginalFirstSeen, so its age spans restarts
+// while FirstSeen still starts with this session. Each entry is used once:
+// a problem that resolves and comes back starts over.
+func WithOriginalFirstSeen(seen map[string]time.Time) WatcherOption {
+	return func(w *Watcher) {
+		w.originalFirstSeen = seen
+	}
+}
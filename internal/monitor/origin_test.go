@@ -0,0 +1,101 @@
+package monitor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/baseline"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// runSession runs one watcher session starting at clock's time, seeded from
+// the state file like the monitor command, and saves the state afterwards
+func runSession(t *testing.T, path string, clock *fakeClock, ids ...string) []*models.Problem {
+	t.Helper()
+	prev, err := baseline.LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	w := newTestWatcher(0, WithClock(clock.now), WithOriginalFirstSeen(prev.FirstSeen()))
+
+	detected := make([]*models.Problem, 0, len(ids))
+	for _, id := range ids {
+		detected = append(detected, &models.Problem{ID: id, Severity: models.SeverityCritical})
+	}
+	w.updateProblems(detected)
+
+	problems := w.GetProblems()
+	if err := baseline.SaveState(path, problems, baseline.StateJSON); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	return problems
+}
+
+func TestOriginalFirstSeen_SurvivesStateReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	clock := newFakeClock()
+	start := clock.now()
+
+	first := runSession(t, path, clock, "prod/db/disk_full")
+	if !first[0].OriginalFirstSeen.IsZero() {
+		t.Errorf("first session OriginalFirstSeen = %v, want zero", first[0].OriginalFirstSeen)
+	}
+
+	// Restarted a day later, then again two days later
+	for _, d := range []time.Duration{24 * time.Hour, 48 * time.Hour} {
+		clock.advance(d)
+		problems := runSession(t, path, clock, "prod/db/disk_full", "prod/api/crashloop")
+		byID := make(map[string]*models.Problem, len(problems))
+		for _, p := range problems {
+			byID[p.ID] = p
+		}
+
+		p := byID["prod/db/disk_full"]
+		if !p.FirstSeen.Equal(clock.now()) {
+			t.Errorf("session FirstSeen = %v, want reset to %v", p.FirstSeen, clock.now())
+		}
+		if !p.OriginalFirstSeen.Equal(start) || !p.Since().Equal(start) {
+			t.Errorf("OriginalFirstSeen = %v, want %v", p.OriginalFirstSeen, start)
+		}
+		if p.Persistence != 0 {
+			t.Errorf("persistence = %v, want 0 for the new session", p.Persistence)
+		}
+	}
+
+	// The crashloop first appeared in the second session
+	problems := runSession(t, path, clock, "prod/api/crashloop")
+	if len(problems) != 1 || !problems[0].OriginalFirstSeen.Equal(start.Add(24*time.Hour)) {
+		t.Errorf("crashloop OriginalFirstSeen = %v, want %v", problems[0].OriginalFirstSeen, start.Add(24*time.Hour))
+	}
+}
+
+func TestOriginalFirstSeen_ResolvedProblemStartsOver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	clock := newFakeClock()
+
+	runSession(t, path, clock, "prod/db/disk_full")
+	clock.advance(time.Hour)
+	runSession(t, path, clock) // Resolved: dropped from the state
+	clock.advance(time.Hour)
+
+	problems := runSession(t, path, clock, "prod/db/disk_full")
+	if !problems[0].OriginalFirstSeen.IsZero() {
+		t.Errorf("OriginalFirstSeen = %v, want zero after the problem resolved", problems[0].OriginalFirstSeen)
+	}
+}
+
+func TestPlainText_ShowsOriginalAge(t *testing.T) {
+	now := time.Date(2025, 6, 4, 12, 0, 0, 0, time.UTC)
+	p := &models.Problem{
+		ID: "prod/db/disk_full", Entity: "prod/db", Title: "Disk almost full", Severity: models.SeverityCritical,
+		FirstSeen:         now.Add(-5 * time.Minute),
+		OriginalFirstSeen: now.Add(-72 * time.Hour),
+		Count:             1,
+	}
+	out := PlainText([]*models.Problem{p}, now)
+	if !strings.Contains(out, humanAge(72*time.Hour)) {
+		t.Errorf("expected the original age %s:\n%s", humanAge(72*time.Hour), out)
+	}
+}
@@ -0,0 +1,57 @@
+package monitor
+
+import (
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// pinMarker prefixes the severity of pinned problems in the TUI
+const pinMarker = "*"
+
+// pinFirst moves problems whose ID is pinned ahead of the rest, keeping the
+// sort order within both groups
+func pinFirst(problems []*models.Problem, pinned map[string]bool) []*models.Problem {
+	if len(pinned) == 0 {
+		return problems
+	}
+	out := make([]*models.Problem, 0, len(problems))
+	for _, p := range problems {
+		if pinned[p.ID] {
+			out = append(out, p)
+		}
+	}
+	for _, p := range problems {
+		if !pinned[p.ID] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// togglePin pins or unpins the selected problem for the rest of the session
+// and keeps the cursor on it. Returns a user-facing status message.
+func (m *Model) togglePin() string {
+	p := m.selectedProblem()
+	if p == nil {
+		return "No problem selected"
+	}
+
+	msg := "Pinned " + p.Entity
+	if m.pinned[p.ID] {
+		delete(m.pinned, p.ID)
+		msg = "Unpinned " + p.Entity
+	} else {
+		if m.pinned == nil {
+			m.pinned = make(map[string]bool)
+		}
+		m.pinned[p.ID] = true
+	}
+
+	m.updateProblems()
+	for i, q := range m.problems {
+		if q.ID == p.ID {
+			m.tbl.SetCursor(i)
+			break
+		}
+	}
+	return msg
+}
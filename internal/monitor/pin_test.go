@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestPinFirst_KeepsOrderWithinGroups(t *testing.T) {
+	problems := []*models.Problem{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}}
+
+	got := pinFirst(problems, map[string]bool{"d": true, "b": true})
+	var ids []string
+	for _, p := range got {
+		ids = append(ids, p.ID)
+	}
+	if strings.Join(ids, ",") != "b,d,a,c" {
+		t.Errorf("order = %v, want b,d,a,c", ids)
+	}
+}
+
+func TestModel_PinnedSortAboveHigherScore(t *testing.T) {
+	w := newTestWatcher(1)
+	now := time.Now()
+	w.problems["fatal"] = &models.Problem{ID: "fatal", Entity: "prod/db", Severity: models.SeverityFatal, LastSeen: now}
+	// Older, so it also sorts last by recency
+	w.problems["warn"] = &models.Problem{ID: "warn", Entity: "prod/web", Severity: models.SeverityWarning, LastSeen: now.Add(-time.Minute)}
+
+	m := newTestModel(120, 30)
+	m.watcher = w
+	m.updateProblems()
+	if m.problems[0].ID != "fatal" {
+		t.Fatalf("expected FATAL first before pinning, got %s", m.problems[0].ID)
+	}
+
+	m.tbl.SetCursor(1)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	m = updated.(Model)
+	if m.problems[0].ID != "warn" {
+		t.Fatalf("pinned WARNING should sort above FATAL, got %s first", m.problems[0].ID)
+	}
+	if m.tbl.Cursor() != 0 {
+		t.Errorf("cursor should follow the pinned problem, got row %d", m.tbl.Cursor())
+	}
+	if got := m.tbl.Rows()[0][1]; got != pinMarker+"WARN" {
+		t.Errorf("severity cell = %q, want %sWARN", got, pinMarker)
+	}
+
+	// Pins survive refreshes and sort changes
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = updated.(Model)
+	updated, _ = m.Update(updateMsg{})
+	m = updated.(Model)
+	if m.problems[0].ID != "warn" {
+		t.Errorf("pin lost after refresh, got %s first", m.problems[0].ID)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	m = updated.(Model)
+	if m.problems[0].ID != "fatal" {
+		t.Errorf("unpinning should restore score order, got %s first", m.problems[0].ID)
+	}
+}
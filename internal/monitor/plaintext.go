@@ -78,8 +78,12 @@ func renderProblemRow(b *strings.Builder, p *models.Problem, now time.Time) {
 		title += fmt.Sprintf(" [recurring %s]", p.History.RecurringSince)
 	}
 	title = truncate(title, 40)
-	age := humanAge(now.Sub(p.FirstSeen))
+	age := humanAge(now.Sub(p.Since()))
 	fmt.Fprintf(b, "%-8s %-30s %-40s %-10s %d\n", sev, entity, title, age, p.Count)
+	if p.QueryURL != "" {
+		// Links are never truncated so they stay clickable
+		fmt.Fprintf(b, "%-8s %s\n", "", p.QueryURL)
+	}
 }
 
 // PlainTextSummary returns a one-line summary of problem counts by severity.
@@ -103,6 +107,24 @@ func PlainTextSummary(problems []*models.Problem) string {
 		len(problems), fatal, critical, warning)
 }
 
+// SummaryLine renders one machine-parseable line for log scrapers, e.g.
+// "SUMMARY fatal=1 critical=3 warning=5 total=9 exit=2"
+func SummaryLine(problems []*models.Problem, exitCode int) string {
+	var fatal, critical, warning int
+	for _, p := range problems {
+		switch p.Severity {
+		case models.SeverityFatal:
+			fatal++
+		case models.SeverityCritical:
+			critical++
+		case models.SeverityWarning:
+			warning++
+		}
+	}
+	return fmt.Sprintf("SUMMARY fatal=%d critical=%d warning=%d total=%d exit=%d",
+		fatal, critical, warning, len(problems), exitCode)
+}
+
 // HighestSeverity returns the highest severity among problems.
 // Returns empty string if no problems.
 func HighestSeverity(problems []*models.Problem) models.Severity {
@@ -134,6 +156,9 @@ func shortSeverity(s models.Severity) string {
 }
 
 func truncate(s string, maxLen int) string {
+	if maxLen <= 0 {
+		return ""
+	}
 	if len(s) <= maxLen {
 		return s
 	}
@@ -139,6 +139,22 @@ func TestPlainTextSummary(t *testing.T) {
 	}
 }
 
+func TestSummaryLine(t *testing.T) {
+	problems := []*models.Problem{
+		{Severity: models.SeverityFatal},
+		{Severity: models.SeverityCritical},
+		{Severity: models.SeverityCritical},
+		{Severity: models.SeverityWarning},
+	}
+	want := "SUMMARY fatal=1 critical=2 warning=1 total=4 exit=2"
+	if got := SummaryLine(problems, 2); got != want {
+		t.Errorf("SummaryLine() = %q, want %q", got, want)
+	}
+	if got := SummaryLine(nil, 0); got != "SUMMARY fatal=0 critical=0 warning=0 total=0 exit=0" {
+		t.Errorf("SummaryLine(nil) = %q", got)
+	}
+}
+
 func TestHighestSeverity(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -265,6 +281,8 @@ func TestTruncate(t *testing.T) {
 		{"exact", "hello", 5, "hello"},
 		{"long", "hello world this is long", 10, "hello w..."},
 		{"very short max", "hello", 2, "he"},
+		{"zero max", "hello", 0, ""},
+		{"negative max", "hello", -4, ""},
 	}
 
 	for _, tt := range tests {
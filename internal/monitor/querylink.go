@@ -0,0 +1,63 @@
+package monitor
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// QueryLinkPlaceholder in a --prometheus-ui-url template is replaced with the
+// URL-encoded PromQL of the problem
+const QueryLinkPlaceholder = "{expr}"
+
+// WithQueryLinks records the PromQL behind every detected problem and links
+// it from the problem. base is either a Prometheus UI root, which gets the
+// graph page with the query pre-filled, or a template containing
+// QueryLinkPlaceholder, e.g. a Grafana Explore URL (empty = disabled).
+func WithQueryLinks(base string) WatcherOption {
+	return func(w *Watcher) {
+		w.queryLinkBase = base
+	}
+}
+
+// QueryLink returns the link opening expr under base (see WithQueryLinks)
+func QueryLink(base, expr string) string {
+	if strings.Contains(base, QueryLinkPlaceholder) {
+		return strings.ReplaceAll(base, QueryLinkPlaceholder, url.QueryEscape(expr))
+	}
+	values := url.Values{}
+	values.Set("g0.expr", expr)
+	values.Set("g0.tab", "0") // Graph tab
+	return strings.TrimRight(base, "/") + "/graph?" + values.Encode()
+}
+
+// link sets Query and QueryURL on each problem: the query of the first
+// sample matching its labels, else the first query that returned anything,
+// else the first query issued
+func (r *evalRecorder) link(problems []*models.Problem, base string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fallback := ""
+	if len(r.samples) > 0 {
+		fallback = r.samples[0].query
+	} else if len(r.queries) > 0 {
+		fallback = r.queries[0]
+	}
+
+	for _, p := range problems {
+		query := fallback
+		for _, s := range r.samples {
+			if matchesLabels(s.metric, p.Labels) {
+				query = s.query
+				break
+			}
+		}
+		if query == "" {
+			continue
+		}
+		p.Query = query
+		p.QueryURL = QueryLink(base, query)
+	}
+}
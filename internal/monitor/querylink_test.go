@@ -0,0 +1,103 @@
+package monitor
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestQueryLink(t *testing.T) {
+	expr := `sum(rate(http_requests_total{status=~"5.."}[5m])) > 0.05`
+
+	tests := []struct {
+		name string
+		base string
+		want string
+	}{
+		{
+			"prometheus root",
+			"http://prom:9090/",
+			"http://prom:9090/graph?g0.expr=sum%28rate%28http_requests_total%7Bstatus%3D~%225..%22%7D%5B5m%5D%29%29+%3E+0.05&g0.tab=0",
+		},
+		{
+			"template",
+			"https://grafana/explore?expr={expr}&orgId=1",
+			"https://grafana/explore?expr=sum%28rate%28http_requests_total%7Bstatus%3D~%225..%22%7D%5B5m%5D%29%29+%3E+0.05&orgId=1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := QueryLink(tt.base, expr)
+			if got != tt.want {
+				t.Fatalf("QueryLink() = %s, want %s", got, tt.want)
+			}
+			u, err := url.Parse(got)
+			if err != nil {
+				t.Fatalf("link does not parse: %v", err)
+			}
+			for _, v := range u.Query() {
+				if v[0] == expr {
+					return
+				}
+			}
+			t.Errorf("no query parameter decodes back to the expression: %s", got)
+		})
+	}
+}
+
+func TestWithQueryLinks_SetsDrivingQuery(t *testing.T) {
+	var issued string
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			issued = query
+			return model.Vector{
+				&model.Sample{Metric: model.Metric{"namespace": "prod", "pod": "api", "container": "app"}, Value: 1},
+			}, nil
+		},
+	}
+	w := NewWatcher(provider, detector.NewRegistry(), 0, 30*time.Second, WithQueryLinks("http://prom:9090"))
+
+	problems, ok := w.detect(context.Background(), detector.NewOOMKillDetector(), provider, "")
+	if !ok || len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d (ok=%v)", len(problems), ok)
+	}
+	p := problems[0]
+	if p.Query != issued {
+		t.Errorf("Query = %q, want %q", p.Query, issued)
+	}
+	if p.QueryURL != QueryLink("http://prom:9090", issued) {
+		t.Errorf("unexpected QueryURL %s", p.QueryURL)
+	}
+}
+
+func TestWithQueryLinks_DisabledByDefault(t *testing.T) {
+	provider := oomSamplesAt(time.Now(), time.Now())
+	w := NewWatcher(provider, detector.NewRegistry(), 0, 30*time.Second)
+
+	problems, _ := w.detect(context.Background(), detector.NewOOMKillDetector(), provider, "")
+	for _, p := range problems {
+		if p.Query != "" || p.QueryURL != "" {
+			t.Errorf("expected no query link without WithQueryLinks, got %q", p.QueryURL)
+		}
+	}
+}
+
+func TestPlainText_ShowsQueryURL(t *testing.T) {
+	now := time.Now()
+	link := QueryLink("http://prom:9090", "up == 0")
+	out := PlainText([]*models.Problem{{
+		Entity: "prod/api", Title: "Target Down", Severity: models.SeverityCritical,
+		FirstSeen: now, Count: 1, QueryURL: link,
+	}}, now)
+	if !strings.Contains(out, link) {
+		t.Errorf("expected untruncated query link in output:\n%s", out)
+	}
+}
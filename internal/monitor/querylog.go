@@ -0,0 +1,110 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+)
+
+// QueryLogger logs the PromQL each detector runs with its result count.
+// Logging is sampled so a long-running monitor does not repeat the same
+// queries every cycle: with every = 0 each distinct query is logged once per
+// detector, with every = N all queries of a detector are logged on its first
+// cycle and every Nth cycle after that.
+type QueryLogger struct {
+	logf  func(format string, args ...any)
+	every int
+
+	mu     sync.Mutex
+	cycles map[string]int  // Detection cycles per detector (and tenant)
+	seen   map[string]bool // detector + query already logged (every = 0)
+}
+
+// NewQueryLogger creates a QueryLogger writing through logf
+func NewQueryLogger(logf func(format string, args ...any), every int) *QueryLogger {
+	return &QueryLogger{
+		logf:   logf,
+		every:  every,
+		cycles: make(map[string]int),
+		seen:   make(map[string]bool),
+	}
+}
+
+// WithQueryLogger logs detector queries through l
+func WithQueryLogger(l *QueryLogger) WatcherOption {
+	return func(w *Watcher) {
+		w.queryLogger = l
+	}
+}
+
+// wrap returns the provider a detector uses for one detection cycle: one that
+// logs its queries when the cycle is sampled, or provider itself otherwise
+func (l *QueryLogger) wrap(detectorName, tenant string, provider metrics.MetricsProvider) metrics.MetricsProvider {
+	key := detectorName
+	if tenant != "" {
+		key += "@" + tenant
+	}
+
+	l.mu.Lock()
+	l.cycles[key]++
+	cycle := l.cycles[key]
+	l.mu.Unlock()
+
+	if l.every > 0 && (cycle-1)%l.every != 0 {
+		return provider
+	}
+	return &loggingProvider{MetricsProvider: provider, logger: l, key: key, cycle: cycle}
+}
+
+// sample reports whether a query of this cycle should be logged
+func (l *QueryLogger) sample(key, query string) bool {
+	if l.every > 0 {
+		return true // Whole cycle was sampled in wrap
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.seen[key+"\x00"+query] {
+		return false
+	}
+	l.seen[key+"\x00"+query] = true
+	return true
+}
+
+func (l *QueryLogger) log(key string, cycle int, query string, series int, elapsed time.Duration, err error) {
+	if err != nil {
+		l.logf("[infranow] query %s cycle=%d error=%q: %s\n", key, cycle, err, query)
+		return
+	}
+	l.logf("[infranow] query %s cycle=%d series=%d duration=%s: %s\n",
+		key, cycle, series, elapsed.Round(time.Millisecond), query)
+}
+
+// loggingProvider logs sampled queries of one detection cycle
+type loggingProvider struct {
+	metrics.MetricsProvider
+	logger *QueryLogger
+	key    string
+	cycle  int
+}
+
+func (p *loggingProvider) QueryInstant(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+	start := time.Now()
+	result, err := p.MetricsProvider.QueryInstant(ctx, query, ts)
+	if p.logger.sample(p.key, query) {
+		p.logger.log(p.key, p.cycle, query, len(result), time.Since(start), err)
+	}
+	return result, err
+}
+
+func (p *loggingProvider) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (model.Matrix, error) {
+	began := time.Now()
+	result, err := p.MetricsProvider.QueryRange(ctx, query, start, end, step)
+	if p.logger.sample(p.key, query) {
+		p.logger.log(p.key, p.cycle, query, len(result), time.Since(began), err)
+	}
+	return result, err
+}
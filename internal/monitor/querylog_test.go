@@ -0,0 +1,111 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+)
+
+// captureLog collects QueryLogger lines
+type captureLog struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (c *captureLog) logf(format string, args ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lines = append(c.lines, fmt.Sprintf(format, args...))
+}
+
+func (c *captureLog) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.lines)
+}
+
+func TestQueryLogger_EveryNthCycle(t *testing.T) {
+	var captured captureLog
+	w := newTestWatcher(0)
+	WithQueryLogger(NewQueryLogger(captured.logf, 2))(w)
+
+	// OOMKill runs one query per cycle; cycles 1, 3 and 5 are sampled
+	d := detector.NewOOMKillDetector()
+	for cycle := 1; cycle <= 5; cycle++ {
+		w.executeDetector(context.Background(), d)
+	}
+
+	if got := captured.count(); got != 3 {
+		t.Fatalf("expected 3 logged queries over 5 cycles with every=2, got %d: %v", got, captured.lines)
+	}
+	for i, want := range []string{"cycle=1", "cycle=3", "cycle=5"} {
+		if !strings.Contains(captured.lines[i], want) {
+			t.Errorf("line %d = %q, want %s", i, captured.lines[i], want)
+		}
+	}
+	if !strings.Contains(captured.lines[0], "kubernetes_oom_kills") || !strings.Contains(captured.lines[0], "series=0") {
+		t.Errorf("line should name the detector and result count: %q", captured.lines[0])
+	}
+}
+
+func TestQueryLogger_OncePerQuery(t *testing.T) {
+	var captured captureLog
+	logger := NewQueryLogger(captured.logf, 0)
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{&model.Sample{Value: 1}}, nil
+		},
+	}
+
+	queries := [][]string{{"up", "vector(1)"}, {"up", "vector(1)"}, {"up", "time()"}}
+	for _, cycle := range queries {
+		p := logger.wrap("test", "", provider)
+		for _, q := range cycle {
+			if _, err := p.QueryInstant(context.Background(), q, time.Now()); err != nil {
+				t.Fatalf("QueryInstant() error = %v", err)
+			}
+		}
+	}
+
+	// up and vector(1) once, then the new time() query
+	if got := captured.count(); got != 3 {
+		t.Fatalf("expected 3 logged queries, got %d: %v", got, captured.lines)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(captured.lines[2]), ": time()") {
+		t.Errorf("third line should be the new query, got %q", captured.lines[2])
+	}
+
+	// Another detector running the same query is logged separately
+	p := logger.wrap("other", "", provider)
+	_, _ = p.QueryInstant(context.Background(), "up", time.Now())
+	if got := captured.count(); got != 4 {
+		t.Errorf("expected the other detector's query to be logged, got %d lines", got)
+	}
+}
+
+func TestQueryLogger_LogsErrors(t *testing.T) {
+	var captured captureLog
+	logger := NewQueryLogger(captured.logf, 1)
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	}
+
+	p := logger.wrap("test", "tenant-a", provider)
+	if _, err := p.QueryInstant(context.Background(), "up", time.Now()); err == nil {
+		t.Fatal("expected the provider error to be returned")
+	}
+	if captured.count() != 1 || !strings.Contains(captured.lines[0], `error="connection refused"`) ||
+		!strings.Contains(captured.lines[0], "test@tenant-a") {
+		t.Errorf("unexpected log: %v", captured.lines)
+	}
+}
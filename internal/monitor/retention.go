@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// ResolvedProblem is a problem that stopped being detected, kept in exported
+// output for the retention window
+type ResolvedProblem struct {
+	Problem    *models.Problem
+	ResolvedAt time.Time
+}
+
+// ResolvedRetention remembers problems after they resolve so scrape-based
+// consumers still see problems that started and ended between two scrapes.
+// It is not safe for concurrent use.
+type ResolvedRetention struct {
+	window   time.Duration
+	active   map[string]*models.Problem
+	resolved map[string]ResolvedProblem
+}
+
+// NewResolvedRetention creates a retention that keeps resolved problems for
+// window (0 = not at all)
+func NewResolvedRetention(window time.Duration) *ResolvedRetention {
+	return &ResolvedRetention{
+		window:   window,
+		active:   make(map[string]*models.Problem),
+		resolved: make(map[string]ResolvedProblem),
+	}
+}
+
+// Observe records the current problems and returns the problems that resolved
+// within the retention window, oldest resolution first. A resolved problem
+// that is detected again is active, not resolved.
+func (r *ResolvedRetention) Observe(problems []*models.Problem, now time.Time) []ResolvedProblem {
+	current := make(map[string]*models.Problem, len(problems))
+	for _, p := range problems {
+		current[p.ID] = p
+		delete(r.resolved, p.ID)
+	}
+
+	if r.window > 0 {
+		for id, p := range r.active {
+			if _, ok := current[id]; !ok {
+				r.resolved[id] = ResolvedProblem{Problem: p, ResolvedAt: now}
+			}
+		}
+	}
+	r.active = current
+
+	var out []ResolvedProblem
+	for id, rp := range r.resolved {
+		if now.Sub(rp.ResolvedAt) > r.window {
+			delete(r.resolved, id)
+			continue
+		}
+		out = append(out, rp)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].ResolvedAt.Equal(out[j].ResolvedAt) {
+			return out[i].ResolvedAt.Before(out[j].ResolvedAt)
+		}
+		return out[i].Problem.ID < out[j].Problem.ID
+	})
+	return out
+}
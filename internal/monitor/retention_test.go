@@ -0,0 +1,35 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestResolvedRetention_Recurrence(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	p := &models.Problem{ID: "oom_kill/abc"}
+	r := NewResolvedRetention(time.Minute)
+
+	if got := r.Observe([]*models.Problem{p}, now); len(got) != 0 {
+		t.Fatalf("active problem reported as resolved: %+v", got)
+	}
+	if got := r.Observe(nil, now.Add(time.Second)); len(got) != 1 || got[0].Problem != p {
+		t.Fatalf("resolved = %+v, want the cleared problem", got)
+	}
+	// Detected again: active, no longer resolved
+	if got := r.Observe([]*models.Problem{p}, now.Add(2*time.Second)); len(got) != 0 {
+		t.Errorf("recurring problem still reported as resolved: %+v", got)
+	}
+}
+
+func TestResolvedRetention_Disabled(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	r := NewResolvedRetention(0)
+
+	r.Observe([]*models.Problem{{ID: "oom_kill/abc"}}, now)
+	if got := r.Observe(nil, now); len(got) != 0 {
+		t.Errorf("zero window retained %+v", got)
+	}
+}
@@ -140,6 +140,12 @@ func SARIF(problems []*models.Problem, toolVersion string) ([]byte, error) {
 				r.Properties["history/recurringSince"] = p.History.RecurringSince
 			}
 		}
+		if !p.OriginalFirstSeen.IsZero() {
+			if r.Properties == nil {
+				r.Properties = map[string]interface{}{}
+			}
+			r.Properties["originalFirstSeen"] = p.OriginalFirstSeen
+		}
 		results = append(results, r)
 	}
 
@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// SeverityOverride raises problems of Types to Severity while Active reports
+// true. Problems already at or above Severity are left alone.
+type SeverityOverride struct {
+	Types    []string
+	Severity models.Severity
+	Active   func(now time.Time) bool // nil = always
+}
+
+// WithSeverityOverrides applies severity overrides to every problem the
+// watcher hands out. They are evaluated on read, so a scheduled override
+// starts and stops applying on time without waiting for re-detection.
+func WithSeverityOverrides(overrides []SeverityOverride) WatcherOption {
+	return func(w *Watcher) {
+		w.severityOverrides = overrides
+	}
+}
+
+// SetSeverityOverrides replaces the severity overrides (config hot-reload)
+func (w *Watcher) SetSeverityOverrides(overrides []SeverityOverride) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.severityOverrides = overrides
+}
+
+// NamespaceCriticality moves problems in namespaces matching one of Patterns
+// (shell globs, e.g. "prod-*") Levels severity levels up (positive) or down
+// (negative), so ranking reflects how much the environment matters
+type NamespaceCriticality struct {
+	Patterns []string
+	Levels   int
+}
+
+// severityLevels orders severities for NamespaceCriticality adjustments
+var severityLevels = []models.Severity{
+	models.SeverityWarning,
+	models.SeverityCritical,
+	models.SeverityFatal,
+}
+
+// WithNamespaceCriticality adjusts the severity of every problem the watcher
+// hands out by the first rule matching its namespace label. Adjustments
+// apply after severity overrides and are clamped to WARNING..FATAL.
+func WithNamespaceCriticality(rules []NamespaceCriticality) WatcherOption {
+	return func(w *Watcher) {
+		w.namespaceCriticality = rules
+	}
+}
+
+// SetNamespaceCriticality replaces the namespace rules (config hot-reload)
+func (w *Watcher) SetNamespaceCriticality(rules []NamespaceCriticality) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.namespaceCriticality = rules
+}
+
+// effectiveSeverityLocked returns p's severity after overrides active at now
+// and its namespace's criticality. Caller must hold w.mu.
+func (w *Watcher) effectiveSeverityLocked(p *models.Problem, now time.Time) models.Severity {
+	severity := p.Severity
+	for _, o := range w.severityOverrides {
+		if severity.AtLeast(o.Severity) || !o.matches(p.Type) {
+			continue
+		}
+		if o.Active == nil || o.Active(now) {
+			severity = o.Severity
+		}
+	}
+	if namespace := p.Labels["namespace"]; namespace != "" {
+		for _, rule := range w.namespaceCriticality {
+			if rule.matches(namespace) {
+				return shiftSeverity(severity, rule.Levels)
+			}
+		}
+	}
+	return severity
+}
+
+func (r NamespaceCriticality) matches(namespace string) bool {
+	for _, pattern := range r.Patterns {
+		if matched, _ := filepath.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// shiftSeverity moves s by levels, stopping at WARNING and FATAL
+func shiftSeverity(s models.Severity, levels int) models.Severity {
+	for i, level := range severityLevels {
+		if level == s {
+			i = min(max(i+levels, 0), len(severityLevels)-1)
+			return severityLevels[i]
+		}
+	}
+	return s
+}
+
+func (o SeverityOverride) matches(problemType string) bool {
+	for _, t := range o.Types {
+		if t == problemType {
+			return true
+		}
+	}
+	return false
+}
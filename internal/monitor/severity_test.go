@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestWatcher_SeverityOverrides(t *testing.T) {
+	w := newTestWatcher(1)
+	inWindow := true
+	w.SetSeverityOverrides([]SeverityOverride{{
+		Types:    []string{"imagepullbackoff"},
+		Severity: models.SeverityFatal,
+		Active:   func(time.Time) bool { return inWindow },
+	}})
+	w.updateProblems([]*models.Problem{
+		{ID: "pull", Type: "imagepullbackoff", Severity: models.SeverityCritical},
+		{ID: "oom", Type: "oom_kill", Severity: models.SeverityCritical},
+	})
+
+	severities := func() map[string]models.Severity {
+		got := make(map[string]models.Severity)
+		for _, p := range w.GetProblems() {
+			got[p.ID] = p.Severity
+		}
+		return got
+	}
+
+	got := severities()
+	if got["pull"] != models.SeverityFatal {
+		t.Errorf("inside the window: severity = %s, want FATAL", got["pull"])
+	}
+	if got["oom"] != models.SeverityCritical {
+		t.Errorf("other types are untouched: severity = %s", got["oom"])
+	}
+	if n := w.GetSummary()[models.SeverityFatal]; n != 1 {
+		t.Errorf("summary FATAL = %d, want 1", n)
+	}
+
+	// The window closes without re-detection: the default comes back
+	inWindow = false
+	if got := severities(); got["pull"] != models.SeverityCritical {
+		t.Errorf("outside the window: severity = %s, want CRITICAL", got["pull"])
+	}
+}
+
+func TestWatcher_SeverityOverridesNeverLower(t *testing.T) {
+	w := newTestWatcher(1)
+	w.SetSeverityOverrides([]SeverityOverride{{
+		Types:    []string{"crashloopbackoff"},
+		Severity: models.SeverityWarning,
+	}})
+	w.updateProblems([]*models.Problem{{ID: "crash", Type: "crashloopbackoff", Severity: models.SeverityFatal}})
+
+	if got := w.GetProblemsByCount()[0].Severity; got != models.SeverityFatal {
+		t.Errorf("severity = %s, overrides must only raise", got)
+	}
+}
+
+func TestWatcher_NamespaceCriticality(t *testing.T) {
+	w := newTestWatcher(1)
+	w.SetNamespaceCriticality([]NamespaceCriticality{
+		{Patterns: []string{"prod", "prod-*"}, Levels: 1},
+		{Patterns: []string{"dev-*"}, Levels: -1},
+	})
+	w.updateProblems([]*models.Problem{
+		{ID: "prod", Type: "crashloopbackoff", Severity: models.SeverityCritical, Labels: map[string]string{"namespace": "prod-payments"}},
+		{ID: "dev", Type: "crashloopbackoff", Severity: models.SeverityCritical, Labels: map[string]string{"namespace": "dev-payments"}},
+		{ID: "staging", Type: "crashloopbackoff", Severity: models.SeverityCritical, Labels: map[string]string{"namespace": "staging"}},
+		{ID: "node", Type: "node_not_ready", Severity: models.SeverityCritical},
+	})
+
+	got := make(map[string]models.Severity)
+	for _, p := range w.GetProblems() {
+		got[p.ID] = p.Severity
+	}
+	want := map[string]models.Severity{
+		"prod":    models.SeverityFatal,
+		"dev":     models.SeverityWarning,
+		"staging": models.SeverityCritical,
+		"node":    models.SeverityCritical, // No namespace label
+	}
+	for id, severity := range want {
+		if got[id] != severity {
+			t.Errorf("%s: severity = %s, want %s", id, got[id], severity)
+		}
+	}
+
+	// Ranking follows: the prod crash sorts first, the dev one last
+	problems := w.GetProblems()
+	if problems[0].ID != "prod" || problems[len(problems)-1].ID != "dev" {
+		t.Errorf("order = %s .. %s, want prod first and dev last", problems[0].ID, problems[len(problems)-1].ID)
+	}
+}
+
+func TestWatcher_NamespaceCriticalityAfterOverridesAndClamped(t *testing.T) {
+	w := newTestWatcher(1)
+	w.SetSeverityOverrides([]SeverityOverride{{
+		Types:    []string{"imagepullbackoff"},
+		Severity: models.SeverityFatal,
+	}})
+	w.SetNamespaceCriticality([]NamespaceCriticality{
+		{Patterns: []string{"prod"}, Levels: 2},
+		{Patterns: []string{"dev"}, Levels: -2},
+	})
+	w.updateProblems([]*models.Problem{
+		{ID: "prod", Type: "oom_kill", Severity: models.SeverityCritical, Labels: map[string]string{"namespace": "prod"}},
+		{ID: "dev", Type: "imagepullbackoff", Severity: models.SeverityWarning, Labels: map[string]string{"namespace": "dev"}},
+	})
+
+	got := make(map[string]models.Severity)
+	for _, p := range w.GetProblems() {
+		got[p.ID] = p.Severity
+	}
+	if got["prod"] != models.SeverityFatal {
+		t.Errorf("prod: severity = %s, want FATAL (clamped)", got["prod"])
+	}
+	// Raised to FATAL by the override, then two levels down
+	if got["dev"] != models.SeverityWarning {
+		t.Errorf("dev: severity = %s, want WARNING", got["dev"])
+	}
+}
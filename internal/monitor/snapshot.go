@@ -0,0 +1,99 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// Snapshot is a frozen, point-in-time set of problems for sharing
+type Snapshot struct {
+	CapturedAt time.Time         `json:"captured_at"`
+	Source     string            `json:"source"` // Sanitized Prometheus URL
+	Problems   []*models.Problem `json:"problems"`
+}
+
+// snapshotTemplate is a self-contained page: no scripts, no external assets,
+// so the file can be mailed around or opened offline
+var snapshotTemplate = template.Must(template.New("snapshot").Funcs(template.FuncMap{
+	"rfc3339": models.FormatTime,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>infranow snapshot {{rfc3339 .CapturedAt}}</title>
+<style>
+body { font-family: ui-monospace, monospace; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #ddd; vertical-align: top; }
+.FATAL { color: #b00; font-weight: bold; }
+.CRITICAL { color: #d60; font-weight: bold; }
+.WARNING { color: #a80; }
+.hint { color: #666; }
+</style>
+</head>
+<body>
+<h1>infranow snapshot</h1>
+<p>Captured {{rfc3339 .CapturedAt}} from {{.Source}}. This view is frozen and does not refresh.</p>
+{{if .Problems}}
+<table>
+<tr><th>Severity</th><th>Entity</th><th>Problem</th><th>First seen</th><th>Count</th></tr>
+{{range .Problems}}<tr>
+<td class="{{.Severity}}">{{.Severity}}</td>
+<td>{{.Entity}}</td>
+<td>{{.Title}}<br>{{.Message}}{{if .Hint}}<br><span class="hint">{{.Hint}}</span>{{end}}{{if .RunbookURL}}<br><a href="{{.RunbookURL}}">runbook</a>{{end}}{{if .QueryURL}}<br><a href="{{.QueryURL}}">query</a>{{end}}</td>
+<td>{{rfc3339 .Since}}</td>
+<td>{{.Count}}</td>
+</tr>
+{{end}}</table>
+{{else}}
+<p>No problems detected.</p>
+{{end}}
+</body>
+</html>
+`))
+
+// SnapshotHTML renders the snapshot as a standalone HTML page
+func SnapshotHTML(s *Snapshot) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := snapshotTemplate.Execute(&buf, s); err != nil {
+		return nil, fmt.Errorf("render snapshot: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SnapshotHandler serves a captured snapshot read-only: the HTML page at /
+// and the problems as JSON at /problems.json. Content is rendered once up
+// front; nothing is queried per request.
+func SnapshotHandler(s *Snapshot) (http.Handler, error) {
+	page, err := SnapshotHTML(s)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode snapshot: %w", err)
+	}
+
+	serve := func(contentType string, body []byte) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				w.Header().Set("Allow", "GET, HEAD")
+				http.Error(w, "snapshot is read-only", http.StatusMethodNotAllowed)
+				return
+			}
+			w.Header().Set("Content-Type", contentType)
+			_, _ = w.Write(body)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/{$}", serve("text/html; charset=utf-8", page))
+	mux.Handle("/problems.json", serve("application/json", data))
+	return mux, nil
+}
@@ -0,0 +1,101 @@
+package monitor
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func testSnapshot() *Snapshot {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return &Snapshot{
+		CapturedAt: now,
+		Source:     "http://prom:9090",
+		Problems: []*models.Problem{
+			{
+				ID:        "oom_kill/abc",
+				Entity:    "prod/api/<app>",
+				Type:      "oom_kill",
+				Severity:  models.SeverityFatal,
+				Title:     "Container OOMKilled",
+				FirstSeen: now.Add(-time.Minute),
+				Count:     3,
+			},
+		},
+	}
+}
+
+func TestSnapshotHandler_ServesCapturedProblems(t *testing.T) {
+	handler, err := SnapshotHandler(testSnapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/problems.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var got Snapshot
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Problems) != 1 || got.Problems[0].ID != "oom_kill/abc" {
+		t.Errorf("problems = %+v, want the captured problem", got.Problems)
+	}
+	if !got.CapturedAt.Equal(testSnapshot().CapturedAt) {
+		t.Errorf("captured_at = %s", got.CapturedAt)
+	}
+
+	resp, err = http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	page := string(body)
+	if !strings.Contains(page, "Container OOMKilled") {
+		t.Error("page should list the captured problem")
+	}
+	if !strings.Contains(page, "prod/api/&lt;app&gt;") {
+		t.Error("entity should be HTML-escaped")
+	}
+}
+
+func TestSnapshotHandler_ReadOnly(t *testing.T) {
+	handler, err := SnapshotHandler(testSnapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/problems.json", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown path status = %d, want 404", rec.Code)
+	}
+}
+
+func TestSnapshotHTML_Empty(t *testing.T) {
+	page, err := SnapshotHTML(&Snapshot{CapturedAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(page), "No problems detected") {
+		t.Error("empty snapshot should say so")
+	}
+}
@@ -0,0 +1,49 @@
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// minStableCheck bounds how often WatchStableProblems polls
+const minStableCheck = time.Second
+
+// StableAtLeast returns the problems at or above threshold that have been
+// reported continuously for at least window at now. A problem that clears
+// and comes back starts over with a new FirstSeen.
+func StableAtLeast(problems []*models.Problem, threshold models.Severity, window time.Duration, now time.Time) []*models.Problem {
+	var stable []*models.Problem
+	for _, p := range problems {
+		if p.Severity.AtLeast(threshold) && now.Sub(p.FirstSeen) >= window {
+			stable = append(stable, p)
+		}
+	}
+	return stable
+}
+
+// WatchStableProblems polls source and calls onStable once, with the
+// qualifying problems, when a problem at or above threshold has persisted for
+// window. Transient problems that clear within the window never trigger it.
+// It returns when ctx is cancelled or after onStable has been called.
+func WatchStableProblems(ctx context.Context, source func() []*models.Problem, threshold models.Severity, window time.Duration, onStable func([]*models.Problem)) {
+	interval := window / 4
+	if interval < minStableCheck {
+		interval = minStableCheck
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if stable := StableAtLeast(source(), threshold, window, now); len(stable) > 0 {
+				onStable(stable)
+				return
+			}
+		}
+	}
+}
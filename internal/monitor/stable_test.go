@@ -0,0 +1,71 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestStableAtLeast(t *testing.T) {
+	now := time.Now()
+	problems := []*models.Problem{
+		{ID: "transient", Severity: models.SeverityCritical, FirstSeen: now.Add(-30 * time.Second)},
+		{ID: "persistent", Severity: models.SeverityCritical, FirstSeen: now.Add(-10 * time.Minute)},
+		{ID: "old-warning", Severity: models.SeverityWarning, FirstSeen: now.Add(-time.Hour)},
+	}
+
+	stable := StableAtLeast(problems, models.SeverityCritical, 5*time.Minute, now)
+	if len(stable) != 1 || stable[0].ID != "persistent" {
+		t.Errorf("stable = %v, want only persistent", stable)
+	}
+	if got := StableAtLeast(problems[:1], models.SeverityCritical, 5*time.Minute, now); got != nil {
+		t.Errorf("a problem younger than the window must not count, got %v", got)
+	}
+}
+
+func TestWatchStableProblems_TransientDoesNotTrigger(t *testing.T) {
+	// The problem is reported for one poll, then clears: it is never older
+	// than the window while present
+	firstSeen := time.Now()
+	polls := 0
+	source := func() []*models.Problem {
+		polls++
+		if polls == 1 {
+			return []*models.Problem{{ID: "startup", Severity: models.SeverityFatal, FirstSeen: firstSeen}}
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+	fired := false
+	WatchStableProblems(ctx, source, models.SeverityCritical, 2*time.Second, func([]*models.Problem) {
+		fired = true
+	})
+	if fired {
+		t.Error("a problem that cleared within the window triggered --fail-on")
+	}
+}
+
+func TestWatchStableProblems_Triggers(t *testing.T) {
+	persistent := &models.Problem{ID: "disk", Severity: models.SeverityCritical, FirstSeen: time.Now().Add(-time.Hour)}
+	source := func() []*models.Problem { return []*models.Problem{persistent} }
+
+	fired := make(chan []*models.Problem, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go WatchStableProblems(ctx, source, models.SeverityCritical, 2*time.Second, func(stable []*models.Problem) {
+		fired <- stable
+	})
+
+	select {
+	case stable := <-fired:
+		if len(stable) != 1 || stable[0].ID != "disk" {
+			t.Errorf("stable = %v, want disk", stable)
+		}
+	case <-ctx.Done():
+		t.Fatal("persistent problem did not trigger")
+	}
+}
@@ -0,0 +1,64 @@
+package monitor
+
+import (
+	"context"
+	"time"
+)
+
+// minStalenessCheck bounds how often WatchDataStaleness polls
+const minStalenessCheck = time.Second
+
+// DataAge returns how long ago the last successful query completed. Before
+// any query has succeeded it is measured from watcher start, so a backend that
+// never answers still goes stale.
+func (w *Watcher) DataAge(now time.Time) time.Duration {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.lastSuccessfulQuery.IsZero() {
+		return now.Sub(w.startTime)
+	}
+	return now.Sub(w.lastSuccessfulQuery)
+}
+
+// HasData reports whether any detector query has succeeded. One-shot modes
+// use it to tell "healthy, zero problems" apart from "every query failed",
+// which also leaves the problem list empty.
+func (w *Watcher) HasData() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return !w.lastSuccessfulQuery.IsZero()
+}
+
+// IsDataStale reports whether the data is older than maxAge (0 = never stale).
+// Unlike the health check this catches a reachable backend that stopped
+// returning fresh results.
+func (w *Watcher) IsDataStale(maxAge time.Duration, now time.Time) bool {
+	return maxAge > 0 && w.DataAge(now) > maxAge
+}
+
+// WatchDataStaleness calls onStale once, with the data age, when the data
+// becomes older than maxAge. It returns when ctx is cancelled or after
+// onStale has been called.
+func (w *Watcher) WatchDataStaleness(ctx context.Context, maxAge time.Duration, onStale func(age time.Duration)) {
+	if maxAge <= 0 {
+		return
+	}
+	interval := maxAge / 4
+	if interval < minStalenessCheck {
+		interval = minStalenessCheck
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if w.IsDataStale(maxAge, now) {
+				onStale(w.DataAge(now))
+				return
+			}
+		}
+	}
+}
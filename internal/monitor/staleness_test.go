@@ -0,0 +1,107 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+)
+
+func TestIsDataStale(t *testing.T) {
+	w := newTestWatcher(0)
+	now := time.Now()
+
+	w.mu.Lock()
+	w.lastSuccessfulQuery = now.Add(-30 * time.Second)
+	w.mu.Unlock()
+
+	if w.IsDataStale(time.Minute, now) {
+		t.Error("30s old data should not be stale with a 1m limit")
+	}
+	if w.IsDataStale(0, now) {
+		t.Error("maxAge 0 disables the guard")
+	}
+
+	// LastSuccessfulQuery ages past the threshold
+	w.mu.Lock()
+	w.lastSuccessfulQuery = now.Add(-5 * time.Minute)
+	w.mu.Unlock()
+
+	if !w.IsDataStale(time.Minute, now) {
+		t.Error("5m old data should be stale with a 1m limit")
+	}
+	if age := w.DataAge(now); age != 5*time.Minute {
+		t.Errorf("DataAge = %s, want 5m", age)
+	}
+}
+
+func TestIsDataStale_NeverSucceeded(t *testing.T) {
+	w := newTestWatcher(0)
+	w.startTime = time.Now().Add(-10 * time.Minute)
+
+	if !w.IsDataStale(time.Minute, time.Now()) {
+		t.Error("no successful query since start should count as stale")
+	}
+}
+
+func TestWatchDataStaleness_Triggers(t *testing.T) {
+	w := newTestWatcher(0)
+	w.mu.Lock()
+	w.lastSuccessfulQuery = time.Now().Add(-time.Hour)
+	w.mu.Unlock()
+
+	fired := make(chan time.Duration, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	go w.WatchDataStaleness(ctx, 100*time.Millisecond, func(age time.Duration) {
+		fired <- age
+	})
+
+	select {
+	case age := <-fired:
+		if age < time.Hour {
+			t.Errorf("reported age = %s, want >= 1h", age)
+		}
+	case <-ctx.Done():
+		t.Fatal("staleness guard did not trigger")
+	}
+}
+
+func TestHasData_AllQueriesFailed(t *testing.T) {
+	// Health passed at startup, then every query errors: zero problems, but
+	// that must not read as healthy
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, errors.New("503 service unavailable")
+		},
+		HealthFunc: func(ctx context.Context) error { return nil },
+	}
+	w := NewWatcher(provider, detector.NewRegistry(), 0, 30*time.Second)
+	w.executeDetector(context.Background(), detector.NewOOMKillDetector())
+	w.executeDetector(context.Background(), detector.NewCrashLoopBackOffDetector())
+
+	if got := len(w.GetProblems()); got != 0 {
+		t.Fatalf("expected 0 problems, got %d", got)
+	}
+	if w.HasData() {
+		t.Error("HasData should be false when every query failed")
+	}
+}
+
+func TestHasData_HealthyNoProblems(t *testing.T) {
+	w := newTestWatcher(0)
+	w.executeDetector(context.Background(), detector.NewOOMKillDetector())
+
+	if got := len(w.GetProblems()); got != 0 {
+		t.Fatalf("expected 0 problems, got %d", got)
+	}
+	if !w.HasData() {
+		t.Error("HasData should be true after a successful empty query")
+	}
+}
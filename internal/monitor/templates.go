@@ -0,0 +1,20 @@
+package monitor
+
+import "github.com/ppiankov/infranow/internal/models"
+
+// WithProblemTemplates rewords detected problems by type (see
+// models.ProblemTemplates), so wording can be localized or adjusted without
+// touching detectors
+func WithProblemTemplates(templates *models.ProblemTemplates) WatcherOption {
+	return func(w *Watcher) {
+		w.problemTemplates = templates
+	}
+}
+
+// SetProblemTemplates replaces the problem templates (config hot-reload).
+// Problems already tracked keep their wording; new ones get the new templates.
+func (w *Watcher) SetProblemTemplates(templates *models.ProblemTemplates) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.problemTemplates = templates
+}
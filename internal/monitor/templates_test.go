@@ -0,0 +1,43 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestWatcher_ProblemTemplateOverridesOOMKillMessage(t *testing.T) {
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return model.Vector{&model.Sample{
+				Metric: model.Metric{"namespace": "prod", "pod": "api-0", "container": "api"},
+				Value:  3,
+			}}, nil
+		},
+	}
+	templates, err := models.CompileTemplates(map[string]models.ProblemTemplate{
+		"oom_kill": {Message: "{{.Labels.container}} in {{.Labels.namespace}}/{{.Labels.pod}} exceeded its memory limit"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWatcher(provider, detector.NewRegistry(), 0, 30*time.Second, WithProblemTemplates(templates))
+
+	w.executeDetector(context.Background(), detector.NewOOMKillDetector())
+	problems := w.GetProblems()
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+	if want := "api in prod/api-0 exceeded its memory limit"; problems[0].Message != want {
+		t.Errorf("message = %q, want %q", problems[0].Message, want)
+	}
+	if problems[0].Title != "Container OOM Killed" {
+		t.Errorf("title = %q, want the detector default", problems[0].Title)
+	}
+}
@@ -0,0 +1,100 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/util"
+)
+
+// textfileSeverities is the fixed order of severity summary gauges, so that
+// every severity is always exported (0 when absent) and output is stable.
+var textfileSeverities = []models.Severity{
+	models.SeverityFatal,
+	models.SeverityCritical,
+	models.SeverityWarning,
+}
+
+// labelEscaper escapes label values per the text exposition format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+// PrometheusTextfile renders problems in the Prometheus text exposition format
+// for node_exporter's textfile collector. Problems in resolved are exported
+// as infranow_problem_resolved with their resolution time, so scrapers still
+// see problems that cleared before the next scrape.
+func PrometheusTextfile(problems []*models.Problem, resolved []ResolvedProblem, now time.Time) []byte {
+	var b strings.Builder
+
+	b.WriteString("# HELP infranow_problem Active infrastructure problem (1 = present).\n")
+	b.WriteString("# TYPE infranow_problem gauge\n")
+
+	// Problems sharing severity/entity/type would produce duplicate series
+	seen := make(map[string]bool)
+	var lines []string
+	for _, p := range problems {
+		line := fmt.Sprintf("infranow_problem{%s} 1\n", problemLabels(p))
+		if seen[line] {
+			continue
+		}
+		seen[line] = true
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+	for _, line := range lines {
+		b.WriteString(line)
+	}
+
+	if len(resolved) > 0 {
+		b.WriteString("# HELP infranow_problem_resolved Recently resolved problem (value = Unix time it resolved).\n")
+		b.WriteString("# TYPE infranow_problem_resolved gauge\n")
+
+		// Latest resolution wins when several resolved problems share a series
+		latest := make(map[string]int64)
+		for _, rp := range resolved {
+			labels := problemLabels(rp.Problem)
+			if ts := rp.ResolvedAt.Unix(); ts > latest[labels] {
+				latest[labels] = ts
+			}
+		}
+		keys := make([]string, 0, len(latest))
+		for labels := range latest {
+			keys = append(keys, labels)
+		}
+		sort.Strings(keys)
+		for _, labels := range keys {
+			fmt.Fprintf(&b, "infranow_problem_resolved{%s,resolved=\"1\"} %d\n", labels, latest[labels])
+		}
+	}
+
+	counts := make(map[models.Severity]int)
+	for _, p := range problems {
+		counts[p.Severity]++
+	}
+
+	b.WriteString("# HELP infranow_problems Number of active problems by severity.\n")
+	b.WriteString("# TYPE infranow_problems gauge\n")
+	for _, sev := range textfileSeverities {
+		fmt.Fprintf(&b, "infranow_problems{severity=\"%s\"} %d\n", string(sev), counts[sev])
+	}
+
+	b.WriteString("# HELP infranow_last_run_timestamp_seconds Unix time of the last completed detection cycle.\n")
+	b.WriteString("# TYPE infranow_last_run_timestamp_seconds gauge\n")
+	fmt.Fprintf(&b, "infranow_last_run_timestamp_seconds %d\n", now.Unix())
+
+	return []byte(b.String())
+}
+
+// problemLabels renders the escaped severity/entity/type label pairs of p
+func problemLabels(p *models.Problem) string {
+	return fmt.Sprintf("severity=\"%s\",entity=\"%s\",type=\"%s\"",
+		labelEscaper.Replace(string(p.Severity)), labelEscaper.Replace(p.Entity), labelEscaper.Replace(p.Type))
+}
+
+// WriteTextfile atomically replaces path with data, so node_exporter never
+// reads a partially written file
+func WriteTextfile(path string, data []byte) error {
+	return util.WriteFileAtomic(path, data, 0o644)
+}
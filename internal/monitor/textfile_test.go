@@ -0,0 +1,185 @@
+package monitor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestPrometheusTextfile_ValidExposition(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	problems := []*models.Problem{
+		{Severity: models.SeverityCritical, Entity: "prod/api", Type: "OOMKill"},
+		{Severity: models.SeverityCritical, Entity: "prod/api", Type: "OOMKill"}, // duplicate series
+		{Severity: models.SeverityWarning, Entity: `odd "name"\path` + "\nline", Type: "DiskSpace"},
+	}
+
+	data := PrometheusTextfile(problems, nil, now)
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("invalid exposition format: %v\n%s", err, data)
+	}
+
+	problem, ok := families["infranow_problem"]
+	if !ok {
+		t.Fatal("missing infranow_problem family")
+	}
+	if got := len(problem.GetMetric()); got != 2 {
+		t.Errorf("infranow_problem series = %d, want 2 (duplicates collapsed)", got)
+	}
+	entities := make(map[string]bool)
+	for _, m := range problem.GetMetric() {
+		if m.GetGauge().GetValue() != 1 {
+			t.Errorf("infranow_problem value = %v, want 1", m.GetGauge().GetValue())
+		}
+		for _, l := range m.GetLabel() {
+			if l.GetName() == "entity" {
+				entities[l.GetValue()] = true
+			}
+		}
+	}
+	if !entities[`odd "name"\path`+"\nline"] {
+		t.Errorf("escaped entity label did not round-trip: %v", entities)
+	}
+
+	summary, ok := families["infranow_problems"]
+	if !ok {
+		t.Fatal("missing infranow_problems family")
+	}
+	counts := make(map[string]float64)
+	for _, m := range summary.GetMetric() {
+		counts[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+	}
+	want := map[string]float64{"FATAL": 0, "CRITICAL": 2, "WARNING": 1}
+	for sev, n := range want {
+		if counts[sev] != n {
+			t.Errorf("infranow_problems{severity=%q} = %v, want %v", sev, counts[sev], n)
+		}
+	}
+
+	ts := families["infranow_last_run_timestamp_seconds"]
+	if ts == nil || ts.GetMetric()[0].GetGauge().GetValue() != float64(now.Unix()) {
+		t.Errorf("infranow_last_run_timestamp_seconds missing or wrong")
+	}
+}
+
+func TestPrometheusTextfile_Empty(t *testing.T) {
+	data := PrometheusTextfile(nil, nil, time.Now())
+
+	var parser expfmt.TextParser
+	if _, err := parser.TextToMetricFamilies(bytes.NewReader(data)); err != nil {
+		t.Fatalf("invalid exposition format: %v", err)
+	}
+	if !strings.Contains(string(data), `infranow_problems{severity="CRITICAL"} 0`) {
+		t.Errorf("expected zero-valued summary gauges, got:\n%s", data)
+	}
+	if strings.Contains(string(data), "infranow_problem_resolved") {
+		t.Errorf("resolved family should be omitted when nothing resolved, got:\n%s", data)
+	}
+}
+
+func TestPrometheusTextfile_ResolvedRetained(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	flapping := &models.Problem{ID: "oom_kill/abc", Severity: models.SeverityFatal, Entity: "prod/api", Type: "oom_kill"}
+	retention := NewResolvedRetention(5 * time.Minute)
+
+	retention.Observe([]*models.Problem{flapping}, start)
+	resolvedAt := start.Add(10 * time.Second)
+
+	// The problem cleared before the next scrape; it stays scrapeable for
+	// the whole retention window
+	for _, scrape := range []time.Duration{0, time.Minute, 5 * time.Minute} {
+		now := resolvedAt.Add(scrape)
+		data := PrometheusTextfile(nil, retention.Observe(nil, now), now)
+
+		var parser expfmt.TextParser
+		families, err := parser.TextToMetricFamilies(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("invalid exposition format: %v\n%s", err, data)
+		}
+		family := families["infranow_problem_resolved"]
+		if family == nil || len(family.GetMetric()) != 1 {
+			t.Fatalf("after %s: want one resolved series, got:\n%s", scrape, data)
+		}
+		m := family.GetMetric()[0]
+		if m.GetGauge().GetValue() != float64(resolvedAt.Unix()) {
+			t.Errorf("after %s: resolved value = %v, want %d", scrape, m.GetGauge().GetValue(), resolvedAt.Unix())
+		}
+		labels := make(map[string]string)
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		if labels["resolved"] != "1" || labels["entity"] != "prod/api" || labels["type"] != "oom_kill" {
+			t.Errorf("after %s: labels = %v", scrape, labels)
+		}
+		if _, ok := families["infranow_problem"]; ok && len(families["infranow_problem"].GetMetric()) > 0 {
+			t.Errorf("after %s: resolved problem must not be exported as active", scrape)
+		}
+	}
+
+	// Past the window it is gone
+	later := resolvedAt.Add(5*time.Minute + time.Second)
+	data := PrometheusTextfile(nil, retention.Observe(nil, later), later)
+	if strings.Contains(string(data), "infranow_problem_resolved") {
+		t.Errorf("resolved problem outlived the retention window:\n%s", data)
+	}
+}
+
+func TestWriteTextfile_Atomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "infranow.prom")
+
+	if err := os.WriteFile(path, []byte("old content\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Hold the old file open: an atomic replace leaves this reader on the
+	// old inode instead of exposing a truncated or half-written file
+	old, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = old.Close() }()
+
+	data := []byte("infranow_problems{severity=\"WARNING\"} 1\n")
+	if err := WriteTextfile(path, data); err != nil {
+		t.Fatalf("WriteTextfile() error = %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("file content = %q, want %q", got, data)
+	}
+
+	oldContent := make([]byte, 64)
+	n, _ := old.Read(oldContent)
+	if string(oldContent[:n]) != "old content\n" {
+		t.Errorf("open reader saw %q, want untouched old content", oldContent[:n])
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the target file, found %d entries (temp file left behind?)", len(entries))
+	}
+}
+
+func TestWriteTextfile_MissingDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "infranow.prom")
+	if err := WriteTextfile(path, []byte("x 1\n")); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
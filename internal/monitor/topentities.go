@@ -0,0 +1,87 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// Groupings for TopEntities
+const (
+	GroupByEntity    = "entity"
+	GroupByNode      = "node"
+	GroupByNamespace = "namespace"
+)
+
+// EntityRank is the problem load of one entity, node or namespace
+type EntityRank struct {
+	Name     string          `json:"name"`
+	Problems int             `json:"problems"`
+	Score    float64         `json:"score"` // Sum of problem scores
+	Worst    models.Severity `json:"worst_severity"`
+	Types    []string        `json:"types"` // Distinct problem types, sorted
+}
+
+// ValidGrouping reports whether by is a TopEntities grouping
+func ValidGrouping(by string) error {
+	switch by {
+	case GroupByEntity, GroupByNode, GroupByNamespace:
+		return nil
+	default:
+		return fmt.Errorf("unknown grouping %q (use %s, %s or %s)", by, GroupByEntity, GroupByNode, GroupByNamespace)
+	}
+}
+
+// TopEntities groups problems across types by entity, node or namespace label
+// and ranks the groups by summed score, then problem count, then name. At
+// most top groups are returned (top <= 0 = all). Problems without the
+// grouping label are skipped.
+func TopEntities(problems []*models.Problem, by string, top int) []EntityRank {
+	groups := make(map[string]*EntityRank)
+	types := make(map[string]map[string]bool)
+	for _, p := range problems {
+		name := p.Entity
+		if by != GroupByEntity {
+			name = p.Labels[by]
+		}
+		if name == "" {
+			continue
+		}
+
+		rank, ok := groups[name]
+		if !ok {
+			rank = &EntityRank{Name: name, Worst: p.Severity}
+			groups[name] = rank
+			types[name] = make(map[string]bool)
+		}
+		rank.Problems++
+		rank.Score += p.Score()
+		if p.Severity.AtLeast(rank.Worst) {
+			rank.Worst = p.Severity
+		}
+		types[name][p.Type] = true
+	}
+
+	ranked := make([]EntityRank, 0, len(groups))
+	for name, rank := range groups {
+		for t := range types[name] {
+			rank.Types = append(rank.Types, t)
+		}
+		sort.Strings(rank.Types)
+		ranked = append(ranked, *rank)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		if ranked[i].Problems != ranked[j].Problems {
+			return ranked[i].Problems > ranked[j].Problems
+		}
+		return ranked[i].Name < ranked[j].Name
+	})
+	if top > 0 && len(ranked) > top {
+		ranked = ranked[:top]
+	}
+	return ranked
+}
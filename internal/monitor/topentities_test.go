@@ -0,0 +1,59 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestTopEntities_RanksBySummedScore(t *testing.T) {
+	problems := []*models.Problem{
+		{Entity: "prod/api", Type: "oom_kill", Severity: models.SeverityCritical, Labels: map[string]string{"namespace": "prod", "node": "n1"}},
+		{Entity: "prod/api", Type: "restart_rate", Severity: models.SeverityWarning, Labels: map[string]string{"namespace": "prod", "node": "n1"}},
+		{Entity: "prod/web", Type: "crashloop", Severity: models.SeverityFatal, Labels: map[string]string{"namespace": "prod", "node": "n2"}},
+		{Entity: "dev/job", Type: "pending", Severity: models.SeverityWarning, Labels: map[string]string{"namespace": "dev"}},
+	}
+
+	ranked := TopEntities(problems, GroupByEntity, 0)
+	if len(ranked) != 3 {
+		t.Fatalf("expected 3 entities, got %d", len(ranked))
+	}
+	// Scores add up: a CRITICAL and a WARNING outrank a single FATAL
+	if ranked[0].Name != "prod/api" || ranked[0].Problems != 2 {
+		t.Errorf("first = %+v, want prod/api with 2 problems", ranked[0])
+	}
+	if ranked[0].Worst != models.SeverityCritical {
+		t.Errorf("worst = %s, want CRITICAL", ranked[0].Worst)
+	}
+	if len(ranked[0].Types) != 2 || ranked[0].Types[0] != "oom_kill" {
+		t.Errorf("types = %v", ranked[0].Types)
+	}
+	if ranked[2].Name != "dev/job" {
+		t.Errorf("last = %s, want dev/job", ranked[2].Name)
+	}
+}
+
+func TestTopEntities_GroupByLabelAndLimit(t *testing.T) {
+	problems := []*models.Problem{
+		{Entity: "a", Severity: models.SeverityWarning, Labels: map[string]string{"node": "n1"}},
+		{Entity: "b", Severity: models.SeverityWarning, Labels: map[string]string{"node": "n1"}},
+		{Entity: "c", Severity: models.SeverityWarning, Labels: map[string]string{"node": "n2"}},
+		{Entity: "d", Severity: models.SeverityFatal}, // No node label
+	}
+
+	ranked := TopEntities(problems, GroupByNode, 1)
+	if len(ranked) != 1 || ranked[0].Name != "n1" || ranked[0].Problems != 2 {
+		t.Errorf("ranked = %+v, want only n1 with 2 problems", ranked)
+	}
+}
+
+func TestValidGrouping(t *testing.T) {
+	for _, by := range []string{GroupByEntity, GroupByNode, GroupByNamespace} {
+		if err := ValidGrouping(by); err != nil {
+			t.Errorf("ValidGrouping(%q) = %v", by, err)
+		}
+	}
+	if err := ValidGrouping("pod"); err == nil {
+		t.Error("expected error for unknown grouping")
+	}
+}
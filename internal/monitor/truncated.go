@@ -0,0 +1,37 @@
+package monitor
+
+import (
+	"fmt"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// TruncatedProblemType is the type of the data-quality problem reported for a
+// detector whose query results the backend truncated
+const TruncatedProblemType = "query_results_truncated"
+
+// truncatedProblem reports that the backend warned some of a detector's
+// query results are incomplete, so the detector may be missing problems.
+// warnings must not be empty.
+func truncatedProblem(detectorName string, warnings []metrics.QueryWarning) *models.Problem {
+	queries := make(map[string]bool, len(warnings))
+	for _, w := range warnings {
+		queries[w.Query] = true
+	}
+	return &models.Problem{
+		Entity:     "detector/" + detectorName,
+		EntityType: "detector",
+		Type:       TruncatedProblemType,
+		Severity:   models.SeverityWarning,
+		Title:      "Query results truncated",
+		Message: fmt.Sprintf("The metrics backend returned incomplete results for %d %s queries (%q); its problems may be incomplete",
+			len(queries), detectorName, warnings[0].Warning),
+		Labels:      map[string]string{"detector": detectorName},
+		Metrics:     map[string]float64{"truncated_queries": float64(len(queries))},
+		Query:       warnings[0].Query,
+		Hint:        "Raise the backend's series or sample limits, or check for unavailable store or ingester components answering with partial results",
+		RunbookURL:  models.RunbookBaseURL + "query_results_truncated.md",
+		BlastRadius: 1,
+	}
+}
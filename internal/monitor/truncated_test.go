@@ -0,0 +1,53 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestDetect_TruncationWarningReportsProblem(t *testing.T) {
+	provider := &metrics.MockProvider{Warnings: []string{"results truncated due to limit"}}
+	w := NewWatcher(provider, detector.NewRegistry(), 0, 30*time.Second)
+
+	problems, ok := w.detect(context.Background(), detector.NewEtcdHealthDetector(), provider, "")
+	if !ok {
+		t.Fatal("detect failed")
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+
+	p := problems[0]
+	if p.Type != TruncatedProblemType || p.Severity != models.SeverityWarning {
+		t.Errorf("got %s %s, want WARNING %s", p.Severity, p.Type, TruncatedProblemType)
+	}
+	if p.Entity != "detector/etcd_health" || p.Labels["detector"] != "etcd_health" || p.ID == "" {
+		t.Errorf("entity %q labels %v id %q", p.Entity, p.Labels, p.ID)
+	}
+	// etcd_health issues three queries, each truncated
+	if p.Metrics["truncated_queries"] != 3 || p.Query == "" {
+		t.Errorf("metrics %v query %q", p.Metrics, p.Query)
+	}
+	want := `The metrics backend returned incomplete results for 3 etcd_health queries ("results truncated due to limit"); its problems may be incomplete`
+	if p.Message != want {
+		t.Errorf("message = %q, want %q", p.Message, want)
+	}
+}
+
+func TestDetect_InformationalWarningIgnored(t *testing.T) {
+	provider := &metrics.MockProvider{Warnings: []string{`PromQL info: metric might not be a counter, name does not end in _total/_sum/_count/_bucket: "etcd_server_leader_changes_seen"`}}
+	w := NewWatcher(provider, detector.NewRegistry(), 0, 30*time.Second)
+
+	problems, ok := w.detect(context.Background(), detector.NewEtcdHealthDetector(), provider, "")
+	if !ok {
+		t.Fatal("detect failed")
+	}
+	if len(problems) != 0 {
+		t.Errorf("expected no problems for an informational warning, got %+v", problems[0])
+	}
+}
@@ -1,8 +1,10 @@
 package monitor
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"os"
 	"os/exec"
 	"runtime"
 	"strings"
@@ -13,6 +15,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/ppiankov/infranow/internal/baseline"
+	"github.com/ppiankov/infranow/internal/filter"
 	"github.com/ppiankov/infranow/internal/models"
 	"github.com/ppiankov/infranow/internal/util"
 )
@@ -24,17 +28,31 @@ const (
 	SortBySeverity SortMode = iota
 	SortByRecency
 	SortByCount
+	SortByBlastRadius
+	SortByAge
+
+	sortModeCount = iota // Number of sort modes, for cycling
+)
+
+// ViewMode determines how densely problems are listed
+type ViewMode int
+
+const (
+	// ViewDetailed shows the title column and a detail panel for the selection
+	ViewDetailed ViewMode = iota
+	// ViewCompact shows one aligned row per problem and no detail panel
+	ViewCompact
 )
 
 // Layout constants for terminal space allocation
 const (
 	headerLines    = 4 // title, prom info, status, separator
-	footerLines    = 2 // separator + help text
-	detailLines    = 7 // detail panel height
-	detailMinLines = 3 // compact detail for small terminals
+	compactHeader  = 1 // single status line for short terminals
 	separatorLines = 1 // between table and detail
 	minTableHeight = 3
 	smallTerminal  = 20 // below this, use compact detail
+	defaultHeight  = 24 // assumed until the terminal reports its size
+	shortTerminal  = 16 // below this, collapse the header to one line
 
 	// Column widths
 	numColWidth      = 3
@@ -45,6 +63,11 @@ const (
 	entityColDefault = 30
 	colPadding       = 10 // total padding between columns
 
+	// Compact view columns
+	typeColWidth      = 22
+	countColWidth     = 5
+	compactColPadding = 12
+
 	// promStaleThreshold triggers a warning if no successful query in this duration
 	promStaleThreshold = 2 * time.Minute
 )
@@ -57,6 +80,40 @@ func (s SortMode) String() string {
 		return "recency"
 	case SortByCount:
 		return "count"
+	case SortByBlastRadius:
+		return "blast-radius"
+	case SortByAge:
+		return "oldest"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseSortMode parses a sort mode name as printed by String. "score" is
+// accepted for severity: scores are banded by severity, so both orders match.
+func ParseSortMode(s string) (SortMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "severity", "score":
+		return SortBySeverity, nil
+	case "recency":
+		return SortByRecency, nil
+	case "count":
+		return SortByCount, nil
+	case "blast-radius":
+		return SortByBlastRadius, nil
+	case "oldest":
+		return SortByAge, nil
+	default:
+		return 0, fmt.Errorf("unknown sort mode %q (use severity, recency, count, blast-radius, oldest or score)", s)
+	}
+}
+
+func (v ViewMode) String() string {
+	switch v {
+	case ViewDetailed:
+		return "detailed"
+	case ViewCompact:
+		return "compact"
 	default:
 		return "unknown"
 	}
@@ -66,11 +123,13 @@ func (s SortMode) String() string {
 type Model struct {
 	watcher         *Watcher
 	prometheusURL   string
+	hideSource      bool // --redact-entities: the header leaves out the URL
 	refreshInterval time.Duration
 	portForward     *util.PortForward
 
 	problems      []*models.Problem
 	sortMode      SortMode
+	viewMode      ViewMode
 	paused        bool
 	tbl           table.Model
 	searchMode    bool
@@ -78,19 +137,122 @@ type Model struct {
 	filteredCount int
 	statusMsg     string
 
+	// drillStack scopes the list to co-located problems (empty = all)
+	drillStack []drillScope
+
+	// mark lists only changes since it was set (nil = show all); markChanges
+	// classifies the listed problems by ID
+	mark        *snapshotMark
+	markChanges map[string]markChange
+
+	// compareTo annotates the full list against a saved baseline while no
+	// mark is set (nil = off); changes land in markChanges
+	compareTo *baseline.Baseline
+
+	// pinned problem IDs, listed first in any sort order for the session
+	pinned map[string]bool
+
+	// mutes hides problems for a chosen duration (nil = muting disabled);
+	// muteTarget is the problem awaiting a duration after M (nil = none)
+	mutes      *filter.Mutes
+	muteTarget *models.Problem
+	muteKey    func(*models.Problem) string // ID a listed problem is muted by
+
+	// showDiagnostic replaces the list with what the watcher is seeing
+	showDiagnostic bool
+
+	// filter is applied to watcher problems before display (nil = show all)
+	filter func([]*models.Problem) []*models.Problem
+
+	// maxDataStaleness raises a header alarm when data is older (0 = off)
+	maxDataStaleness time.Duration
+
+	// fixedWidth overrides the reported terminal width (0 = use reported)
+	fixedWidth int
+
 	width  int
 	height int
 	ready  bool
 }
 
-type tickMsg time.Time
+// ModelOption configures optional Model behavior
+type ModelOption func(*Model)
 
-type updateMsg struct {
-	problems []*models.Problem
+// WithProblemFilter applies fn to the problem list on every refresh.
+// fn is called each time so filters that change at runtime (config hot-reload)
+// take effect without restarting the TUI.
+func WithProblemFilter(fn func([]*models.Problem) []*models.Problem) ModelOption {
+	return func(m *Model) {
+		m.filter = fn
+	}
 }
 
+// WithMutes enables muting the selected problem with M. Muted problems are
+// hidden from the list and counts until their mute expires; share mutes with
+// the notification filters so they stay quiet too.
+func WithMutes(mutes *filter.Mutes) ModelOption {
+	return func(m *Model) {
+		m.mutes = mutes
+	}
+}
+
+// WithMuteKey sets the ID listed problems are muted by, for filters that
+// hand the TUI copies with other IDs (e.g. redacted): mutes must use the IDs
+// the notification filters see. Default: the problem's own ID.
+func WithMuteKey(key func(*models.Problem) string) ModelOption {
+	return func(m *Model) {
+		m.muteKey = key
+	}
+}
+
+// WithMaxDataStaleness shows a prominent alarm when no fresh data has been
+// received for longer than maxAge, even if health checks still pass.
+func WithMaxDataStaleness(maxAge time.Duration) ModelOption {
+	return func(m *Model) {
+		m.maxDataStaleness = maxAge
+	}
+}
+
+// WithFixedWidth lays out the TUI at width columns regardless of the size the
+// terminal reports. The model renders immediately instead of waiting for a
+// WindowSizeMsg, which never arrives without a real TTY (tmux capture, CI).
+func WithFixedWidth(width int) ModelOption {
+	return func(m *Model) {
+		m.fixedWidth = width
+	}
+}
+
+// WithBaseline compares the live list to b on every refresh: new and
+// escalated problems are marked, unchanged ones listed as usual, and problems
+// resolved since b listed last
+func WithBaseline(b *baseline.Baseline) ModelOption {
+	return func(m *Model) {
+		m.compareTo = b
+	}
+}
+
+// WithHiddenSource leaves the Prometheus URL out of the header, as redacted
+// JSON leaves it out of its metadata
+func WithHiddenSource() ModelOption {
+	return func(m *Model) {
+		m.hideSource = true
+	}
+}
+
+// WithSortMode sets the initial sort order (default SortBySeverity)
+func WithSortMode(mode SortMode) ModelOption {
+	return func(m *Model) {
+		m.sortMode = mode
+	}
+}
+
+type tickMsg time.Time
+
+// updateMsg signals that the watcher finished a detection cycle
+type updateMsg struct{}
+
 // NewModel creates a new TUI model
-func NewModel(watcher *Watcher, prometheusURL string, refreshInterval time.Duration, portForward *util.PortForward) Model {
+func NewModel(watcher *Watcher, prometheusURL string, refreshInterval time.Duration, portForward *util.PortForward, opts ...ModelOption) Model {
 	cols := computeColumns(80)
 	t := table.New(
 		table.WithColumns(cols),
@@ -101,7 +263,7 @@ func NewModel(watcher *Watcher, prometheusURL string, refreshInterval time.Durat
 		table.WithStyles(infranowTableStyles()),
 	)
 
-	return Model{
+	m := Model{
 		watcher:         watcher,
 		prometheusURL:   prometheusURL,
 		refreshInterval: refreshInterval,
@@ -110,6 +272,17 @@ func NewModel(watcher *Watcher, prometheusURL string, refreshInterval time.Durat
 		sortMode:        SortBySeverity,
 		tbl:             t,
 	}
+
+	for _, opt := range opts {
+		opt(&m)
+	}
+
+	if m.fixedWidth > 0 {
+		sized, _ := m.handleResize(tea.WindowSizeMsg{Width: m.fixedWidth, Height: defaultHeight})
+		m = sized.(Model)
+	}
+
+	return m
 }
 
 func infranowTableKeyMap() table.KeyMap {
@@ -152,6 +325,23 @@ func computeColumns(width int) []table.Column {
 	}
 }
 
+// computeCompactColumns lays out the compact view: the entity takes whatever
+// the fixed-width columns leave
+func computeCompactColumns(width int) []table.Column {
+	entityWidth := width - numColWidth - sevColWidth - typeColWidth - countColWidth - ageColWidth - compactColPadding
+	if entityWidth < entityColMin {
+		entityWidth = entityColMin
+	}
+	return []table.Column{
+		{Title: "#", Width: numColWidth},
+		{Title: "SEV", Width: sevColWidth},
+		{Title: "ENTITY", Width: entityWidth},
+		{Title: "TYPE", Width: typeColWidth},
+		{Title: "COUNT", Width: countColWidth},
+		{Title: "AGE", Width: ageColWidth},
+	}
+}
+
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
@@ -162,11 +352,24 @@ func (m Model) Init() tea.Cmd {
 
 // Update handles messages
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	// Any message may change the header, footer or detail panel height
+	if nm, ok := next.(Model); ok && nm.ready {
+		nm.fitTable()
+		return nm, cmd
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if m.searchMode {
 			return m.handleSearchKey(msg)
 		}
+		if m.muteTarget != nil {
+			return m.handleMuteKey(msg)
+		}
 		return m.handleNormalKey(msg)
 
 	case tea.WindowSizeMsg:
@@ -180,8 +383,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tickCmd(m.refreshInterval)
 
 	case updateMsg:
-		m.problems = msg.problems
-		m.rebuildTableRows()
+		// Rebuild through updateProblems so the sort order and search stay
+		m.updateProblems()
 		return m, waitForUpdate(m.watcher)
 	}
 
@@ -197,14 +400,40 @@ func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "p", " ":
 		m.paused = !m.paused
 	case "s":
-		m.sortMode = (m.sortMode + 1) % 3
+		m.sortMode = (m.sortMode + 1) % sortModeCount
 		m.updateProblems()
+	case "m":
+		m.statusMsg = m.toggleMark()
+	case "f":
+		m.statusMsg = m.togglePin()
+	case "M":
+		m.statusMsg = m.startMute()
+	case "U":
+		m.statusMsg = m.unmuteAll()
+	case "v":
+		m.viewMode = (m.viewMode + 1) % 2
+		m.applyColumns()
+		m.statusMsg = "View: " + m.viewMode.String()
 	case "/":
 		m.searchMode = true
 		m.searchQuery = ""
+	case "d":
+		m.showDiagnostic = !m.showDiagnostic
 	case "esc":
+		if m.showDiagnostic {
+			m.showDiagnostic = false
+			break
+		}
+		if m.searchQuery == "" && len(m.drillStack) > 0 {
+			m.drillUp()
+			break
+		}
 		m.searchQuery = ""
 		m.updateProblems()
+	case "enter":
+		m.statusMsg = m.drillIntoSelected()
+	case "backspace":
+		m.drillUp()
 	case "r":
 		if m.portForward != nil {
 			go func() {
@@ -217,6 +446,8 @@ func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.statusMsg = m.copySelectedProblem()
 	case "y":
 		m.statusMsg = m.yankSelectedEntity()
+	case "Y":
+		m.statusMsg = m.yankSelectedJSON()
 	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
 		m.jumpToRow(int(msg.String()[0] - '0'))
 	default:
@@ -251,25 +482,47 @@ func (m Model) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 func (m Model) handleResize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
 	m.width = msg.Width
+	if m.fixedWidth > 0 {
+		m.width = m.fixedWidth
+	}
 	m.height = msg.Height
 
-	cols := computeColumns(msg.Width)
-	m.tbl.SetColumns(cols)
-	m.tbl.SetWidth(msg.Width)
+	m.tbl.SetWidth(m.width)
+	m.ready = true
+	m.applyColumns()
+	m.fitTable()
+	return m, nil
+}
+
+// applyColumns lays out the table columns for the current width and view
+// mode, then rebuilds the rows to match
+func (m *Model) applyColumns() {
+	// Drop rows first: the table re-renders on SetColumns and rows built
+	// for the other view have a different number of cells
+	m.tbl.SetRows(nil)
+	if m.viewMode == ViewCompact {
+		m.tbl.SetColumns(computeCompactColumns(m.width))
+	} else {
+		m.tbl.SetColumns(computeColumns(m.width))
+	}
+	m.rebuildTableRows()
+}
 
-	detailHeight := detailLines
-	if msg.Height < smallTerminal {
-		detailHeight = detailMinLines
+// fitTable gives the table every row not used by the rendered header, footer
+// and detail panel. Their heights vary with terminal size, search mode,
+// status messages and the selected problem, so they are measured rather
+// than assumed.
+func (m *Model) fitTable() {
+	chrome := lipgloss.Height(m.renderHeader()) + lipgloss.Height(m.renderFooter())
+	if m.viewMode == ViewDetailed {
+		chrome += separatorLines + lipgloss.Height(m.renderDetailPanel())
 	}
-	tableHeight := msg.Height - headerLines - footerLines - separatorLines - detailHeight
+
+	tableHeight := m.height - chrome
 	if tableHeight < minTableHeight {
 		tableHeight = minTableHeight
 	}
 	m.tbl.SetHeight(tableHeight)
-
-	m.ready = true
-	m.rebuildTableRows()
-	return m, nil
 }
 
 // View renders the TUI
@@ -283,14 +536,19 @@ func (m Model) View() string {
 	b.WriteString(m.renderHeader())
 	b.WriteString("\n")
 
-	if len(m.problems) == 0 {
+	switch {
+	case m.showDiagnostic:
+		b.WriteString(m.renderDiagnostic())
+	case len(m.problems) == 0:
 		b.WriteString(m.renderEmptyState())
-	} else {
+	default:
 		b.WriteString(m.tbl.View())
-		b.WriteString("\n")
-		b.WriteString(strings.Repeat("─", m.width))
-		b.WriteString("\n")
-		b.WriteString(m.renderDetailPanel())
+		if m.viewMode == ViewDetailed {
+			b.WriteString("\n")
+			b.WriteString(rule(m.width))
+			b.WriteString("\n")
+			b.WriteString(m.renderDetailPanel())
+		}
 	}
 
 	b.WriteString("\n")
@@ -308,18 +566,55 @@ func (m Model) selectedProblem() *models.Problem {
 	return m.problems[idx]
 }
 
+// filteredProblems returns the watcher problems in sort order after the
+// problem filter, before marks, drill-down and search
+func (m *Model) filteredProblems() []*models.Problem {
+	problems := m.watcher.ProblemsBy(m.sortMode)
+	if m.filter != nil {
+		problems = m.filter(problems)
+	}
+	if m.mutes != nil {
+		problems, _ = m.mutes.SplitBy(problems, m.muteID)
+	}
+	return problems
+}
+
+// muteID returns the ID p is muted by
+func (m *Model) muteID(p *models.Problem) string {
+	if m.muteKey != nil {
+		return m.muteKey(p)
+	}
+	return p.ID
+}
+
+// toggleMark captures the current problems as an in-memory baseline, or
+// clears it, and returns a status message
+func (m *Model) toggleMark() string {
+	if m.mark != nil {
+		m.mark = nil
+		m.markChanges = nil
+		m.updateProblems()
+		return "Mark cleared"
+	}
+	problems := m.filteredProblems()
+	m.mark = newSnapshotMark(problems, time.Now())
+	m.updateProblems()
+	return fmt.Sprintf("Marked %d problems; showing changes since %s (m: clear)",
+		len(problems), models.DisplayTime(m.mark.at).Format(time.TimeOnly))
+}
+
 func (m *Model) updateProblems() {
-	var allProblems []*models.Problem
-	switch m.sortMode {
-	case SortBySeverity:
-		allProblems = m.watcher.GetProblems()
-	case SortByRecency:
-		allProblems = m.watcher.GetProblemsByRecency()
-	case SortByCount:
-		allProblems = m.watcher.GetProblemsByCount()
+	allProblems := m.filteredProblems()
+	switch {
+	case m.mark != nil:
+		allProblems, m.markChanges = m.mark.diff(allProblems)
+	case m.compareTo != nil:
+		allProblems, m.markChanges = compareToBaseline(allProblems, m.compareTo)
 	}
+	allProblems = drillDownFilter(allProblems, m.drillStack)
 
 	m.watcher.AnnotateHistory(allProblems)
+	m.watcher.AnnotateObservations(allProblems)
 
 	if m.searchQuery != "" {
 		filtered := make([]*models.Problem, 0)
@@ -339,6 +634,7 @@ func (m *Model) updateProblems() {
 		m.problems = allProblems
 		m.filteredCount = 0
 	}
+	m.problems = pinFirst(m.problems, m.pinned)
 
 	m.rebuildTableRows()
 }
@@ -348,6 +644,21 @@ func (m *Model) rebuildTableRows() {
 	now := time.Now()
 	cols := m.tbl.Columns()
 
+	if m.viewMode == ViewCompact {
+		for i, p := range m.problems {
+			rows[i] = table.Row{
+				fmt.Sprintf("%d", i+1),
+				m.severityCell(p),
+				truncate(p.Entity, cols[2].Width),
+				truncate(p.Type, cols[3].Width),
+				fmt.Sprintf("%d", p.Count),
+				humanAge(now.Sub(p.Since())),
+			}
+		}
+		m.tbl.SetRows(rows)
+		return
+	}
+
 	// Determine entity and title widths from current columns
 	entityWidth := entityColDefault
 	titleWidth := titleColMin
@@ -359,15 +670,33 @@ func (m *Model) rebuildTableRows() {
 	for i, p := range m.problems {
 		rows[i] = table.Row{
 			fmt.Sprintf("%d", i+1),
-			shortSeverity(p.Severity),
+			m.severityCell(p),
 			truncate(p.Entity, entityWidth),
 			truncate(p.Title, titleWidth),
-			humanAge(now.Sub(p.FirstSeen)),
+			humanAge(now.Sub(p.Since())),
 		}
 	}
 	m.tbl.SetRows(rows)
 }
 
+// severityCell renders the severity, prefixed with the change since the mark
+// or baseline (+ new, - resolved, ↑ escalated) while one is set, with
+// allowedMarker when
+// the problem is allow-listed and with pinMarker when it is pinned
+func (m *Model) severityCell(p *models.Problem) string {
+	cell := shortSeverity(p.Severity)
+	if p.Allowed {
+		cell = allowedMarker + cell
+	}
+	if change, ok := m.markChanges[p.ID]; ok && (m.mark != nil || m.compareTo != nil) {
+		cell = change.symbol() + cell
+	}
+	if m.pinned[p.ID] {
+		cell = pinMarker + cell
+	}
+	return cell
+}
+
 func (m *Model) jumpToRow(n int) {
 	if n < 1 || n > len(m.problems) {
 		return
@@ -375,6 +704,33 @@ func (m *Model) jumpToRow(n int) {
 	m.tbl.SetCursor(n - 1)
 }
 
+// drillIntoSelected narrows the list to problems sharing the selected
+// problem's node (or namespace). Returns a user-facing status message.
+func (m *Model) drillIntoSelected() string {
+	p := m.selectedProblem()
+	if p == nil {
+		return "No problem selected"
+	}
+	scope, ok := drillScopeFor(p, m.drillStack)
+	if !ok {
+		return "No node or namespace to drill into"
+	}
+	m.drillStack = append(m.drillStack, scope)
+	m.tbl.SetCursor(0)
+	m.updateProblems()
+	return ""
+}
+
+// drillUp pops one drill-down level
+func (m *Model) drillUp() {
+	if len(m.drillStack) == 0 {
+		return
+	}
+	m.drillStack = m.drillStack[:len(m.drillStack)-1]
+	m.tbl.SetCursor(0)
+	m.updateProblems()
+}
+
 func (m *Model) copySelectedProblem() string {
 	p := m.selectedProblem()
 	if p == nil {
@@ -391,6 +747,33 @@ func (m *Model) yankSelectedEntity() string {
 	return copyToClipboard(p.Entity)
 }
 
+// yankSelectedJSON copies the selected problem as JSON, e.g. for pasting
+// into a ticket
+func (m *Model) yankSelectedJSON() string {
+	p := m.selectedProblem()
+	if p == nil {
+		return "No problem selected"
+	}
+	text, err := problemJSON(p)
+	if err != nil {
+		return fmt.Sprintf("JSON error: %v", err)
+	}
+	name := "infranow-" + strings.ReplaceAll(p.ID, "/", "-") + ".json"
+	return copyOrSave(text, os.TempDir(), name)
+}
+
+// problemJSON renders p as indented JSON, with the formatted metrics of
+// --output json
+func problemJSON(p *models.Problem) (string, error) {
+	c := *p
+	c.MetricsFormatted = c.FormattedMetrics()
+	data, err := json.MarshalIndent(&c, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
 func (m *Model) openSelectedRunbook() string {
 	p := m.selectedProblem()
 	if p == nil {
@@ -413,6 +796,16 @@ func (m *Model) openSelectedRunbook() string {
 	return "Opening runbook..."
 }
 
+// firstSeenAge renders how long ago p was first seen, and when a previous
+// run saw it first, how long ago this session did
+func firstSeenAge(p *models.Problem) string {
+	age := humanAge(time.Since(p.Since()))
+	if !p.Since().Equal(p.FirstSeen) {
+		age += fmt.Sprintf(" (this session %s)", humanAge(time.Since(p.FirstSeen)))
+	}
+	return age
+}
+
 func formatProblemDetail(p *models.Problem) string {
 	var b strings.Builder
 	fmt.Fprintf(&b, "%s: %s\n", p.Severity, p.Title)
@@ -420,13 +813,19 @@ func formatProblemDetail(p *models.Problem) string {
 	if p.Message != "" {
 		fmt.Fprintf(&b, "Message: %s\n", p.Message)
 	}
-	fmt.Fprintf(&b, "First seen: %s | Count: %d\n", humanAge(time.Since(p.FirstSeen)), p.Count)
+	fmt.Fprintf(&b, "First seen: %s | Count: %d\n", firstSeenAge(p), p.Count)
 	if p.Hint != "" {
 		fmt.Fprintf(&b, "Hint: %s\n", p.Hint)
 	}
+	if len(p.Metrics) > 0 {
+		fmt.Fprintf(&b, "Metrics: %s\n", p.MetricsSummary())
+	}
 	if p.RunbookURL != "" {
 		fmt.Fprintf(&b, "Runbook: %s\n", p.RunbookURL)
 	}
+	if p.QueryURL != "" {
+		fmt.Fprintf(&b, "Query: %s\n", p.QueryURL)
+	}
 	return b.String()
 }
 
@@ -461,11 +860,42 @@ func (m Model) renderDetailPanel() string {
 	b.WriteString(labelStyle.Render(fmt.Sprintf("  Type: %s | Count: %d | Blast: %d", p.Type, p.Count, p.BlastRadius)))
 	b.WriteString("\n")
 	b.WriteString(labelStyle.Render(fmt.Sprintf("  First: %s | Last: %s",
-		humanAge(time.Since(p.FirstSeen)), humanAge(time.Since(p.LastSeen)))))
+		firstSeenAge(p), humanAge(time.Since(p.LastSeen)))))
 	b.WriteString("\n")
 	b.WriteString(labelStyle.Render("  Hint: "))
 	b.WriteString(hintStyle.Render(p.Hint))
 
+	if len(p.Metrics) > 0 {
+		b.WriteString("\n")
+		b.WriteString(labelStyle.Render("  Metrics: "))
+		b.WriteString(p.MetricsSummary())
+	}
+
+	if m.compareTo != nil && m.mark == nil {
+		b.WriteString("\n")
+		b.WriteString(m.baselineDetail(p))
+	}
+
+	if p.Allowed {
+		b.WriteString("\n")
+		b.WriteString(labelStyle.Render("  Allow-listed (not counted)"))
+		if p.AllowReason != "" {
+			b.WriteString(labelStyle.Render(": " + p.AllowReason))
+		}
+	}
+
+	if cause := p.Labels["likely_cause"]; cause != "" {
+		b.WriteString("\n")
+		b.WriteString(labelStyle.Render("  Likely cause: "))
+		b.WriteString(cause)
+	}
+
+	if trajectory := formatTrajectory(p.Observations); trajectory != "" {
+		b.WriteString("\n")
+		b.WriteString(labelStyle.Render(fmt.Sprintf("  Last %d cycles: ", len(p.Observations))))
+		b.WriteString(trajectory)
+	}
+
 	if p.History != nil {
 		b.WriteString("\n")
 		if p.History.TotalOccurrences > 1 {
@@ -482,6 +912,12 @@ func (m Model) renderDetailPanel() string {
 		b.WriteString(p.RunbookURL)
 	}
 
+	if m.height >= smallTerminal && p.QueryURL != "" {
+		b.WriteString("\n")
+		b.WriteString(labelStyle.Render("  Query: "))
+		b.WriteString(p.QueryURL)
+	}
+
 	return b.String()
 }
 
@@ -501,10 +937,18 @@ func (m Model) renderHeader() string {
 		Foreground(lipgloss.Color("11")).
 		Bold(true)
 
+	alarmStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("15")).
+		Background(lipgloss.Color("9")).
+		Bold(true)
+
 	stats := m.watcher.GetPrometheusStats()
 	var status string
 
-	if !stats.Healthy {
+	if now := time.Now(); m.watcher.IsDataStale(m.maxDataStaleness, now) {
+		status = alarmStyle.Render(fmt.Sprintf(" ⚠  STALE DATA: nothing fresh for %s (limit %s) ",
+			humanAge(m.watcher.DataAge(now)), m.maxDataStaleness))
+	} else if !stats.Healthy {
 		timeSince := time.Since(stats.LastCheck)
 		status = errorStyle.Render(fmt.Sprintf("⚠  Prometheus DOWN (%s ago)", formatDuration(timeSince)))
 	} else if stats.ErrorRate > 0.5 && stats.QueryCount > 10 {
@@ -517,16 +961,33 @@ func (m Model) renderHeader() string {
 		status = statusStyle.Render(fmt.Sprintf("●  Running (Q:%d E:%d)", stats.QueryCount, stats.ErrorCount))
 	}
 
-	title := titleStyle.Render("infranow - Infrastructure Monitor")
+	if m.height < shortTerminal {
+		return m.renderCompactHeader(status)
+	}
+
+	titleText := "infranow - Infrastructure Monitor"
+	if cluster := m.watcher.ClusterName(); cluster != "" {
+		titleText += " [" + cluster + "]"
+	}
+	title := titleStyle.Render(titleText)
 	sortInfo := fmt.Sprintf("Sort: %s", m.sortMode)
+	switch {
+	case m.mark != nil:
+		sortInfo = fmt.Sprintf("Changes since %s  %s", models.DisplayTime(m.mark.at).Format(time.TimeOnly), sortInfo)
+	case m.compareTo != nil:
+		sortInfo = fmt.Sprintf("%s  %s", m.baselineSummary(), sortInfo)
+	}
 
 	line1 := lipgloss.JoinHorizontal(lipgloss.Left,
 		title,
-		strings.Repeat(" ", m.width-lipgloss.Width(title)-lipgloss.Width(sortInfo)),
+		spaces(m.width-lipgloss.Width(title)-lipgloss.Width(sortInfo)),
 		sortInfo,
 	)
 
 	promInfo := fmt.Sprintf("Prometheus: %s", sanitizeURL(m.prometheusURL))
+	if m.hideSource {
+		promInfo = "Prometheus: redacted"
+	}
 
 	var pfStatus string
 	if m.portForward != nil {
@@ -548,17 +1009,39 @@ func (m Model) renderHeader() string {
 		}
 	}
 
+	var tenantStatus string
+	if health := m.watcher.GetTenantHealth(); health != nil {
+		var parts []string
+		for _, name := range m.watcher.Tenants() {
+			if health[name] {
+				parts = append(parts, statusStyle.Render(name+" ●"))
+			} else {
+				parts = append(parts, errorStyle.Render(name+" ⚠"))
+			}
+		}
+		tenantStatus = " [Tenants: " + strings.Join(parts, " ") + "]"
+	}
+
 	line2 := lipgloss.JoinHorizontal(lipgloss.Left,
 		promInfo,
 		pfStatus,
+		tenantStatus,
 		strings.Repeat(" ", 5),
 		fmt.Sprintf("Refresh: %s", m.refreshInterval),
+		namespaceSummary(m.problems),
 	)
 
 	summary := m.watcher.GetSummary()
-	problemCount := fmt.Sprintf("Problems: %d", len(m.problems))
+	allowed := countAllowed(m.problems)
+	problemCount := fmt.Sprintf("Problems: %d", len(m.problems)-allowed)
 	if m.filteredCount > 0 {
-		problemCount = fmt.Sprintf("Problems: %d (%d filtered)", len(m.problems), m.filteredCount)
+		problemCount = fmt.Sprintf("Problems: %d (%d filtered)", len(m.problems)-allowed, m.filteredCount)
+	}
+	if allowed > 0 {
+		problemCount += fmt.Sprintf(" +%d allowed", allowed)
+	}
+	if muted := m.mutes.Active(); muted > 0 {
+		problemCount += fmt.Sprintf(" +%d muted", muted)
 	}
 
 	line3 := lipgloss.JoinHorizontal(lipgloss.Left,
@@ -573,9 +1056,70 @@ func (m Model) renderHeader() string {
 		fmt.Sprintf("Warning: %d", summary[models.SeverityWarning]),
 	)
 
-	border := strings.Repeat("─", m.width)
+	border := rule(m.width)
 
-	return strings.Join([]string{line1, line2, line3, border}, "\n")
+	return strings.Join([]string{
+		fitWidth(line1, m.width),
+		fitWidth(line2, m.width),
+		fitWidth(line3, m.width),
+		border,
+	}, "\n")
+}
+
+// namespaceSummary renders the affected namespace breadth for the header,
+// e.g. "     Namespaces: 4 (prod 3, staging 2, batch 1)"
+func namespaceSummary(problems []*models.Problem) string {
+	count, top := AffectedNamespaces(problems, TopNamespaces)
+	if count == 0 {
+		return ""
+	}
+	parts := make([]string, len(top))
+	for i, ns := range top {
+		parts[i] = fmt.Sprintf("%s %d", ns.Namespace, ns.Problems)
+	}
+	return fmt.Sprintf("%sNamespaces: %d (%s)", strings.Repeat(" ", 5), count, strings.Join(parts, ", "))
+}
+
+// renderCompactHeader squeezes status and counts into one line so short
+// terminals keep their rows for the problem list
+func (m Model) renderCompactHeader(status string) string {
+	summary := m.watcher.GetSummary()
+	if cluster := m.watcher.ClusterName(); cluster != "" {
+		status = "[" + cluster + "] " + status
+	}
+	return fitWidth(fmt.Sprintf("%s  P:%d F:%d C:%d W:%d  Sort: %s",
+		status,
+		len(m.problems)-countAllowed(m.problems),
+		summary[models.SeverityFatal],
+		summary[models.SeverityCritical],
+		summary[models.SeverityWarning],
+		m.sortMode,
+	), m.width)
+}
+
+// spaces returns n spaces, or none when a narrow terminal makes n negative
+func spaces(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat(" ", n)
+}
+
+// rule returns a horizontal separator n cells wide (none if n is negative)
+func rule(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat("─", n)
+}
+
+// fitWidth truncates a rendered line to width display cells. Widths are
+// measured with lipgloss so wide runes and ANSI styling are not miscounted.
+func fitWidth(line string, width int) string {
+	if width <= 0 || lipgloss.Width(line) <= width {
+		return line
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(line)
 }
 
 func (m Model) renderEmptyState() string {
@@ -591,16 +1135,39 @@ func (m Model) renderEmptyState() string {
 	}
 
 	centerText := "✓ No problems detected"
-	leftPadding := (m.width - len(centerText)) / 2
+	if m.mark != nil {
+		centerText = "✓ No changes since " + models.DisplayTime(m.mark.at).Format(time.TimeOnly)
+	}
+	leftPadding := (m.width - lipgloss.Width(centerText)) / 2
 
-	b.WriteString(strings.Repeat(" ", leftPadding))
+	b.WriteString(spaces(leftPadding))
 	b.WriteString(emptyStyle.Render(centerText))
 
+	hint := "d: why no problems?"
+	b.WriteString("\n\n")
+	b.WriteString(spaces((m.width - lipgloss.Width(hint)) / 2))
+	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(hint))
+
 	return b.String()
 }
 
+// renderDiagnostic shows backend health and every detector's last run,
+// cut to the rows between header and footer
+func (m Model) renderDiagnostic() string {
+	lines := strings.Split(Diagnostic(m.watcher.GetPrometheusStats(), m.watcher.DetectorStats(), time.Now()), "\n")
+	avail := m.height - lipgloss.Height(m.renderHeader()) - lipgloss.Height(m.renderFooter())
+	if avail > 1 && len(lines) > avail {
+		more := len(lines) - avail + 1
+		lines = append(lines[:avail-1], fmt.Sprintf("… %d more lines", more))
+	}
+	for i, line := range lines {
+		lines[i] = fitWidth("  "+line, m.width)
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m Model) renderFooter() string {
-	border := strings.Repeat("─", m.width)
+	border := rule(m.width)
 	helpStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("8"))
 
@@ -609,12 +1176,18 @@ func (m Model) renderFooter() string {
 		Bold(true)
 
 	var help string
-	if m.searchMode {
+	if m.showDiagnostic {
+		help = searchStyle.Render("Diagnostics") + helpStyle.Render("  (d/esc: close)  q: quit")
+	} else if m.muteTarget != nil {
+		help = searchStyle.Render("Mute "+m.muteTarget.Entity+" for:") + helpStyle.Render("  "+muteChoicesHelp()+"  (esc: cancel)")
+	} else if m.searchMode {
 		help = searchStyle.Render(fmt.Sprintf("Search: %s_", m.searchQuery)) + helpStyle.Render("  (enter: apply  esc: cancel)")
 	} else if m.searchQuery != "" {
 		help = helpStyle.Render(fmt.Sprintf("Filter: %s  ", m.searchQuery)) + searchStyle.Render("(esc: clear)") + helpStyle.Render("  s: sort  p: pause  /: search  q: quit")
+	} else if len(m.drillStack) > 0 {
+		help = searchStyle.Render(breadcrumb(m.drillStack)) + helpStyle.Render("  (esc: back)  enter: drill  s: sort  p: pause  /: search  q: quit")
 	} else {
-		baseHelp := "s: sort  p: pause  /: search  ?: runbook  c: copy  y: yank  1-9: jump  jk: nav"
+		baseHelp := "s: sort  v: view  p: pause  /: search  enter: drill  m: mark  f: pin  M: mute  U: unmute  ?: runbook  c: copy  y: yank  Y: json  d: diag  1-9: jump  jk: nav"
 		if m.portForward != nil {
 			baseHelp += "  r: pf"
 		}
@@ -622,9 +1195,9 @@ func (m Model) renderFooter() string {
 		help = helpStyle.Render(baseHelp)
 	}
 
-	footer := border + "\n" + help
+	footer := border + "\n" + fitWidth(help, m.width)
 	if m.statusMsg != "" {
-		footer += "\n" + helpStyle.Render(m.statusMsg)
+		footer += "\n" + fitWidth(helpStyle.Render(m.statusMsg), m.width)
 	}
 	return footer
 }
@@ -638,9 +1211,7 @@ func tickCmd(interval time.Duration) tea.Cmd {
 func waitForUpdate(watcher *Watcher) tea.Cmd {
 	return func() tea.Msg {
 		<-watcher.UpdateChan()
-		return updateMsg{
-			problems: watcher.GetProblems(),
-		}
+		return updateMsg{}
 	}
 }
 
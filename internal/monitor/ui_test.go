@@ -0,0 +1,336 @@
+package monitor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func newTestModel(width, height int) Model {
+	m := NewModel(newTestWatcher(1), "http://localhost:9090", 2*time.Second, nil)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	return updated.(Model)
+}
+
+func TestHeaderHeight(t *testing.T) {
+	tests := []struct {
+		termHeight int
+		want       int
+	}{
+		{10, compactHeader},
+		{15, compactHeader},
+		{16, headerLines},
+		{40, headerLines},
+	}
+
+	for _, tt := range tests {
+		m := newTestModel(100, tt.termHeight)
+		if got := strings.Count(m.renderHeader(), "\n") + 1; got != tt.want {
+			t.Errorf("height %d: rendered header has %d lines, want %d", tt.termHeight, got, tt.want)
+		}
+	}
+}
+
+func TestFitTable_MeasuredChrome(t *testing.T) {
+	now := time.Now()
+	for _, height := range []int{15, 24, 50} {
+		m := newTestModel(120, height)
+		m.problems = []*models.Problem{
+			{ID: "a", Entity: "prod/api", Title: "CrashLoop", Severity: models.SeverityCritical, FirstSeen: now, LastSeen: now,
+				Labels: map[string]string{"likely_cause": "deploy v2"}, RunbookURL: "https://example.com/runbook"},
+		}
+		m.rebuildTableRows()
+		m.statusMsg = "copied"
+		m.fitTable()
+
+		chrome := lipgloss.Height(m.renderHeader()) + lipgloss.Height(m.renderFooter()) +
+			separatorLines + lipgloss.Height(m.renderDetailPanel())
+		if got, want := lipgloss.Height(m.tbl.View()), height-chrome; got != want {
+			t.Errorf("height %d: table height = %d, want %d", height, got, want)
+		}
+		if got := lipgloss.Height(m.View()); got != height {
+			t.Errorf("height %d: view has %d lines", height, got)
+		}
+	}
+}
+
+func TestRenderHeader_NarrowWithWideRunes(t *testing.T) {
+	for _, height := range []int{10, 40} {
+		m := NewModel(newTestWatcher(1), "http://prometheus-🔥.monitoring.svc:9090", 2*time.Second, nil)
+		updated, _ := m.Update(tea.WindowSizeMsg{Width: 20, Height: height})
+		m = updated.(Model)
+		m.paused = true // "⏸" status icon
+
+		header := m.renderHeader() // Must not panic
+		for i, line := range strings.Split(header, "\n") {
+			if w := lipgloss.Width(line); w > 20 {
+				t.Errorf("height %d: header line %d is %d cells wide, want <= 20", height, i, w)
+			}
+		}
+	}
+}
+
+func TestView_TinyWidthsDoNotPanic(t *testing.T) {
+	now := time.Now()
+	long := &models.Problem{
+		ID:        "long",
+		Entity:    "production-cluster/" + strings.Repeat("very-long-namespace-", 5) + "/pod-0",
+		Title:     "A problem title far wider than any terminal in this test",
+		Severity:  models.SeverityFatal,
+		FirstSeen: now,
+		LastSeen:  now,
+	}
+
+	for _, width := range []int{0, 1, 5, 12} {
+		for _, problems := range [][]*models.Problem{nil, {long}} {
+			m := newTestModel(width, 30)
+			m.problems = problems
+			m.rebuildTableRows()
+			m.statusMsg = "copied"
+
+			// Each renderer must clamp its padding instead of panicking
+			_ = m.renderHeader()
+			_ = m.renderEmptyState()
+			_ = m.renderFooter()
+			_ = m.View()
+		}
+	}
+}
+
+func TestWithFixedWidth_RendersWithoutWindowSize(t *testing.T) {
+	m := NewModel(newTestWatcher(1), "http://localhost:9090", 2*time.Second, nil, WithFixedWidth(60))
+
+	view := m.View()
+	if view == "Initializing..." {
+		t.Fatal("model with fixed width should render without a WindowSizeMsg")
+	}
+	if m.height != defaultHeight {
+		t.Errorf("height = %d, want default %d", m.height, defaultHeight)
+	}
+	for i, line := range strings.Split(view, "\n") {
+		if w := lipgloss.Width(line); w > 60 {
+			t.Errorf("line %d is %d cells wide, want <= 60", i, w)
+		}
+	}
+
+	// A later size report changes the height but keeps the fixed width
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 200, Height: 30})
+	m = updated.(Model)
+	if m.width != 60 || m.height != 30 {
+		t.Errorf("after resize: %dx%d, want 60x30", m.width, m.height)
+	}
+}
+
+func TestRenderHeader_HiddenSource(t *testing.T) {
+	m := NewModel(newTestWatcher(1), "http://prometheus.payments.svc:9090", 2*time.Second, nil, WithFixedWidth(200), WithHiddenSource())
+	header := m.renderHeader()
+	if strings.Contains(header, "payments") || !strings.Contains(header, "Prometheus: redacted") {
+		t.Errorf("header should not show the Prometheus URL:\n%s", header)
+	}
+}
+
+func TestRenderHeader_AffectedNamespaces(t *testing.T) {
+	m := newTestModel(200, 40)
+	if strings.Contains(m.renderHeader(), "Namespaces:") {
+		t.Error("header should not show namespaces without problems")
+	}
+
+	m.problems = []*models.Problem{nsProblem("prod"), nsProblem("prod"), nsProblem("dev"), nsProblem("")}
+	if header := m.renderHeader(); !strings.Contains(header, "Namespaces: 2 (prod 2, dev 1)") {
+		t.Errorf("header missing namespace summary:\n%s", header)
+	}
+}
+
+func TestView_ToggleCompact(t *testing.T) {
+	now := time.Now()
+	problems := []*models.Problem{
+		{ID: "a", Entity: "prod/api-0", Type: "crashloopbackoff", Title: "Pod CrashLoopBackOff",
+			Severity: models.SeverityFatal, Count: 7, FirstSeen: now, LastSeen: now, Hint: "Check logs"},
+		{ID: "b", Entity: "prod/db-0", Type: "oom_kill", Title: "Container OOM Killed",
+			Severity: models.SeverityCritical, Count: 2, FirstSeen: now, LastSeen: now},
+	}
+	m := newTestModel(120, 30)
+	m.problems = problems
+	m.rebuildTableRows()
+
+	detailed := m.View()
+	if !strings.Contains(detailed, "TITLE") || !strings.Contains(detailed, "Hint: ") {
+		t.Errorf("detailed view should show titles and the detail panel:\n%s", detailed)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	m = updated.(Model)
+	if m.viewMode != ViewCompact {
+		t.Fatalf("view mode = %s, want compact", m.viewMode)
+	}
+	compact := m.View()
+	for _, want := range []string{"TYPE", "COUNT", "crashloopbackoff", "oom_kill"} {
+		if !strings.Contains(compact, want) {
+			t.Errorf("compact view missing %q:\n%s", want, compact)
+		}
+	}
+	if strings.Contains(compact, "Hint: ") || strings.Contains(compact, "Pod CrashLoopBackOff") {
+		t.Errorf("compact view should not show titles or the detail panel:\n%s", compact)
+	}
+	if got := lipgloss.Height(compact); got != 30 {
+		t.Errorf("compact view has %d lines, want 30", got)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	m = updated.(Model)
+	if m.viewMode != ViewDetailed || !strings.Contains(m.View(), "Hint: ") {
+		t.Error("second toggle should return to the detailed view")
+	}
+}
+
+func TestParseSortMode(t *testing.T) {
+	tests := []struct {
+		in   string
+		want SortMode
+	}{
+		{"severity", SortBySeverity},
+		{"score", SortBySeverity},
+		{"Recency", SortByRecency},
+		{"count", SortByCount},
+		{"blast-radius", SortByBlastRadius},
+		{"oldest", SortByAge},
+	}
+	for _, tt := range tests {
+		got, err := ParseSortMode(tt.in)
+		if err != nil {
+			t.Errorf("ParseSortMode(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSortMode(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+	if _, err := ParseSortMode("name"); err == nil {
+		t.Error("expected error for unknown sort mode")
+	}
+}
+
+func TestWithSortMode_CyclesFromInitialMode(t *testing.T) {
+	m := NewModel(newTestWatcher(1), "http://localhost:9090", 2*time.Second, nil, WithSortMode(SortByBlastRadius))
+	if m.sortMode != SortByBlastRadius {
+		t.Fatalf("initial sort mode = %s, want blast-radius", m.sortMode)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = updated.(Model)
+	if m.sortMode != SortByAge {
+		t.Errorf("sort mode after s = %s, want oldest", m.sortMode)
+	}
+
+	// The last mode wraps around to the first
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = updated.(Model)
+	if m.sortMode != SortBySeverity {
+		t.Errorf("sort mode after s = %s, want severity", m.sortMode)
+	}
+}
+
+func TestUpdateMsg_KeepsSortMode(t *testing.T) {
+	w := newTestWatcher(1)
+	now := time.Now()
+	w.problems["fatal"] = &models.Problem{ID: "fatal", Severity: models.SeverityFatal, BlastRadius: 1, LastSeen: now}
+	w.problems["wide"] = &models.Problem{ID: "wide", Severity: models.SeverityWarning, BlastRadius: 20, LastSeen: now}
+
+	m := NewModel(w, "http://localhost:9090", 2*time.Second, nil, WithSortMode(SortByBlastRadius))
+	updated, _ := m.Update(updateMsg{})
+	m = updated.(Model)
+	if len(m.problems) != 2 || m.problems[0].ID != "wide" {
+		t.Errorf("watcher update should keep the blast radius order, got %v", m.problems)
+	}
+}
+
+func TestDetailPanel_Trajectory(t *testing.T) {
+	clock := newFakeClock()
+	w := newTestWatcher(1, WithObservationHistory(DefaultObservationHistory), WithClock(clock.now))
+	for _, severity := range []models.Severity{models.SeverityWarning, models.SeverityWarning, models.SeverityCritical} {
+		clock.advance(10 * time.Second)
+		w.updateProblems([]*models.Problem{{ID: "crash", Entity: "prod/api", Title: "CrashLoopBackOff", Severity: severity}})
+	}
+
+	m := NewModel(w, "http://localhost:9090", 2*time.Second, nil)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	m.updateProblems()
+
+	panel := m.renderDetailPanel()
+	if !strings.Contains(panel, "Last 3 cycles") || !strings.Contains(panel, "W W C | count 1->3") {
+		t.Errorf("detail panel lacks the trajectory:\n%s", panel)
+	}
+}
+
+func TestFormatTrajectory_NeedsTwoObservations(t *testing.T) {
+	if got := formatTrajectory([]models.Observation{{Severity: models.SeverityWarning, Count: 1}}); got != "" {
+		t.Errorf("formatTrajectory(one) = %q, want empty", got)
+	}
+}
+
+func TestProblemJSON_SelectedProblem(t *testing.T) {
+	w := newTestWatcher(1)
+	now := time.Now()
+	w.problems["disk"] = &models.Problem{
+		ID: "disk_full/3f9a1c0b", Entity: "node-1:/var", Type: "disk_full", Severity: models.SeverityCritical,
+		Title: "Disk Space Critical", FirstSeen: now, LastSeen: now, Count: 4,
+		Labels:  map[string]string{"node": "node-1"},
+		Metrics: map[string]float64{"usage_percent": 96},
+	}
+
+	m := NewModel(w, "http://localhost:9090", 2*time.Second, nil)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	m.updateProblems()
+
+	selected := m.selectedProblem()
+	if selected == nil {
+		t.Fatal("no problem selected")
+	}
+	text, err := problemJSON(selected)
+	if err != nil {
+		t.Fatalf("problemJSON: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &got); err != nil {
+		t.Fatalf("not valid JSON: %v\n%s", err, text)
+	}
+	if got["ID"] != "disk_full/3f9a1c0b" || got["Entity"] != "node-1:/var" || got["Count"] != float64(4) {
+		t.Errorf("unexpected fields in %s", text)
+	}
+	if formatted, _ := got["metrics_formatted"].(map[string]interface{}); formatted["usage_percent"] != "96%" {
+		t.Errorf("metrics_formatted = %v, want usage_percent 96%%", got["metrics_formatted"])
+	}
+	if selected.MetricsFormatted != nil {
+		t.Error("problemJSON modified the selected problem")
+	}
+}
+
+func TestCopyOrSave_SavesWithoutClipboard(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("hides xclip, the linux clipboard command, by clearing PATH")
+	}
+	t.Setenv("PATH", "") // no xclip
+	dir := t.TempDir()
+
+	msg := copyOrSave("{}\n", dir, "infranow-problem.json")
+
+	path := filepath.Join(dir, "infranow-problem.json")
+	if msg != "No clipboard, saved to "+path {
+		t.Errorf("status = %q", msg)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "{}\n" {
+		t.Errorf("saved file = %q, %v", data, err)
+	}
+}
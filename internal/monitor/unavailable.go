@@ -0,0 +1,74 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// UnavailableProblemType is the type of the synthetic problem reported while
+// monitoring itself is down (see WithUnavailableProblem)
+const UnavailableProblemType = "monitoring_unavailable"
+
+// blastRadiusUnavailable: nothing in the cluster is being watched
+const blastRadiusUnavailable = 100
+
+// WithUnavailableProblem reports one FATAL monitoring_unavailable problem
+// while the backend is down and every detector's last run failed, so an
+// outage of the metrics pipeline trips --fail-on and notifications instead of
+// reading as zero problems. Problems already tracked are reported alongside it
+// until they go stale.
+func WithUnavailableProblem() WatcherOption {
+	return func(w *Watcher) {
+		w.reportUnavailable = true
+	}
+}
+
+// unavailableProblemLocked returns the synthetic problem when monitoring is
+// unavailable, nil otherwise. Caller must hold w.mu.
+func (w *Watcher) unavailableProblemLocked(now time.Time) *models.Problem {
+	if !w.reportUnavailable || w.prometheusHealthy {
+		return nil
+	}
+	detectors := w.registry.All()
+	if len(detectors) == 0 {
+		return nil
+	}
+	for _, d := range detectors {
+		if w.detectorFailureStreakLocked(d.Name()) == 0 {
+			return nil // Still getting data from somewhere
+		}
+	}
+
+	since := w.lastSuccessfulQuery
+	if since.IsZero() {
+		since = w.startTime
+	}
+	entity := "monitoring"
+	if w.clusterName != "" {
+		entity = "monitoring/" + w.clusterName
+	}
+	p := &models.Problem{
+		Entity:     entity,
+		EntityType: "monitoring",
+		Type:       UnavailableProblemType,
+		Severity:   models.SeverityFatal,
+		State:      models.StateFiring,
+		Title:      "Cluster monitoring unavailable",
+		Message: fmt.Sprintf("The metrics backend fails health checks and all %d detectors are failing; no data since %s, so the absence of problems means nothing",
+			len(detectors), since.Format(time.RFC3339)),
+		Hint:        "Check that Prometheus (or the port-forward to it) is up and reachable from infranow",
+		RunbookURL:  models.RunbookBaseURL + "monitoring_unavailable.md",
+		BlastRadius: blastRadiusUnavailable,
+		FirstSeen:   since,
+		LastSeen:    now,
+		Count:       1,
+	}
+	if w.clusterName != "" {
+		p.Labels = map[string]string{ClusterLabel: w.clusterName}
+	}
+	p.ID = models.StableID(p.EntityType, p.Type, p.Labels)
+	p.UpdatePersistence()
+	return p
+}
@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/detector"
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// downProvider fails health checks and every query while down is set
+func downProvider(down *bool) *metrics.MockProvider {
+	errDown := errors.New("connection refused")
+	return &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			if *down {
+				return nil, errDown
+			}
+			return nil, nil
+		},
+		HealthFunc: func(ctx context.Context) error {
+			if *down {
+				return errDown
+			}
+			return nil
+		},
+	}
+}
+
+// unavailableWatcher runs two detectors against provider
+func unavailableWatcher(provider metrics.MetricsProvider, opts ...WatcherOption) (*Watcher, []detector.Detector) {
+	detectors := []detector.Detector{detector.NewOOMKillDetector(), detector.NewDiskSpaceDetector()}
+	registry := detector.NewRegistry()
+	for _, d := range detectors {
+		registry.Register(d)
+	}
+	return NewWatcher(provider, registry, 0, 30*time.Second, opts...), detectors
+}
+
+func TestUnavailableProblem_BackendDown(t *testing.T) {
+	down := true
+	w, detectors := unavailableWatcher(downProvider(&down), WithUnavailableProblem())
+	for _, d := range detectors {
+		w.executeDetector(context.Background(), d)
+	}
+
+	problems := w.GetProblems()
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 synthetic problem, got %d", len(problems))
+	}
+	p := problems[0]
+	if p.Type != UnavailableProblemType || p.Severity != models.SeverityFatal {
+		t.Errorf("got %s %s, want FATAL %s", p.Severity, p.Type, UnavailableProblemType)
+	}
+	if p.ID == "" || p.RunbookURL == "" {
+		t.Errorf("synthetic problem lacks an ID or runbook: %+v", p)
+	}
+	if got := w.GetSummary()[models.SeverityFatal]; got != 1 {
+		t.Errorf("summary FATAL = %d, want 1", got)
+	}
+
+	// Recovery: one successful detector clears it
+	down = false
+	w.executeDetector(context.Background(), detectors[0])
+	if n := len(w.GetProblems()); n != 0 {
+		t.Errorf("expected no problems after recovery, got %d", n)
+	}
+}
+
+func TestUnavailableProblem_NeedsEveryDetectorFailing(t *testing.T) {
+	down := true
+	w, detectors := unavailableWatcher(downProvider(&down), WithUnavailableProblem())
+	w.executeDetector(context.Background(), detectors[0])
+
+	// The health check failed, but the second detector has not run yet
+	if n := len(w.GetProblems()); n != 0 {
+		t.Errorf("expected no synthetic problem before every detector failed, got %d", n)
+	}
+}
+
+func TestUnavailableProblem_OffByDefault(t *testing.T) {
+	down := true
+	w, detectors := unavailableWatcher(downProvider(&down))
+	for _, d := range detectors {
+		w.executeDetector(context.Background(), d)
+	}
+	if n := len(w.GetProblems()); n != 0 {
+		t.Errorf("expected zero problems without WithUnavailableProblem, got %d", n)
+	}
+}
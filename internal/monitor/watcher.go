@@ -33,20 +33,142 @@ func WithHistoryStore(store history.Store) WatcherOption {
 	}
 }
 
+// WithClock replaces time.Now as the watcher's clock, so tests can drive
+// FirstSeen/LastSeen, stale pruning and TTL expiry deterministically
+func WithClock(now func() time.Time) WatcherOption {
+	return func(w *Watcher) {
+		w.now = now
+	}
+}
+
+// WithProblemTTLs expires problems of the given types once they are older than
+// their TTL, regardless of whether detectors still report them
+func WithProblemTTLs(ttls map[string]time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		w.ttls = ttls
+	}
+}
+
+// WithDetectionWindow sets the query window passed to detectors that have
+// no override (default detector.DefaultWindow)
+func WithDetectionWindow(window time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		w.window = window
+	}
+}
+
+// WithDetectorWindows overrides the query window passed to the named
+// detectors (see detector.EffectiveWindow)
+func WithDetectorWindows(windows map[string]time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		w.windows = windows
+	}
+}
+
+// WithTenants runs every detector once per tenant provider (e.g. one Mimir
+// client per X-Scope-OrgID). Problems are labeled with their tenant and their
+// IDs and entities are prefixed so identical problems in two tenants never
+// collide. Health is tracked per tenant.
+func WithTenants(providers map[string]metrics.MetricsProvider) WatcherOption {
+	return func(w *Watcher) {
+		names := make([]string, 0, len(providers))
+		for name := range providers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		w.tenants = make([]tenant, 0, len(names))
+		for _, name := range names {
+			w.tenants = append(w.tenants, tenant{name: name, provider: providers[name]})
+		}
+		w.tenantHealth = make(map[string]bool, len(names))
+		for _, name := range names {
+			w.tenantHealth[name] = true
+		}
+	}
+}
+
+// tenant is a named metrics provider scoped to one backend tenant
+type tenant struct {
+	name     string
+	provider metrics.MetricsProvider
+}
+
 // Watcher orchestrates problem detection and state management
 type Watcher struct {
 	provider metrics.MetricsProvider
 	registry *detector.Registry
 
+	// Multi-tenant mode (empty = single provider)
+	tenants      []tenant
+	tenantHealth map[string]bool
+
 	mu       sync.RWMutex
 	problems map[string]*models.Problem // Keyed by Problem.ID
 
+	// Per-type problem expiry. Expired problems are suppressed (ID -> last
+	// report) until detectors stop reporting them long enough to go stale.
+	ttls    map[string]time.Duration
+	expired map[string]time.Time
+
+	// Query window passed to Detect, and per-detector overrides of it
+	window  time.Duration
+	windows map[string]time.Duration
+
+	// Severity raised per problem type on read (see SeverityOverride)
+	severityOverrides []SeverityOverride
+
+	// Severity moved up or down by namespace (see NamespaceCriticality)
+	namespaceCriticality []NamespaceCriticality
+
+	// Label value rewrites applied before ID generation, keyed by label name
+	labelNormalization map[string][]string
+
+	// Title and message rewording by problem type (nil = detector wording)
+	problemTemplates *models.ProblemTemplates
+
+	// Static labels added to every problem of a detector, keyed by detector
+	// name (see WithDetectorLabels)
+	detectorLabels map[string]map[string]string
+
+	// Sampled PromQL logging (nil unless WithQueryLogger)
+	queryLogger *QueryLogger
+
+	// Sample evaluation time annotation (0 = off, see WithEvalTime)
+	evalStaleAfter time.Duration
+
+	// Monitored cluster name and whether problems are labeled with it (see
+	// WithClusterName)
+	clusterName  string
+	clusterLabel bool
+
+	// Base URL or template for per-problem query links, empty = off (see
+	// WithQueryLinks)
+	queryLinkBase string
+
+	// Recent detections per problem ID (empty unless WithObservationHistory)
+	observationSize int
+	observations    map[string]*observationRing
+
 	prometheusHealthy   bool
 	lastPrometheusCheck time.Time
+
+	// Consecutive failures a source (detector or health check, per tenant)
+	// needs before the backend is reported down (see WithDownAfterFailures)
+	downAfterFailures int
+	failureStreaks    map[string]int
+
+	// Report a synthetic FATAL problem while monitoring is down (see
+	// WithUnavailableProblem)
+	reportUnavailable bool
+
 	lastSuccessfulQuery time.Time
 	queryCount          int64
 	errorCount          int64
 
+	// Last run per detector, keyed like failureStreaks
+	detectorStats map[string]*DetectorStat
+
 	// Concurrency controls (v0.1.2)
 	maxConcurrency  int
 	detectorTimeout time.Duration
@@ -56,7 +178,16 @@ type Watcher struct {
 	historyStore history.Store
 	startTime    time.Time
 
+	// Clock for FirstSeen/LastSeen, pruning and health timestamps (see
+	// WithClock)
+	now func() time.Time
+
+	// First sighting of problems by earlier runs, by ID, consumed as they
+	// are detected (see WithOriginalFirstSeen)
+	originalFirstSeen map[string]time.Time
+
 	updateChan chan struct{} // Notify UI of changes
+	reloadChan chan struct{} // Signals a registry swap from config hot-reload
 	stopChan   chan struct{}
 	stopped    bool
 }
@@ -67,17 +198,25 @@ func NewWatcher(provider metrics.MetricsProvider, registry *detector.Registry, m
 		provider:          provider,
 		registry:          registry,
 		problems:          make(map[string]*models.Problem),
+		expired:           make(map[string]time.Time),
+		window:            detector.DefaultWindow,
+		observations:      make(map[string]*observationRing),
 		prometheusHealthy: true,
+		downAfterFailures: 1,
+		failureStreaks:    make(map[string]int),
+		detectorStats:     make(map[string]*DetectorStat),
 		maxConcurrency:    maxConcurrency,
 		detectorTimeout:   detectorTimeout,
-		startTime:         time.Now(),
+		now:               time.Now,
 		updateChan:        make(chan struct{}, 1),
+		reloadChan:        make(chan struct{}, 1),
 		stopChan:          make(chan struct{}),
 	}
 
 	for _, opt := range opts {
 		opt(w)
 	}
+	w.startTime = w.now()
 
 	// Initialize semaphore if concurrency limited
 	if maxConcurrency > 0 {
@@ -87,51 +226,103 @@ func NewWatcher(provider metrics.MetricsProvider, registry *detector.Registry, m
 	return w
 }
 
-// Start begins the monitoring loop
+// Start begins the monitoring loop. Detectors are restarted against the new
+// registry whenever Reload is called; problem state is preserved across reloads.
+// With no detectors it waits for a reload that enables some.
 func (w *Watcher) Start(ctx context.Context) error {
-	detectors := w.registry.All()
-	if len(detectors) == 0 {
-		return nil
+	for {
+		// Start each detector in its own goroutine
+		runCtx, runCancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		for _, d := range w.currentRegistry().All() {
+			wg.Add(1)
+			go func(det detector.Detector) {
+				defer wg.Done()
+				w.runDetector(runCtx, det)
+			}(d)
+		}
+
+		select {
+		case <-ctx.Done():
+			// Mark as stopped and wait for all detectors to finish
+			w.mu.Lock()
+			w.stopped = true
+			w.mu.Unlock()
+
+			runCancel()
+			wg.Wait()
+			close(w.updateChan)
+			return nil
+
+		case <-w.reloadChan:
+			runCancel()
+			wg.Wait()
+		}
 	}
+}
 
-	// Start each detector in its own goroutine
-	var wg sync.WaitGroup
-	for _, d := range detectors {
-		wg.Add(1)
-		go func(det detector.Detector) {
-			defer wg.Done()
-			w.runDetector(ctx, det)
-		}(d)
+// Reload swaps the detector registry. Running detectors are stopped and the
+// new set is started immediately; problem state is kept.
+func (w *Watcher) Reload(registry *detector.Registry) {
+	w.mu.Lock()
+	w.registry = registry
+	w.mu.Unlock()
+
+	select {
+	case w.reloadChan <- struct{}{}:
+	default:
+		// A reload is already pending and will pick up this registry
 	}
+}
 
-	// Wait for context cancellation
-	<-ctx.Done()
+// SetProblemTTLs replaces the per-type problem expiry (config hot-reload)
+func (w *Watcher) SetProblemTTLs(ttls map[string]time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.ttls = ttls
+}
 
-	// Mark as stopped and wait for all detectors to finish
+// SetDetectorWindows replaces the per-detector query windows (config
+// hot-reload). Running detectors use them from their next run.
+func (w *Watcher) SetDetectorWindows(windows map[string]time.Duration) {
 	w.mu.Lock()
-	w.stopped = true
-	w.mu.Unlock()
+	defer w.mu.Unlock()
+	w.windows = windows
+}
 
-	wg.Wait()
-	close(w.updateChan)
+// DetectorNames returns the sorted names of the detectors currently scheduled
+func (w *Watcher) DetectorNames() []string {
+	detectors := w.currentRegistry().All()
+	names := make([]string, 0, len(detectors))
+	for _, d := range detectors {
+		names = append(names, d.Name())
+	}
+	sort.Strings(names)
+	return names
+}
 
-	return nil
+func (w *Watcher) currentRegistry() *detector.Registry {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.registry
 }
 
-// runDetector runs a single detector at its specified interval
+// runDetector runs a single detector at its specified interval, backed off
+// while it keeps failing (see DetectorInterval)
 func (w *Watcher) runDetector(ctx context.Context, d detector.Detector) {
-	ticker := time.NewTicker(d.Interval())
-	defer ticker.Stop()
-
-	// Run immediately on start
-	w.executeDetector(ctx, d)
+	timer := time.NewTimer(0) // Run immediately on start
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case <-timer.C:
+			start := time.Now()
 			w.executeDetector(ctx, d)
+			// Keep the schedule fixed-rate: a slow run delays the next by
+			// its overrun only
+			timer.Reset(max(w.DetectorInterval(d)-time.Since(start), 0))
 		}
 	}
 }
@@ -151,30 +342,28 @@ func (w *Watcher) executeDetector(ctx context.Context, d detector.Detector) {
 	// Check Prometheus health periodically
 	w.checkPrometheusHealth(ctx)
 
-	// Create context with configurable timeout for this detection cycle
-	detCtx, cancel := context.WithTimeout(ctx, w.detectorTimeout)
-	defer cancel()
-
-	problems, err := d.Detect(detCtx, w.provider, 5*time.Minute)
-
-	w.mu.Lock()
-	w.queryCount++
-	if err != nil {
-		// Mark Prometheus as unhealthy on persistent errors
-		w.prometheusHealthy = false
-		w.lastPrometheusCheck = time.Now()
-		w.errorCount++
-		w.mu.Unlock()
-		// Errors are tracked via errorCount and surfaced through GetPrometheusStats
-		return
+	var problems []*models.Problem
+	if len(w.tenants) == 0 {
+		var ok bool
+		if problems, ok = w.detect(ctx, d, w.provider, ""); !ok {
+			if w.reportUnavailable {
+				w.signalUpdate() // May have made monitoring unavailable
+			}
+			return
+		}
+	} else {
+		for _, t := range w.tenants {
+			tenantProblems, ok := w.detect(ctx, d, t.provider, t.name)
+			if !ok {
+				continue // This tenant's problems go stale; others still update
+			}
+			for _, p := range tenantProblems {
+				labelTenant(p, t.name)
+			}
+			problems = append(problems, tenantProblems...)
+		}
 	}
 
-	// Mark as healthy on successful query
-	w.prometheusHealthy = true
-	w.lastPrometheusCheck = time.Now()
-	w.lastSuccessfulQuery = time.Now()
-	w.mu.Unlock()
-
 	// Always update problems, even if empty (for cleanup)
 	w.updateProblems(problems)
 
@@ -200,6 +389,98 @@ func (w *Watcher) executeDetector(ctx context.Context, d detector.Detector) {
 	}
 }
 
+// detect runs one detector against provider and records query health.
+// tenant is empty in single-provider mode. Returns false on query failure.
+func (w *Watcher) detect(ctx context.Context, d detector.Detector, provider metrics.MetricsProvider, tenant string) ([]*models.Problem, bool) {
+	// Create context with configurable timeout for this detection cycle
+	detCtx, cancel := context.WithTimeout(ctx, w.detectorTimeout)
+	defer cancel()
+	detCtx, warnings := metrics.WithWarnings(detCtx)
+
+	if w.queryLogger != nil {
+		provider = w.queryLogger.wrap(d.Name(), tenant, provider)
+	}
+	counter := &countingProvider{MetricsProvider: provider}
+	provider = counter
+	var recorder *evalRecorder
+	if w.evalStaleAfter > 0 || w.queryLinkBase != "" {
+		recorder = &evalRecorder{MetricsProvider: provider}
+		provider = recorder
+	}
+	w.mu.RLock()
+	window := detector.EffectiveWindow(d, w.window, w.windows)
+	w.mu.RUnlock()
+	problems, err := d.Detect(detCtx, provider, window)
+	if err == nil && w.evalStaleAfter > 0 {
+		recorder.annotate(problems, w.now(), w.evalStaleAfter)
+	}
+	if err == nil && w.queryLinkBase != "" {
+		recorder.link(problems, w.queryLinkBase)
+	}
+	if truncated := warnings.Truncated(); err == nil && len(truncated) > 0 {
+		problems = append(problems, truncatedProblem(d.Name(), truncated))
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.queryCount++
+	w.lastPrometheusCheck = w.now()
+	w.recordDetectorStatLocked(tenant, d.Name(), int(counter.series.Load()), len(problems), err)
+	if err != nil {
+		// Mark Prometheus as unhealthy on persistent errors
+		w.recordHealthLocked(tenant, d.Name(), false)
+		w.errorCount++
+		// Errors are tracked via errorCount and surfaced through GetPrometheusStats
+		return nil, false
+	}
+
+	// Mark as healthy on successful query
+	w.recordHealthLocked(tenant, d.Name(), true)
+	w.lastSuccessfulQuery = w.now()
+
+	if w.clusterLabel && w.clusterName != "" {
+		for _, p := range problems {
+			labelCluster(p, w.clusterName)
+		}
+	}
+
+	// IDs come from one place so they never depend on how a detector
+	// formats its entity; templates see the normalized labels
+	problems = w.assignIDsLocked(problems)
+	for _, p := range problems {
+		p.AddStaticLabels(w.detectorLabels[d.Name()])
+		w.problemTemplates.Apply(p)
+	}
+	return problems, true
+}
+
+// setHealthLocked records backend health. In multi-tenant mode the overall
+// status is healthy only when every tenant is. Caller must hold w.mu.
+func (w *Watcher) setHealthLocked(tenant string, healthy bool) {
+	if tenant == "" {
+		w.prometheusHealthy = healthy
+		return
+	}
+	w.tenantHealth[tenant] = healthy
+	w.prometheusHealthy = true
+	for _, h := range w.tenantHealth {
+		if !h {
+			w.prometheusHealthy = false
+			break
+		}
+	}
+}
+
+// labelTenant scopes a detected problem to its tenant
+func labelTenant(p *models.Problem, tenant string) {
+	p.ID = tenant + ":" + p.ID
+	p.Entity = tenant + ":" + p.Entity
+	if p.Labels == nil {
+		p.Labels = make(map[string]string)
+	}
+	p.Labels["tenant"] = tenant
+}
+
 // checkPrometheusHealth performs periodic health check
 func (w *Watcher) checkPrometheusHealth(ctx context.Context) {
 	w.mu.RLock()
@@ -207,19 +488,31 @@ func (w *Watcher) checkPrometheusHealth(ctx context.Context) {
 	w.mu.RUnlock()
 
 	// Only check every 30 seconds
-	if time.Since(lastCheck) < 30*time.Second {
+	if w.now().Sub(lastCheck) < 30*time.Second {
 		return
 	}
 
 	healthCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	err := w.provider.Health(healthCtx)
+	if len(w.tenants) == 0 {
+		err := w.provider.Health(healthCtx)
 
-	w.mu.Lock()
-	w.prometheusHealthy = (err == nil)
-	w.lastPrometheusCheck = time.Now()
-	w.mu.Unlock()
+		w.mu.Lock()
+		w.recordHealthLocked("", healthCheckSource, err == nil)
+		w.lastPrometheusCheck = w.now()
+		w.mu.Unlock()
+		return
+	}
+
+	for _, t := range w.tenants {
+		err := t.provider.Health(healthCtx)
+
+		w.mu.Lock()
+		w.recordHealthLocked(t.name, healthCheckSource, err == nil)
+		w.lastPrometheusCheck = w.now()
+		w.mu.Unlock()
+	}
 }
 
 // updateProblems merges detected problems with existing state
@@ -227,30 +520,55 @@ func (w *Watcher) updateProblems(detected []*models.Problem) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	now := time.Now()
+	now := w.now()
 	updated := false
 
+	// Stale = not seen in last 1 minute (2x detector interval)
+	staleThreshold := now.Add(-1 * time.Minute)
+
+	for id, lastReported := range w.expired {
+		if lastReported.Before(staleThreshold) {
+			delete(w.expired, id) // Gone long enough to count as a new occurrence
+		}
+	}
+
 	for _, p := range detected {
+		if _, ok := w.expired[p.ID]; ok {
+			// TTL-expired and still reported: keep suppressed
+			w.expired[p.ID] = now
+			continue
+		}
 		if existing, ok := w.problems[p.ID]; ok {
 			// Update existing problem
 			existing.Count++
 			existing.LastSeen = now
 			existing.Metrics = p.Metrics
+			existing.EvaluatedAt = p.EvaluatedAt
+			existing.StaleSample = p.StaleSample
+			existing.Query = p.Query
+			existing.QueryURL = p.QueryURL
 			existing.UpdatePersistence()
+			w.recordObservation(existing, p.Severity)
 			updated = true
 		} else {
 			// New problem
 			p.FirstSeen = now
 			p.LastSeen = now
 			p.Count = 1
+			if since, ok := w.originalFirstSeen[p.ID]; ok {
+				if since.Before(now) {
+					p.OriginalFirstSeen = since
+				}
+				delete(w.originalFirstSeen, p.ID)
+			}
 			p.UpdatePersistence()
 			w.problems[p.ID] = p
+			w.recordObservation(p, p.Severity)
 			updated = true
 		}
 	}
 
-	// Prune stale problems (not seen in last 1 minute = 2x detector interval)
-	staleThreshold := now.Add(-1 * time.Minute)
+	// Prune stale problems
 	for id, p := range w.problems {
 		if p.LastSeen.Before(staleThreshold) {
 			delete(w.problems, id)
@@ -258,6 +576,15 @@ func (w *Watcher) updateProblems(detected []*models.Problem) {
 		}
 	}
 
+	// Expire problems older than their type's TTL (layered on stale pruning)
+	for id, p := range w.problems {
+		if ttl, ok := w.ttls[p.Type]; ok && ttl > 0 && now.Sub(p.FirstSeen) >= ttl {
+			delete(w.problems, id)
+			w.expired[id] = p.LastSeen
+			updated = true
+		}
+	}
+
 	// Cap problem map size to prevent unbounded growth
 	if len(w.problems) > maxProblems {
 		// Find and remove oldest non-critical problems
@@ -275,13 +602,20 @@ func (w *Watcher) updateProblems(detected []*models.Problem) {
 		}
 	}
 
+	w.pruneObservations()
+
 	// Notify UI if there were changes
 	if updated {
-		select {
-		case w.updateChan <- struct{}{}:
-		default:
-			// Channel already has a pending notification
-		}
+		w.signalUpdate()
+	}
+}
+
+// signalUpdate tells the UI the problem list changed
+func (w *Watcher) signalUpdate() {
+	select {
+	case w.updateChan <- struct{}{}:
+	default:
+		// Channel already has a pending notification
 	}
 }
 
@@ -290,12 +624,7 @@ func (w *Watcher) GetProblems() []*models.Problem {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
-	list := make([]*models.Problem, 0, len(w.problems))
-	for _, p := range w.problems {
-		// Create a copy to avoid race conditions
-		pCopy := *p
-		list = append(list, &pCopy)
-	}
+	list := w.copyProblemsLocked()
 
 	// Sort by score descending
 	sort.Slice(list, func(i, j int) bool {
@@ -310,11 +639,7 @@ func (w *Watcher) GetProblemsByRecency() []*models.Problem {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
-	list := make([]*models.Problem, 0, len(w.problems))
-	for _, p := range w.problems {
-		pCopy := *p
-		list = append(list, &pCopy)
-	}
+	list := w.copyProblemsLocked()
 
 	sort.Slice(list, func(i, j int) bool {
 		return list[i].LastSeen.After(list[j].LastSeen)
@@ -328,11 +653,7 @@ func (w *Watcher) GetProblemsByCount() []*models.Problem {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
-	list := make([]*models.Problem, 0, len(w.problems))
-	for _, p := range w.problems {
-		pCopy := *p
-		list = append(list, &pCopy)
-	}
+	list := w.copyProblemsLocked()
 
 	sort.Slice(list, func(i, j int) bool {
 		return list[i].Count > list[j].Count
@@ -341,6 +662,59 @@ func (w *Watcher) GetProblemsByCount() []*models.Problem {
 	return list
 }
 
+// GetProblemsByBlastRadius returns problems sorted by blast radius
+// descending, then by score
+func (w *Watcher) GetProblemsByBlastRadius() []*models.Problem {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	list := w.copyProblemsLocked()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].BlastRadius != list[j].BlastRadius {
+			return list[i].BlastRadius > list[j].BlastRadius
+		}
+		return list[i].Score() > list[j].Score()
+	})
+
+	return list
+}
+
+// GetProblemsByAge returns problems sorted by FirstSeen, oldest first, then
+// by score. Chronic problems nobody fixes come first instead of being buried
+// under fresh severe ones.
+func (w *Watcher) GetProblemsByAge() []*models.Problem {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	list := w.copyProblemsLocked()
+
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].FirstSeen.Equal(list[j].FirstSeen) {
+			return list[i].FirstSeen.Before(list[j].FirstSeen)
+		}
+		return list[i].Score() > list[j].Score()
+	})
+
+	return list
+}
+
+// ProblemsBy returns problems in the order of mode
+func (w *Watcher) ProblemsBy(mode SortMode) []*models.Problem {
+	switch mode {
+	case SortByRecency:
+		return w.GetProblemsByRecency()
+	case SortByCount:
+		return w.GetProblemsByCount()
+	case SortByBlastRadius:
+		return w.GetProblemsByBlastRadius()
+	case SortByAge:
+		return w.GetProblemsByAge()
+	default:
+		return w.GetProblems()
+	}
+}
+
 // GetSummary returns problem count by severity
 func (w *Watcher) GetSummary() map[models.Severity]int {
 	w.mu.RLock()
@@ -352,13 +726,34 @@ func (w *Watcher) GetSummary() map[models.Severity]int {
 		models.SeverityWarning:  0,
 	}
 
+	now := w.now()
 	for _, p := range w.problems {
-		summary[p.Severity]++
+		summary[w.effectiveSeverityLocked(p, now)]++
+	}
+	if w.unavailableProblemLocked(now) != nil {
+		summary[models.SeverityFatal]++
 	}
 
 	return summary
 }
 
+// copyProblemsLocked returns copies of the current problems, so callers never
+// race with detection, with severity overrides applied. Caller must hold w.mu.
+func (w *Watcher) copyProblemsLocked() []*models.Problem {
+	now := w.now()
+	list := make([]*models.Problem, 0, len(w.problems))
+	for _, p := range w.problems {
+		pCopy := *p
+		pCopy.Severity = w.effectiveSeverityLocked(p, now)
+		pCopy.State = models.StateFiring
+		list = append(list, &pCopy)
+	}
+	if p := w.unavailableProblemLocked(now); p != nil {
+		list = append(list, p)
+	}
+	return list
+}
+
 // UpdateChan returns the channel for UI update notifications
 func (w *Watcher) UpdateChan() <-chan struct{} {
 	return w.updateChan
@@ -376,6 +771,32 @@ func (w *Watcher) GetPrometheusHealth() (healthy bool, lastCheck time.Time) {
 	return w.prometheusHealthy, w.lastPrometheusCheck
 }
 
+// GetTenantHealth returns per-tenant backend health (nil in single-provider mode)
+func (w *Watcher) GetTenantHealth() map[string]bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if len(w.tenants) == 0 {
+		return nil
+	}
+	health := make(map[string]bool, len(w.tenantHealth))
+	for name, h := range w.tenantHealth {
+		health[name] = h
+	}
+	return health
+}
+
+// Tenants returns the sorted tenant names (nil in single-provider mode)
+func (w *Watcher) Tenants() []string {
+	if len(w.tenants) == 0 {
+		return nil
+	}
+	names := make([]string, len(w.tenants))
+	for i, t := range w.tenants {
+		names[i] = t.name
+	}
+	return names
+}
+
 // PrometheusStats contains Prometheus watchdog statistics
 type PrometheusStats struct {
 	Healthy             bool
@@ -432,7 +853,7 @@ func (w *Watcher) AnnotateHistory(problems []*models.Problem) {
 				TotalOccurrences: rec.OccurrenceCount,
 			}
 			if rec.OccurrenceCount > 1 {
-				p.History.RecurringSince = humanDuration(time.Since(rec.FirstSeen))
+				p.History.RecurringSince = humanDuration(w.now().Sub(rec.FirstSeen))
 			}
 		}
 	}
@@ -2,6 +2,8 @@ package monitor
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,7 +14,20 @@ import (
 	"github.com/ppiankov/infranow/internal/models"
 )
 
-func newTestWatcher(maxConcurrency int) *Watcher {
+// fakeClock is a manually advanced clock for WithClock
+type fakeClock struct {
+	t time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{t: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestWatcher(maxConcurrency int, opts ...WatcherOption) *Watcher {
 	provider := &metrics.MockProvider{
 		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
 			return model.Vector{}, nil
@@ -22,7 +37,7 @@ func newTestWatcher(maxConcurrency int) *Watcher {
 		},
 	}
 	registry := detector.NewRegistry()
-	return NewWatcher(provider, registry, maxConcurrency, 30*time.Second)
+	return NewWatcher(provider, registry, maxConcurrency, 30*time.Second, opts...)
 }
 
 func TestNewWatcher(t *testing.T) {
@@ -81,7 +96,8 @@ func TestUpdateProblems_NewProblem(t *testing.T) {
 }
 
 func TestUpdateProblems_UpdateExisting(t *testing.T) {
-	w := newTestWatcher(0)
+	clock := newFakeClock()
+	w := newTestWatcher(0, WithClock(clock.now))
 
 	// Insert initial problem
 	initial := []*models.Problem{
@@ -93,8 +109,7 @@ func TestUpdateProblems_UpdateExisting(t *testing.T) {
 	firstSeen := w.problems["test/problem1"].FirstSeen
 	w.mu.RUnlock()
 
-	// Small delay to ensure LastSeen changes
-	time.Sleep(time.Millisecond)
+	clock.advance(30 * time.Second)
 
 	// Update same problem
 	update := []*models.Problem{
@@ -112,24 +127,31 @@ func TestUpdateProblems_UpdateExisting(t *testing.T) {
 	if !p.FirstSeen.Equal(firstSeen) {
 		t.Error("FirstSeen should not change on update")
 	}
-	if !p.LastSeen.After(firstSeen) {
-		t.Error("LastSeen should be updated")
+	if !p.LastSeen.Equal(clock.now()) {
+		t.Errorf("LastSeen = %v, want %v", p.LastSeen, clock.now())
+	}
+	if p.Persistence != 30 {
+		t.Errorf("persistence = %v, want 30s", p.Persistence)
 	}
 }
 
 func TestUpdateProblems_StalePruning(t *testing.T) {
-	w := newTestWatcher(0)
+	clock := newFakeClock()
+	w := newTestWatcher(0, WithClock(clock.now))
+	w.updateProblems([]*models.Problem{{ID: "stale/problem", Severity: models.SeverityWarning}})
 
-	// Manually insert a stale problem
-	w.mu.Lock()
-	w.problems["stale/problem"] = &models.Problem{
-		ID:       "stale/problem",
-		LastSeen: time.Now().Add(-2 * time.Minute),
+	// Exactly one minute unseen is not yet stale
+	clock.advance(time.Minute)
+	w.updateProblems(nil)
+	w.mu.RLock()
+	_, ok := w.problems["stale/problem"]
+	w.mu.RUnlock()
+	if !ok {
+		t.Fatal("problem unseen for exactly 1m should be kept")
 	}
-	w.mu.Unlock()
 
-	// Trigger update with empty list
-	w.updateProblems([]*models.Problem{})
+	clock.advance(time.Second)
+	w.updateProblems(nil)
 
 	w.mu.RLock()
 	defer w.mu.RUnlock()
@@ -139,6 +161,77 @@ func TestUpdateProblems_StalePruning(t *testing.T) {
 	}
 }
 
+func TestUpdateProblems_TTLExpiry(t *testing.T) {
+	clock := newFakeClock()
+	w := newTestWatcher(0, WithClock(clock.now))
+	w.SetProblemTTLs(map[string]time.Duration{"job_failed": 10 * time.Minute})
+
+	report := func() []*models.Problem {
+		return []*models.Problem{
+			{ID: "batch/job-1", Type: "job_failed", Severity: models.SeverityWarning},
+			{ID: "prod/api", Type: "crashloop", Severity: models.SeverityCritical},
+		}
+	}
+	// Report both problems every 30s until just before the TTL
+	for elapsed := time.Duration(0); elapsed < 10*time.Minute; elapsed += 30 * time.Second {
+		w.updateProblems(report())
+		clock.advance(30 * time.Second)
+	}
+	w.mu.RLock()
+	_, jobPresent := w.problems["batch/job-1"]
+	w.mu.RUnlock()
+	if !jobPresent {
+		t.Fatal("problem should be kept until its TTL")
+	}
+
+	// The cycle at exactly 10m expires it
+	w.updateProblems(report())
+
+	w.mu.RLock()
+	_, jobPresent = w.problems["batch/job-1"]
+	_, apiPresent := w.problems["prod/api"]
+	w.mu.RUnlock()
+	if jobPresent {
+		t.Error("TTL-expired problem should be removed even while still reported")
+	}
+	if !apiPresent {
+		t.Error("problem type without TTL should persist")
+	}
+
+	// Still reported on the next cycle: must not come back as a new problem
+	clock.advance(30 * time.Second)
+	w.updateProblems(report())
+
+	w.mu.RLock()
+	_, jobPresent = w.problems["batch/job-1"]
+	w.mu.RUnlock()
+	if jobPresent {
+		t.Error("expired problem should stay suppressed while still reported")
+	}
+}
+
+func TestUpdateProblems_TTLExpiredRecurs(t *testing.T) {
+	clock := newFakeClock()
+	w := newTestWatcher(0, WithClock(clock.now))
+	w.SetProblemTTLs(map[string]time.Duration{"job_failed": 10 * time.Minute})
+
+	// Expired long enough ago that it is no longer being reported
+	w.mu.Lock()
+	w.expired["batch/job-1"] = clock.now()
+	w.mu.Unlock()
+	clock.advance(2 * time.Minute)
+
+	w.updateProblems([]*models.Problem{
+		{ID: "batch/job-1", Type: "job_failed", Severity: models.SeverityWarning},
+	})
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if _, ok := w.problems["batch/job-1"]; !ok {
+		t.Error("problem reported again after going stale should be tracked as new")
+	}
+}
+
 func TestUpdateProblems_NotifiesUpdateChan(t *testing.T) {
 	w := newTestWatcher(0)
 
@@ -192,6 +285,18 @@ func TestGetProblems_SortedByScore(t *testing.T) {
 	}
 }
 
+func TestGetProblems_StateFiring(t *testing.T) {
+	w := newTestWatcher(0)
+
+	w.mu.Lock()
+	w.problems["a"] = &models.Problem{ID: "a", Severity: models.SeverityCritical, LastSeen: time.Now()}
+	w.mu.Unlock()
+
+	if got := w.GetProblems()[0].State; got != models.StateFiring {
+		t.Errorf("state = %q, want firing", got)
+	}
+}
+
 func TestGetProblems_ReturnsCopies(t *testing.T) {
 	w := newTestWatcher(0)
 
@@ -280,6 +385,80 @@ func TestGetProblemsByCount(t *testing.T) {
 	}
 }
 
+func TestGetProblemsByBlastRadius(t *testing.T) {
+	w := newTestWatcher(0)
+
+	now := time.Now()
+	w.mu.Lock()
+	w.problems["a"] = &models.Problem{ID: "a", Severity: models.SeverityFatal, BlastRadius: 1, Count: 1, FirstSeen: now, LastSeen: now}
+	w.problems["b"] = &models.Problem{ID: "b", Severity: models.SeverityWarning, BlastRadius: 8, Count: 1, FirstSeen: now, LastSeen: now}
+	w.problems["c"] = &models.Problem{ID: "c", Severity: models.SeverityCritical, BlastRadius: 8, Count: 1, FirstSeen: now, LastSeen: now}
+	w.mu.Unlock()
+
+	problems := w.GetProblemsByBlastRadius()
+
+	var ids []string
+	for _, p := range problems {
+		ids = append(ids, p.ID)
+	}
+	// Equal blast radius falls back to score
+	if got := strings.Join(ids, ","); got != "c,b,a" {
+		t.Errorf("order = %s, want c,b,a", got)
+	}
+}
+
+func TestGetProblemsByAge(t *testing.T) {
+	w := newTestWatcher(0)
+
+	now := time.Now()
+	w.mu.Lock()
+	w.problems["fresh-fatal"] = &models.Problem{ID: "fresh-fatal", Severity: models.SeverityFatal, BlastRadius: 10, Count: 1, FirstSeen: now.Add(-time.Minute), LastSeen: now}
+	w.problems["chronic"] = &models.Problem{ID: "chronic", Severity: models.SeverityWarning, BlastRadius: 1, Count: 1, FirstSeen: now.Add(-7 * 24 * time.Hour), LastSeen: now}
+	w.problems["week-critical"] = &models.Problem{ID: "week-critical", Severity: models.SeverityCritical, BlastRadius: 1, Count: 1, FirstSeen: now.Add(-7 * 24 * time.Hour), LastSeen: now}
+	w.problems["day"] = &models.Problem{ID: "day", Severity: models.SeverityWarning, BlastRadius: 1, Count: 1, FirstSeen: now.Add(-24 * time.Hour), LastSeen: now}
+	w.mu.Unlock()
+
+	var ids []string
+	for _, p := range w.GetProblemsByAge() {
+		ids = append(ids, p.ID)
+	}
+	// Oldest FirstSeen first; equal FirstSeen falls back to score
+	if got := strings.Join(ids, ","); got != "week-critical,chronic,day,fresh-fatal" {
+		t.Errorf("order = %s, want week-critical,chronic,day,fresh-fatal", got)
+	}
+}
+
+func TestProblemsBy(t *testing.T) {
+	w := newTestWatcher(0)
+
+	now := time.Now()
+	w.mu.Lock()
+	w.problems["a"] = &models.Problem{ID: "a", Severity: models.SeverityFatal, BlastRadius: 1, Count: 2, FirstSeen: now.Add(-time.Hour), LastSeen: now.Add(-time.Minute)}
+	w.problems["b"] = &models.Problem{ID: "b", Severity: models.SeverityWarning, BlastRadius: 5, Count: 9, FirstSeen: now.Add(-3 * time.Hour), LastSeen: now.Add(-2 * time.Minute)}
+	w.problems["c"] = &models.Problem{ID: "c", Severity: models.SeverityCritical, BlastRadius: 3, Count: 1, FirstSeen: now.Add(-2 * time.Hour), LastSeen: now}
+	w.mu.Unlock()
+
+	tests := []struct {
+		mode SortMode
+		want string
+	}{
+		{SortBySeverity, "a,c,b"},
+		{SortByRecency, "c,a,b"},
+		{SortByCount, "b,a,c"},
+		{SortByBlastRadius, "b,c,a"},
+		{SortByAge, "b,c,a"},
+	}
+	for _, tt := range tests {
+		var ids []string
+		for _, p := range w.ProblemsBy(tt.mode) {
+			ids = append(ids, p.ID)
+		}
+		if got := strings.Join(ids, ","); got != tt.want {
+			t.Errorf("ProblemsBy(%s) = %s, want %s", tt.mode, got, tt.want)
+		}
+	}
+}
+
 func TestGetPrometheusHealth(t *testing.T) {
 	w := newTestWatcher(0)
 
@@ -331,3 +510,304 @@ func TestGetPrometheusStats(t *testing.T) {
 		t.Errorf("error rate = %f, want 0.25", stats.ErrorRate)
 	}
 }
+
+func TestWatcher_Reload(t *testing.T) {
+	w := newTestWatcher(0)
+	w.registry.Register(detector.NewOOMKillDetector())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = w.Start(ctx)
+	}()
+
+	next := detector.NewRegistry()
+	next.Register(detector.NewCrashLoopBackOffDetector())
+	next.Register(detector.NewImagePullBackOffDetector())
+
+	w.Reload(next)
+
+	names := w.DetectorNames()
+	if len(names) != 2 || names[0] != "kubernetes_crashloop" || names[1] != "kubernetes_imagepull" {
+		t.Errorf("DetectorNames() = %v, want [kubernetes_crashloop kubernetes_imagepull]", names)
+	}
+}
+
+func TestWatcher_ReloadStartsDetectorsFromEmptyRegistry(t *testing.T) {
+	// Every detector disabled at startup, then enabled by a config reload
+	w := NewWatcher(oomProvider("api"), detector.NewRegistry(), 0, 30*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = w.Start(ctx)
+	}()
+
+	next := detector.NewRegistry()
+	next.Register(detector.NewOOMKillDetector())
+	w.Reload(next)
+
+	select {
+	case <-w.UpdateChan():
+	case <-time.After(5 * time.Second):
+		t.Fatal("detectors enabled by a reload never ran")
+	}
+	if problems := w.GetProblems(); len(problems) != 1 {
+		t.Errorf("expected 1 problem after the reload, got %d", len(problems))
+	}
+}
+
+func oomProvider(pods ...string) *metrics.MockProvider {
+	return &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			vec := model.Vector{}
+			for _, pod := range pods {
+				vec = append(vec, &model.Sample{
+					Metric: model.Metric{"namespace": "prod", "pod": model.LabelValue(pod), "container": "app"},
+					Value:  1,
+				})
+			}
+			return vec, nil
+		},
+		HealthFunc: func(ctx context.Context) error { return nil },
+	}
+}
+
+func TestWatcher_Tenants(t *testing.T) {
+	// Both tenants report the same pod; tenant-b also reports a second one
+	providers := map[string]metrics.MetricsProvider{
+		"tenant-a": oomProvider("api"),
+		"tenant-b": oomProvider("api", "worker"),
+	}
+	w := NewWatcher(providers["tenant-a"], detector.NewRegistry(), 0, 30*time.Second, WithTenants(providers))
+
+	w.executeDetector(context.Background(), detector.NewOOMKillDetector())
+
+	problems := w.GetProblems()
+	if len(problems) != 3 {
+		t.Fatalf("expected 3 non-colliding problems, got %d", len(problems))
+	}
+
+	byTenant := map[string]int{}
+	ids := map[string]bool{}
+	for _, p := range problems {
+		tenant := p.Labels["tenant"]
+		byTenant[tenant]++
+		ids[p.ID] = true
+		if tenant == "" {
+			t.Errorf("problem %s missing tenant label", p.ID)
+		}
+		if p.Entity[:len(tenant)+1] != tenant+":" {
+			t.Errorf("entity %q should be prefixed with tenant %q", p.Entity, tenant)
+		}
+	}
+	if byTenant["tenant-a"] != 1 || byTenant["tenant-b"] != 2 {
+		t.Errorf("problems per tenant = %v, want tenant-a:1 tenant-b:2", byTenant)
+	}
+	apiID := models.StableID("kubernetes_pod", "oom_kill", map[string]string{"namespace": "prod", "pod": "api", "container": "app"})
+	if !ids["tenant-a:"+apiID] || !ids["tenant-b:"+apiID] {
+		t.Errorf("same problem in two tenants should have distinct IDs, got %v", ids)
+	}
+}
+
+func TestWatcher_TenantHealth(t *testing.T) {
+	failing := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			return nil, context.DeadlineExceeded
+		},
+		HealthFunc: func(ctx context.Context) error { return context.DeadlineExceeded },
+	}
+	providers := map[string]metrics.MetricsProvider{
+		"tenant-a": oomProvider("api"),
+		"tenant-b": failing,
+	}
+	w := NewWatcher(providers["tenant-a"], detector.NewRegistry(), 0, 30*time.Second, WithTenants(providers))
+
+	w.executeDetector(context.Background(), detector.NewOOMKillDetector())
+
+	health := w.GetTenantHealth()
+	if !health["tenant-a"] || health["tenant-b"] {
+		t.Errorf("tenant health = %v, want tenant-a healthy, tenant-b down", health)
+	}
+	if healthy, _ := w.GetPrometheusHealth(); healthy {
+		t.Error("overall health should be down while any tenant is down")
+	}
+	if got := len(w.GetProblems()); got != 1 {
+		t.Errorf("healthy tenant should still report problems, got %d", got)
+	}
+	if names := w.Tenants(); len(names) != 2 || names[0] != "tenant-a" {
+		t.Errorf("Tenants() = %v", names)
+	}
+}
+
+func TestObservationHistory_AccumulatesAndIsBounded(t *testing.T) {
+	clock := newFakeClock()
+	w := NewWatcher(&metrics.MockProvider{}, detector.NewRegistry(), 0, 30*time.Second, WithObservationHistory(3), WithClock(clock.now))
+
+	for i := 1; i <= 5; i++ {
+		clock.advance(30 * time.Second)
+		w.updateProblems([]*models.Problem{
+			{ID: "prod/api", Severity: models.SeverityWarning, Metrics: map[string]float64{"cycle": float64(i)}},
+		})
+
+		problems := w.GetProblems()
+		w.AnnotateObservations(problems)
+		want := i
+		if want > 3 {
+			want = 3
+		}
+		if got := len(problems[0].Observations); got != want {
+			t.Fatalf("cycle %d: %d observations, want %d", i, got, want)
+		}
+	}
+
+	problems := w.GetProblems()
+	w.AnnotateObservations(problems)
+	for i, o := range problems[0].Observations {
+		if want := float64(i + 3); o.Metrics["cycle"] != want {
+			t.Errorf("observation %d: cycle = %v, want %v (oldest first)", i, o.Metrics["cycle"], want)
+		}
+	}
+	if problems[0].Count != 5 {
+		t.Errorf("count = %d, want 5", problems[0].Count)
+	}
+
+	// History is dropped with the problem
+	clock.advance(2 * time.Minute)
+	w.updateProblems(nil)
+	if len(w.observations) != 0 {
+		t.Errorf("observations kept for pruned problem: %d", len(w.observations))
+	}
+}
+
+func TestObservationHistory_RecordsCountAndSeverityPerCycle(t *testing.T) {
+	clock := newFakeClock()
+	w := NewWatcher(&metrics.MockProvider{}, detector.NewRegistry(), 0, 30*time.Second, WithObservationHistory(3), WithClock(clock.now))
+
+	for _, severity := range []models.Severity{models.SeverityWarning, models.SeverityCritical, models.SeverityCritical, models.SeverityFatal} {
+		clock.advance(30 * time.Second)
+		w.updateProblems([]*models.Problem{{ID: "prod/api", Severity: severity}})
+	}
+
+	problems := w.GetProblems()
+	w.AnnotateObservations(problems)
+	want := []struct {
+		severity models.Severity
+		count    int
+	}{
+		{models.SeverityCritical, 2},
+		{models.SeverityCritical, 3},
+		{models.SeverityFatal, 4},
+	}
+	if len(problems[0].Observations) != len(want) {
+		t.Fatalf("%d observations, want %d", len(problems[0].Observations), len(want))
+	}
+	for i, o := range problems[0].Observations {
+		if o.Severity != want[i].severity || o.Count != want[i].count {
+			t.Errorf("cycle %d: %s count %d, want %s count %d", i, o.Severity, o.Count, want[i].severity, want[i].count)
+		}
+	}
+}
+
+func TestObservationHistory_DisabledByDefault(t *testing.T) {
+	w := newTestWatcher(0)
+	w.updateProblems([]*models.Problem{{ID: "prod/api", Severity: models.SeverityWarning}})
+
+	problems := w.GetProblems()
+	w.AnnotateObservations(problems)
+	if problems[0].Observations != nil {
+		t.Errorf("observations = %v, want nil without WithObservationHistory", problems[0].Observations)
+	}
+}
+
+func TestDetect_PassesConfiguredWindow(t *testing.T) {
+	var queries []string
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			queries = append(queries, query)
+			return model.Vector{}, nil
+		},
+	}
+	w := newTestWatcher(0, WithDetectorWindows(map[string]time.Duration{"kubernetes_oom_kills": 30 * time.Minute}))
+
+	w.detect(context.Background(), detector.NewOOMKillDetector(), provider, "")
+	w.detect(context.Background(), detector.NewRestartRateDetector(), provider, "")
+	if len(queries) != 2 {
+		t.Fatalf("got %d queries, want 2", len(queries))
+	}
+	if !strings.Contains(queries[0], "[30m]") {
+		t.Errorf("oom kill query does not use the configured window: %s", queries[0])
+	}
+	if !strings.Contains(queries[1], "[15m]") {
+		t.Errorf("restart rate query does not use its own default window: %s", queries[1])
+	}
+
+	// Hot reload
+	queries = nil
+	w.SetDetectorWindows(nil)
+	w.detect(context.Background(), detector.NewOOMKillDetector(), provider, "")
+	if !strings.Contains(queries[0], "[5m]") {
+		t.Errorf("oom kill query after reload: %s", queries[0])
+	}
+}
+
+func TestDetect_PassesDetectionWindow(t *testing.T) {
+	var query string
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, q string, ts time.Time) (model.Vector, error) {
+			query = q
+			return model.Vector{}, nil
+		},
+	}
+	w := newTestWatcher(0, WithDetectionWindow(20*time.Minute))
+
+	w.detect(context.Background(), detector.NewOOMKillDetector(), provider, "")
+	if !strings.Contains(query, "[20m]") {
+		t.Errorf("oom kill query does not use --detection-window: %s", query)
+	}
+	w.detect(context.Background(), detector.NewRestartRateDetector(), provider, "")
+	if !strings.Contains(query, "[20m]") {
+		t.Errorf("a wider detection window should widen the restart rate's 15m default: %s", query)
+	}
+}
+
+// requestsPerCycle runs every default detector once, concurrently like the
+// first cycle after Start, and returns the instant queries the backend saw
+func requestsPerCycle(wrap func(metrics.MetricsProvider) metrics.MetricsProvider) int {
+	var mu sync.Mutex
+	var requests int
+	backend := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			mu.Lock()
+			requests++
+			mu.Unlock()
+			return model.Vector{}, nil
+		},
+	}
+	provider := wrap(backend)
+	registry := detector.DefaultRegistry()
+	w := NewWatcher(provider, registry, 0, 30*time.Second)
+
+	var wg sync.WaitGroup
+	for _, d := range registry.All() {
+		wg.Add(1)
+		go func(d detector.Detector) {
+			defer wg.Done()
+			w.detect(context.Background(), d, provider, "")
+		}(d)
+	}
+	wg.Wait()
+	return requests
+}
+
+func TestBatchingProvider_FewerRequestsPerCycle(t *testing.T) {
+	direct := requestsPerCycle(func(p metrics.MetricsProvider) metrics.MetricsProvider { return p })
+	batched := requestsPerCycle(func(p metrics.MetricsProvider) metrics.MetricsProvider {
+		return metrics.NewBatchingProvider(p)
+	})
+
+	t.Logf("instant query requests per cycle: %d direct, %d batched", direct, batched)
+	if batched*2 > direct {
+		t.Errorf("batched cycle sent %d requests, want at most half of the %d sent directly", batched, direct)
+	}
+}
@@ -0,0 +1,119 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// DefaultDigestTopN is how many problems a digest lists
+const DefaultDigestTopN = 5
+
+// Digest is a point-in-time summary of the current problem set
+type Digest struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Total     int               `json:"total_problems"`
+	Fatal     int               `json:"fatal"`
+	Critical  int               `json:"critical"`
+	Warning   int               `json:"warning"`
+	Top       []*models.Problem `json:"top_problems"`
+}
+
+// BuildDigest summarizes problems, listing the topN by score
+func BuildDigest(problems []*models.Problem, topN int, now time.Time) Digest {
+	d := Digest{Timestamp: now, Total: len(problems)}
+	for _, p := range problems {
+		switch p.Severity {
+		case models.SeverityFatal:
+			d.Fatal++
+		case models.SeverityCritical:
+			d.Critical++
+		case models.SeverityWarning:
+			d.Warning++
+		}
+	}
+
+	ranked := make([]*models.Problem, len(problems))
+	copy(ranked, problems)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score() > ranked[j].Score()
+	})
+	if len(ranked) > topN {
+		ranked = ranked[:topN]
+	}
+	d.Top = ranked
+
+	return d
+}
+
+// Text renders the digest for chat platforms
+func (d Digest) Text() string {
+	var b strings.Builder
+	if d.Total == 0 {
+		b.WriteString("infranow digest: no problems detected")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "infranow digest: %d FATAL, %d CRITICAL, %d WARNING", d.Fatal, d.Critical, d.Warning)
+	for i, p := range d.Top {
+		fmt.Fprintf(&b, "\n%d. [%s] %s: %s", i+1, p.Severity, p.Entity, p.Title)
+	}
+	if rest := d.Total - len(d.Top); rest > 0 {
+		fmt.Fprintf(&b, "\n... and %d more", rest)
+	}
+	return b.String()
+}
+
+// Message wraps the digest for delivery
+func (d Digest) Message() Message {
+	return Message{Event: "digest", Text: d.Text(), Data: d}
+}
+
+// Digester sends a digest of the current problems on a fixed schedule,
+// whether or not anything changed
+type Digester struct {
+	interval time.Duration
+	topN     int
+	source   func() []*models.Problem
+	senders  []Sender
+	onError  func(error)
+}
+
+// NewDigester creates a digester. source is called on every tick to fetch the
+// current (already filtered) problem set; onError receives delivery failures.
+func NewDigester(interval time.Duration, source func() []*models.Problem, onError func(error), senders ...Sender) *Digester {
+	return &Digester{
+		interval: interval,
+		topN:     DefaultDigestTopN,
+		source:   source,
+		senders:  senders,
+		onError:  onError,
+	}
+}
+
+// Run sends a digest every interval until ctx is cancelled
+func (d *Digester) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			d.send(ctx, BuildDigest(d.source(), d.topN, now).Message())
+		}
+	}
+}
+
+func (d *Digester) send(ctx context.Context, msg Message) {
+	for _, s := range d.senders {
+		if err := s.Send(ctx, msg); err != nil && d.onError != nil {
+			d.onError(err)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+type recordingSender struct {
+	mu   sync.Mutex
+	msgs []Message
+}
+
+func (r *recordingSender) Send(_ context.Context, msg Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.msgs = append(r.msgs, msg)
+	return nil
+}
+
+func (r *recordingSender) messages() []Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Message(nil), r.msgs...)
+}
+
+func digestTestProblems() []*models.Problem {
+	return []*models.Problem{
+		{ID: "a", Severity: models.SeverityFatal, Entity: "prod/db", Title: "Primary down"},
+		{ID: "b", Severity: models.SeverityFatal, Entity: "prod/cache", Title: "Cache down"},
+		{ID: "c", Severity: models.SeverityCritical, Entity: "prod/api", Title: "CrashLoop"},
+		{ID: "d", Severity: models.SeverityWarning, Entity: "dev/web", Title: "Disk 85%"},
+	}
+}
+
+func TestBuildDigest_Counts(t *testing.T) {
+	d := BuildDigest(digestTestProblems(), 2, time.Now())
+
+	if d.Total != 4 || d.Fatal != 2 || d.Critical != 1 || d.Warning != 1 {
+		t.Errorf("counts = total %d fatal %d critical %d warning %d, want 4/2/1/1",
+			d.Total, d.Fatal, d.Critical, d.Warning)
+	}
+	if len(d.Top) != 2 {
+		t.Fatalf("top = %d problems, want 2", len(d.Top))
+	}
+	for _, p := range d.Top {
+		if p.Severity != models.SeverityFatal {
+			t.Errorf("top problems should be the highest scored, got %s", p.Severity)
+		}
+	}
+
+	text := d.Text()
+	if !strings.Contains(text, "2 FATAL, 1 CRITICAL, 1 WARNING") {
+		t.Errorf("text missing summary: %q", text)
+	}
+	if !strings.Contains(text, "and 2 more") {
+		t.Errorf("text should mention remaining problems: %q", text)
+	}
+}
+
+func TestBuildDigest_Empty(t *testing.T) {
+	d := BuildDigest(nil, DefaultDigestTopN, time.Now())
+	if d.Total != 0 || len(d.Top) != 0 {
+		t.Errorf("empty digest = %+v", d)
+	}
+	if !strings.Contains(d.Text(), "no problems") {
+		t.Errorf("empty digest text = %q", d.Text())
+	}
+}
+
+func TestDigester_FiresOnInterval(t *testing.T) {
+	sender := &recordingSender{}
+	digester := NewDigester(20*time.Millisecond, digestTestProblems, nil, sender)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		digester.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.After(2 * time.Second)
+	for len(sender.messages()) < 2 {
+		select {
+		case <-deadline:
+			t.Fatalf("expected at least 2 digests, got %d", len(sender.messages()))
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	cancel()
+	<-done
+
+	for _, msg := range sender.messages() {
+		if msg.Event != "digest" {
+			t.Errorf("event = %q, want digest", msg.Event)
+		}
+		d, ok := msg.Data.(Digest)
+		if !ok {
+			t.Fatalf("data = %T, want Digest", msg.Data)
+		}
+		if d.Fatal != 2 || d.Critical != 1 || d.Warning != 1 {
+			t.Errorf("digest counts = %d/%d/%d, want 2/1/1", d.Fatal, d.Critical, d.Warning)
+		}
+	}
+}
+
+func TestSenders_Post(t *testing.T) {
+	var mu sync.Mutex
+	bodies := map[string]map[string]interface{}{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		bodies[r.URL.Path] = body
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	webhook, err := NewWebhookSender(srv.URL + "/hook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slack, err := NewSlackSender(srv.URL + "/slack")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := BuildDigest(digestTestProblems(), DefaultDigestTopN, time.Now()).Message()
+	for _, s := range []Sender{webhook, slack} {
+		if err := s.Send(context.Background(), msg); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if bodies["/hook"]["event"] != "digest" || bodies["/hook"]["data"] == nil {
+		t.Errorf("webhook body = %v, want event and data", bodies["/hook"])
+	}
+	if text, _ := bodies["/slack"]["text"].(string); !strings.Contains(text, "2 FATAL") {
+		t.Errorf("slack text = %q", text)
+	}
+}
+
+func TestClusterSender_StampsCluster(t *testing.T) {
+	rec := &recordingSender{}
+	s := NewClusterSender(rec, "prod-eu")
+
+	if err := s.Send(context.Background(), Message{Event: EventFiring, Text: "CrashLoop prod/api"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	msgs := rec.messages()
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(msgs))
+	}
+	if msgs[0].Cluster != "prod-eu" || msgs[0].Text != "[prod-eu] CrashLoop prod/api" {
+		t.Errorf("unexpected message %+v", msgs[0])
+	}
+}
+
+func TestSender_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	s, err := NewWebhookSender(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(context.Background(), Message{Event: "digest"}); err == nil {
+		t.Error("expected error on 500 response")
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	for _, raw := range []string{"ftp://example.com", "hooks.slack.com/x", "http://", "://bad"} {
+		if err := ValidateURL(raw); err == nil {
+			t.Errorf("ValidateURL(%q) expected error", raw)
+		}
+	}
+	if err := ValidateURL("https://hooks.slack.com/services/x"); err != nil {
+		t.Errorf("ValidateURL(valid) error = %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// DefaultDedupKey deduplicates notifications on the problem ID
+const DefaultDedupKey = "{{.ID}}"
+
+// Notification event names
+const (
+	EventFiring   = "firing"
+	EventResolved = "resolved"
+)
+
+// DedupKey renders the key notifications are deduplicated on from a
+// text/template over the problem, e.g.
+// `{{.Labels.namespace}}/{{workload .Labels.pod}}/{{.Type}}`
+type DedupKey struct {
+	tmpl *template.Template
+}
+
+// ParseDedupKey compiles a dedup key template. Besides the Problem fields the
+// template can call workload, which strips pod hash suffixes so every pod of
+// a Deployment or DaemonSet shares one key across deploys.
+func ParseDedupKey(text string) (*DedupKey, error) {
+	tmpl, err := template.New("dedup-key").
+		Option("missingkey=zero").
+		Funcs(template.FuncMap{"workload": models.WorkloadName}).
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dedup key template: %w", err)
+	}
+
+	// Catch unknown fields now rather than on the first notification
+	if err := tmpl.Execute(&strings.Builder{}, &models.Problem{}); err != nil {
+		return nil, fmt.Errorf("invalid dedup key template: %w", err)
+	}
+	return &DedupKey{tmpl: tmpl}, nil
+}
+
+// Key renders the dedup key for p. It falls back to the problem ID if the
+// template fails or renders empty.
+func (k *DedupKey) Key(p *models.Problem) string {
+	var b strings.Builder
+	if err := k.tmpl.Execute(&b, p); err != nil || b.Len() == 0 {
+		return p.ID
+	}
+	return b.String()
+}
+
+// EventData is the structured payload of a firing/resolved notification
+type EventData struct {
+	Key     string          `json:"key"`
+	Status  string          `json:"status"`
+	Count   int             `json:"count"` // Problems sharing the key
+	Problem *models.Problem `json:"problem"`
+}
+
+// EventNotifier sends a notification when a problem starts and when it
+// resolves. Problems that render the same dedup key are notified once.
+type EventNotifier struct {
+	interval time.Duration
+	source   func() []*models.Problem
+	key      *DedupKey
+	senders  []Sender
+	onError  func(error)
+	now      func() time.Time
+
+	// Hysteresis (see SetHysteresis): a key must be present for fireAfter
+	// before it fires and absent for resolveAfter before it resolves
+	fireAfter    time.Duration
+	resolveAfter time.Duration
+	pendingSince map[string]time.Time // Present, not yet fired
+	absentSince  map[string]time.Time // Fired, missing since
+
+	// active maps dedup keys already notified as firing to their problem
+	active map[string]*models.Problem
+
+	// statePath persists active across restarts (see SetStateFile)
+	statePath string
+}
+
+// NewEventNotifier creates an event notifier. source is polled every interval
+// for the current (already filtered) problems; onError receives delivery
+// failures.
+func NewEventNotifier(interval time.Duration, source func() []*models.Problem, key *DedupKey, onError func(error), senders ...Sender) *EventNotifier {
+	return &EventNotifier{
+		interval: interval,
+		source:   source,
+		key:      key,
+		senders:  senders,
+		onError:  onError,
+		now:      time.Now,
+		active:   make(map[string]*models.Problem),
+
+		pendingSince: make(map[string]time.Time),
+		absentSince:  make(map[string]time.Time),
+	}
+}
+
+// SetHysteresis delays firing until a key has been present for fireAfter and
+// resolving until it has been absent for resolveAfter, so a problem hovering
+// at its threshold does not page fire, resolve, fire. Zero means immediately.
+func (n *EventNotifier) SetHysteresis(fireAfter, resolveAfter time.Duration) {
+	n.fireAfter = fireAfter
+	n.resolveAfter = resolveAfter
+}
+
+// SetStateFile restores the keys notified as firing from path and saves them
+// there whenever they change. After a restart, problems that were already
+// notified do not fire again, and ones that resolved meanwhile are resolved.
+func (n *EventNotifier) SetStateFile(path string) error {
+	s, err := LoadEventState(path)
+	if err != nil {
+		return err
+	}
+	n.statePath = path
+	n.active = s.Firing
+	return nil
+}
+
+// Run checks for started and resolved problems every interval until ctx is
+// cancelled
+func (n *EventNotifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n.Observe(ctx, n.source())
+		}
+	}
+}
+
+// Observe compares problems with the previously notified set, sending one
+// firing notification per new dedup key and one resolved notification per
+// key that disappeared, once they pass the hysteresis. A key only changes
+// state once some sender accepted its notification.
+func (n *EventNotifier) Observe(ctx context.Context, problems []*models.Problem) {
+	now := n.now()
+	groups := make(map[string][]*models.Problem)
+	for _, p := range problems {
+		k := n.key.Key(p)
+		groups[k] = append(groups[k], p)
+	}
+
+	// Firing requires uninterrupted presence
+	for k := range n.pendingSince {
+		if _, ok := groups[k]; !ok {
+			delete(n.pendingSince, k)
+		}
+	}
+
+	changed := false
+	for _, k := range sortedKeys(groups) {
+		delete(n.absentSince, k)
+		if _, ok := n.active[k]; ok {
+			continue
+		}
+		if !held(n.pendingSince, k, now, n.fireAfter) {
+			continue
+		}
+		group := groups[k]
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].Score() > group[j].Score()
+		})
+		// Undelivered keys stay pending and are retried next time
+		if !n.send(ctx, eventMessage(EventFiring, k, group[0], len(group))) {
+			continue
+		}
+		delete(n.pendingSince, k)
+		n.active[k] = group[0]
+		changed = true
+	}
+
+	for _, k := range sortedKeys(n.active) {
+		if _, ok := groups[k]; ok {
+			continue
+		}
+		if !held(n.absentSince, k, now, n.resolveAfter) {
+			continue
+		}
+		if !n.send(ctx, eventMessage(EventResolved, k, n.active[k], 0)) {
+			continue
+		}
+		delete(n.absentSince, k)
+		delete(n.active, k)
+		changed = true
+	}
+
+	if changed {
+		n.saveState(now)
+	}
+}
+
+// saveState writes the firing keys to the state file, if one is set
+func (n *EventNotifier) saveState(now time.Time) {
+	if n.statePath == "" {
+		return
+	}
+	err := SaveEventState(n.statePath, &EventState{Timestamp: now, Firing: n.active})
+	if err != nil && n.onError != nil {
+		n.onError(err)
+	}
+}
+
+// held reports whether the state tracked in since has lasted at least d for
+// key k, starting the clock on first sight
+func held(since map[string]time.Time, k string, now time.Time, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	start, ok := since[k]
+	if !ok {
+		since[k] = now
+		return false
+	}
+	return now.Sub(start) >= d
+}
+
+// send delivers msg to every sender and reports whether any succeeded
+func (n *EventNotifier) send(ctx context.Context, msg Message) bool {
+	delivered := false
+	for _, s := range n.senders {
+		if err := s.Send(ctx, msg); err != nil {
+			if n.onError != nil {
+				n.onError(err)
+			}
+			continue
+		}
+		delivered = true
+	}
+	return delivered
+}
+
+func eventMessage(status, key string, p *models.Problem, count int) Message {
+	var text string
+	if status == EventResolved {
+		text = fmt.Sprintf("RESOLVED %s: %s", p.Entity, p.Title)
+	} else {
+		text = fmt.Sprintf("FIRING [%s] %s: %s", p.Severity, p.Entity, p.Title)
+		if count > 1 {
+			text += fmt.Sprintf(" (+%d more)", count-1)
+		}
+	}
+	return Message{
+		Event: status,
+		Text:  text,
+		Data:  EventData{Key: key, Status: status, Count: count, Problem: p},
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
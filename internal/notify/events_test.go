@@ -0,0 +1,201 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+const workloadKey = "{{.Labels.namespace}}/{{workload .Labels.pod}}/{{.Type}}"
+
+func crashloopProblem(pod string) *models.Problem {
+	entity := "prod/" + pod + "/app"
+	return &models.Problem{
+		ID:       entity + "/crashloop",
+		Entity:   entity,
+		Type:     "crashloopbackoff",
+		Severity: models.SeverityFatal,
+		Title:    "Pod CrashLoopBackOff",
+		Labels:   map[string]string{"namespace": "prod", "pod": pod, "container": "app"},
+	}
+}
+
+func TestEventNotifier_WorkloadKeyDedupsPods(t *testing.T) {
+	key, err := ParseDedupKey(workloadKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{}
+	n := NewEventNotifier(0, nil, key, nil, sender)
+
+	// Same Deployment, pods from two ReplicaSets (before/after a deploy)
+	n.Observe(context.Background(), []*models.Problem{
+		crashloopProblem("api-7d9f8c6b5-xk2lp"),
+		crashloopProblem("api-5c4b7d9f8-zz9qw"),
+	})
+
+	msgs := sender.messages()
+	if len(msgs) != 1 {
+		t.Fatalf("got %d notifications, want 1", len(msgs))
+	}
+	data := msgs[0].Data.(EventData)
+	if msgs[0].Event != EventFiring || data.Key != "prod/api/crashloopbackoff" || data.Count != 2 {
+		t.Errorf("notification = %s %+v", msgs[0].Event, data)
+	}
+
+	// A replacement pod with a new hash is still the same workload
+	n.Observe(context.Background(), []*models.Problem{crashloopProblem("api-5c4b7d9f8-q7m2n")})
+	if got := len(sender.messages()); got != 1 {
+		t.Errorf("replacement pod re-notified: %d notifications", got)
+	}
+
+	n.Observe(context.Background(), nil)
+	msgs = sender.messages()
+	if len(msgs) != 2 || msgs[1].Event != EventResolved {
+		t.Errorf("expected one resolved notification, got %+v", msgs)
+	}
+}
+
+func TestEventNotifier_DefaultKeyUsesID(t *testing.T) {
+	key, err := ParseDedupKey(DefaultDedupKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{}
+	n := NewEventNotifier(0, nil, key, nil, sender)
+
+	n.Observe(context.Background(), []*models.Problem{
+		crashloopProblem("api-7d9f8c6b5-xk2lp"),
+		crashloopProblem("api-5c4b7d9f8-zz9qw"),
+	})
+	if got := len(sender.messages()); got != 2 {
+		t.Errorf("got %d notifications, want one per problem ID", got)
+	}
+}
+
+func TestParseDedupKey_Invalid(t *testing.T) {
+	for _, text := range []string{"{{.Labels.namespace", "{{.NoSuchField}}", "{{unknownFunc .ID}}"} {
+		if _, err := ParseDedupKey(text); err == nil {
+			t.Errorf("ParseDedupKey(%q) expected error", text)
+		}
+	}
+}
+
+func TestEventNotifier_HysteresisSuppressesFlapping(t *testing.T) {
+	key, err := ParseDedupKey(DefaultDedupKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &recordingSender{}
+	n := NewEventNotifier(0, nil, key, nil, sender)
+	n.SetHysteresis(time.Minute, 2*time.Minute)
+	clock := time.Now()
+	n.now = func() time.Time { return clock }
+
+	flapping := []*models.Problem{crashloopProblem("api-0")}
+	observe := func(problems []*models.Problem, after time.Duration) {
+		clock = clock.Add(after)
+		n.Observe(context.Background(), problems)
+	}
+
+	// Hovering at the threshold: present 30s, gone, present again
+	observe(flapping, 0)
+	observe(flapping, 30*time.Second)
+	observe(nil, 10*time.Second)
+	observe(flapping, 10*time.Second)
+	if got := len(sender.messages()); got != 0 {
+		t.Fatalf("flapping below fireAfter sent %d notifications", got)
+	}
+
+	// Present for a full minute: fires once
+	observe(flapping, time.Minute)
+	msgs := sender.messages()
+	if len(msgs) != 1 || msgs[0].Event != EventFiring {
+		t.Fatalf("expected one firing notification, got %+v", msgs)
+	}
+
+	// Short gaps do not resolve and re-fire
+	for range 3 {
+		observe(nil, time.Minute)
+		observe(flapping, 10*time.Second)
+	}
+	if got := len(sender.messages()); got != 1 {
+		t.Fatalf("gaps below resolveAfter caused churn: %d notifications", got)
+	}
+
+	// Gone for resolveAfter: resolves once
+	observe(nil, 10*time.Second)
+	observe(nil, 2*time.Minute)
+	msgs = sender.messages()
+	if len(msgs) != 2 || msgs[1].Event != EventResolved {
+		t.Errorf("expected a resolved notification, got %+v", msgs)
+	}
+}
+
+// flakySender fails while down is set, otherwise records like recordingSender
+type flakySender struct {
+	recordingSender
+	down bool
+}
+
+func (f *flakySender) Send(ctx context.Context, msg Message) error {
+	if f.down {
+		return errors.New("webhook unavailable")
+	}
+	return f.recordingSender.Send(ctx, msg)
+}
+
+func TestEventNotifier_RetriesUndeliveredEvents(t *testing.T) {
+	key, err := ParseDedupKey(DefaultDedupKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &flakySender{down: true}
+	var errs int
+	n := NewEventNotifier(0, nil, key, func(error) { errs++ }, sender)
+	path := filepath.Join(t.TempDir(), "events.json")
+	if err := n.SetStateFile(path); err != nil {
+		t.Fatalf("SetStateFile: %v", err)
+	}
+	problems := []*models.Problem{crashloopProblem("api-0")}
+
+	// A failed firing send is not recorded or persisted as notified
+	n.Observe(context.Background(), problems)
+	if errs != 1 {
+		t.Errorf("got %d delivery errors, want 1", errs)
+	}
+	if s, err := LoadEventState(path); err != nil || len(s.Firing) != 0 {
+		t.Fatalf("undelivered key persisted as firing: %+v, %v", s, err)
+	}
+
+	sender.down = false
+	n.Observe(context.Background(), problems)
+	msgs := sender.messages()
+	if len(msgs) != 1 || msgs[0].Event != EventFiring {
+		t.Fatalf("expected the firing notification to be retried, got %+v", msgs)
+	}
+	if s, err := LoadEventState(path); err != nil || len(s.Firing) != 1 {
+		t.Fatalf("delivered key not persisted: %+v, %v", s, err)
+	}
+
+	// Likewise a failed resolved send keeps the key firing
+	sender.down = true
+	n.Observe(context.Background(), nil)
+	if s, err := LoadEventState(path); err != nil || len(s.Firing) != 1 {
+		t.Fatalf("undelivered resolve dropped the key: %+v, %v", s, err)
+	}
+
+	sender.down = false
+	n.Observe(context.Background(), nil)
+	msgs = sender.messages()
+	if len(msgs) != 2 || msgs[1].Event != EventResolved {
+		t.Fatalf("expected the resolved notification to be retried, got %+v", msgs)
+	}
+	if s, err := LoadEventState(path); err != nil || len(s.Firing) != 0 {
+		t.Errorf("resolved key still persisted: %+v, %v", s, err)
+	}
+}
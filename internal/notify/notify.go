@@ -0,0 +1,127 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// sendTimeout bounds a single notification delivery
+const sendTimeout = 10 * time.Second
+
+// Message is a rendered notification ready for delivery
+type Message struct {
+	Event   string      `json:"event"`             // "digest", "firing", "resolved"
+	Cluster string      `json:"cluster,omitempty"` // --cluster-name, see ClusterSender
+	Text    string      `json:"text"`              // Human-readable body (chat platforms)
+	Data    interface{} `json:"data,omitempty"`    // Structured payload (webhooks)
+}
+
+// Sender delivers notifications to one destination
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// ClusterSender stamps the cluster name into every message before passing it
+// on, so notifications from several infranow instances can be told apart
+type ClusterSender struct {
+	next    Sender
+	cluster string
+}
+
+// NewClusterSender wraps next to stamp cluster into each message
+func NewClusterSender(next Sender, cluster string) *ClusterSender {
+	return &ClusterSender{next: next, cluster: cluster}
+}
+
+// Send sets msg.Cluster and prefixes the text with the cluster name
+func (s *ClusterSender) Send(ctx context.Context, msg Message) error {
+	msg.Cluster = s.cluster
+	msg.Text = "[" + s.cluster + "] " + msg.Text
+	return s.next.Send(ctx, msg)
+}
+
+// WebhookSender posts the full Message as JSON to a generic webhook
+type WebhookSender struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSender creates a generic JSON webhook sender
+func NewWebhookSender(rawURL string) (*WebhookSender, error) {
+	if err := ValidateURL(rawURL); err != nil {
+		return nil, err
+	}
+	return &WebhookSender{url: rawURL, client: &http.Client{Timeout: sendTimeout}}, nil
+}
+
+// Send posts msg as JSON
+func (s *WebhookSender) Send(ctx context.Context, msg Message) error {
+	return postJSON(ctx, s.client, s.url, msg)
+}
+
+// SlackSender posts to a Slack incoming webhook
+type SlackSender struct {
+	url    string
+	client *http.Client
+}
+
+// NewSlackSender creates a Slack incoming webhook sender
+func NewSlackSender(rawURL string) (*SlackSender, error) {
+	if err := ValidateURL(rawURL); err != nil {
+		return nil, err
+	}
+	return &SlackSender{url: rawURL, client: &http.Client{Timeout: sendTimeout}}, nil
+}
+
+// Send posts msg.Text as a Slack message
+func (s *SlackSender) Send(ctx context.Context, msg Message) error {
+	return postJSON(ctx, s.client, s.url, map[string]string{"text": msg.Text})
+}
+
+// ValidateURL checks that a notification URL is absolute http(s)
+func ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid notification URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("notification URL must use http or https scheme, got %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("notification URL must include a host")
+	}
+	return nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, target string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send notification: %w", err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body) // Drain for connection reuse
+		_ = resp.Body.Close()                 // Best-effort
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("send notification: unexpected status %s", resp.Status)
+	}
+	return nil
+}
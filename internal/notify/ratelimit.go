@@ -0,0 +1,174 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// EventThrottled is the event name of a coalesced overflow notification
+const EventThrottled = "throttled"
+
+// DefaultRateWindow is the window --notify-*-rate limits are counted over
+const DefaultRateWindow = time.Minute
+
+// ThrottleData is the structured payload of a coalesced overflow notification
+type ThrottleData struct {
+	Suppressed int             `json:"suppressed"`
+	BySeverity map[string]int  `json:"by_severity,omitempty"` // Firing/resolved events only
+	Worst      *models.Problem `json:"worst,omitempty"`       // Highest-scoring suppressed problem
+	Window     string          `json:"window"`
+}
+
+// RateLimitedSender forwards at most limit messages per window to the
+// wrapped sender. Messages over the limit are counted instead of sent and
+// delivered as one "+N more" summary when the window ends, so a node taking
+// down 50 pods pages once with a count rather than 50 times.
+type RateLimitedSender struct {
+	next   Sender
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	sent        int
+	suppressed  int
+	bySeverity  map[string]int
+	worst       *models.Problem
+}
+
+// NewRateLimitedSender wraps next with a limit of limit messages per window
+func NewRateLimitedSender(next Sender, limit int, window time.Duration) *RateLimitedSender {
+	return &RateLimitedSender{
+		next:       next,
+		limit:      limit,
+		window:     window,
+		now:        time.Now,
+		bySeverity: make(map[string]int),
+	}
+}
+
+// Send forwards msg if the window still has room, otherwise folds it into the
+// pending overflow summary. A summary left over from an earlier window is
+// delivered first.
+func (r *RateLimitedSender) Send(ctx context.Context, msg Message) error {
+	r.mu.Lock()
+	summary, hasSummary := r.rollLocked(r.now())
+	forward := r.sent < r.limit
+	if forward {
+		r.sent++
+	} else {
+		r.suppressLocked(msg)
+	}
+	r.mu.Unlock()
+
+	if hasSummary {
+		if err := r.next.Send(ctx, summary); err != nil {
+			return err
+		}
+	}
+	if !forward {
+		return nil
+	}
+	return r.next.Send(ctx, msg)
+}
+
+// Run delivers the overflow summary at the end of each window, even if no
+// further messages arrive, until ctx is cancelled. onError receives delivery
+// failures.
+func (r *RateLimitedSender) Run(ctx context.Context, onError func(error)) {
+	ticker := time.NewTicker(r.window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Flush(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Flush delivers the overflow summary if the current window has ended
+func (r *RateLimitedSender) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	summary, ok := r.rollLocked(r.now())
+	r.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return r.next.Send(ctx, summary)
+}
+
+// rollLocked starts a new window once the current one has ended, returning
+// the summary of what it suppressed. The summary counts against the new
+// window.
+func (r *RateLimitedSender) rollLocked(now time.Time) (Message, bool) {
+	if !r.windowStart.IsZero() && now.Sub(r.windowStart) < r.window {
+		return Message{}, false
+	}
+
+	var summary Message
+	hasSummary := r.suppressed > 0
+	if hasSummary {
+		summary = r.summaryLocked()
+	}
+
+	r.windowStart = now
+	r.sent = 0
+	r.suppressed = 0
+	r.bySeverity = make(map[string]int)
+	r.worst = nil
+	if hasSummary {
+		r.sent++
+	}
+	return summary, hasSummary
+}
+
+func (r *RateLimitedSender) suppressLocked(msg Message) {
+	r.suppressed++
+	data, ok := msg.Data.(EventData)
+	if !ok || data.Problem == nil {
+		return
+	}
+	r.bySeverity[string(data.Problem.Severity)]++
+	if msg.Event == EventFiring && (r.worst == nil || data.Problem.Score() > r.worst.Score()) {
+		r.worst = data.Problem
+	}
+}
+
+func (r *RateLimitedSender) summaryLocked() Message {
+	text := fmt.Sprintf("+%d more notifications suppressed (limit %d per %s)", r.suppressed, r.limit, r.window)
+
+	var counts []string
+	for _, sev := range []models.Severity{models.SeverityFatal, models.SeverityCritical, models.SeverityWarning} {
+		if n := r.bySeverity[string(sev)]; n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, sev))
+		}
+	}
+	if len(counts) > 0 {
+		text += ": " + strings.Join(counts, ", ")
+	}
+	if r.worst != nil {
+		text += fmt.Sprintf("\nWorst: [%s] %s: %s", r.worst.Severity, r.worst.Entity, r.worst.Title)
+	}
+
+	data := ThrottleData{
+		Suppressed: r.suppressed,
+		Worst:      r.worst,
+		Window:     r.window.String(),
+	}
+	if len(r.bySeverity) > 0 {
+		data.BySeverity = r.bySeverity
+	}
+	return Message{Event: EventThrottled, Text: text, Data: data}
+}
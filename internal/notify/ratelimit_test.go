@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func firingMessage(i int, severity models.Severity) Message {
+	p := &models.Problem{
+		ID:       fmt.Sprintf("pod-%d", i),
+		Entity:   fmt.Sprintf("prod/api-%d", i),
+		Severity: severity,
+		Title:    "Pod CrashLoopBackOff",
+	}
+	return eventMessage(EventFiring, p.ID, p, 1)
+}
+
+func TestRateLimitedSender_CoalescesOverflow(t *testing.T) {
+	clock := time.Unix(1700000000, 0)
+	sender := &recordingSender{}
+	r := NewRateLimitedSender(sender, 5, time.Minute)
+	r.now = func() time.Time { return clock }
+
+	// A node failure takes down 50 pods at once
+	for i := 0; i < 50; i++ {
+		severity := models.SeverityCritical
+		if i == 42 {
+			severity = models.SeverityFatal
+		}
+		if err := r.Send(context.Background(), firingMessage(i, severity)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := len(sender.messages()); got != 5 {
+		t.Fatalf("sent %d notifications within the window, want 5", got)
+	}
+
+	// Nothing is summarized until the window ends
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(sender.messages()); got != 5 {
+		t.Fatalf("summary sent before the window ended: %d notifications", got)
+	}
+
+	clock = clock.Add(time.Minute)
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	msgs := sender.messages()
+	if len(msgs) != 6 {
+		t.Fatalf("got %d notifications, want 5 + one summary", len(msgs))
+	}
+	summary := msgs[5]
+	if summary.Event != EventThrottled || !strings.HasPrefix(summary.Text, "+45 more notifications") {
+		t.Errorf("summary = %s %q", summary.Event, summary.Text)
+	}
+	data := summary.Data.(ThrottleData)
+	if data.Suppressed != 45 || data.BySeverity["FATAL"] != 1 || data.BySeverity["CRITICAL"] != 44 {
+		t.Errorf("summary data = %+v", data)
+	}
+	if data.Worst == nil || data.Worst.Entity != "prod/api-42" {
+		t.Errorf("worst = %+v, want the FATAL problem", data.Worst)
+	}
+
+	// The summary is sent once
+	clock = clock.Add(time.Minute)
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(sender.messages()); got != 6 {
+		t.Errorf("summary repeated: %d notifications", got)
+	}
+}
+
+func TestRateLimitedSender_SummaryBeforeNextWindow(t *testing.T) {
+	clock := time.Unix(1700000000, 0)
+	sender := &recordingSender{}
+	r := NewRateLimitedSender(sender, 1, time.Minute)
+	r.now = func() time.Time { return clock }
+
+	for i := 0; i < 3; i++ {
+		_ = r.Send(context.Background(), firingMessage(i, models.SeverityWarning))
+	}
+
+	// Next window: the pending summary goes out first and uses the slot
+	clock = clock.Add(2 * time.Minute)
+	_ = r.Send(context.Background(), firingMessage(3, models.SeverityWarning))
+
+	msgs := sender.messages()
+	if len(msgs) != 2 || msgs[1].Event != EventThrottled {
+		t.Fatalf("messages = %+v, want firing then throttled summary", msgs)
+	}
+	if msgs[1].Data.(ThrottleData).Suppressed != 2 {
+		t.Errorf("suppressed = %d, want 2", msgs[1].Data.(ThrottleData).Suppressed)
+	}
+}
+
+func TestRateLimitedSender_UnderLimit(t *testing.T) {
+	sender := &recordingSender{}
+	r := NewRateLimitedSender(sender, 10, time.Minute)
+
+	for i := 0; i < 10; i++ {
+		_ = r.Send(context.Background(), firingMessage(i, models.SeverityCritical))
+	}
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range sender.messages() {
+		if msg.Event == EventThrottled {
+			t.Fatal("no summary expected under the limit")
+		}
+	}
+	if got := len(sender.messages()); got != 10 {
+		t.Errorf("sent %d, want 10", got)
+	}
+}
@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+	"github.com/ppiankov/infranow/internal/util"
+)
+
+// EventState is the set of dedup keys notified as firing, persisted so a
+// restarted infranow neither re-notifies them nor forgets to resolve them
+type EventState struct {
+	Timestamp time.Time                  `json:"timestamp"`
+	Firing    map[string]*models.Problem `json:"firing"` // Keyed by dedup key
+}
+
+// LoadEventState reads a notifier state file. A missing file yields an empty
+// state, as on the very first run.
+func LoadEventState(path string) (*EventState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &EventState{Firing: map[string]*models.Problem{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read notify state file: %w", err)
+	}
+
+	var s EventState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse notify state file: %w", err)
+	}
+	if s.Firing == nil {
+		s.Firing = map[string]*models.Problem{}
+	}
+	return &s, nil
+}
+
+// SaveEventState atomically writes the notifier state
+func SaveEventState(path string, s *EventState) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal notify state: %w", err)
+	}
+
+	if err := util.WriteFileAtomic(path, data, 0o600); err != nil {
+		return fmt.Errorf("write notify state file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func newStatefulNotifier(t *testing.T, path string, sender Sender) *EventNotifier {
+	t.Helper()
+	key, err := ParseDedupKey(DefaultDedupKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := NewEventNotifier(0, nil, key, func(err error) { t.Errorf("notify error: %v", err) }, sender)
+	if err := n.SetStateFile(path); err != nil {
+		t.Fatalf("SetStateFile: %v", err)
+	}
+	return n
+}
+
+func TestEventNotifier_StateFileSuppressesRenotifyAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify-state.json")
+	problems := []*models.Problem{crashloopProblem("api-0"), crashloopProblem("db-0")}
+
+	first := &recordingSender{}
+	newStatefulNotifier(t, path, first).Observe(context.Background(), problems)
+	if got := len(first.messages()); got != 2 {
+		t.Fatalf("first run sent %d notifications, want 2", got)
+	}
+
+	// Restarted process: same problems, nothing new to say
+	second := &recordingSender{}
+	n := newStatefulNotifier(t, path, second)
+	n.Observe(context.Background(), problems)
+	if got := second.messages(); len(got) != 0 {
+		t.Fatalf("restart re-notified already firing problems: %+v", got)
+	}
+
+	// A problem that is new after the restart still fires
+	n.Observe(context.Background(), append(problems, crashloopProblem("web-0")))
+	msgs := second.messages()
+	if len(msgs) != 1 || msgs[0].Data.(EventData).Problem.ID != crashloopProblem("web-0").ID {
+		t.Errorf("expected only web-0 to fire, got %+v", msgs)
+	}
+}
+
+func TestEventNotifier_StateFileResolvesAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify-state.json")
+	api := crashloopProblem("api-0")
+
+	newStatefulNotifier(t, path, &recordingSender{}).Observe(context.Background(), []*models.Problem{api})
+
+	// Resolved while infranow was down: the restarted process resolves it
+	sender := &recordingSender{}
+	n := newStatefulNotifier(t, path, sender)
+	n.Observe(context.Background(), nil)
+	msgs := sender.messages()
+	if len(msgs) != 1 || msgs[0].Event != EventResolved {
+		t.Fatalf("expected one resolved notification, got %+v", msgs)
+	}
+	if got := msgs[0].Data.(EventData).Problem.Entity; got != api.Entity {
+		t.Errorf("resolved entity = %q, want %q", got, api.Entity)
+	}
+
+	s, err := LoadEventState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Firing) != 0 {
+		t.Errorf("resolved key still persisted: %v", s.Firing)
+	}
+}
+
+func TestLoadEventState(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := LoadEventState(filepath.Join(dir, "missing.json"))
+	if err != nil {
+		t.Fatalf("missing file: %v", err)
+	}
+	if s.Firing == nil || len(s.Firing) != 0 {
+		t.Errorf("missing file should yield an empty state, got %+v", s)
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadEventState(corrupt); err == nil {
+		t.Error("expected an error for a corrupt state file")
+	}
+}
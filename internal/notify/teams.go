@@ -0,0 +1,184 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// Adaptive Card container styles for the severity-colored header
+const (
+	teamsStyleAttention = "attention" // Red: FATAL and CRITICAL
+	teamsStyleWarning   = "warning"   // Yellow: WARNING, throttled overflow
+	teamsStyleGood      = "good"      // Green: resolved, empty digest
+	teamsStyleDefault   = "emphasis"
+)
+
+// TeamsSender posts to a Microsoft Teams incoming webhook (Workflows or a
+// legacy connector) as an Adaptive Card
+type TeamsSender struct {
+	url    string
+	client *http.Client
+}
+
+// NewTeamsSender creates a Microsoft Teams webhook sender
+func NewTeamsSender(rawURL string) (*TeamsSender, error) {
+	if err := ValidateURL(rawURL); err != nil {
+		return nil, err
+	}
+	return &TeamsSender{url: rawURL, client: &http.Client{Timeout: sendTimeout}}, nil
+}
+
+// Send posts msg as an Adaptive Card
+func (s *TeamsSender) Send(ctx context.Context, msg Message) error {
+	return postJSON(ctx, s.client, s.url, TeamsCard(msg))
+}
+
+// teamsPayload is the message envelope Teams webhooks accept
+type teamsPayload struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     adaptiveCard `json:"content"`
+}
+
+type adaptiveCard struct {
+	Schema  string        `json:"$schema"`
+	Type    string        `json:"type"`
+	Version string        `json:"version"`
+	Body    []interface{} `json:"body"`
+}
+
+type cardContainer struct {
+	Type  string        `json:"type"`
+	Style string        `json:"style"`
+	Bleed bool          `json:"bleed"`
+	Items []interface{} `json:"items"`
+}
+
+type cardText struct {
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	Weight string `json:"weight,omitempty"`
+	Size   string `json:"size,omitempty"`
+	Wrap   bool   `json:"wrap"`
+}
+
+type cardFactSet struct {
+	Type  string     `json:"type"`
+	Facts []cardFact `json:"facts"`
+}
+
+type cardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// TeamsCard renders msg as a Teams Adaptive Card: the first line of the text
+// as a header colored by severity, then the problem's facts (entity, type,
+// count, hint) for firing/resolved events, or one fact per top problem for
+// digests
+func TeamsCard(msg Message) interface{} {
+	header, rest, _ := strings.Cut(msg.Text, "\n")
+	body := []interface{}{cardContainer{
+		Type:  "Container",
+		Style: teamsStyle(msg),
+		Bleed: true,
+		Items: []interface{}{cardText{Type: "TextBlock", Text: header, Weight: "Bolder", Size: "Medium", Wrap: true}},
+	}}
+
+	switch data := msg.Data.(type) {
+	case EventData:
+		body = append(body, cardFactSet{Type: "FactSet", Facts: problemFacts(data.Problem, data.Count)})
+	case Digest:
+		facts := make([]cardFact, 0, len(data.Top))
+		for _, p := range data.Top {
+			facts = append(facts, cardFact{Title: string(p.Severity), Value: p.Entity + ": " + p.Title})
+		}
+		if len(facts) > 0 {
+			body = append(body, cardFactSet{Type: "FactSet", Facts: facts})
+		}
+		if more := data.Total - len(data.Top); more > 0 {
+			body = append(body, cardText{Type: "TextBlock", Text: fmt.Sprintf("... and %d more", more), Wrap: true})
+		}
+	default:
+		if rest != "" {
+			body = append(body, cardText{Type: "TextBlock", Text: rest, Wrap: true})
+		}
+	}
+
+	return teamsPayload{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content: adaptiveCard{
+				Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+				Type:    "AdaptiveCard",
+				Version: "1.4",
+				Body:    body,
+			},
+		}},
+	}
+}
+
+// teamsStyle colors a message's header by its worst severity
+func teamsStyle(msg Message) string {
+	switch data := msg.Data.(type) {
+	case EventData:
+		if data.Status == EventResolved {
+			return teamsStyleGood
+		}
+		return severityStyle(data.Problem.Severity)
+	case Digest:
+		switch {
+		case data.Fatal > 0 || data.Critical > 0:
+			return teamsStyleAttention
+		case data.Warning > 0:
+			return teamsStyleWarning
+		default:
+			return teamsStyleGood
+		}
+	case ThrottleData:
+		if data.Worst != nil {
+			return severityStyle(data.Worst.Severity)
+		}
+		return teamsStyleWarning
+	default:
+		return teamsStyleDefault
+	}
+}
+
+func severityStyle(s models.Severity) string {
+	if s == models.SeverityWarning {
+		return teamsStyleWarning
+	}
+	return teamsStyleAttention
+}
+
+// problemFacts lists what an on-call needs first about p; count is how many
+// problems share its dedup key
+func problemFacts(p *models.Problem, count int) []cardFact {
+	facts := []cardFact{
+		{Title: "Entity", Value: p.Entity},
+		{Title: "Type", Value: p.Type},
+		{Title: "Severity", Value: string(p.Severity)},
+		{Title: "Count", Value: strconv.Itoa(p.Count)},
+	}
+	if count > 1 {
+		facts = append(facts, cardFact{Title: "Grouped", Value: fmt.Sprintf("%d problems", count)})
+	}
+	if p.Hint != "" {
+		facts = append(facts, cardFact{Title: "Hint", Value: p.Hint})
+	}
+	if p.RunbookURL != "" {
+		facts = append(facts, cardFact{Title: "Runbook", Value: p.RunbookURL})
+	}
+	return facts
+}
@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// teamsCapture starts a server that keeps the last Teams payload it receives
+func teamsCapture(t *testing.T) (*TeamsSender, func() map[string]interface{}) {
+	t.Helper()
+	bodies := make(chan map[string]interface{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		bodies <- body
+	}))
+	t.Cleanup(srv.Close)
+
+	s, err := NewTeamsSender(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, func() map[string]interface{} { return <-bodies }
+}
+
+// cardBody digs the Adaptive Card body out of a Teams payload
+func cardBody(t *testing.T, payload map[string]interface{}) []interface{} {
+	t.Helper()
+	if payload["type"] != "message" {
+		t.Fatalf("payload type = %v, want message", payload["type"])
+	}
+	attachments, _ := payload["attachments"].([]interface{})
+	if len(attachments) != 1 {
+		t.Fatalf("expected 1 attachment, got %v", payload["attachments"])
+	}
+	attachment := attachments[0].(map[string]interface{})
+	if attachment["contentType"] != "application/vnd.microsoft.card.adaptive" {
+		t.Errorf("contentType = %v", attachment["contentType"])
+	}
+	content := attachment["content"].(map[string]interface{})
+	if content["type"] != "AdaptiveCard" {
+		t.Errorf("content type = %v", content["type"])
+	}
+	body, _ := content["body"].([]interface{})
+	return body
+}
+
+func TestTeamsSender_FiringCard(t *testing.T) {
+	s, captured := teamsCapture(t)
+	p := &models.Problem{
+		Entity: "prod/api-0", Type: "crashloopbackoff", Severity: models.SeverityFatal, Title: "CrashLoopBackOff",
+		Count: 4, Hint: "Check container logs", RunbookURL: models.RunbookBaseURL + "crashloopbackoff.md",
+	}
+	if err := s.Send(context.Background(), eventMessage(EventFiring, p.ID, p, 1)); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	body := cardBody(t, captured())
+	if len(body) != 2 {
+		t.Fatalf("expected header and facts, got %v", body)
+	}
+	header := body[0].(map[string]interface{})
+	if header["style"] != teamsStyleAttention {
+		t.Errorf("FATAL header style = %v, want %s", header["style"], teamsStyleAttention)
+	}
+	title := header["items"].([]interface{})[0].(map[string]interface{})
+	if title["text"] != "FIRING [FATAL] prod/api-0: CrashLoopBackOff" {
+		t.Errorf("header text = %v", title["text"])
+	}
+
+	facts := map[string]string{}
+	for _, f := range body[1].(map[string]interface{})["facts"].([]interface{}) {
+		fact := f.(map[string]interface{})
+		facts[fact["title"].(string)] = fact["value"].(string)
+	}
+	want := map[string]string{"Entity": "prod/api-0", "Type": "crashloopbackoff", "Count": "4", "Hint": "Check container logs"}
+	for k, v := range want {
+		if facts[k] != v {
+			t.Errorf("fact %s = %q, want %q", k, facts[k], v)
+		}
+	}
+}
+
+func TestTeamsCard_Styles(t *testing.T) {
+	warning := &models.Problem{Entity: "node-1", Severity: models.SeverityWarning, Title: "High load"}
+	tests := []struct {
+		name string
+		msg  Message
+		want string
+	}{
+		{"warning firing", eventMessage(EventFiring, "k", warning, 1), teamsStyleWarning},
+		{"resolved", eventMessage(EventResolved, "k", warning, 1), teamsStyleGood},
+		{"digest with fatal", BuildDigest(digestTestProblems(), DefaultDigestTopN, time.Now()).Message(), teamsStyleAttention},
+		{"empty digest", BuildDigest(nil, DefaultDigestTopN, time.Now()).Message(), teamsStyleGood},
+		{"throttled", Message{Event: EventThrottled, Text: "+3 more", Data: ThrottleData{Suppressed: 3}}, teamsStyleWarning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := teamsStyle(tt.msg); got != tt.want {
+				t.Errorf("style = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTeamsCard_DigestListsTopProblems(t *testing.T) {
+	d := BuildDigest(digestTestProblems(), 2, time.Now())
+	data, err := json.Marshal(TeamsCard(d.Message()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatal(err)
+	}
+	body := cardBody(t, payload)
+	facts := body[1].(map[string]interface{})["facts"].([]interface{})
+	if len(facts) != 2 {
+		t.Errorf("expected the top 2 problems as facts, got %d", len(facts))
+	}
+	if more := body[len(body)-1].(map[string]interface{})["text"]; d.Total > 2 && more == nil {
+		t.Error("expected a trailing count of the remaining problems")
+	}
+}
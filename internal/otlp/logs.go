@@ -0,0 +1,258 @@
+// Package otlp exports problems as OpenTelemetry log records, so infranow
+// findings land in the same backend as traces and metrics. Records are sent
+// with the OTLP/HTTP JSON encoding, which every OpenTelemetry Collector
+// accepts without extra dependencies.
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// LogsPath is the OTLP/HTTP logs endpoint path, appended to endpoints given
+// without one
+const LogsPath = "/v1/logs"
+
+// exportTimeout bounds a single export request
+const exportTimeout = 10 * time.Second
+
+// OpenTelemetry severity numbers (WARN, ERROR and FATAL ranges)
+const (
+	SeverityNumberWarn  = 13
+	SeverityNumberError = 17
+	SeverityNumberFatal = 21
+)
+
+// LogRecord is one problem as an OpenTelemetry log record
+type LogRecord struct {
+	Time           time.Time
+	SeverityNumber int
+	SeverityText   string
+	Body           string
+	Attributes     map[string]string
+}
+
+// LogExporter delivers log records to one destination
+type LogExporter interface {
+	Export(ctx context.Context, records []LogRecord) error
+}
+
+// SeverityNumber maps a problem severity to its OpenTelemetry severity number
+func SeverityNumber(s models.Severity) int {
+	switch s {
+	case models.SeverityFatal:
+		return SeverityNumberFatal
+	case models.SeverityCritical:
+		return SeverityNumberError
+	default:
+		return SeverityNumberWarn
+	}
+}
+
+// Records converts problems to log records observed at now: the message is
+// the body, problem fields are infranow.* attributes and labels keep their
+// names
+func Records(problems []*models.Problem, now time.Time) []LogRecord {
+	records := make([]LogRecord, 0, len(problems))
+	for _, p := range problems {
+		attrs := make(map[string]string, len(p.Labels)+8)
+		for k, v := range p.Labels {
+			attrs[k] = v
+		}
+		attrs["infranow.problem.id"] = p.ID
+		attrs["infranow.problem.type"] = p.Type
+		attrs["infranow.entity"] = p.Entity
+		attrs["infranow.entity_type"] = p.EntityType
+		attrs["infranow.title"] = p.Title
+		attrs["infranow.count"] = strconv.Itoa(p.Count)
+		attrs["infranow.blast_radius"] = strconv.Itoa(p.BlastRadius)
+		if p.RunbookURL != "" {
+			attrs["infranow.runbook_url"] = p.RunbookURL
+		}
+
+		body := p.Message
+		if body == "" {
+			body = p.Title
+		}
+		records = append(records, LogRecord{
+			Time:           now,
+			SeverityNumber: SeverityNumber(p.Severity),
+			SeverityText:   string(p.Severity),
+			Body:           body,
+			Attributes:     attrs,
+		})
+	}
+	return records
+}
+
+// HTTPExporter posts log records to an OTLP/HTTP logs endpoint as JSON
+type HTTPExporter struct {
+	url      string
+	resource map[string]string
+	version  string
+	client   *http.Client
+}
+
+// NewHTTPExporter creates an exporter for endpoint, e.g.
+// http://otel-collector:4318 (LogsPath is appended when no path is given).
+// resource holds resource attributes; service.name defaults to infranow.
+func NewHTTPExporter(endpoint, version string, resource map[string]string) (*HTTPExporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("OTLP endpoint must use http or https scheme, got %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("OTLP endpoint must include a host")
+	}
+	if strings.Trim(u.Path, "/") == "" {
+		u.Path = LogsPath
+	}
+
+	attrs := map[string]string{"service.name": "infranow"}
+	for k, v := range resource {
+		attrs[k] = v
+	}
+	return &HTTPExporter{
+		url:      u.String(),
+		resource: attrs,
+		version:  version,
+		client:   &http.Client{Timeout: exportTimeout},
+	}, nil
+}
+
+// Export sends records in one request. No records, no request.
+func (e *HTTPExporter) Export(ctx context.Context, records []LogRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(e.request(records))
+	if err != nil {
+		return fmt.Errorf("encode OTLP logs: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create OTLP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("export OTLP logs: %w", err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body) // Drain for connection reuse
+		_ = resp.Body.Close()                 // Best-effort
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("export OTLP logs: unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// OTLP JSON encoding of ExportLogsServiceRequest. 64-bit integers are
+// strings, as in the protobuf JSON mapping.
+type (
+	exportRequest struct {
+		ResourceLogs []resourceLogs `json:"resourceLogs"`
+	}
+	resourceLogs struct {
+		Resource  resource    `json:"resource"`
+		ScopeLogs []scopeLogs `json:"scopeLogs"`
+	}
+	resource struct {
+		Attributes []keyValue `json:"attributes"`
+	}
+	scopeLogs struct {
+		Scope      scope       `json:"scope"`
+		LogRecords []logRecord `json:"logRecords"`
+	}
+	scope struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+	}
+	logRecord struct {
+		TimeUnixNano         string     `json:"timeUnixNano"`
+		ObservedTimeUnixNano string     `json:"observedTimeUnixNano"`
+		SeverityNumber       int        `json:"severityNumber"`
+		SeverityText         string     `json:"severityText"`
+		Body                 anyValue   `json:"body"`
+		Attributes           []keyValue `json:"attributes"`
+	}
+	keyValue struct {
+		Key   string   `json:"key"`
+		Value anyValue `json:"value"`
+	}
+	anyValue struct {
+		StringValue string `json:"stringValue"`
+	}
+)
+
+func (e *HTTPExporter) request(records []LogRecord) exportRequest {
+	logs := make([]logRecord, 0, len(records))
+	for _, r := range records {
+		ts := strconv.FormatInt(r.Time.UnixNano(), 10)
+		logs = append(logs, logRecord{
+			TimeUnixNano:         ts,
+			ObservedTimeUnixNano: ts,
+			SeverityNumber:       r.SeverityNumber,
+			SeverityText:         r.SeverityText,
+			Body:                 anyValue{StringValue: r.Body},
+			Attributes:           keyValues(r.Attributes),
+		})
+	}
+	return exportRequest{ResourceLogs: []resourceLogs{{
+		Resource: resource{Attributes: keyValues(e.resource)},
+		ScopeLogs: []scopeLogs{{
+			Scope:      scope{Name: "infranow", Version: e.version},
+			LogRecords: logs,
+		}},
+	}}}
+}
+
+// keyValues returns attrs sorted by key, for stable output
+func keyValues(attrs map[string]string) []keyValue {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kvs := make([]keyValue, 0, len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, keyValue{Key: k, Value: anyValue{StringValue: attrs[k]}})
+	}
+	return kvs
+}
+
+// Run exports the problems source returns every interval until ctx is
+// cancelled, for long-running modes. Export errors go to onError.
+func Run(ctx context.Context, exporter LogExporter, interval time.Duration, source func() []*models.Problem, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := exporter.Export(ctx, Records(source(), now)); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
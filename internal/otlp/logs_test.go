@@ -0,0 +1,143 @@
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// memoryExporter keeps exported records in memory
+type memoryExporter struct {
+	mu      sync.Mutex
+	records []LogRecord
+}
+
+func (m *memoryExporter) Export(ctx context.Context, records []LogRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, records...)
+	return nil
+}
+
+func (m *memoryExporter) exported() []LogRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]LogRecord(nil), m.records...)
+}
+
+var testProblems = []*models.Problem{
+	{ID: "crashloopbackoff/1", Type: "crashloopbackoff", Entity: "prod/api", EntityType: "kubernetes_pod", Severity: models.SeverityFatal,
+		Title: "CrashLoopBackOff", Message: "Container api is crash looping", Count: 3, BlastRadius: 1,
+		Labels: map[string]string{"namespace": "prod", "pod": "api"}},
+	{ID: "oom_kill/2", Type: "oom_kill", Entity: "prod/worker", EntityType: "kubernetes_pod", Severity: models.SeverityCritical, Title: "OOM kill"},
+	{ID: "high_load/3", Type: "high_load", Entity: "node-1", EntityType: "node", Severity: models.SeverityWarning, Title: "High Load Average"},
+}
+
+func TestRecords_OnePerProblemWithSeverity(t *testing.T) {
+	exporter := &memoryExporter{}
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	if err := exporter.Export(context.Background(), Records(testProblems, now)); err != nil {
+		t.Fatal(err)
+	}
+
+	records := exporter.exported()
+	if len(records) != len(testProblems) {
+		t.Fatalf("%d records, want one per problem (%d)", len(records), len(testProblems))
+	}
+	want := []struct {
+		number int
+		text   string
+	}{
+		{SeverityNumberFatal, "FATAL"},
+		{SeverityNumberError, "CRITICAL"},
+		{SeverityNumberWarn, "WARNING"},
+	}
+	for i, r := range records {
+		if r.SeverityNumber != want[i].number || r.SeverityText != want[i].text {
+			t.Errorf("record %d: severity %d %s, want %d %s", i, r.SeverityNumber, r.SeverityText, want[i].number, want[i].text)
+		}
+		if !r.Time.Equal(now) {
+			t.Errorf("record %d: time = %s, want %s", i, r.Time, now)
+		}
+	}
+
+	first := records[0]
+	if first.Body != "Container api is crash looping" {
+		t.Errorf("body = %q, want the message", first.Body)
+	}
+	if first.Attributes["namespace"] != "prod" || first.Attributes["infranow.problem.type"] != "crashloopbackoff" || first.Attributes["infranow.count"] != "3" {
+		t.Errorf("unexpected attributes %v", first.Attributes)
+	}
+	if records[1].Body != "OOM kill" {
+		t.Errorf("body without message = %q, want the title", records[1].Body)
+	}
+}
+
+func TestHTTPExporter_PostsOTLPJSON(t *testing.T) {
+	var got exportRequest
+	var path, contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	exporter, err := NewHTTPExporter(srv.URL, "1.2.3", map[string]string{"k8s.cluster.name": "prod-eu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 5)
+	if err := exporter.Export(context.Background(), Records(testProblems[:1], now)); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	if path != LogsPath || contentType != "application/json" {
+		t.Errorf("posted to %s as %s, want %s as application/json", path, contentType, LogsPath)
+	}
+	if len(got.ResourceLogs) != 1 || len(got.ResourceLogs[0].ScopeLogs) != 1 {
+		t.Fatalf("unexpected request shape: %+v", got)
+	}
+	resourceAttrs := got.ResourceLogs[0].Resource.Attributes
+	if len(resourceAttrs) != 2 || resourceAttrs[0].Key != "k8s.cluster.name" || resourceAttrs[1].Value.StringValue != "infranow" {
+		t.Errorf("resource attributes = %+v", resourceAttrs)
+	}
+	logs := got.ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(logs) != 1 {
+		t.Fatalf("%d log records, want 1", len(logs))
+	}
+	if logs[0].SeverityNumber != SeverityNumberFatal || logs[0].TimeUnixNano != "1700000000000000005" {
+		t.Errorf("unexpected record %+v", logs[0])
+	}
+}
+
+func TestHTTPExporter_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	exporter, err := NewHTTPExporter(srv.URL+"/custom/logs", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := exporter.Export(context.Background(), Records(testProblems, time.Now())); err == nil {
+		t.Error("expected an error for a 400 response")
+	}
+	if err := exporter.Export(context.Background(), nil); err != nil {
+		t.Errorf("no records should send nothing, got %v", err)
+	}
+
+	for _, endpoint := range []string{"ftp://collector:4318", "http://", "://bad"} {
+		if _, err := NewHTTPExporter(endpoint, "", nil); err == nil {
+			t.Errorf("NewHTTPExporter(%q) should fail", endpoint)
+		}
+	}
+}
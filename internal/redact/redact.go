@@ -0,0 +1,230 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// tokenPrefix marks pseudonyms in output, e.g. anon-3fa92c1b
+const tokenPrefix = "anon-"
+
+// keptLabels describe what is wrong rather than who it happens to, so their
+// values are left readable
+var keptLabels = map[string]bool{
+	"condition": true,
+	"phase":     true,
+	"reason":    true,
+	"resource":  true,
+	"source":    true,
+	"status":    true,
+}
+
+// Redactor replaces names with tokens derived from a per-session key: the
+// same name always gets the same token within a session, tokens cannot be
+// reversed without the key, and Reveal maps them back for this session only.
+type Redactor struct {
+	key []byte
+
+	mu        sync.Mutex
+	originals map[string]string // Token -> name
+	ids       map[string]string // Redacted problem ID -> ID
+}
+
+// New returns a Redactor with a random key
+func New() (*Redactor, error) {
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate redaction key: %w", err)
+	}
+	return NewWithKey(key), nil
+}
+
+// NewWithKey returns a Redactor with a fixed key, so tokens are stable
+// across runs that share it
+func NewWithKey(key []byte) *Redactor {
+	return &Redactor{key: key, originals: make(map[string]string), ids: make(map[string]string)}
+}
+
+// Token returns the pseudonym for name
+func (r *Redactor) Token(name string) string {
+	if name == "" {
+		return ""
+	}
+	token := tokenPrefix + r.hash(name)[:8]
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.originals[token] = name
+	return token
+}
+
+// Reveal returns the name behind a token handed out by this Redactor
+func (r *Redactor) Reveal(token string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	name, ok := r.originals[token]
+	return name, ok
+}
+
+// RevealID returns the problem ID behind a redacted one handed out by this
+// Redactor, e.g. to mute the real problem from a redacted view
+func (r *Redactor) RevealID(redacted string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id, ok := r.ids[redacted]
+	return id, ok
+}
+
+func (r *Redactor) hash(s string) string {
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(s))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Problems returns redacted copies of problems. Entities, label values
+// (except keptLabels) and the names in titles, messages, hints and queries
+// are replaced by tokens; IDs and incident references are re-derived so
+// correlations still line up. Severity, type, counts and metrics are kept.
+// Query links are dropped since they name the Prometheus host.
+func (r *Redactor) Problems(problems []*models.Problem) []*models.Problem {
+	redacted := make([]*models.Problem, len(problems))
+	for i, p := range problems {
+		redacted[i] = r.problem(p)
+	}
+	return redacted
+}
+
+func (r *Redactor) problem(p *models.Problem) *models.Problem {
+	c := *p
+
+	// Every name this problem mentions, so free text can be scrubbed too
+	names := make(map[string]bool)
+	if p.Labels != nil {
+		c.Labels = make(map[string]string, len(p.Labels))
+		for k, v := range p.Labels {
+			if keptLabels[k] {
+				c.Labels[k] = v
+				continue
+			}
+			c.Labels[k] = r.Token(v)
+			names[v] = true
+		}
+	}
+	c.Entity = r.entity(p.Entity, names)
+
+	c.Title = r.text(p.Title, names)
+	c.Message = r.text(p.Message, names)
+	c.Hint = r.text(p.Hint, names)
+	c.Query = r.text(p.Query, names)
+	c.QueryURL = ""
+
+	c.ID = r.id(p.ID, p.Type)
+	if c.ID != "" {
+		r.mu.Lock()
+		r.ids[c.ID] = p.ID
+		r.mu.Unlock()
+	}
+	if p.RelatedIDs != nil {
+		c.RelatedIDs = make([]string, len(p.RelatedIDs))
+		for i, id := range p.RelatedIDs {
+			c.RelatedIDs[i] = r.id(id, "")
+		}
+		sort.Strings(c.RelatedIDs)
+	}
+	if rule, key, ok := strings.Cut(p.IncidentID, "/"); ok {
+		c.IncidentID = rule + "/" + r.Token(key)
+	}
+	return &c
+}
+
+// entity tokenizes each segment of a "tenant:namespace/pod"-style entity and
+// records the segments in names
+func (r *Redactor) entity(entity string, names map[string]bool) string {
+	var b strings.Builder
+	start := 0
+	for i := 0; i <= len(entity); i++ {
+		if i < len(entity) && entity[i] != '/' && entity[i] != ':' {
+			continue
+		}
+		if segment := entity[start:i]; segment != "*" {
+			names[segment] = true
+			b.WriteString(r.Token(segment))
+		} else {
+			b.WriteString(segment)
+		}
+		if i < len(entity) {
+			b.WriteByte(entity[i])
+		}
+		start = i + 1
+	}
+	return b.String()
+}
+
+// id keeps the problem type readable and replaces the rest with a hash that
+// is still unique per problem. RelatedIDs only need to match IDs, so the
+// type is taken from the ID itself when problemType is empty.
+func (r *Redactor) id(id, problemType string) string {
+	if id == "" {
+		return ""
+	}
+	if problemType == "" {
+		if _, rest, ok := strings.Cut(id, ":"); ok {
+			id = rest // Tenant prefix
+		}
+		problemType, _, _ = strings.Cut(id, "/")
+	}
+	return problemType + "/" + r.hash(id)[:16]
+}
+
+// text replaces whole-word occurrences of names in s, longest names first so
+// "api-0" is not split by a shorter "api"
+func (r *Redactor) text(s string, names map[string]bool) string {
+	if s == "" {
+		return s
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		if name != "" {
+			sorted = append(sorted, name)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	for _, name := range sorted {
+		s = replaceWord(s, name, r.Token(name))
+	}
+	return s
+}
+
+// replaceWord replaces old in s where it is not part of a longer word
+func replaceWord(s, old, replacement string) string {
+	var b strings.Builder
+	last := 0 // End of the part of s already written
+	for from := 0; ; {
+		i := strings.Index(s[from:], old)
+		if i < 0 {
+			b.WriteString(s[last:])
+			return b.String()
+		}
+		start, end := from+i, from+i+len(old)
+		if (start == 0 || !isWordByte(s[start-1])) && (end == len(s) || !isWordByte(s[end])) {
+			b.WriteString(s[last:start])
+			b.WriteString(replacement)
+			last = end
+		}
+		from = end
+	}
+}
+
+func isWordByte(c byte) bool {
+	return c == '-' || c == '_' ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
@@ -0,0 +1,146 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func testProblems() []*models.Problem {
+	return []*models.Problem{
+		{
+			ID:         "oom_kill/1a2b3c",
+			Entity:     "payments/api-0/app",
+			EntityType: "kubernetes_pod",
+			Type:       "oom_kill",
+			Severity:   models.SeverityCritical,
+			Title:      "OOM Kill",
+			Message:    "Container payments/api-0/app was OOM killed",
+			Labels:     map[string]string{"namespace": "payments", "pod": "api-0", "container": "app", "reason": "OOMKilled"},
+			Metrics:    map[string]float64{"restart_count": 3},
+			Count:      4,
+			QueryURL:   "http://prometheus.internal:9090/graph?g0.expr=x",
+			IncidentID: "namespace_meltdown/payments",
+			RelatedIDs: []string{"crashloopbackoff/4d5e6f"},
+		},
+		{
+			ID:         "crashloopbackoff/4d5e6f",
+			Entity:     "payments/api-1/app",
+			EntityType: "kubernetes_pod",
+			Type:       "crashloopbackoff",
+			Severity:   models.SeverityFatal,
+			Message:    "Pod api-1 in payments is crash looping; the application exits",
+			Labels:     map[string]string{"namespace": "payments", "pod": "api-1"},
+			IncidentID: "namespace_meltdown/payments",
+			RelatedIDs: []string{"oom_kill/1a2b3c"},
+		},
+	}
+}
+
+func TestRedactor_NoPlaintext(t *testing.T) {
+	r := NewWithKey([]byte("test"))
+	original := testProblems()
+	redacted := r.Problems(original)
+
+	for _, p := range redacted {
+		for _, field := range []string{p.ID, p.Entity, p.Title, p.Message, p.IncidentID, strings.Join(p.RelatedIDs, " ")} {
+			for _, name := range []string{"payments", "api-0", "api-1"} {
+				if strings.Contains(field, name) {
+					t.Errorf("%q still contains %q", field, name)
+				}
+			}
+		}
+		if p.Labels["namespace"] == "payments" || p.QueryURL != "" {
+			t.Errorf("labels %v, query_url %q not redacted", p.Labels, p.QueryURL)
+		}
+	}
+
+	p := redacted[0]
+	if p.Severity != models.SeverityCritical || p.Type != "oom_kill" || p.Count != 4 || p.Metrics["restart_count"] != 3 {
+		t.Errorf("severity, type, count and metrics must be kept: %+v", p)
+	}
+	if p.Labels["reason"] != "OOMKilled" {
+		t.Errorf("reason = %q, want it kept", p.Labels["reason"])
+	}
+	if !strings.Contains(redacted[1].Message, "the application exits") {
+		t.Errorf("words containing a name must be left alone: %q", redacted[1].Message)
+	}
+	if original[0].Entity != "payments/api-0/app" || original[0].Labels["namespace"] != "payments" {
+		t.Error("redaction must not modify the input problems")
+	}
+}
+
+func TestRedactor_Consistent(t *testing.T) {
+	r := NewWithKey([]byte("test"))
+	redacted := r.Problems(testProblems())
+	again := r.Problems(testProblems())
+
+	ns := redacted[0].Labels["namespace"]
+	if ns != redacted[1].Labels["namespace"] || ns != again[0].Labels["namespace"] {
+		t.Error("the same namespace must get the same token")
+	}
+	if !strings.HasPrefix(redacted[0].Entity, ns+"/") || !strings.Contains(redacted[0].Message, ns) {
+		t.Errorf("entity %q and message %q should use the namespace token %s", redacted[0].Entity, redacted[0].Message, ns)
+	}
+	if redacted[0].Labels["pod"] == redacted[1].Labels["pod"] {
+		t.Error("different pods must get different tokens")
+	}
+	if redacted[0].IncidentID != redacted[1].IncidentID {
+		t.Error("problems of one incident must keep a shared incident ID")
+	}
+	if redacted[0].RelatedIDs[0] != redacted[1].ID || redacted[1].RelatedIDs[0] != redacted[0].ID {
+		t.Error("related IDs must point at the redacted IDs")
+	}
+	if !strings.HasPrefix(redacted[0].ID, "oom_kill/") {
+		t.Errorf("ID %q should keep the problem type", redacted[0].ID)
+	}
+
+	other := NewWithKey([]byte("other session")).Problems(testProblems())
+	if other[0].Labels["namespace"] == ns {
+		t.Error("tokens must depend on the session key")
+	}
+}
+
+func TestRedactor_Reveal(t *testing.T) {
+	r, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	p := r.Problems(testProblems())[0]
+
+	if name, ok := r.Reveal(p.Labels["namespace"]); !ok || name != "payments" {
+		t.Errorf("Reveal(%s) = %q, %v, want payments", p.Labels["namespace"], name, ok)
+	}
+	if _, ok := NewWithKey([]byte("x")).Reveal(p.Labels["namespace"]); ok {
+		t.Error("another session must not reveal the token")
+	}
+}
+
+func TestRedactor_RevealID(t *testing.T) {
+	r := NewWithKey([]byte("test"))
+	p := r.Problems(testProblems())[0]
+
+	if id, ok := r.RevealID(p.ID); !ok || id != "oom_kill/1a2b3c" {
+		t.Errorf("RevealID(%s) = %q, %v, want oom_kill/1a2b3c", p.ID, id, ok)
+	}
+	if _, ok := r.RevealID("oom_kill/unknown"); ok {
+		t.Error("an ID this redactor never handed out must not be revealed")
+	}
+}
+
+func TestReplaceWord(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"api down", "X down"},
+		{"pod api, then api.", "pod X, then X."},
+		{"apiapi api-0 rapid", "apiapi api-0 rapid"},
+		{"prod/api:api", "prod/X:X"},
+	}
+	for _, tt := range tests {
+		if got := replaceWord(tt.s, "api", "X"); got != tt.want {
+			t.Errorf("replaceWord(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Package service derives the logical service a problem belongs to. Most
+// Kubernetes problems are about one pod; SREs think in the services those
+// pods make up.
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+// Label is the problem label Annotate sets
+const Label = "service"
+
+// PodLabelsQuery returns the pod labels kube-state-metrics exports, one
+// series per pod. kube-state-metrics only exports the labels allowed by its
+// --metric-labels-allowlist.
+const PodLabelsQuery = `kube_pod_labels`
+
+// nameLabels are the kube_pod_labels labels naming a pod's service, in
+// order of preference: the recommended app.kubernetes.io/name, then the
+// older app and k8s-app conventions
+var nameLabels = []model.LabelName{
+	"label_app_kubernetes_io_name",
+	"label_app",
+	"label_k8s_app",
+}
+
+// workloadLabels are problem labels that already name a workload, for
+// problems about a Deployment or StatefulSet rather than one of its pods
+var workloadLabels = []string{"deployment", "statefulset", "daemonset", "workload"}
+
+// FromPodLabels returns the service named by a kube_pod_labels series, or ""
+// if the pod carries none of the service name labels
+func FromPodLabels(metric model.Metric) string {
+	for _, name := range nameLabels {
+		if v := string(metric[name]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Resolve queries kube_pod_labels and maps "namespace/pod" to the service of
+// every pod that has a service name label
+func Resolve(ctx context.Context, provider metrics.MetricsProvider) (map[string]string, error) {
+	result, err := provider.QueryInstant(ctx, PodLabelsQuery, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("pod labels query failed: %w", err)
+	}
+	pods := make(map[string]string, len(result))
+	for _, sample := range result {
+		if svc := FromPodLabels(sample.Metric); svc != "" {
+			pods[string(sample.Metric["namespace"])+"/"+string(sample.Metric["pod"])] = svc
+		}
+	}
+	return pods, nil
+}
+
+// Derive returns the service of p: its pod's service from pods (as returned
+// by Resolve), else its workload approximated from the pod name the way
+// owner references would name it, else a workload label. Problems about
+// neither a pod nor a workload have no service.
+func Derive(p *models.Problem, pods map[string]string) string {
+	if pod := p.Labels["pod"]; pod != "" {
+		if svc := pods[p.Labels["namespace"]+"/"+pod]; svc != "" {
+			return svc
+		}
+		return models.WorkloadName(pod)
+	}
+	for _, key := range workloadLabels {
+		if v := p.Labels[key]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Annotate sets the service label on every problem with a derivable service.
+// A service label set by the detector is kept. Labels are copied before
+// modification because problem copies from the watcher share their label
+// maps.
+func Annotate(problems []*models.Problem, pods map[string]string) {
+	for _, p := range problems {
+		if p.Labels[Label] != "" {
+			continue
+		}
+		svc := Derive(p, pods)
+		if svc == "" {
+			continue
+		}
+		labels := make(map[string]string, len(p.Labels)+1)
+		for k, v := range p.Labels {
+			labels[k] = v
+		}
+		labels[Label] = svc
+		p.Labels = labels
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/ppiankov/infranow/internal/metrics"
+	"github.com/ppiankov/infranow/internal/models"
+)
+
+func TestFromPodLabels_RecommendedLabelsFirst(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric model.Metric
+		want   string
+	}{
+		{"recommended name", model.Metric{"label_app_kubernetes_io_name": "checkout", "label_app": "checkout-v2"}, "checkout"},
+		{"app", model.Metric{"label_app": "payments", "label_k8s_app": "kube-dns"}, "payments"},
+		{"k8s-app", model.Metric{"label_k8s_app": "kube-dns"}, "kube-dns"},
+		{"none", model.Metric{"label_team": "platform"}, ""},
+	}
+	for _, tt := range tests {
+		if got := FromPodLabels(tt.metric); got != tt.want {
+			t.Errorf("%s: FromPodLabels = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	provider := &metrics.MockProvider{
+		QueryInstantFunc: func(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+			if query != PodLabelsQuery {
+				return nil, errors.New("unexpected query")
+			}
+			return model.Vector{
+				&model.Sample{Metric: model.Metric{"namespace": "prod", "pod": "checkout-7d9f8c6b5-xk2lp", "label_app_kubernetes_io_name": "checkout"}, Value: 1},
+				&model.Sample{Metric: model.Metric{"namespace": "prod", "pod": "debug"}, Value: 1},
+			}, nil
+		},
+	}
+
+	pods, err := Resolve(context.Background(), provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pods) != 1 || pods["prod/checkout-7d9f8c6b5-xk2lp"] != "checkout" {
+		t.Errorf("pods = %v, want only prod/checkout-7d9f8c6b5-xk2lp=checkout", pods)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	pods := map[string]string{"prod/web-6c4f9d7b8-q8w4n": "storefront"}
+	shared := map[string]string{"namespace": "prod", "pod": "web-6c4f9d7b8-q8w4n"}
+	problems := []*models.Problem{
+		{Entity: "prod/web-6c4f9d7b8-q8w4n", Labels: shared},
+		{Entity: "prod/api-7d9f8c6b5-xk2lp", Labels: map[string]string{"namespace": "prod", "pod": "api-7d9f8c6b5-xk2lp"}},
+		{Entity: "prod/db-0", Labels: map[string]string{"namespace": "prod", "pod": "db-0"}},
+		{Entity: "prod/worker", Labels: map[string]string{"namespace": "prod", "deployment": "worker"}},
+		{Entity: "prod/svc", Labels: map[string]string{"namespace": "prod", "pod": "svc-1", "service": "billing"}},
+		{Entity: "node-1", Labels: map[string]string{"node": "node-1"}},
+	}
+
+	Annotate(problems, pods)
+
+	want := []string{"storefront", "api", "db-0", "worker", "billing", ""}
+	for i, p := range problems {
+		if got := p.Labels[Label]; got != want[i] {
+			t.Errorf("%s: service = %q, want %q", p.Entity, got, want[i])
+		}
+	}
+	if _, ok := shared[Label]; ok {
+		t.Error("Annotate modified a shared label map")
+	}
+}
+
+func TestAnnotate_WorkloadFallbackWithoutPodLabels(t *testing.T) {
+	problems := []*models.Problem{
+		{Entity: "prod/checkout-7d9f8c6b5-xk2lp", Labels: map[string]string{"namespace": "prod", "pod": "checkout-7d9f8c6b5-xk2lp"}},
+	}
+
+	Annotate(problems, nil)
+
+	if got := problems[0].Labels[Label]; got != "checkout" {
+		t.Errorf("service = %q, want checkout", got)
+	}
+}
@@ -0,0 +1,48 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteFileAtomic(path, []byte("new"), 0o600); err != nil {
+		t.Fatalf("WriteFileAtomic() error = %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "new" {
+		t.Errorf("file content = %q, want %q", got, "new")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the target file, found %d entries (temp file left behind?)", len(entries))
+	}
+}
+
+func TestWriteFileAtomic_MissingDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "state.json")
+	if err := WriteFileAtomic(path, []byte("x"), 0o600); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
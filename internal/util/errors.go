@@ -0,0 +1,77 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+)
+
+// InvalidInputError is returned for bad flags, arguments or configuration.
+// It maps to ExitInvalidInput.
+type InvalidInputError struct {
+	Err error
+}
+
+func (e *InvalidInputError) Error() string { return e.Err.Error() }
+func (e *InvalidInputError) Unwrap() error { return e.Err }
+
+// RuntimeError is returned when a valid request fails while running, e.g. an
+// unreachable Prometheus. It maps to ExitRuntimeError.
+type RuntimeError struct {
+	Err error
+}
+
+func (e *RuntimeError) Error() string { return e.Err.Error() }
+func (e *RuntimeError) Unwrap() error { return e.Err }
+
+// ExitError ends a command with Code without being a failure, e.g. problems
+// at or above --fail-on. main exits with Code and prints nothing.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string { return fmt.Sprintf("exit status %d", e.Code) }
+
+// ExitStatus returns an ExitError for code, or nil for ExitSuccess
+func ExitStatus(code int) error {
+	if code == ExitSuccess {
+		return nil
+	}
+	return &ExitError{Code: code}
+}
+
+// InvalidInput wraps err as an InvalidInputError
+func InvalidInput(err error) error {
+	return &InvalidInputError{Err: err}
+}
+
+// InvalidInputf formats an InvalidInputError; %w wraps as in fmt.Errorf
+func InvalidInputf(format string, args ...interface{}) error {
+	return &InvalidInputError{Err: fmt.Errorf(format, args...)}
+}
+
+// Runtime wraps err as a RuntimeError
+func Runtime(err error) error {
+	return &RuntimeError{Err: err}
+}
+
+// Runtimef formats a RuntimeError; %w wraps as in fmt.Errorf
+func Runtimef(format string, args ...interface{}) error {
+	return &RuntimeError{Err: fmt.Errorf(format, args...)}
+}
+
+// ExitCode returns the exit code for an error returned by a command. Untyped
+// errors count as runtime errors.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitSuccess
+	}
+	var status *ExitError
+	if errors.As(err, &status) {
+		return status.Code
+	}
+	var invalid *InvalidInputError
+	if errors.As(err, &invalid) {
+		return ExitInvalidInput
+	}
+	return ExitRuntimeError
+}
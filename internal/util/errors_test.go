@@ -0,0 +1,57 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	base := errors.New("boom")
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitSuccess},
+		{"untyped", base, ExitRuntimeError},
+		{"invalid input", InvalidInput(base), ExitInvalidInput},
+		{"invalid inputf", InvalidInputf("--limit: %w", base), ExitInvalidInput},
+		{"runtime", Runtime(base), ExitRuntimeError},
+		{"runtimef", Runtimef("query: %w", base), ExitRuntimeError},
+		{"wrapped invalid input", fmt.Errorf("monitor: %w", InvalidInput(base)), ExitInvalidInput},
+		{"wrapped runtime", fmt.Errorf("monitor: %w", Runtime(base)), ExitRuntimeError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypedErrors_KeepMessageAndCause(t *testing.T) {
+	base := errors.New("connection refused")
+	err := Runtimef("query failed: %w", base)
+	if err.Error() != "query failed: connection refused" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+	if !errors.Is(err, base) {
+		t.Error("expected Runtimef to wrap its cause")
+	}
+	if !errors.Is(InvalidInput(base), base) {
+		t.Error("expected InvalidInput to wrap its cause")
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	if err := ExitStatus(ExitSuccess); err != nil {
+		t.Errorf("ExitStatus(ExitSuccess) = %v, want nil", err)
+	}
+	for _, code := range []int{ExitProblemsWarning, ExitProblemsCritical, ExitProblemsChanged} {
+		if got := ExitCode(ExitStatus(code)); got != code {
+			t.Errorf("ExitCode(ExitStatus(%d)) = %d", code, got)
+		}
+	}
+}
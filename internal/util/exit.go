@@ -12,6 +12,8 @@ const (
 	ExitProblemsCritical = 2 // CRITICAL or FATAL problems found
 	ExitInvalidInput     = 3 // Invalid user input or configuration
 	ExitRuntimeError     = 4 // Runtime error (connection failure, etc.)
+	ExitProblemsChanged  = 5 // Problem set differs from --state-file
+	ExitTimeout          = 6 // --max-runtime reached; output is incomplete
 )
 
 // Exit terminates the program with the given exit code
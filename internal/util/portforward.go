@@ -7,10 +7,12 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -50,9 +52,51 @@ func (s PortForwardStatus) String() string {
 	}
 }
 
+// TargetKind is the kind of object a port-forward target names
+type TargetKind int
+
+const (
+	TargetService  TargetKind = iota // Forward to a running pod behind a service
+	TargetPod                        // Forward to one named pod
+	TargetSelector                   // Forward to a ready pod matching a label selector
+)
+
+// Target is what a port-forward connects to
+type Target struct {
+	Kind TargetKind
+	Name string
+}
+
+// ServiceTarget forwards to a running pod selected by the service
+func ServiceTarget(name string) Target {
+	return Target{Kind: TargetService, Name: name}
+}
+
+// PodTarget forwards to the named pod, e.g. a specific Prometheus replica
+func PodTarget(name string) Target {
+	return Target{Kind: TargetPod, Name: name}
+}
+
+// SelectorTarget forwards to a ready pod matching the label selector
+// (app=prometheus,component=server), re-resolved on every restart
+func SelectorTarget(selector string) Target {
+	return Target{Kind: TargetSelector, Name: selector}
+}
+
+func (t Target) String() string {
+	switch t.Kind {
+	case TargetPod:
+		return "pod/" + t.Name
+	case TargetSelector:
+		return "pods matching " + t.Name
+	default:
+		return "service/" + t.Name
+	}
+}
+
 // PortForward manages Kubernetes port-forwarding using client-go
 type PortForward struct {
-	service    string
+	target     Target
 	namespace  string
 	localPort  string
 	remotePort string
@@ -68,10 +112,11 @@ type PortForward struct {
 	lastError    error
 	startTime    time.Time
 	restartCount int
+	pod          string // Pod the current or last forward connected to
 }
 
 // NewPortForward creates a new native Go port-forward manager
-func NewPortForward(service, namespace, localPort, remotePort string) (*PortForward, error) {
+func NewPortForward(target Target, namespace, localPort, remotePort string) (*PortForward, error) {
 	// Load kubeconfig
 	kubeconfig := os.Getenv("KUBECONFIG")
 	if kubeconfig == "" {
@@ -95,7 +140,7 @@ func NewPortForward(service, namespace, localPort, remotePort string) (*PortForw
 	}
 
 	return &PortForward{
-		service:    service,
+		target:     target,
 		namespace:  namespace,
 		localPort:  localPort,
 		remotePort: remotePort,
@@ -115,13 +160,69 @@ func (pf *PortForward) Start() error {
 	pf.status = StatusStarting
 	pf.mu.Unlock()
 
+	podName, err := pf.resolvePod()
+	if err != nil {
+		pf.setStatus(StatusFailed, err)
+		return err
+	}
+
+	// Setup port-forward
+	if err := pf.setupPortForward(podName); err != nil {
+		pf.setStatus(StatusFailed, err)
+		return err
+	}
+
+	pf.mu.Lock()
+	pf.status = StatusRunning
+	pf.pod = podName
+	pf.startTime = time.Now()
+	pf.restartCount++
+	pf.mu.Unlock()
+
+	return nil
+}
+
+// resolvePod returns the name of the running pod to forward to
+func (pf *PortForward) resolvePod() (string, error) {
+	if pf.target.Kind == TargetPod {
+		apiCtx, apiCancel := context.WithTimeout(context.Background(), kubeAPITimeout)
+		defer apiCancel()
+		pod, err := pf.clientset.CoreV1().Pods(pf.namespace).Get(apiCtx, pf.target.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to get pod: %w", err)
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return "", fmt.Errorf("pod %s is %s, not Running", pf.target.Name, pod.Status.Phase)
+		}
+		return pod.Name, nil
+	}
+
+	if pf.target.Kind == TargetSelector {
+		podCtx, podCancel := context.WithTimeout(context.Background(), kubeAPITimeout)
+		defer podCancel()
+		pods, err := pf.clientset.CoreV1().Pods(pf.namespace).List(podCtx, metav1.ListOptions{
+			LabelSelector: pf.target.Name,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to list pods: %w", err)
+		}
+
+		pf.mu.RLock()
+		previous := pf.pod
+		pf.mu.RUnlock()
+		name, ok := pickReadyPod(pods.Items, previous)
+		if !ok {
+			return "", fmt.Errorf("no ready pods match selector %s", pf.target.Name)
+		}
+		return name, nil
+	}
+
 	// Get service to find a pod
 	apiCtx, apiCancel := context.WithTimeout(context.Background(), kubeAPITimeout)
 	defer apiCancel()
-	svc, err := pf.clientset.CoreV1().Services(pf.namespace).Get(apiCtx, pf.service, metav1.GetOptions{})
+	svc, err := pf.clientset.CoreV1().Services(pf.namespace).Get(apiCtx, pf.target.Name, metav1.GetOptions{})
 	if err != nil {
-		pf.setStatus(StatusFailed, fmt.Errorf("failed to get service: %w", err))
-		return err
+		return "", fmt.Errorf("failed to get service: %w", err)
 	}
 
 	// Find a pod matching service selector
@@ -132,32 +233,49 @@ func (pf *PortForward) Start() error {
 		FieldSelector: "status.phase=Running",
 	})
 	if err != nil {
-		pf.setStatus(StatusFailed, fmt.Errorf("failed to list pods: %w", err))
-		return err
+		return "", fmt.Errorf("failed to list pods: %w", err)
 	}
 
 	if len(pods.Items) == 0 {
-		err := fmt.Errorf("no running pods found for service %s", pf.service)
-		pf.setStatus(StatusFailed, err)
-		return err
+		return "", fmt.Errorf("no running pods found for service %s", pf.target.Name)
 	}
 
 	// Use first running pod
-	pod := pods.Items[0]
+	return pods.Items[0].Name, nil
+}
 
-	// Setup port-forward
-	if err := pf.setupPortForward(pod.Name); err != nil {
-		pf.setStatus(StatusFailed, err)
-		return err
+// pickReadyPod chooses the pod to forward to among pods matching a selector.
+// The previous pod is kept while it stays ready, so restarts don't hop between
+// replicas; otherwise the first ready pod by name is used.
+func pickReadyPod(pods []corev1.Pod, previous string) (string, bool) {
+	var ready []string
+	for i := range pods {
+		if podReady(&pods[i]) {
+			if pods[i].Name == previous {
+				return previous, true
+			}
+			ready = append(ready, pods[i].Name)
+		}
 	}
+	if len(ready) == 0 {
+		return "", false
+	}
+	sort.Strings(ready)
+	return ready[0], true
+}
 
-	pf.mu.Lock()
-	pf.status = StatusRunning
-	pf.startTime = time.Now()
-	pf.restartCount++
-	pf.mu.Unlock()
-
-	return nil
+// podReady reports whether pod is running, not terminating, and passing its
+// readiness checks
+func podReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
 }
 
 // setupPortForward configures the actual port-forward connection
@@ -296,9 +414,9 @@ func (pf *PortForward) setStatus(status PortForwardStatus, err error) {
 
 // NewPortForwardForContext creates a PortForward using a pre-built KubeContext.
 // Used by sweep to target a specific kubeconfig context.
-func NewPortForwardForContext(kctx *KubeContext, service, namespace, localPort, remotePort string) *PortForward {
+func NewPortForwardForContext(kctx *KubeContext, target Target, namespace, localPort, remotePort string) *PortForward {
 	return &PortForward{
-		service:    service,
+		target:     target,
 		namespace:  namespace,
 		localPort:  localPort,
 		remotePort: remotePort,
@@ -333,7 +451,8 @@ func (pf *PortForward) GetInfo() map[string]string {
 	defer pf.mu.RUnlock()
 
 	info := map[string]string{
-		"service":     pf.service,
+		"target":      pf.target.String(),
+		"pod":         pf.pod,
 		"namespace":   pf.namespace,
 		"local_port":  pf.localPort,
 		"remote_port": pf.remotePort,
@@ -0,0 +1,100 @@
+package util
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testPod(name string, phase corev1.PodPhase, ready bool) corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.PodStatus{
+			Phase:      phase,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestPickReadyPod(t *testing.T) {
+	terminating := testPod("prometheus-0", corev1.PodRunning, true)
+	now := metav1.Now()
+	terminating.DeletionTimestamp = &now
+
+	tests := []struct {
+		name     string
+		pods     []corev1.Pod
+		previous string
+		want     string
+		wantOK   bool
+	}{
+		{name: "no pods"},
+		{
+			name: "first ready by name",
+			pods: []corev1.Pod{
+				testPod("prometheus-2", corev1.PodRunning, true),
+				testPod("prometheus-1", corev1.PodRunning, true),
+			},
+			want: "prometheus-1", wantOK: true,
+		},
+		{
+			name: "skips unready, pending and terminating",
+			pods: []corev1.Pod{
+				terminating,
+				testPod("prometheus-1", corev1.PodRunning, false),
+				testPod("prometheus-2", corev1.PodPending, false),
+				testPod("prometheus-3", corev1.PodRunning, true),
+			},
+			want: "prometheus-3", wantOK: true,
+		},
+		{
+			name: "keeps previous pod while ready",
+			pods: []corev1.Pod{
+				testPod("prometheus-1", corev1.PodRunning, true),
+				testPod("prometheus-2", corev1.PodRunning, true),
+			},
+			previous: "prometheus-2",
+			want:     "prometheus-2", wantOK: true,
+		},
+		{
+			name: "moves on when previous pod was replaced",
+			pods: []corev1.Pod{
+				testPod("prometheus-2", corev1.PodRunning, false),
+				testPod("prometheus-7", corev1.PodRunning, true),
+			},
+			previous: "prometheus-2",
+			want:     "prometheus-7", wantOK: true,
+		},
+		{
+			name: "none ready",
+			pods: []corev1.Pod{testPod("prometheus-1", corev1.PodRunning, false)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := pickReadyPod(tt.pods, tt.previous)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("pickReadyPod() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestTargetString(t *testing.T) {
+	tests := map[string]Target{
+		"service/prometheus-operated":  ServiceTarget("prometheus-operated"),
+		"pod/prometheus-k8s-1":         PodTarget("prometheus-k8s-1"),
+		"pods matching app=prometheus": SelectorTarget("app=prometheus"),
+	}
+	for want, target := range tests {
+		if got := target.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
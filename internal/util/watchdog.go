@@ -0,0 +1,82 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"runtime/metrics"
+	"time"
+)
+
+// DefaultWatchdogInterval is how often the watchdog samples the process
+const DefaultWatchdogInterval = 10 * time.Second
+
+// Runtime metrics the watchdog samples; both are cheap to read and, unlike
+// runtime.ReadMemStats, do not stop the world
+const (
+	goroutinesMetric = "/sched/goroutines:goroutines"
+	heapMetric       = "/memory/classes/heap/objects:bytes"
+)
+
+// WatchdogLimits bound the process's own resource use (0 = unbounded)
+type WatchdogLimits struct {
+	MaxGoroutines int
+	MaxHeapBytes  uint64
+}
+
+// Watchdog aborts a long-running process whose goroutines or heap outgrow
+// their bounds, a sign of a leak (e.g. a label cardinality blowup) that would
+// otherwise grow until the node runs out of memory
+type Watchdog struct {
+	limits   WatchdogLimits
+	interval time.Duration
+	usage    func() (goroutines int, heapBytes uint64)
+}
+
+// NewWatchdog creates a watchdog sampling the process every interval
+func NewWatchdog(limits WatchdogLimits, interval time.Duration) *Watchdog {
+	return &Watchdog{limits: limits, interval: interval, usage: runtimeUsage}
+}
+
+// Check returns an error naming the first bound the process exceeds
+func (w *Watchdog) Check() error {
+	goroutines, heap := w.usage()
+	if w.limits.MaxGoroutines > 0 && goroutines > w.limits.MaxGoroutines {
+		return fmt.Errorf("%d goroutines exceed the limit of %d", goroutines, w.limits.MaxGoroutines)
+	}
+	if w.limits.MaxHeapBytes > 0 && heap > w.limits.MaxHeapBytes {
+		return fmt.Errorf("heap of %d MiB exceeds the limit of %d MiB", heap>>20, w.limits.MaxHeapBytes>>20)
+	}
+	return nil
+}
+
+// Run checks the bounds every interval and calls onExceeded once when one is
+// exceeded. It returns when ctx is cancelled or after onExceeded was called.
+func (w *Watchdog) Run(ctx context.Context, onExceeded func(error)) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Check(); err != nil {
+				onExceeded(err)
+				return
+			}
+		}
+	}
+}
+
+// runtimeUsage samples the live goroutine count and heap object bytes
+func runtimeUsage() (goroutines int, heapBytes uint64) {
+	samples := []metrics.Sample{{Name: goroutinesMetric}, {Name: heapMetric}}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		goroutines = int(samples[0].Value.Uint64())
+	}
+	if samples[1].Value.Kind() == metrics.KindUint64 {
+		heapBytes = samples[1].Value.Uint64()
+	}
+	return goroutines, heapBytes
+}
@@ -0,0 +1,91 @@
+package util
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubUsage reports fixed resource use
+func stubUsage(goroutines int, heap uint64) func() (int, uint64) {
+	return func() (int, uint64) { return goroutines, heap }
+}
+
+func TestWatchdog_Check(t *testing.T) {
+	tests := []struct {
+		name       string
+		limits     WatchdogLimits
+		goroutines int
+		heap       uint64
+		wantErr    string
+	}{
+		{"within bounds", WatchdogLimits{MaxGoroutines: 100, MaxHeapBytes: 64 << 20}, 50, 32 << 20, ""},
+		{"unbounded", WatchdogLimits{}, 100000, 8 << 30, ""},
+		{"too many goroutines", WatchdogLimits{MaxGoroutines: 100}, 101, 0, "101 goroutines exceed the limit of 100"},
+		{"heap too large", WatchdogLimits{MaxHeapBytes: 64 << 20}, 10, 96 << 20, "heap of 96 MiB exceeds the limit of 64 MiB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWatchdog(tt.limits, time.Second)
+			w.usage = stubUsage(tt.goroutines, tt.heap)
+			err := w.Check()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWatchdog_RunTriggersActionOnce(t *testing.T) {
+	w := NewWatchdog(WatchdogLimits{MaxHeapBytes: 1 << 20}, 5*time.Millisecond)
+	w.usage = stubUsage(1, 2<<20)
+
+	triggered := make(chan error, 2)
+	done := make(chan struct{})
+	go func() {
+		w.Run(context.Background(), func(err error) { triggered <- err })
+		close(done)
+	}()
+
+	select {
+	case err := <-triggered:
+		if !strings.Contains(err.Error(), "heap") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("watchdog action not triggered")
+	}
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after triggering")
+	}
+	if len(triggered) != 0 {
+		t.Error("action triggered more than once")
+	}
+}
+
+func TestWatchdog_RuntimeGoroutineBound(t *testing.T) {
+	// The test binary itself runs more than one goroutine
+	if err := NewWatchdog(WatchdogLimits{MaxGoroutines: 1}, time.Second).Check(); err == nil {
+		t.Error("expected the real goroutine count to exceed a limit of 1")
+	}
+	if err := NewWatchdog(WatchdogLimits{MaxGoroutines: 1 << 20, MaxHeapBytes: 1 << 40}, time.Second).Check(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestWatchdog_RunStopsOnCancel(t *testing.T) {
+	w := NewWatchdog(WatchdogLimits{MaxGoroutines: 100}, time.Millisecond)
+	w.usage = stubUsage(1, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w.Run(ctx, func(err error) { t.Errorf("unexpected trigger: %v", err) })
+}